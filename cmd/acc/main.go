@@ -3,17 +3,31 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/cloudcwfranck/acc/internal/attest"
 	"github.com/cloudcwfranck/acc/internal/build"
+	"github.com/cloudcwfranck/acc/internal/cache"
+	"github.com/cloudcwfranck/acc/internal/clean"
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/crypto"
 	"github.com/cloudcwfranck/acc/internal/inspect"
+	"github.com/cloudcwfranck/acc/internal/oci"
 	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/pool"
 	"github.com/cloudcwfranck/acc/internal/profile"
 	"github.com/cloudcwfranck/acc/internal/promote"
 	"github.com/cloudcwfranck/acc/internal/push"
 	"github.com/cloudcwfranck/acc/internal/runtime"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+	"github.com/cloudcwfranck/acc/internal/schema"
+	"github.com/cloudcwfranck/acc/internal/slsa"
 	"github.com/cloudcwfranck/acc/internal/trust"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	"github.com/cloudcwfranck/acc/internal/upgrade"
@@ -35,12 +49,173 @@ var (
 	noEmojiFlag bool
 	policyPack  string
 	configFile  string
+	logLevel    string
+	cacheDir    string
+	concurrency int
+	rateLimit   float64
 )
 
+// readImageRef returns ref, or reads it from stdin when ref is "-"
+// Stdin must contain exactly one non-empty line; surrounding whitespace is trimmed
+func readImageRef(stdin io.Reader, ref string) (string, error) {
+	if ref != "-" {
+		return ref, nil
+	}
+
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image reference from stdin: %w", err)
+	}
+
+	var refs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			refs = append(refs, line)
+		}
+	}
+
+	switch {
+	case len(refs) == 0:
+		return "", fmt.Errorf("no image reference on stdin")
+	case len(refs) > 1:
+		return "", fmt.Errorf("expected one image reference on stdin, got %d lines", len(refs))
+	case strings.ContainsAny(refs[0], " \t") || strings.HasPrefix(refs[0], "-"):
+		return "", fmt.Errorf("invalid image reference on stdin: %q", refs[0])
+	}
+
+	return refs[0], nil
+}
+
+// digestFlagUsage is the help text shared by --digest flags
+const digestFlagUsage = "use this image ID (config digest: sha256:<hex>, sha512:<hex>, or bare sha256 hex) for the image instead of resolving it with a container runtime"
+
+// applyDigest pins ref to digest (--digest), replacing any tag or digest in ref. A bare hex
+// digest is read as algorithm (sha256 when empty). Without a digest, ref is returned as is.
+// e.g. ghcr.io/org/app:1.0 + abc... -> ghcr.io/org/app@sha256:abc...
+// The digest is the image ID (config digest), the key state and attestations use for images
+// resolved by a container runtime; when a runtime knows the tagged ref, the two must agree.
+func applyDigest(ref, digest, algorithm string) (string, error) {
+	if digest == "" {
+		return ref, nil
+	}
+	if ref == "" {
+		return "", fmt.Errorf("--digest requires an image reference (repository) to associate the digest with")
+	}
+
+	parsed, err := oci.ParseDigest(digest, algorithm)
+	if err != nil {
+		return "", fmt.Errorf("invalid --digest %q: %v", digest, err)
+	}
+
+	repository := ref
+	if idx := strings.Index(repository, "@"); idx >= 0 {
+		repository = repository[:idx]
+	}
+	// A tag colon comes after the last "/" (a registry port colon does not)
+	if idx := strings.LastIndex(repository, ":"); idx > strings.LastIndex(repository, "/") {
+		repository = repository[:idx]
+	}
+
+	// A registry (manifest) digest would key state and attestations apart from runs without --digest
+	if repository != ref && !strings.Contains(ref, "@") {
+		if id, err := oci.InspectImageID(ref); err == nil && id != parsed {
+			return "", fmt.Errorf("--digest %s is not the image ID of %s (%s); pass the image ID, e.g. docker inspect --format '{{.Id}}' %s",
+				oci.QualifiedDigest(parsed), ref, oci.QualifiedDigest(id), ref)
+		}
+	}
+
+	return repository + "@" + oci.QualifiedDigest(parsed), nil
+}
+
+// fieldFlagUsage is the help text shared by --field flags
+const fieldFlagUsage = "print a single value from the JSON result (e.g. status, artifacts.attestations[0])"
+
+// printField prints the value at path in result for --field
+func printField(result interface{}, path string) error {
+	value, err := ui.FormatField(result, path)
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+// checkExpect returns an error when --expect is set and the verify result's status differs.
+// main renders it like any other failure (the error envelope under --json).
+func checkExpect(result *verify.VerifyResult, expect string) error {
+	if expect == "" || result.Status == expect {
+		return nil
+	}
+	return ui.NewError(ui.CodeVerificationFailed,
+		fmt.Sprintf("expected %s but got %s (%d violations)", expect, result.Status, len(result.Violations)),
+		"The image no longer produces the expected outcome; check the policy or the fixture image")
+}
+
+// verifyExitCode returns the exit code for a verify result. With --expect (already checked by
+// checkExpect), a matching status exits 0.
+func verifyExitCode(result *verify.VerifyResult, expect string, quiet bool) int {
+	if expect == "" {
+		return result.ExitCode()
+	}
+	if !quiet {
+		ui.PrintSuccess(fmt.Sprintf("Got expected status: %s", expect))
+	}
+	return 0
+}
+
+// loadConfigBundle loads the config for verify --config-from-oci, warning when the bundle is
+// neither pinned by digest nor signature-checked
+func loadConfigBundle(ref, cosignKey string, reuse, quiet bool) (*config.Config, error) {
+	cfg, bundle, err := verify.LoadConfigBundle(ref, verify.ConfigBundleOptions{CosignKey: cosignKey, Reuse: reuse})
+	if err != nil {
+		return nil, ui.WrapError(ui.CodeConfig, fmt.Errorf("failed to load config bundle: %w", err), "Check the reference and registry credentials; the bundle needs one "+oci.MediaTypeConfigBundleConfig+" layer")
+	}
+	if !quiet {
+		source := "pulled"
+		if bundle.Cached {
+			source = "cached"
+		}
+		ui.PrintInfo(fmt.Sprintf("Using config bundle %s (%s, %s)", ref, bundle.Digest, source))
+		if _, _, pinned := oci.SplitDigestRef(ref); cosignKey == "" && !pinned {
+			ui.PrintWarning("Config bundle is neither pinned by digest nor signature-checked; pin it with @sha256:<digest> or pass --config-oci-key")
+		}
+	}
+	return cfg, nil
+}
+
+// configLoadError reports a failure to load acc.yaml
+func configLoadError(err error) error {
+	return ui.WrapError(ui.CodeConfig, fmt.Errorf("failed to load config: %w", err), "Run 'acc init' to create a configuration file")
+}
+
+// applyPolicyPack selects the --policy-pack pack (overriding policy.pack) and checks that
+// the configured pack resolves, so an unknown name fails before any image work
+func applyPolicyPack(cfg *config.Config) error {
+	if policyPack != "" {
+		cfg.Policy.Pack = policyPack
+	}
+	pack, err := policy.ResolvePack(cfg.Policy.Pack)
+	if err != nil {
+		return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Create .acc/policy-packs/<name>/ with .rego files, or use a built-in pack: baseline, cis")
+	}
+	pack.Cleanup()
+	return nil
+}
+
+// imageRefRequired reports a missing image argument
+func imageRefRequired(usage string) error {
+	return ui.NewError(ui.CodeInvalidArgument, "image reference required", "Usage: "+usage)
+}
+
 func main() {
 	rootCmd := NewRootCmd()
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		// --json failures use the {"error":{"code","message","hint"}} envelope on stdout
+		if jsonFlag {
+			fmt.Println(ui.FormatErrorJSON(err))
+		} else {
+			fmt.Fprintln(os.Stderr, ui.FormatError(err.Error()))
+		}
 		os.Exit(1)
 	}
 }
@@ -53,20 +228,37 @@ func NewRootCmd() *cobra.Command {
 that can be built, verified, run, pushed, and promoted with cryptographic and policy gates.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Apply global UI settings
 			ui.SetColorMode(colorFlag)
 			ui.SetEmojiEnabled(!noEmojiFlag)
+			cache.SetDir(cacheDir)
+			if concurrency < 0 {
+				return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --concurrency %d", concurrency), "Use a positive number, or 0 for the CPU-based default")
+			}
+			if rateLimit < 0 {
+				return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --rate-limit %g", rateLimit), "Use requests per second, or 0 for no limit")
+			}
+			pool.Configure(concurrency, rateLimit)
+			return ui.SetLogLevel(logLevel)
 		},
 	}
 
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return ui.WrapError(ui.CodeInvalidArgument, err, fmt.Sprintf("Run '%s --help' for usage", cmd.CommandPath()))
+	})
+
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&colorFlag, "color", "auto", "colorize output (auto|always|never)")
 	rootCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "output in JSON format")
 	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "suppress non-critical output")
 	rootCmd.PersistentFlags().BoolVar(&noEmojiFlag, "no-emoji", false, "disable emoji in output")
-	rootCmd.PersistentFlags().StringVar(&policyPack, "policy-pack", "", "path to policy pack")
+	rootCmd.PersistentFlags().StringVar(&policyPack, "policy-pack", "", "policy pack to evaluate instead of .acc/policy: a name in .acc/policy-packs/, a built-in pack (baseline, cis), or a directory (overrides policy.pack)")
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "path to config file")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level (info|debug)")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "shared cache for policy evaluations and registry lookups (default $ACC_CACHE_DIR)")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 0, "maximum parallel OPA evaluations and registry fetches (default: CPU count, 2-16)")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "maximum registry requests per second (0 = unlimited)")
 
 	// Add all subcommands
 	rootCmd.AddCommand(
@@ -80,8 +272,12 @@ that can be built, verified, run, pushed, and promoted with cryptographic and po
 		NewAttestCmd(),
 		NewInspectCmd(),
 		NewTrustCmd(),
+		NewSBOMCmd(),
+		NewWaiverCmd(),
 		NewConfigCmd(),
+		NewCleanCmd(),
 		NewLoginCmd(),
+		NewSchemaCmd(),
 		NewVersionCmd(),
 		NewUpgradeCmd(),
 	)
@@ -113,6 +309,9 @@ func NewInitCmd() *cobra.Command {
 
 func NewBuildCmd() *cobra.Command {
 	var tag string
+	var labelFlags []string
+	var buildArgFlags []string
+	var cacheFrom, cacheTo []string
 
 	cmd := &cobra.Command{
 		Use:   "build [image]",
@@ -129,7 +328,7 @@ func NewBuildCmd() *cobra.Command {
 			// Load config
 			cfg, err := config.Load(configFile)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+				return configLoadError(err)
 			}
 
 			// v0.2.3: Accept positional argument for backward compatibility
@@ -143,8 +342,23 @@ func NewBuildCmd() *cobra.Command {
 				}
 			}
 
+			labels, err := build.ParseLabels(labelFlags)
+			if err != nil {
+				return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Usage: acc build --label key=value [--label key=value ...]")
+			}
+
+			buildArgs, err := build.ParseBuildArgs(buildArgFlags)
+			if err != nil {
+				return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Usage: acc build --build-arg KEY=value [--build-arg KEY=value ...]")
+			}
+
+			cache := build.CacheOptions{From: cacheFrom, To: cacheTo}
+			if err := build.ValidateCacheOptions(cache); err != nil {
+				return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Usage: acc build --cache-from <spec> --cache-to <spec>, e.g. type=registry,ref=registry.example.com/app:buildcache")
+			}
+
 			// Build image
-			result, err := build.Build(cfg, finalTag, jsonFlag)
+			result, err := build.Build(cfg, finalTag, labels, buildArgs, cache, version, jsonFlag)
 			if err != nil {
 				return err
 			}
@@ -158,6 +372,10 @@ func NewBuildCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&tag, "tag", "t", "", "image tag (default: from config)")
+	cmd.Flags().StringArrayVar(&labelFlags, "label", nil, "image label key=value, added to the automatic org.opencontainers.image.* labels (repeatable)")
+	cmd.Flags().StringArrayVar(&buildArgFlags, "build-arg", nil, "build-time variable KEY=value passed to the build tool and recorded in the build manifest; secret-looking values are redacted there (repeatable)")
+	cmd.Flags().StringArrayVar(&cacheFrom, "cache-from", nil, "layer cache source passed to the builder, e.g. type=registry,ref=<image> with docker buildx or an image repository with podman/buildah (repeatable)")
+	cmd.Flags().StringArrayVar(&cacheTo, "cache-to", nil, "layer cache destination passed to the builder, e.g. type=registry,ref=<image>,mode=max (repeatable)")
 
 	return cmd
 }
@@ -166,6 +384,68 @@ func NewVerifyCmd() *cobra.Command {
 	var (
 		imageRef    string
 		profilePath string
+		policyMode  string
+		twoPhase    string
+		digestAlg   string
+		ignoreFile  string
+		requireProv bool
+		provSource  string
+		maxViol     int
+		bundleOut   string
+		signBundle  bool
+		cosignKey   string
+		field       string
+		digest      string
+		remoteCfg   bool
+		printInput  bool
+		summaryFile string
+		commentOut  string
+		parallelOPA bool
+		fromManif   bool
+		dataFiles   []string
+		verifySig   bool
+		identityRe  string
+		issuerRe    string
+		noWaivers   bool
+		noState     bool
+		regoPrint   bool
+		missingOPA  bool
+		explainDeny bool
+		sbomSigned  bool
+		envName     string
+		team        string
+		regoQuery   string
+		warnBudget  int
+		regoTimeout time.Duration
+		reqLabels   []string
+		explainSch  bool
+		minSBOMComp int
+		reqSBOMFmt  string
+		sinceBuild  bool
+		sbomFromImg bool
+		reqPinned   bool
+		reqNonRoot  bool
+		reqHealth   bool
+		healthSev   string
+		forbidPorts bool
+		portSev     string
+		denyEnvSecr bool
+		envSecrPats []string
+		sbomBase    string
+		denyNewPkgs string
+		writeBase   string
+		reportUnusd bool
+		failUnused  bool
+		fixtureDir  string
+		inputFile   string
+		captureInsp string
+		policyDir   string
+		jsonCompact bool
+		jsonErrOnly bool
+		expect      string
+		configOCI   string
+		configKey   string
+		bundleCache bool
 	)
 
 	cmd := &cobra.Command{
@@ -174,10 +454,311 @@ func NewVerifyCmd() *cobra.Command {
 		Long:  "Verify SBOM exists, evaluate policy, and check signature/attestation presence",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Load config
-			cfg, err := config.Load(configFile)
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+			// --explain-json-schema documents the --json result; nothing is verified
+			if explainSch {
+				return printSchema("verify")
+			}
+
+			// --json-compact is --json on a single line
+			if jsonCompact {
+				jsonFlag = true
+			}
+
+			// --json-errors-only is --json with every human message suppressed, so stdout is
+			// exactly one JSON document: the result, or the error envelope
+			if jsonErrOnly {
+				if field != "" || printInput {
+					return ui.NewError(ui.CodeInvalidArgument, "--json-errors-only cannot be used with --field or --print-input", "Use --json-errors-only alone to get the full JSON result")
+				}
+				jsonFlag = true
+				ui.SetSilent(true)
+			}
+
+			// --expect asserts the outcome for negative tests (known-bad fixtures)
+			if expect != "" && expect != "pass" && expect != "fail" {
+				return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --expect %q", expect), "Use --expect pass or --expect fail")
+			}
+
+			// Load config (--config-from-oci: the central config and policy bundle replaces acc.yaml)
+			var cfg *config.Config
+			var err error
+			if configOCI != "" {
+				if configFile != "" || policyPack != "" || policyDir != "" {
+					return ui.NewError(ui.CodeInvalidArgument, "--config-from-oci cannot be used with --config, --policy-pack, or --policy", "The bundle supplies both acc.yaml and the policy pack")
+				}
+				cfg, err = loadConfigBundle(configOCI, configKey, bundleCache, jsonFlag || field != "")
+				if err != nil {
+					return err
+				}
+			} else {
+				if bundleCache {
+					return ui.NewError(ui.CodeInvalidArgument, "--rego-bundle-cache requires --config-from-oci", "Pass the policy bundle with --config-from-oci <ref>")
+				}
+				cfg, err = config.Load(configFile)
+				if err != nil {
+					return configLoadError(err)
+				}
+			}
+
+			// --policy evaluates a policy directory, e.g. a --fixture's policy/ on replay
+			if policyDir != "" {
+				if policyPack != "" {
+					return ui.NewError(ui.CodeInvalidArgument, "--policy cannot be used with --policy-pack", "Use --policy for a directory, or --policy-pack for a named pack")
+				}
+				if !strings.ContainsAny(policyDir, `/\`) {
+					// A pack value without a separator is a pack name
+					policyDir = "./" + policyDir
+				}
+				cfg.Policy.Pack = policyDir
+			}
+
+			if err := applyPolicyPack(cfg); err != nil {
+				return err
+			}
+
+			// --input evaluates a recorded policy input instead of inspecting the image
+			if inputFile != "" {
+				cfg.Policy.Input = inputFile
+			}
+
+			// --capture-inspect saves the raw runtime inspect JSON the policy input is built from
+			if captureInsp != "" {
+				if inputFile != "" {
+					return ui.NewError(ui.CodeInvalidArgument, "--capture-inspect cannot be combined with --input", "--input replays a recorded policy input, so no image is inspected")
+				}
+				cfg.Policy.CaptureInspect = captureInsp
+			}
+
+			// --policy-mode takes precedence over policy.mode in config
+			if policyMode != "" {
+				if err := cfg.OverridePolicyMode(policyMode); err != nil {
+					return err
+				}
+			}
+
+			// --two-phase runs the build-time or deploy-time checks (overrides policy.phase)
+			if twoPhase != "" {
+				if twoPhase != verify.PhaseBuild && twoPhase != verify.PhaseDeploy {
+					return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --two-phase %q", twoPhase), "Use build (SBOM and policy) or deploy (signature, provenance, and freshness after a passing build phase)")
+				}
+				cfg.Policy.Phase = twoPhase
+			}
+
+			// --rego-query evaluates a different decision document than policy.regoQuery
+			if regoQuery != "" {
+				if err := cfg.OverrideRegoQuery(regoQuery); err != nil {
+					return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Use a data reference, e.g. --rego-query data.mycompany.images.decision")
+				}
+			}
+
+			// --remote reads the image config from the registry when the image is not local
+			if remoteCfg {
+				cfg.Registry.RemoteConfig = true
+			}
+
+			// --input-from-manifest uses the image config recorded by acc build (falls back to inspection)
+			if fromManif {
+				cfg.Policy.InputFromManifest = true
+			}
+
+			// --parallel-opa evaluates policy groups (subdirectories) in separate OPA invocations
+			if parallelOPA {
+				cfg.Policy.ParallelOPA = true
+			}
+
+			// --no-waivers evaluates without .acc/waivers.yaml (audit view)
+			if noWaivers {
+				cfg.Policy.NoWaivers = true
+			}
+
+			// --no-state verifies without writing .acc/state (read-only or ephemeral environments)
+			if noState {
+				cfg.Policy.NoState = true
+			}
+
+			// --rego-print shows print() output from policies (debugging)
+			if regoPrint {
+				cfg.Policy.RegoPrint = true
+			}
+
+			// --team overrides project.team for attributing this run
+			if team != "" {
+				cfg.Project.Team = team
+			}
+
+			// --allow-missing-opa-advisory lets reporting-only runs proceed without opa
+			if missingOPA {
+				cfg.Policy.AllowMissingOPA = true
+			}
+
+			// --explain-deny attributes each violation to the policy file that reported it
+			if explainDeny {
+				cfg.Policy.ExplainDeny = true
+			}
+
+			// --data adds external data files (on top of policy.data) under data.acc.external
+			cfg.Policy.Data = append(cfg.Policy.Data, dataFiles...)
+
+			// --require-provenance enables policy.requireProvenance for this run
+			if requireProv {
+				cfg.Policy.RequireProvenance = true
+			}
+
+			// --require-provenance-source requires provenance built from a matching repository
+			if provSource != "" {
+				if err := slsa.ValidateSourcePattern(provSource); err != nil {
+					return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Use a repository URL glob, e.g. --require-provenance-source 'https://github.com/org/*'")
+				}
+				cfg.Policy.RequireProvenance = true
+				cfg.Policy.ProvenanceSource = provSource
+			}
+
+			// --require-digest-pinned enables policy.requireDigestPinned for this run
+			if reqPinned {
+				cfg.Policy.RequireDigestPinned = true
+			}
+
+			// --require-nonroot enables the built-in no-root-user check for this run
+			if reqNonRoot {
+				cfg.Policy.RequireNonRoot = true
+			}
+
+			// --require-healthcheck reports images without a HEALTHCHECK (a warning unless --healthcheck-severity critical)
+			if reqHealth {
+				cfg.Policy.RequireHealthcheck = true
+			}
+			if cmd.Flags().Changed("healthcheck-severity") {
+				if healthSev != "warning" && healthSev != "critical" {
+					return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --healthcheck-severity %q", healthSev), "Use warning or critical")
+				}
+				cfg.Policy.RequireHealthcheck = true
+				cfg.Policy.HealthcheckSeverity = healthSev
+			}
+
+			// --forbid-privileged-ports reports EXPOSE below 1024 (a violation unless --privileged-port-severity warning)
+			if forbidPorts {
+				cfg.Policy.ForbidPrivilegedPorts = true
+			}
+			if cmd.Flags().Changed("privileged-port-severity") {
+				if portSev != "warning" && portSev != "critical" {
+					return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --privileged-port-severity %q", portSev), "Use warning or critical")
+				}
+				cfg.Policy.ForbidPrivilegedPorts = true
+				cfg.Policy.PrivilegedPortSeverity = portSev
+			}
+
+			// --deny-env-secrets reports ENV values that look like credentials; --env-secret-patterns
+			// adds name patterns to policy.envSecretPatterns
+			if denyEnvSecr {
+				cfg.Policy.DenyEnvSecrets = true
+			}
+			for _, p := range envSecrPats {
+				if _, err := regexp.Compile(p); err != nil {
+					return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --env-secret-patterns entry %q: %v", p, err), "Use a Go regular expression, e.g. DSN or ^DB_")
+				}
+			}
+			cfg.Policy.EnvSecretPatterns = append(cfg.Policy.EnvSecretPatterns, envSecrPats...)
+
+			// --sbom-baseline overrides sbom.baseline for this run
+			if sbomBase != "" {
+				cfg.SBOM.Baseline = sbomBase
+			}
+
+			// --deny-new-packages overrides sbom.denyNewPackages for this run
+			if denyNewPkgs != "" {
+				cfg.SBOM.DenyNewPackages = denyNewPkgs
+			}
+
+			// --report-unused-waivers / --fail-on-unused-waivers flag waivers that suppress nothing
+			if reportUnusd {
+				cfg.Policy.ReportUnusedWaivers = true
+			}
+			if failUnused {
+				cfg.Policy.FailOnUnusedWaivers = true
+			}
+
+			// --require-labels adds to policy.requiredLabels
+			cfg.Policy.RequiredLabels = append(cfg.Policy.RequiredLabels, reqLabels...)
+
+			if signBundle && bundleOut == "" {
+				return fmt.Errorf("--sign requires --bundle-output")
+			}
+
+			// --verify-image-signature checks the image digest with cosign verify and
+			// --require-sbom-signed checks the SBOM signature; --cosign-key is the public
+			// key there, so it cannot also be the --sign key
+			if verifySig || sbomSigned {
+				if signBundle && cosignKey != "" {
+					if verifySig {
+						return fmt.Errorf("--cosign-key cannot be used with both --sign and --verify-image-signature")
+					}
+					return fmt.Errorf("--cosign-key cannot be used with both --sign and --require-sbom-signed")
+				}
+				if cosignKey != "" {
+					cfg.Signing.Key = cosignKey
+				}
+			}
+			if verifySig {
+				cfg.Policy.VerifyImageSignature = true
+			}
+			if sbomSigned {
+				cfg.Policy.RequireSBOMSigned = true
+			}
+			if identityRe != "" {
+				cfg.Signing.IdentityRegexp = identityRe
+			}
+			if issuerRe != "" {
+				cfg.Signing.IssuerRegexp = issuerRe
+			}
+
+			// --max-violations limits human output only
+			if cmd.Flags().Changed("max-violations") {
+				if maxViol < 0 {
+					return fmt.Errorf("--max-violations must be >= 0")
+				}
+				cfg.Policy.MaxViolations = maxViol
+			}
+
+			// --rego-timeout bounds each opa eval for this run
+			if cmd.Flags().Changed("rego-timeout") {
+				if regoTimeout <= 0 {
+					return fmt.Errorf("--rego-timeout must be positive")
+				}
+				cfg.Policy.RegoTimeout = regoTimeout
+			}
+
+			// --min-sbom-components overrides sbom.minComponents for this run
+			if cmd.Flags().Changed("min-sbom-components") {
+				if minSBOMComp < 0 {
+					return ui.NewError(ui.CodeInvalidArgument, "--min-sbom-components must be >= 0", "")
+				}
+				cfg.SBOM.MinComponents = minSBOMComp
+			}
+
+			// --require-sbom-format overrides sbom.requireFormat for this run
+			if cmd.Flags().Changed("require-sbom-format") {
+				if reqSBOMFmt != "spdx" && reqSBOMFmt != "cyclonedx" {
+					return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --require-sbom-format %q", reqSBOMFmt), "Use spdx or cyclonedx")
+				}
+				cfg.SBOM.RequireFormat = reqSBOMFmt
+			}
+
+			// --since-build fails verification for an SBOM older than the image
+			if sinceBuild {
+				cfg.SBOM.SinceBuild = true
+			}
+
+			// --sbom-from-image verifies the SBOM attached to the image in its registry
+			if sbomFromImg {
+				cfg.SBOM.FromImage = true
+			}
+
+			// --fail-on-warning-count sets a budget for warnings (suppressed violations)
+			if cmd.Flags().Changed("fail-on-warning-count") {
+				if warnBudget < 0 {
+					return fmt.Errorf("--fail-on-warning-count must be >= 0")
+				}
+				cfg.Policy.FailOnWarningCount = &warnBudget
 			}
 
 			ref := imageRef
@@ -185,22 +766,62 @@ func NewVerifyCmd() *cobra.Command {
 				ref = args[0]
 			}
 
-			// v0.2.0: Load profile if specified
-			var prof *profile.Profile
-			if profilePath != "" {
-				prof, err = profile.Load(profilePath)
+			// "-" reads the image reference from stdin (e.g. acc build ... | acc verify -)
+			ref, err = readImageRef(cmd.InOrStdin(), ref)
+			if err != nil {
+				return err
+			}
+
+			// --digest pins the image to a known digest (no container runtime needed);
+			// --digest-algorithm names the algorithm of a bare hex --digest
+			if cmd.Flags().Changed("digest-algorithm") {
+				if digestAlg != oci.DigestSHA256 && digestAlg != oci.DigestSHA512 {
+					return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --digest-algorithm %q", digestAlg), "Use sha256 or sha512")
+				}
+				if digest == "" {
+					return ui.NewError(ui.CodeInvalidArgument, "--digest-algorithm requires --digest", "Usage: acc verify <image> --digest <hex> --digest-algorithm sha512")
+				}
+			}
+			ref, err = applyDigest(ref, digest, digestAlg)
+			if err != nil {
+				return err
+			}
+
+			// --print-input shows what the policy would see, without evaluating it
+			if printInput {
+				input, err := verify.BuildInput(cfg, ref)
 				if err != nil {
-					return fmt.Errorf("failed to load profile: %w", err)
+					return fmt.Errorf("failed to build policy input: %w", err)
+				}
+				data, err := json.MarshalIndent(input, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal policy input: %w", err)
 				}
+				fmt.Println(string(data))
+				return nil
+			}
+
+			// v0.2.0: Load profile if specified (--profile, else the profiles.byEnv mapping for --env)
+			prof, err := loadProfileForEnv(cfg, profilePath, envName, jsonFlag || field != "")
+			if err != nil {
+				return err
 			}
 
-			// Verify
-			result, err := verify.Verify(cfg, ref, false, jsonFlag, prof)
+			// .accignore acts as an inline profile; an explicit --profile takes precedence
+			prof, err = applyIgnoreFile(prof, ignoreFile, cmd.Flags().Changed("ignore-file"))
+			if err != nil {
+				return err
+			}
+
+			// Verify (--field suppresses human output like --json)
+			quiet := jsonFlag || field != ""
+			cfg.Policy.Env = envName
+			result, err := verify.Verify(cfg, ref, false, quiet, prof)
 
 			// v0.1.4: Defensive nil check (should never happen after v0.1.4 fixes)
 			if result == nil {
 				if jsonFlag {
-					fmt.Println(`{"status":"fail","error":"internal error: nil result"}`)
+					fmt.Println(ui.FormatErrorJSON(ui.NewError(ui.CodeInternal, "internal error: nil result", "")))
 				} else {
 					fmt.Fprintln(os.Stderr, "Error: verification failed with internal error")
 					if err != nil {
@@ -210,28 +831,200 @@ func NewVerifyCmd() *cobra.Command {
 				os.Exit(2)
 			}
 
+			// The summary line is written for every outcome so gates can branch on it
+			if summaryFile != "" {
+				if summaryErr := verify.WriteSummaryFile(summaryFile, ref, result); summaryErr != nil {
+					return summaryErr
+				}
+			}
+
+			// The PR comment body is written for every outcome; posting it is left to CI
+			if commentOut != "" {
+				if commentErr := verify.WriteComment(commentOut, ref, result); commentErr != nil {
+					return commentErr
+				}
+			}
+
+			// The fixture is written for every outcome; capturing a failure is its main use
+			if fixtureDir != "" {
+				fixture, fixtureErr := verify.WriteFixture(fixtureDir, cfg, ref, result, prof)
+				if fixtureErr != nil {
+					return fmt.Errorf("failed to write fixture: %w", fixtureErr)
+				}
+				if !quiet {
+					ui.PrintSuccess(fmt.Sprintf("Fixture written: %s", fixtureDir))
+					fmt.Printf("  Replay: cd %s && %s\n", fixtureDir, fixture.Replay)
+				}
+			}
+
 			if err != nil {
-				if jsonFlag {
-					fmt.Println(result.FormatJSON())
+				if expectErr := checkExpect(result, expect); expectErr != nil {
+					return expectErr
 				}
-				os.Exit(result.ExitCode())
+				if field != "" {
+					if fieldErr := printField(result, field); fieldErr != nil {
+						return fieldErr
+					}
+				} else if jsonFlag {
+					fmt.Println(verifyResultJSON(result, jsonCompact))
+				}
+				os.Exit(verifyExitCode(result, expect, quiet))
 			}
 
-			if jsonFlag {
-				fmt.Println(result.FormatJSON())
+			// Evidence bundle is only written for a successful verification
+			if bundleOut != "" {
+				bundle, err := verify.WriteBundle(bundleOut, cfg, ref, result, prof, verify.BundleOptions{
+					Sign:      signBundle,
+					CosignKey: cosignKey,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to write evidence bundle: %w", err)
+				}
+				if !quiet {
+					ui.PrintSuccess(fmt.Sprintf("Evidence bundle written: %s", bundle.Path))
+					if bundle.Signature != "" {
+						fmt.Printf("  Signature: %s\n", bundle.Signature)
+					}
+				}
 			}
 
-			os.Exit(result.ExitCode())
+			// The approved package set is only snapshotted from a successful verification
+			if writeBase != "" {
+				count, err := verify.WriteSBOMBaseline(writeBase, cfg)
+				if err != nil {
+					return err
+				}
+				if !quiet {
+					ui.PrintSuccess(fmt.Sprintf("SBOM baseline written: %s (%d packages)", writeBase, count))
+				}
+			}
+
+			if err := checkExpect(result, expect); err != nil {
+				return err
+			}
+			if field != "" {
+				if err := printField(result, field); err != nil {
+					return err
+				}
+			} else if jsonFlag {
+				fmt.Println(verifyResultJSON(result, jsonCompact))
+			}
+
+			os.Exit(verifyExitCode(result, expect, quiet))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to verify")
+	cmd.Flags().StringVar(&field, "field", "", fieldFlagUsage)
+	cmd.Flags().BoolVar(&jsonCompact, "json-compact", false, "output the JSON result on a single line (implies --json)")
+	cmd.Flags().BoolVar(&jsonErrOnly, "json-errors-only", false, "suppress all human output: stdout is only the JSON result or error envelope, and nothing is written to stderr (implies --json)")
+	cmd.Flags().StringVar(&digest, "digest", "", digestFlagUsage)
+	cmd.Flags().StringVar(&digestAlg, "digest-algorithm", "", "algorithm of a bare hex --digest: sha256 (default) or sha512")
+	cmd.Flags().BoolVar(&remoteCfg, "remote", false, "read image config from the registry when the image is not available locally")
+	cmd.Flags().BoolVar(&printInput, "print-input", false, "print the JSON input policy rules receive for the image and exit without evaluating")
 	cmd.Flags().StringVar(&profilePath, "profile", "", "policy profile name or path (.acc/profiles/<name>.yaml or explicit path)")
+	cmd.Flags().StringVar(&envName, "env", "", "environment whose profile (profiles.byEnv in acc.yaml) to apply; --profile takes precedence")
+	cmd.Flags().StringVar(&team, "team", "", "owning team recorded as team in the result, state, and --summary-file line (default: project.team)")
+	cmd.Flags().StringVar(&policyMode, "policy-mode", "", "override policy.mode for this run (enforce|warn)")
+	cmd.Flags().StringVar(&twoPhase, "two-phase", "", "run one pipeline phase: build (skips signature, provenance, and freshness checks) or deploy (requires them and a passing build phase for the digest)")
+	cmd.Flags().BoolVar(&fromManif, "input-from-manifest", false, "build policy input from the acc build manifest (.acc/state/build/<digest>.json), falling back to image inspection")
+	cmd.Flags().BoolVar(&parallelOPA, "parallel-opa", false, "evaluate each .acc/policy subdirectory in its own OPA invocation, in parallel")
+	cmd.Flags().StringSliceVar(&reqLabels, "require-labels", nil, "comma-separated image labels that must be present, added to policy.requiredLabels (e.g. org.opencontainers.image.source)")
+	cmd.Flags().BoolVar(&reqNonRoot, "require-nonroot", false, "fail with no-root-user when the image runs as root (USER empty, 0, or root); built in, no OPA or Rego needed")
+	cmd.Flags().BoolVar(&reqHealth, "require-healthcheck", false, "report no-healthcheck when the image defines no HEALTHCHECK (a warning unless --healthcheck-severity critical)")
+	cmd.Flags().StringVar(&healthSev, "healthcheck-severity", "", "severity of no-healthcheck: warning or critical (fails verification); implies --require-healthcheck")
+	cmd.Flags().BoolVar(&forbidPorts, "forbid-privileged-ports", false, "report privileged-port for each exposed port below 1024 (a violation unless --privileged-port-severity warning)")
+	cmd.Flags().BoolVar(&denyEnvSecr, "deny-env-secrets", false, "report env-secret-detected for image environment variables whose name or value looks like a secret")
+	cmd.Flags().StringSliceVar(&envSecrPats, "env-secret-patterns", nil, "comma-separated env var name regexps treated as secrets, added to policy.envSecretPatterns")
+	cmd.Flags().StringVar(&portSev, "privileged-port-severity", "", "severity of privileged-port: critical (fails verification) or warning; implies --forbid-privileged-ports")
+	cmd.Flags().BoolVar(&reqPinned, "require-digest-pinned", false, "fail with tag-not-digest-pinned unless the image is referenced by digest (name@sha256:...)")
+	cmd.Flags().IntVar(&minSBOMComp, "min-sbom-components", 0, "fail with sbom-too-sparse when the SBOM lists fewer components (overrides sbom.minComponents)")
+	cmd.Flags().StringVar(&reqSBOMFmt, "require-sbom-format", "", "fail with sbom-format-mismatch unless the SBOM is in this format, spdx or cyclonedx (overrides sbom.requireFormat, independent of sbom.format)")
+	cmd.Flags().BoolVar(&sinceBuild, "since-build", false, "fail with sbom-stale when the SBOM file is older than the image build time (from the build manifest, else the image's Created time)")
+	cmd.Flags().BoolVar(&sbomFromImg, "sbom-from-image", false, "verify the SBOM attached to the image (OCI referrer or cosign .sbom tag) instead of .acc/sbom; pulled once per digest and cached under .acc/sbom/image/")
+	cmd.Flags().StringVar(&sbomBase, "sbom-baseline", "", "previous build's SBOM; policies receive the package diff as input.sbom.changed (overrides sbom.baseline)")
+	cmd.Flags().StringVar(&denyNewPkgs, "deny-new-packages", "", "approved baseline SBOM; fail with unexpected-package for each package not in it (overrides sbom.denyNewPackages)")
+	cmd.Flags().StringVar(&writeBase, "write-sbom-baseline", "", "after a successful verify, copy the SBOM to this path as the approved set for --deny-new-packages")
+	cmd.Flags().BoolVar(&explainSch, "explain-json-schema", false, "print the JSON Schema of the verify --json result and exit (same as acc schema verify)")
+	cmd.Flags().StringArrayVar(&dataFiles, "data", nil, "JSON or YAML file loaded into policy evaluation under data.acc.external (repeatable)")
+	cmd.Flags().DurationVar(&regoTimeout, "rego-timeout", 0, "stop policy evaluation after this long with a policy-evaluation-timeout violation (default: policy.regoTimeout or 30s)")
+	cmd.Flags().StringVar(&regoQuery, "rego-query", "", "decision document to evaluate (default: policy.regoQuery or data.acc.policy.result)")
+	cmd.Flags().BoolVar(&explainDeny, "explain-deny", false, "record the policy file:line that reported each violation as its source (shown by verify, --json, and policy explain)")
+	cmd.Flags().BoolVar(&missingOPA, "allow-missing-opa-advisory", false, "advisory/reporting runs: if opa is not installed, skip policy evaluation with a policy-unevaluated warning (policyUnevaluated=true) instead of failing with opa-required")
+	cmd.Flags().BoolVar(&regoPrint, "rego-print", false, "show print() output from policies on stderr (policyResult.print with --json); bypasses the policy evaluation cache")
+	cmd.Flags().BoolVar(&noWaivers, "no-waivers", false, "ignore .acc/waivers.yaml: report waived violations and skip expired-waiver failures (waiversApplied=false)")
+	cmd.Flags().BoolVar(&noState, "no-state", false, "do not write verification state (.acc/state); the result and exit code are unchanged, but attest, push, and policy explain cannot use this run")
+	cmd.Flags().BoolVar(&reportUnusd, "report-unused-waivers", false, "report waivers whose rule did not fire in this run (unusedWaivers; shown by acc waiver list)")
+	cmd.Flags().BoolVar(&failUnused, "fail-on-unused-waivers", false, "fail with unused-waiver for each waiver whose rule did not fire (implies --report-unused-waivers)")
+	cmd.Flags().BoolVar(&requireProv, "require-provenance", false, "require SLSA build provenance for the image digest")
+	cmd.Flags().StringVar(&provSource, "require-provenance-source", "", "require the provenance source repository to match this URL glob, e.g. 'https://github.com/org/*' (implies --require-provenance)")
+	cmd.Flags().StringVar(&fixtureDir, "fixture", "", "save the policy input, policy files, profile, and config to this directory as a replayable test case (for any outcome)")
+	cmd.Flags().StringVar(&inputFile, "input", "", "evaluate this policy input (JSON from --print-input or a fixture's input.json) instead of inspecting the image")
+	cmd.Flags().StringVar(&captureInsp, "capture-inspect", "", "write the raw docker/podman/nerdctl inspect JSON the policy input is built from to this path, for debugging unexpected input fields")
+	cmd.Flags().StringVar(&policyDir, "policy", "", "evaluate the .rego files in this directory instead of .acc/policy (e.g. a fixture's policy/)")
+	cmd.Flags().StringVar(&configOCI, "config-from-oci", "", "pull acc.yaml and the policy pack from this OCI config bundle (registry/repo:tag or @sha256:<digest>) instead of using local config; cached by digest")
+	cmd.Flags().BoolVar(&bundleCache, "rego-bundle-cache", false, "pull the --config-from-oci bundle once per process, and use a digest-pinned bundle from the cache without contacting the registry")
+	cmd.Flags().StringVar(&configKey, "config-oci-key", "", "cosign public key that must have signed the --config-from-oci bundle")
+	cmd.Flags().StringVar(&expect, "expect", "", "expected status (pass|fail): exit 0 when the result matches and 1 otherwise, for negative tests of known-bad images")
+	cmd.Flags().StringVar(&summaryFile, "summary-file", "", "write a one-line summary (status=<status> violations=<n> image=<ref>, plus team=<name> when set) to this path")
+	cmd.Flags().StringVar(&commentOut, "comment-out", "", "write a Markdown summary (status, checks, violations, warnings, profile) to this path, for CI to post as a PR/MR comment")
+	cmd.Flags().StringVar(&bundleOut, "bundle-output", "", "after a successful verify, write a tar evidence bundle (result, SBOM, policy hash, profile) to this path")
+	cmd.Flags().BoolVar(&signBundle, "sign", false, "sign the evidence bundle with cosign sign-blob (requires --bundle-output)")
+	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "cosign private key for --sign, or public key for --verify-image-signature/--require-sbom-signed (keyless if empty)")
+	cmd.Flags().BoolVar(&verifySig, "verify-image-signature", false, "verify the image's cosign signature (image-unsigned / image-signature-invalid violations)")
+	cmd.Flags().BoolVar(&sbomSigned, "require-sbom-signed", false, "require a cosign signature over the SBOM: <sbom>.sig or a registry SBOM attestation (sbom-unsigned / sbom-signature-invalid violations)")
+	cmd.Flags().StringVar(&identityRe, "certificate-identity-regexp", "", "keyless signature verification: accepted certificate identity (default \".*\")")
+	cmd.Flags().StringVar(&issuerRe, "certificate-oidc-issuer-regexp", "", "keyless signature verification: accepted OIDC issuer (default \".*\")")
+	cmd.Flags().IntVar(&warnBudget, "fail-on-warning-count", 0, "fail when more than N warnings (violations downgraded by the profile or waivers) remain; reported as warningBudget")
+	cmd.Flags().IntVar(&maxViol, "max-violations", 0, "show at most N violations (sorted by severity) in human output; --json always includes all")
+	cmd.Flags().StringVar(&ignoreFile, "ignore-file", profile.DefaultIgnoreFile, "file listing rule IDs or severities to downgrade to warnings (ignored when --profile is set)")
 
 	return cmd
 }
 
+// verifyResultJSON formats a verify result for --json, on one line for --json-compact
+func verifyResultJSON(result *verify.VerifyResult, compact bool) string {
+	if compact {
+		return result.FormatJSONCompact()
+	}
+	return result.FormatJSON()
+}
+
+// loadProfileForEnv loads the profile selected by an explicit --profile or, failing
+// that, the profiles.byEnv mapping for env. Returns nil when neither selects one.
+func loadProfileForEnv(cfg *config.Config, explicit, env string, quiet bool) (*profile.Profile, error) {
+	selected := cfg.SelectProfile(explicit, env)
+	if selected == "" {
+		if env != "" {
+			ui.PrintDebug(fmt.Sprintf("no profile mapped for environment %q in profiles.byEnv", env))
+		}
+		return nil, nil
+	}
+
+	prof, err := profile.Load(selected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+	if explicit == "" && !quiet {
+		ui.PrintInfo(fmt.Sprintf("Using profile '%s' for environment '%s'", selected, env))
+	}
+	return prof, nil
+}
+
+// applyIgnoreFile selects between prof and the ignore file at path (see profile.Select).
+// A missing ignore file is skipped unless it was named explicitly (required).
+func applyIgnoreFile(prof *profile.Profile, path string, required bool) (*profile.Profile, error) {
+	if _, err := os.Stat(path); err != nil && !required {
+		return prof, nil
+	}
+	ignoreProf, err := profile.LoadIgnoreFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return profile.Select(prof, ignoreProf), nil
+}
+
 func NewRunCmd() *cobra.Command {
 	var (
 		imageRef    string
@@ -239,6 +1032,10 @@ func NewRunCmd() *cobra.Command {
 		networkMode string
 		readOnly    bool
 		caps        []string
+		profilePath string
+		healthCmd   string
+		healthURL   string
+		healthWait  time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -249,7 +1046,11 @@ func NewRunCmd() *cobra.Command {
 			// Load config
 			cfg, err := config.Load(configFile)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+				return configLoadError(err)
+			}
+
+			if err := applyPolicyPack(cfg); err != nil {
+				return err
 			}
 
 			// Parse image ref and command args
@@ -264,7 +1065,7 @@ func NewRunCmd() *cobra.Command {
 			}
 
 			if ref == "" {
-				return fmt.Errorf("image reference required")
+				return imageRefRequired("acc run <image> [-- command args...]")
 			}
 
 			opts := &runtime.RunOptions{
@@ -276,6 +1077,27 @@ func NewRunCmd() *cobra.Command {
 				Capabilities: caps,
 			}
 
+			// --health-check / --health-url start the workload detached and wait for readiness
+			if healthCmd != "" && healthURL != "" {
+				return ui.NewError(ui.CodeInvalidArgument, "--health-check and --health-url are mutually exclusive", "")
+			}
+			if healthCmd != "" || healthURL != "" {
+				if healthWait <= 0 {
+					return ui.NewError(ui.CodeInvalidArgument, "--health-timeout must be positive", "")
+				}
+				opts.Health = &runtime.HealthCheck{Command: healthCmd, URL: healthURL, Timeout: healthWait}
+			} else if cmd.Flags().Changed("health-timeout") {
+				return ui.NewError(ui.CodeInvalidArgument, "--health-timeout requires --health-check or --health-url", "")
+			}
+
+			// Gate verification on a specific profile if requested
+			if profilePath != "" {
+				opts.Profile, err = profile.Load(profilePath)
+				if err != nil {
+					return fmt.Errorf("failed to load profile: %w", err)
+				}
+			}
+
 			return runtime.Run(cfg, opts, jsonFlag)
 		},
 	}
@@ -285,12 +1107,20 @@ func NewRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&networkMode, "network", "none", "network mode (none|bridge|host)")
 	cmd.Flags().BoolVar(&readOnly, "read-only", false, "mount root filesystem as read-only")
 	cmd.Flags().StringSliceVar(&caps, "cap-add", []string{}, "add Linux capabilities")
+	cmd.Flags().StringVar(&profilePath, "verify-profile", "", "policy profile used by the verification gate (.acc/profiles/<name>.yaml or explicit path)")
+	cmd.Flags().StringVar(&healthCmd, "health-check", "", "run detached and poll this command inside the container (sh -c) until it succeeds, then stop the container")
+	cmd.Flags().StringVar(&healthURL, "health-url", "", "run detached and poll this URL from the host until it returns 2xx, then stop the container (needs --network bridge or host)")
+	cmd.Flags().DurationVar(&healthWait, "health-timeout", runtime.DefaultHealthTimeout, "fail (and stop the container) if the workload is not healthy within this long")
 
 	return cmd
 }
 
 func NewPushCmd() *cobra.Command {
-	var imageRef string
+	var (
+		imageRef    string
+		profilePath string
+		envName     string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "push [image]",
@@ -301,7 +1131,7 @@ func NewPushCmd() *cobra.Command {
 			// Load config
 			cfg, err := config.Load(configFile)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+				return configLoadError(err)
 			}
 
 			ref := imageRef
@@ -309,15 +1139,32 @@ func NewPushCmd() *cobra.Command {
 				ref = args[0]
 			}
 
+			// "-" reads the image reference from stdin (e.g. acc build ... | acc verify -)
+			ref, err = readImageRef(cmd.InOrStdin(), ref)
+			if err != nil {
+				return err
+			}
+
 			if ref == "" {
-				return fmt.Errorf("image reference required\n\nUsage: acc push <image>")
+				return imageRefRequired("acc push <image>")
+			}
+
+			// --profile / --env: the last verification must have used this profile
+			prof, err := loadProfileForEnv(cfg, profilePath, envName, jsonFlag)
+			if err != nil {
+				return err
+			}
+			requiredProfile := ""
+			if prof != nil {
+				requiredProfile = prof.Name
 			}
 
 			// Push (with verification gate)
-			result, err := push.Push(cfg, ref, jsonFlag)
+			result, err := push.Push(cfg, ref, requiredProfile, jsonFlag)
 			if err != nil {
 				return err
 			}
+			result.Env = envName
 
 			if jsonFlag {
 				fmt.Println(result.FormatJSON())
@@ -328,14 +1175,19 @@ func NewPushCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to push")
+	cmd.Flags().StringVar(&profilePath, "profile", "", "require that the last verification used this profile (name or path)")
+	cmd.Flags().StringVar(&envName, "env", "", "environment whose profile (profiles.byEnv in acc.yaml) the last verification must have used; --profile takes precedence")
 
 	return cmd
 }
 
 func NewPromoteCmd() *cobra.Command {
 	var (
-		imageRef  string
-		targetEnv string
+		imageRef           string
+		targetEnv          string
+		requireAttestation bool
+		profilePath        string
+		toRegistry         string
 	)
 
 	cmd := &cobra.Command{
@@ -347,7 +1199,11 @@ func NewPromoteCmd() *cobra.Command {
 			// Load config
 			cfg, err := config.Load(configFile)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+				return configLoadError(err)
+			}
+
+			if err := applyPolicyPack(cfg); err != nil {
+				return err
 			}
 
 			ref := imageRef
@@ -355,12 +1211,29 @@ func NewPromoteCmd() *cobra.Command {
 				ref = args[0]
 			}
 
+			// "-" reads the image reference from stdin (e.g. acc build ... | acc verify -)
+			ref, err = readImageRef(cmd.InOrStdin(), ref)
+			if err != nil {
+				return err
+			}
+
 			if ref == "" {
-				return fmt.Errorf("image reference required\n\nUsage: acc promote <image> --to <env>")
+				return imageRefRequired("acc promote <image> --to <env>")
+			}
+
+			// --require-attestation enables policy.requireAttestation for this promotion
+			if requireAttestation {
+				cfg.Policy.RequireAttestation = true
+			}
+
+			// The target environment selects its profiles.byEnv profile unless --profile is set
+			prof, err := loadProfileForEnv(cfg, profilePath, targetEnv, jsonFlag)
+			if err != nil {
+				return err
 			}
 
 			// Promote
-			result, err := promote.Promote(cfg, ref, targetEnv, jsonFlag)
+			result, err := promote.Promote(cfg, ref, targetEnv, toRegistry, prof, jsonFlag)
 			if err != nil {
 				return err
 			}
@@ -376,6 +1249,9 @@ func NewPromoteCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to promote")
 	cmd.Flags().StringVar(&targetEnv, "to", "", "target environment (required)")
+	cmd.Flags().StringVar(&profilePath, "profile", "", "policy profile for the verification gate (default: profiles.byEnv mapping for --to)")
+	cmd.Flags().BoolVar(&requireAttestation, "require-attestation", false, "block promotion unless a valid attestation exists for the image")
+	cmd.Flags().StringVar(&toRegistry, "to-registry", "", "copy the verified digest to another registry (<host> or <host>/<repository>), tagged as the environment")
 	cmd.MarkFlagRequired("to")
 
 	return cmd
@@ -401,13 +1277,103 @@ func NewPolicyCmd() *cobra.Command {
 		},
 	}
 
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the rules a policy pack defines",
+		Long: `List rule IDs, severities, and descriptions declared in .acc/policy (or the pack selected with --policy-pack).
+OPA metadata annotations (# METADATA with title, description, custom.rule, and custom.severity) are used where present;
+otherwise the rule, severity, and message literals of each rule's violation object.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return configLoadError(err)
+			}
+			if policyPack != "" {
+				cfg.Policy.Pack = policyPack
+			}
+
+			result, err := policy.ListPack(cfg.Policy.Pack)
+			if err != nil {
+				return ui.WrapError(ui.CodeInvalidArgument, err, "")
+			}
+			if jsonFlag {
+				data, _ := json.MarshalIndent(result, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			result.Print()
+			return nil
+		},
+	}
+
+	var benchInput string
+	var benchQueries []string
+	var benchCount int
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure policy evaluation time",
+		Long: `Evaluate .acc/policy (or the pack selected with --policy-pack) with OPA's metrics and profiler,
+reporting per-query and total evaluation time and the slowest expressions. The input is a
+policy input file (from 'acc verify --print-input' or a fixture's input.json), or a sample input.`,
+		Example: `  acc policy bench
+  acc verify myapp:latest --print-input > input.json && acc policy bench --input input.json --count 50`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return configLoadError(err)
+			}
+			if policyPack != "" {
+				cfg.Policy.Pack = policyPack
+			}
+			if benchCount < 1 {
+				return ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("invalid --count %d", benchCount), "Use a count of at least 1")
+			}
+
+			result, err := verify.Bench(cfg, benchInput, benchQueries, benchCount)
+			if err != nil {
+				return ui.WrapError(ui.CodeInvalidArgument, err, "")
+			}
+			if jsonFlag {
+				data, _ := json.MarshalIndent(result, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			result.Print()
+			return nil
+		},
+	}
+	benchCmd.Flags().StringVar(&benchInput, "input", "", "policy input to evaluate (JSON from acc verify --print-input); default: a sample input")
+	benchCmd.Flags().StringArrayVar(&benchQueries, "query", nil, "query to time, repeatable (default: policy.regoQuery or data.acc.policy.result)")
+	benchCmd.Flags().IntVar(&benchCount, "count", 10, "evaluations per query; times are averaged")
+
 	cmd.AddCommand(explainCmd)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(benchCmd)
 	return cmd
 }
 
 func NewAttestCmd() *cobra.Command {
 	var imageRef string
 	var remote bool
+	var dryRun bool
+	var overwrite bool
+	var digest string
+	var sign bool
+	var cosignKey string
+	var keyEnv string
+	var keyFile string
+	var tlogUpload bool
+	var noTlogUpload bool
+	var timestamp string
+	var subjectName string
+	var annotationFlags []string
+	var verifyFirst bool
+	var profilePath string
+	var envName string
+	var reproduce string
+	var list bool
 
 	cmd := &cobra.Command{
 		Use:   "attest [image]",
@@ -415,10 +1381,22 @@ func NewAttestCmd() *cobra.Command {
 		Long:  "Create minimal attestation with build metadata and policy hash",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Load config
-			cfg, err := config.Load(configFile)
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+			// --reproduce audits an existing attestation against the current verification state
+			if reproduce != "" {
+				if len(args) > 0 || imageRef != "" || verifyFirst || remote || dryRun || overwrite || sign || list {
+					return ui.NewError(ui.CodeInvalidArgument, "--reproduce cannot be combined with an image or attestation-creating flags", "Usage: acc attest --reproduce <attestation.json>")
+				}
+				result, err := attest.Reproduce(reproduce, jsonFlag)
+				if err != nil {
+					return err
+				}
+				if jsonFlag {
+					fmt.Println(result.FormatJSON())
+				}
+				if !result.Match {
+					os.Exit(1)
+				}
+				return nil
 			}
 
 			ref := imageRef
@@ -426,12 +1404,98 @@ func NewAttestCmd() *cobra.Command {
 				ref = args[0]
 			}
 
+			// "-" reads the image reference from stdin (e.g. acc build ... | acc verify -)
+			ref, err := readImageRef(cmd.InOrStdin(), ref)
+			if err != nil {
+				return err
+			}
+
+			// --digest pins the image to a known digest (no container runtime needed)
+			ref, err = applyDigest(ref, digest, "")
+			if err != nil {
+				return err
+			}
+
 			if ref == "" {
-				return fmt.Errorf("image reference required\n\nUsage: acc attest <image>")
+				return imageRefRequired("acc attest <image>")
+			}
+
+			// --list shows the image's existing attestations (with --remote, also the registry's)
+			if list {
+				if verifyFirst || dryRun || overwrite || sign || tlogUpload || len(annotationFlags) > 0 {
+					return ui.NewError(ui.CodeInvalidArgument, "--list cannot be combined with attestation-creating flags", "Usage: acc attest --list <image> [--remote]")
+				}
+				listings, err := trust.ListAttestations(ref, remote, jsonFlag)
+				if err != nil {
+					return ui.WrapError(ui.CodeError, err, "Pull the image, or pass --digest sha256:<digest>")
+				}
+				if jsonFlag {
+					data, _ := json.MarshalIndent(listings, "", "  ")
+					fmt.Println(string(data))
+					return nil
+				}
+				return trust.WriteAttestationList(os.Stdout, listings)
+			}
+
+			// Load config
+			cfg, err := config.Load(configFile)
+			if err != nil {
+				return configLoadError(err)
+			}
+
+			// --key-env / --key-file take the cosign key from a CI secret instead of a fixed path
+			if keyEnv != "" || keyFile != "" {
+				if cosignKey != "" {
+					return ui.NewError(ui.CodeInvalidArgument, "--cosign-key cannot be combined with --key-env or --key-file", "Pass the key one way")
+				}
+				if !sign && !tlogUpload {
+					return ui.NewError(ui.CodeInvalidArgument, "--key-env and --key-file require --sign", "Usage: acc attest <image> --sign --key-env COSIGN_KEY")
+				}
+				cosignKey, err = crypto.CosignKeyRef(keyEnv, keyFile, true)
+				if err != nil {
+					return ui.WrapError(ui.CodeInvalidArgument, err, "Provide a cosign private key (cosign generate-key-pair); set COSIGN_PASSWORD for an encrypted key")
+				}
+			}
+
+			signOpts, err := attestSignOptions(sign, cosignKey, tlogUpload, noTlogUpload)
+			if err != nil {
+				return err
+			}
+
+			// --annotation only applies to the registry descriptor
+			annotations, err := attest.ParseAnnotations(annotationFlags)
+			if err != nil {
+				return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Usage: acc attest --remote --annotation key=value [--annotation key=value ...]")
+			}
+			if len(annotations) > 0 && !remote {
+				return ui.NewError(ui.CodeInvalidArgument, "--annotation requires --remote", "Annotations are set on the attestation published to the registry")
+			}
+
+			if !verifyFirst && (profilePath != "" || envName != "") {
+				return ui.NewError(ui.CodeInvalidArgument, "--profile and --env require --verify-first", "They select the profile of the verification run by --verify-first")
+			}
+
+			// --verify-first records a fresh verification of this image, so the attestation
+			// below matches it and carries its current status
+			if verifyFirst {
+				if err := verifyBeforeAttest(cfg, ref, profilePath, envName); err != nil {
+					return err
+				}
 			}
 
 			// Create attestation (v0.3.2: optionally publish to remote registry)
-			result, err := attest.Attest(cfg, ref, version, commit, remote, jsonFlag)
+			result, err := attest.Attest(cfg, ref, attest.AttestOptions{
+				Version:     version,
+				Commit:      commit,
+				Timestamp:   timestamp,
+				SubjectName: subjectName,
+				Annotations: annotations,
+				Remote:      remote,
+				DryRun:      dryRun,
+				Overwrite:   overwrite,
+				OutputJSON:  jsonFlag,
+				Sign:        signOpts,
+			})
 			if err != nil {
 				return err
 			}
@@ -445,13 +1509,97 @@ func NewAttestCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to attest")
-	cmd.Flags().BoolVar(&remote, "remote", false, "publish attestation to remote registry (v0.3.2)")
+	cmd.Flags().BoolVar(&remote, "remote", false, "publish attestation to remote registry (v0.3.2); with --list, fetch the registry's attestations first")
+	cmd.Flags().StringArrayVar(&annotationFlags, "annotation", nil, "annotation key=value on the published attestation, e.g. team or environment (repeatable; requires --remote; acc.* and org.opencontainers.* are reserved)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the attestation without writing or publishing it")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "replace the image's latest local attestation instead of adding another one (registry attestations are not deleted)")
+	cmd.Flags().BoolVar(&verifyFirst, "verify-first", false, "run acc verify on the image first and attest its result; a failed verification stops the attestation in enforce mode")
+	cmd.Flags().BoolVar(&list, "list", false, "list the image's attestations (path or tag, timestamp, status, signature, digest match) instead of creating one; with --remote, fetch the registry's first")
+	cmd.Flags().StringVar(&reproduce, "reproduce", "", "recompute the verification results hash from the current state and compare it with this attestation's (exit 1 on mismatch)")
+	cmd.Flags().StringVar(&profilePath, "profile", "", "policy profile for the --verify-first verification (name or path)")
+	cmd.Flags().StringVar(&envName, "env", "", "environment whose profile (profiles.byEnv in acc.yaml) the --verify-first verification applies; --profile takes precedence")
+	cmd.Flags().StringVar(&digest, "digest", "", digestFlagUsage)
+	cmd.Flags().BoolVar(&sign, "sign", false, "sign the attestation with cosign sign-blob (requires cosign in PATH)")
+	cmd.Flags().StringVar(&subjectName, "subject-name", "", "subject name recorded in the attestation (default: the image reference); the digest is always the attested image's")
+	cmd.Flags().StringVar(&timestamp, "timestamp", "", "fixed attestation timestamp, RFC3339 or Unix seconds (default $SOURCE_DATE_EPOCH, else now)")
+	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "cosign private key for --sign (keyless if empty)")
+	cmd.Flags().StringVar(&keyEnv, "key-env", "", "environment variable holding the cosign private key (PEM) for --sign; the password is read from COSIGN_PASSWORD")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "file holding the cosign private key for --sign, e.g. a mounted secret; checked before signing")
+	cmd.Flags().BoolVar(&tlogUpload, "tlog-upload", false, "record the cosign signature in the Rekor transparency log (implies --sign; default for keyless signing)")
+	cmd.Flags().BoolVar(&noTlogUpload, "no-tlog-upload", false, "do not upload the cosign signature to Rekor")
 
 	return cmd
 }
 
+// verifyBeforeAttest runs the verification for attest --verify-first with the profile verify
+// would apply (--profile, profiles.byEnv for --env, or .accignore). A failed verification
+// blocks the attestation in enforce mode; in warn mode the failed status is attested.
+func verifyBeforeAttest(cfg *config.Config, ref, profilePath, envName string) error {
+	if err := applyPolicyPack(cfg); err != nil {
+		return err
+	}
+
+	prof, err := loadProfileForEnv(cfg, profilePath, envName, jsonFlag)
+	if err != nil {
+		return err
+	}
+	prof, err = applyIgnoreFile(prof, profile.DefaultIgnoreFile, false)
+	if err != nil {
+		return err
+	}
+
+	cfg.Policy.Env = envName
+	result, err := verify.Verify(cfg, ref, false, jsonFlag, prof)
+	if err == nil {
+		return nil
+	}
+	if result == nil || cfg.Policy.Mode == "enforce" {
+		return ui.WrapError(ui.CodeVerificationFailed, fmt.Errorf("attestation not created: %w", err), "Run 'acc policy explain' to see why, fix the violations, and attest again")
+	}
+	if !jsonFlag {
+		ui.PrintWarning("Verification failed (policy mode: warn); attesting the failed status")
+	}
+	return nil
+}
+
+// attestSignOptions resolves the attest signing flags. Rekor upload follows sigstore
+// practice: on by default for keyless signing, opt-in for key-based signing.
+func attestSignOptions(sign bool, cosignKey string, tlogUpload, noTlogUpload bool) (attest.SignOptions, error) {
+	if tlogUpload && noTlogUpload {
+		return attest.SignOptions{}, fmt.Errorf("--tlog-upload and --no-tlog-upload are mutually exclusive")
+	}
+
+	// --tlog-upload needs a signature to upload
+	sign = sign || tlogUpload
+	if !sign {
+		if cosignKey != "" || noTlogUpload {
+			return attest.SignOptions{}, fmt.Errorf("--cosign-key and --no-tlog-upload require --sign")
+		}
+		return attest.SignOptions{}, nil
+	}
+
+	upload := cosignKey == ""
+	if tlogUpload {
+		upload = true
+	}
+	if noTlogUpload {
+		upload = false
+	}
+
+	return attest.SignOptions{
+		Sign:       true,
+		CosignKey:  cosignKey,
+		TlogUpload: upload,
+	}, nil
+}
+
 func NewInspectCmd() *cobra.Command {
 	var imageRef string
+	var field string
+	var digest string
+	var sbomSummary bool
+	var showInput bool
+	var watch bool
 
 	cmd := &cobra.Command{
 		Use:   "inspect [image]",
@@ -462,7 +1610,7 @@ func NewInspectCmd() *cobra.Command {
 			// Load config
 			cfg, err := config.Load(configFile)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w\n\nHint: Run 'acc init' to create a configuration file", err)
+				return configLoadError(err)
 			}
 
 			ref := imageRef
@@ -470,16 +1618,49 @@ func NewInspectCmd() *cobra.Command {
 				ref = args[0]
 			}
 
+			// "-" reads the image reference from stdin (e.g. acc build ... | acc verify -)
+			ref, err = readImageRef(cmd.InOrStdin(), ref)
+			if err != nil {
+				return err
+			}
+
+			// --digest pins the image to a known digest (no container runtime needed)
+			ref, err = applyDigest(ref, digest, "")
+			if err != nil {
+				return err
+			}
+
 			if ref == "" {
-				return fmt.Errorf("image reference required\n\nUsage: acc inspect <image>")
+				return imageRefRequired("acc inspect <image>")
+			}
+
+			// --watch redraws the human summary whenever state, attestations, SBOMs, or waivers change
+			if watch {
+				if jsonFlag || field != "" {
+					return ui.NewError(ui.CodeInvalidArgument, "--watch cannot be combined with --json or --field", "Use --watch for the live human-readable summary only")
+				}
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				return inspect.Watch(ctx, cfg, func() {
+					fmt.Print("\033[H\033[2J")
+					if _, err := inspect.Inspect(cfg, ref, sbomSummary, showInput, false); err != nil {
+						ui.PrintError(err.Error())
+					}
+					fmt.Println()
+					ui.PrintInfo(fmt.Sprintf("Watching .acc for changes (updated %s, Ctrl-C to exit)", time.Now().Format("15:04:05")))
+				})
 			}
 
-			// Inspect
-			result, err := inspect.Inspect(cfg, ref, jsonFlag)
+			// Inspect (--field suppresses human output like --json)
+			result, err := inspect.Inspect(cfg, ref, sbomSummary, showInput, jsonFlag || field != "")
 			if err != nil {
 				return err
 			}
 
+			if field != "" {
+				return printField(result, field)
+			}
+
 			if jsonFlag {
 				fmt.Println(result.FormatJSON())
 			}
@@ -489,6 +1670,11 @@ func NewInspectCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to inspect")
+	cmd.Flags().StringVar(&field, "field", "", fieldFlagUsage)
+	cmd.Flags().StringVar(&digest, "digest", "", digestFlagUsage)
+	cmd.Flags().BoolVar(&sbomSummary, "sbom-summary", false, "parse the SBOM and report component count, top licenses, and sbom.watchlist matches")
+	cmd.Flags().BoolVar(&showInput, "show-input", false, "show the policy input (user, labels, SBOM/attestation presence, ...) recorded by the image's last verify")
+	cmd.Flags().BoolVar(&watch, "watch", false, "keep running and redraw the summary when .acc state, attestations, SBOMs, or waivers change (Ctrl-C to exit)")
 
 	return cmd
 }
@@ -512,6 +1698,15 @@ func NewTrustCmd() *cobra.Command {
 func NewTrustStatusCmd() *cobra.Command {
 	var imageRef string
 	var remote bool
+	var failOnUnknown bool
+	var requirePass bool
+	var field string
+	var digest string
+	var format string
+	var wrap bool
+	var attestationDetails bool
+	var compareAttest bool
+	var exportSBOM string
 
 	cmd := &cobra.Command{
 		Use:   "status [image]",
@@ -524,27 +1719,73 @@ func NewTrustStatusCmd() *cobra.Command {
 				ref = args[0]
 			}
 
+			// --digest pins the image to a known digest (no container runtime needed)
+			ref, err := applyDigest(ref, digest, "")
+			if err != nil {
+				return err
+			}
+
 			if ref == "" {
-				return fmt.Errorf("image reference required\n\nUsage: acc trust status <image>")
+				return imageRefRequired("acc trust status <image>")
 			}
 
+			switch format {
+			case "text", "table", "json":
+			default:
+				return fmt.Errorf("invalid --format %q: must be text, table, or json", format)
+			}
+			outputJSON := jsonFlag || format == "json"
+
 			// Load trust status (v0.3.2: optionally fetch remote attestations)
-			result, err := trust.Status(ref, remote, jsonFlag)
+			quiet := outputJSON || field != "" || format == "table"
+			result, err := trust.Status(ref, remote, attestationDetails, compareAttest, quiet)
 			if err != nil {
 				return err
 			}
 
-			if jsonFlag {
+			// --export-sbom writes the image's SBOM (registry referrer with --remote, else local)
+			if exportSBOM != "" {
+				export, err := trust.ExportSBOM(ref, exportSBOM, remote, quiet)
+				if err != nil {
+					return ui.WrapError(ui.CodeError, err, "Generate the SBOM with 'acc build', or attach one to the pushed image and pass --remote")
+				}
+				result.SBOMExport = export
+				if !quiet {
+					ui.PrintSuccess(fmt.Sprintf("SBOM (%s, from %s) written to %s", export.Format, export.Location, export.Path))
+				}
+			}
+
+			if field != "" {
+				if err := printField(result, field); err != nil {
+					return err
+				}
+			} else if outputJSON {
 				fmt.Println(result.FormatJSON())
+			} else if format == "table" {
+				if err := result.WriteTable(os.Stdout, wrap); err != nil {
+					return err
+				}
 			}
 
-			os.Exit(result.ExitCode())
+			os.Exit(result.ExitCodeWith(trust.ExitCodeOptions{
+				FailOnUnknown: failOnUnknown,
+				RequirePass:   requirePass,
+			}))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to check")
+	cmd.Flags().StringVar(&field, "field", "", fieldFlagUsage)
+	cmd.Flags().StringVar(&digest, "digest", "", digestFlagUsage)
+	cmd.Flags().StringVar(&format, "format", "text", "output format: text, table, or json")
+	cmd.Flags().BoolVar(&wrap, "wrap", false, "wrap long violation messages in --format table instead of truncating")
 	cmd.Flags().BoolVar(&remote, "remote", false, "fetch attestations from remote registry (v0.3.2)")
+	cmd.Flags().BoolVar(&attestationDetails, "attestation-details", false, "validate each attestation and show its schema validity, digest match, and signature status")
+	cmd.Flags().StringVar(&exportSBOM, "export-sbom", "", "write the image's SBOM to this path: its registry SBOM referrer with --remote, else the SBOM from its build manifest (validated as SPDX or CycloneDX)")
+	cmd.Flags().BoolVar(&compareAttest, "compare-attestation", false, "fail with attestation-stale when the latest attestation's verificationResultsHash differs from the current verification state")
+	cmd.Flags().BoolVar(&failOnUnknown, "fail-on-unknown", true, "exit 2 when status is unknown (set =false to exit 0 for advisory checks)")
+	cmd.Flags().BoolVar(&requirePass, "require-pass", false, "exit 1 for any status other than pass (including unknown)")
 
 	return cmd
 }
@@ -552,6 +1793,9 @@ func NewTrustStatusCmd() *cobra.Command {
 func NewTrustVerifyCmd() *cobra.Command {
 	var imageRef string
 	var remote bool
+	var field string
+	var digest string
+	var checkDrift bool
 
 	cmd := &cobra.Command{
 		Use:   "verify [image]",
@@ -564,22 +1808,36 @@ func NewTrustVerifyCmd() *cobra.Command {
 				ref = args[0]
 			}
 
+			// --digest pins the image to a known digest (no container runtime needed)
+			ref, err := applyDigest(ref, digest, "")
+			if err != nil {
+				return err
+			}
+
 			if ref == "" {
-				return fmt.Errorf("image reference required\n\nUsage: acc trust verify <image>")
+				return imageRefRequired("acc trust verify <image>")
 			}
 
 			// Verify attestations (v0.3.2: optionally fetch from remote registry)
-			result, err := trust.VerifyAttestations(ref, remote, jsonFlag)
+			result, err := trust.VerifyAttestations(ref, remote, checkDrift, jsonFlag || field != "")
 			if err != nil {
-				// Still print JSON if requested, even on error
-				if jsonFlag && result != nil {
+				// Still print JSON (or the requested field) if requested, even on error
+				if field != "" && result != nil {
+					if fieldErr := printField(result, field); fieldErr != nil {
+						return fieldErr
+					}
+				} else if jsonFlag && result != nil {
 					fmt.Println(result.FormatJSON())
 				}
 				os.Exit(result.ExitCode())
 				return nil
 			}
 
-			if jsonFlag {
+			if field != "" {
+				if err := printField(result, field); err != nil {
+					return err
+				}
+			} else if jsonFlag {
 				fmt.Println(result.FormatJSON())
 			}
 
@@ -590,10 +1848,161 @@ func NewTrustVerifyCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&imageRef, "image", "i", "", "image reference to verify")
 	cmd.Flags().BoolVar(&remote, "remote", false, "fetch attestations from remote registry (v0.3.2)")
+	cmd.Flags().BoolVar(&checkDrift, "check-policy-drift", false, "fail unless an attestation was created with the current .acc/policy pack")
+	cmd.Flags().StringVar(&field, "field", "", fieldFlagUsage)
+	cmd.Flags().StringVar(&digest, "digest", "", digestFlagUsage)
+
+	return cmd
+}
+
+func NewSBOMCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "Work with SBOMs",
+		Long:  "Inspect and compare SPDX or CycloneDX JSON SBOMs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewSBOMDiffCmd())
+	return cmd
+}
+
+func NewSBOMDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare two SBOMs",
+		Long:  "Report packages added, removed, and upgraded (old -> new version), and license changes, between two SBOMs (SPDX or CycloneDX JSON)",
+		Example: `  # What changed since the last release build
+  acc sbom diff release/app.spdx.json .acc/sbom/app.spdx.json
+
+  # Machine-readable delta
+  acc sbom diff old.json new.json --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldDoc, err := sbom.ParseFile(args[0])
+			if err != nil {
+				return ui.WrapError(ui.CodeInvalidArgument, err, "Both arguments must be SPDX or CycloneDX JSON SBOMs")
+			}
+			newDoc, err := sbom.ParseFile(args[1])
+			if err != nil {
+				return ui.WrapError(ui.CodeInvalidArgument, err, "Both arguments must be SPDX or CycloneDX JSON SBOMs")
+			}
+
+			delta := sbom.Diff(oldDoc, newDoc)
+			if jsonFlag {
+				data, _ := json.MarshalIndent(delta, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+			printSBOMDiff(delta)
+			return nil
+		},
+	}
+}
+
+// printSBOMDiff prints the acc sbom diff human output
+func printSBOMDiff(delta *sbom.Delta) {
+	if delta.Empty() {
+		ui.PrintSuccess("No package or license changes")
+		return
+	}
+	if len(delta.Added) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Added (%d):", len(delta.Added)))
+		for _, pkg := range delta.Added {
+			fmt.Printf("  + %s %s\n", pkg.Name, pkg.Version)
+		}
+	}
+	if len(delta.Removed) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Removed (%d):", len(delta.Removed)))
+		for _, pkg := range delta.Removed {
+			fmt.Printf("  - %s %s\n", pkg.Name, pkg.Version)
+		}
+	}
+	if len(delta.Upgraded) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Upgraded (%d):", len(delta.Upgraded)))
+		for _, change := range delta.Upgraded {
+			fmt.Printf("  ~ %s %s -> %s\n", change.Name, change.OldVersion, change.NewVersion)
+		}
+	}
+	if len(delta.LicenseChanged) > 0 {
+		ui.PrintWarning(fmt.Sprintf("License changes (%d):", len(delta.LicenseChanged)))
+		for _, change := range delta.LicenseChanged {
+			fmt.Printf("  ! %s: %s -> %s\n", change.Name, licenseList(change.OldLicenses), licenseList(change.NewLicenses))
+		}
+	}
+}
+
+func licenseList(licenses []string) string {
+	if len(licenses) == 0 {
+		return "(none)"
+	}
+	return strings.Join(licenses, ", ")
+}
+
+func NewWaiverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "waiver",
+		Short: "Inspect policy waivers",
+		Long:  "Inspect the policy waivers in .acc/waivers.yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
 
+	cmd.AddCommand(NewWaiverListCmd())
 	return cmd
 }
 
+func NewWaiverListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List waivers and their status",
+		Long: `List the waivers in .acc/waivers.yaml as active, expired, or unused.
+A waiver is unused when the last verification, run with --report-unused-waivers, found that its rule did not fire.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := verify.ListWaivers()
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				data, _ := json.MarshalIndent(result, "", "  ")
+				fmt.Println(string(data))
+				return nil
+			}
+
+			if len(result.Waivers) == 0 {
+				ui.PrintInfo("No waivers in .acc/waivers.yaml")
+				return nil
+			}
+			for _, w := range result.Waivers {
+				line := fmt.Sprintf("[%s] %s", w.Status, w.RuleID)
+				if w.Expiry != "" {
+					line += fmt.Sprintf(" (expires %s)", w.Expiry)
+				}
+				switch w.Status {
+				case verify.WaiverExpired:
+					fmt.Println(ui.FormatError(line))
+				case verify.WaiverUnused:
+					ui.PrintWarning(line)
+				default:
+					ui.PrintInfo(line)
+				}
+				if w.Justification != "" {
+					fmt.Printf("    %s\n", w.Justification)
+				}
+			}
+			if result.UnusedFrom != "" {
+				fmt.Printf("\nUnused status from the last verification of %s\n", result.UnusedFrom)
+			}
+			return nil
+		},
+	}
+}
+
 func NewConfigCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "config",
@@ -605,6 +2014,61 @@ func NewConfigCmd() *cobra.Command {
 	}
 }
 
+func NewCleanCmd() *cobra.Command {
+	var (
+		opts      clean.CleanOptions
+		all       bool
+		olderThan string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Prune local state, caches, and old attestations",
+		Long:  "Remove acc's accumulated local files under .acc (state, caches, attestations). Policies, profiles, SBOMs, waivers, and acc.yaml are never removed.",
+		Example: `  # Show what would be removed
+  acc clean --all --dry-run
+
+  # Remove attestations older than 30 days
+  acc clean --attestations --older-than 30d
+
+  # Remove verification state and caches
+  acc clean --state --cache`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all {
+				opts.State, opts.Cache, opts.Attestations = true, true, true
+			}
+			if olderThan != "" {
+				age, err := clean.ParseAge(olderThan)
+				if err != nil {
+					return ui.WrapError(ui.CodeInvalidArgument, err, "")
+				}
+				opts.OlderThan = age
+			}
+
+			result, err := clean.Clean(opts, jsonFlag)
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				data, _ := json.MarshalIndent(result, "", "  ")
+				fmt.Println(string(data))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.State, "state", false, "remove verification, build, and run state (.acc/state)")
+	cmd.Flags().BoolVar(&opts.Cache, "cache", false, "remove cached data (.acc/cache and --cache-dir / $ACC_CACHE_DIR)")
+	cmd.Flags().BoolVar(&opts.Attestations, "attestations", false, "remove local and cached remote attestations (.acc/attestations)")
+	cmd.Flags().BoolVar(&all, "all", false, "clean state, caches, and attestations")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "only remove files last modified longer ago than this (e.g. 30d, 36h)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "report what would be removed without removing anything")
+
+	return cmd
+}
+
 func NewLoginCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "login",
@@ -616,21 +2080,81 @@ func NewLoginCmd() *cobra.Command {
 	}
 }
 
-func NewVersionCmd() *cobra.Command {
+func NewSchemaCmd() *cobra.Command {
 	return &cobra.Command{
+		Use:   "schema <command>",
+		Short: "Print the JSON Schema of a command's --json result",
+		Long: fmt.Sprintf(`Print a JSON Schema (draft 2020-12) for the result a command prints with --json,
+generated from acc's result types. Available: %s`, strings.Join(schema.Commands(), ", ")),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return printSchema(strings.Join(args, " "))
+		},
+	}
+}
+
+// printSchema prints the JSON Schema for command's result
+func printSchema(command string) error {
+	s, err := schema.For(command)
+	if err != nil {
+		return ui.NewError(ui.CodeInvalidArgument, err.Error(), "Run 'acc schema --help' for the available commands")
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func NewVersionCmd() *cobra.Command {
+	var checkUpdate bool
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
 		Long:  "Print version, commit, and build info",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Offline by default; only contact the release API when asked
+			var check *upgrade.CheckResult
+			if checkUpdate {
+				var err error
+				check, err = upgrade.CheckForUpdate(os.Getenv("ACC_UPGRADE_API_BASE"), version)
+				if err != nil {
+					return err
+				}
+			}
+
 			if jsonFlag {
-				fmt.Printf(`{"version":"%s","commit":"%s","date":"%s"}%s`, version, commit, date, "\n")
-			} else {
-				fmt.Printf("acc version %s\n", version)
-				fmt.Printf("commit: %s\n", commit)
-				fmt.Printf("built: %s\n", date)
+				out := struct {
+					Version         string `json:"version"`
+					Commit          string `json:"commit"`
+					Date            string `json:"date"`
+					LatestVersion   string `json:"latestVersion,omitempty"`
+					UpdateAvailable *bool  `json:"updateAvailable,omitempty"`
+				}{Version: version, Commit: commit, Date: date}
+				if check != nil {
+					out.LatestVersion = check.LatestVersion
+					out.UpdateAvailable = &check.UpdateAvailable
+				}
+				data, _ := json.Marshal(out)
+				fmt.Println(string(data))
+				return nil
+			}
+
+			fmt.Printf("acc version %s\n", version)
+			fmt.Printf("commit: %s\n", commit)
+			fmt.Printf("built: %s\n", date)
+			if check != nil {
+				fmt.Printf("\n%s\n", check.Message)
 			}
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&checkUpdate, "check-update", false, "check whether a newer release is available (contacts GitHub; nothing is installed)")
+
+	return cmd
 }
 
 func NewUpgradeCmd() *cobra.Command {
@@ -639,7 +2163,11 @@ func NewUpgradeCmd() *cobra.Command {
 		dryRun           bool
 		verifySignature  bool
 		cosignKey        string
+		keyEnv           string
+		keyFile          string
 		verifyProvenance bool
+		installDir       string
+		verifyOnly       string
 	)
 
 	cmd := &cobra.Command{
@@ -662,8 +2190,43 @@ func NewUpgradeCmd() *cobra.Command {
   acc upgrade --verify-provenance
 
   # Upgrade with both verifications (enterprise mode)
-  acc upgrade --verify-signature --verify-provenance`,
+  acc upgrade --verify-signature --verify-provenance
+
+  # Install to a user-writable directory (acc installed in a root-owned location)
+  acc upgrade --install-dir ~/.local/bin
+
+  # Verify a pre-downloaded release archive without installing it
+  acc upgrade --verify-only ./acc_0.2.7_linux_amd64.tar.gz --version v0.2.7 --verify-signature --verify-provenance`,
 		Run: func(cmd *cobra.Command, args []string) {
+			// --key-env / --key-file take the cosign public key from the environment or a mounted file
+			if keyEnv != "" || keyFile != "" {
+				var err error
+				if cosignKey != "" {
+					err = fmt.Errorf("--cosign-key cannot be combined with --key-env or --key-file")
+				} else {
+					cosignKey, err = crypto.CosignKeyRef(keyEnv, keyFile, false)
+				}
+				if err != nil {
+					if jsonFlag {
+						fmt.Println(ui.FormatErrorJSON(ui.WrapError(ui.CodeInvalidArgument, err, "")))
+					} else {
+						fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					}
+					os.Exit(1)
+				}
+			}
+
+			if verifyOnly != "" {
+				runUpgradeVerifyOnly(verifyOnly, &upgrade.UpgradeOptions{
+					Version:          targetVersion,
+					VerifySignature:  verifySignature,
+					CosignKey:        cosignKey,
+					VerifyProvenance: verifyProvenance,
+					DownloadBase:     os.Getenv("ACC_UPGRADE_DOWNLOAD_BASE"),
+				})
+				return
+			}
+
 			// Get upgrade package
 			opts := &upgrade.UpgradeOptions{
 				Version:          targetVersion,
@@ -672,6 +2235,7 @@ func NewUpgradeCmd() *cobra.Command {
 				VerifySignature:  verifySignature,
 				CosignKey:        cosignKey,
 				VerifyProvenance: verifyProvenance,
+				InstallDir:       installDir,
 				// Read env vars for testing overrides
 				APIBase:        os.Getenv("ACC_UPGRADE_API_BASE"),
 				DownloadBase:   os.Getenv("ACC_UPGRADE_DOWNLOAD_BASE"),
@@ -681,7 +2245,7 @@ func NewUpgradeCmd() *cobra.Command {
 			result, err := upgrade.Upgrade(opts)
 			if err != nil {
 				if jsonFlag {
-					fmt.Printf(`{"error":"%s"}%s`, err.Error(), "\n")
+					fmt.Println(ui.FormatErrorJSON(ui.WrapError(ui.CodeUpgradeFailed, err, "")))
 				} else {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				}
@@ -711,6 +2275,9 @@ func NewUpgradeCmd() *cobra.Command {
 						fmt.Printf("Installed to:    %s\n", result.InstallPath)
 					}
 					fmt.Printf("\n%s\n", result.Message)
+					if result.PathInstructions != "" {
+						fmt.Printf("\n%s\n", result.PathInstructions)
+					}
 				}
 			}
 		},
@@ -722,7 +2289,57 @@ func NewUpgradeCmd() *cobra.Command {
 	// Supply-chain verification flags (opt-in)
 	cmd.Flags().BoolVar(&verifySignature, "verify-signature", false, "verify cosign signature (requires cosign in PATH)")
 	cmd.Flags().StringVar(&cosignKey, "cosign-key", "", "path/URL to cosign public key (optional, uses keyless if not provided)")
+	cmd.Flags().StringVar(&keyEnv, "key-env", "", "environment variable holding the cosign public key (PEM) for --verify-signature")
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "file holding the cosign public key for --verify-signature; checked before verifying")
 	cmd.Flags().BoolVar(&verifyProvenance, "verify-provenance", false, "verify SLSA provenance")
+	cmd.Flags().StringVar(&installDir, "install-dir", "", "install the new binary into this directory instead of replacing the running executable")
+	cmd.Flags().StringVar(&verifyOnly, "verify-only", "", "verify a downloaded release archive (checksum, plus --verify-signature/--verify-provenance) without installing; requires --version")
 
 	return cmd
 }
+
+// runUpgradeVerifyOnly verifies a local release archive and reports each check (exit 1 if any fails)
+func runUpgradeVerifyOnly(archivePath string, opts *upgrade.UpgradeOptions) {
+	result, err := upgrade.VerifyArchive(opts, archivePath)
+	if result == nil {
+		if jsonFlag {
+			fmt.Println(ui.FormatErrorJSON(ui.WrapError(ui.CodeInvalidArgument, err, "")))
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	if jsonFlag {
+		data, _ := json.Marshal(result)
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Archive:  %s\n", result.ArchivePath)
+		fmt.Printf("Version:  %s\n", result.Version)
+		fmt.Println()
+		for _, check := range result.Checks {
+			line := fmt.Sprintf("%-11s %s", check.Name+":", check.Status)
+			if check.Message != "" {
+				line += " - " + check.Message
+			}
+			switch check.Status {
+			case upgrade.CheckPass:
+				ui.PrintSuccess(line)
+			case upgrade.CheckFail:
+				ui.PrintError(line)
+			default:
+				ui.PrintInfo(line)
+			}
+		}
+		fmt.Println()
+		if result.Verified {
+			ui.PrintSuccess("Archive verified (not installed)")
+		} else {
+			ui.PrintError(err.Error())
+		}
+	}
+
+	if !result.Verified {
+		os.Exit(1)
+	}
+}
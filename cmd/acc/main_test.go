@@ -0,0 +1,697 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/attest"
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/verify"
+)
+
+func TestReadImageRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain ref ignores stdin", ref: "demo:latest", stdin: "other:latest", want: "demo:latest"},
+		{name: "dash reads stdin", ref: "-", stdin: "demo:latest\n", want: "demo:latest"},
+		{name: "whitespace is trimmed", ref: "-", stdin: "\n  ghcr.io/org/app@sha256:abc  \n\n", want: "ghcr.io/org/app@sha256:abc"},
+		{name: "empty stdin", ref: "-", stdin: " \n", wantErr: true},
+		{name: "multiple refs", ref: "-", stdin: "a:1\nb:2\n", wantErr: true},
+		{name: "ref with spaces", ref: "-", stdin: "demo latest\n", wantErr: true},
+		{name: "flag-like ref", ref: "-", stdin: "--json\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readImageRef(strings.NewReader(tt.stdin), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readImageRef() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readImageRef() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMainHelper runs main() in a subprocess for commands that call os.Exit
+func TestMainHelper(t *testing.T) {
+	if os.Getenv("ACC_TEST_MAIN") != "1" {
+		t.Skip("helper process")
+	}
+	args := strings.Split(os.Getenv("ACC_TEST_ARGS"), " ")
+	os.Args = append([]string{"acc"}, args...)
+	main()
+	os.Exit(0)
+}
+
+// TestVerify_ImageRefFromStdin tests that "acc verify -" verifies the ref read from stdin
+func TestVerify_ImageRefFromStdin(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS=verify - --json")
+	cmd.Stdin = strings.NewReader("  piped/app:1.0  \n")
+	output, _ := cmd.Output()
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("expected JSON verify result, got %q: %v", output, err)
+	}
+
+	stateData, err := os.ReadFile(filepath.Join(tmpDir, ".acc", "state", "last_verify.json"))
+	if err != nil {
+		t.Fatalf("verify did not save state: %v", err)
+	}
+	var state struct {
+		ImageRef string `json:"imageRef"`
+	}
+	json.Unmarshal(stateData, &state)
+	if state.ImageRef != "piped/app:1.0" {
+		t.Errorf("verified imageRef = %q, want piped/app:1.0", state.ImageRef)
+	}
+}
+
+// TestVerify_PrintInput tests that --print-input prints the policy input built for a seeded image config
+func TestVerify_PrintInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	sbomDir := filepath.Join(tmpDir, ".acc", "sbom")
+	os.MkdirAll(sbomDir, 0755)
+	os.WriteFile(filepath.Join(sbomDir, "demo.spdx.json"), []byte(`{"spdxVersion":"SPDX-2.3"}`), 0644)
+
+	// Fake docker returning a seeded image config
+	binDir := t.TempDir()
+	dockerScript := `#!/bin/sh
+echo '[{"Config":{"User":"1000","Labels":{"org.opencontainers.image.source":"https://example.com/app"}}}]'
+`
+	if err := os.WriteFile(filepath.Join(binDir, "docker"), []byte(dockerScript), 0755); err != nil {
+		t.Fatalf("failed to write fake docker: %v", err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(),
+		"ACC_TEST_MAIN=1",
+		"ACC_TEST_ARGS=verify seeded/app:1.0 --print-input",
+		"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("verify --print-input failed: %v (output %q)", err, output)
+	}
+
+	var got verify.RegoInput
+	if err := json.Unmarshal(output, &got); err != nil {
+		t.Fatalf("expected JSON policy input, got %q: %v", output, err)
+	}
+
+	want := verify.RegoInput{
+		Config: verify.ImageConfig{
+			User:   "1000",
+			Labels: map[string]string{"org.opencontainers.image.source": "https://example.com/app"},
+		},
+		SBOM: verify.SBOMInfo{Present: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("printed input = %+v, want %+v", got, want)
+	}
+
+	// Printing the input must not evaluate policy or record a verification
+	if _, err := os.Stat(filepath.Join(tmpDir, ".acc", "state", "last_verify.json")); !os.IsNotExist(err) {
+		t.Error("expected --print-input not to save verification state")
+	}
+}
+
+// TestVerify_SummaryFile tests that --summary-file records the same status and violation count as the result
+func TestVerify_SummaryFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	summaryPath := filepath.Join(tmpDir, "verify.summary")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS=verify summary/app:1.0 --json --summary-file "+summaryPath)
+	output, _ := cmd.Output()
+
+	var result verify.VerifyResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("expected JSON verify result, got %q: %v", output, err)
+	}
+
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("summary file not written: %v", err)
+	}
+	want := fmt.Sprintf("status=%s violations=%d image=summary/app:1.0\n", result.Status, len(result.Violations))
+	if string(data) != want {
+		t.Errorf("summary = %q, want %q", data, want)
+	}
+}
+
+// TestVerify_Expect tests that --expect exits 0 when the status matches and 1 with an error when it
+// does not, rendered as the error envelope under --json with nothing on stderr under --json-errors-only
+func TestVerify_Expect(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	// Without an SBOM the image fails verification
+	run := func(args string) (int, string, string) {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+		cmd.Dir = tmpDir
+		cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS=verify bad/app:1.0 "+args)
+		var stdout, stderr strings.Builder
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if exitErr, ok := cmd.Run().(*exec.ExitError); ok {
+			return exitErr.ExitCode(), stdout.String(), stderr.String()
+		}
+		return 0, stdout.String(), stderr.String()
+	}
+
+	if code, _, stderr := run("--json --expect fail"); code != 0 {
+		t.Errorf("--expect fail on a failing image exited %d, want 0 (stderr %q)", code, stderr)
+	}
+	code, _, stderr := run("--expect pass")
+	if code != 1 {
+		t.Errorf("--expect pass on a failing image exited %d, want 1", code)
+	}
+	if !strings.Contains(stderr, "expected pass but got fail") {
+		t.Errorf("expected a mismatch message, got stderr %q", stderr)
+	}
+
+	code, stdout, stderr := run("--json-errors-only --expect pass")
+	var envelope struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &envelope); err != nil || code != 1 {
+		t.Fatalf("expected the error envelope and exit 1, got %d %q: %v", code, stdout, err)
+	}
+	if envelope.Error.Code != "VERIFICATION_FAILED" || !strings.Contains(envelope.Error.Message, "expected pass but got fail") {
+		t.Errorf("unexpected error envelope %+v", envelope.Error)
+	}
+	if stderr != "" {
+		t.Errorf("expected no stderr output under --json-errors-only, got %q", stderr)
+	}
+
+	for _, expect := range []string{"broken", "warn"} {
+		if code, _, _ := run("--expect " + expect); code == 0 {
+			t.Errorf("expected --expect %s to be rejected", expect)
+		}
+	}
+}
+
+// TestVerify_Team tests that --team stamps the result, the saved state, and the summary line
+func TestVerify_Team(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	summaryPath := filepath.Join(tmpDir, "verify.summary")
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS=verify team/app:1.0 --json --team payments --summary-file "+summaryPath)
+	output, _ := cmd.Output()
+
+	var result verify.VerifyResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("expected JSON verify result, got %q: %v", output, err)
+	}
+	if result.Team != "payments" {
+		t.Errorf("result team = %q, want payments", result.Team)
+	}
+
+	stateData, err := os.ReadFile(filepath.Join(tmpDir, ".acc", "state", "last_verify.json"))
+	if err != nil {
+		t.Fatalf("state not written: %v", err)
+	}
+	var state verify.VerifyState
+	if err := json.Unmarshal(stateData, &state); err != nil {
+		t.Fatalf("invalid state: %v", err)
+	}
+	if state.Result == nil || state.Result.Team != "payments" {
+		t.Errorf("expected team payments in saved state, got %s", stateData)
+	}
+
+	data, err := os.ReadFile(summaryPath)
+	if err != nil {
+		t.Fatalf("summary file not written: %v", err)
+	}
+	if !strings.HasSuffix(string(data), " team=payments\n") {
+		t.Errorf("summary = %q, want a team=payments label", data)
+	}
+}
+
+// TestVerify_JSONCompact tests that --json-compact prints the verify result as a single line
+func TestVerify_JSONCompact(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+	cmd.Dir = tmpDir
+	cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS=verify compact/app:1.0 --json-compact")
+	output, _ := cmd.Output()
+
+	lines := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one line of output, got %d: %q", len(lines), output)
+	}
+	var result verify.VerifyResult
+	if err := json.Unmarshal([]byte(lines[0]), &result); err != nil {
+		t.Fatalf("expected JSON verify result, got %q: %v", output, err)
+	}
+	if result.Status == "" {
+		t.Errorf("expected a status in %q", output)
+	}
+}
+
+// TestVerify_JSONErrorsOnly tests that --json-errors-only writes exactly one JSON document to
+// stdout (the result, or the error envelope) and nothing to stderr
+func TestVerify_JSONErrorsOnly(t *testing.T) {
+	configured := t.TempDir()
+	if err := os.WriteFile(filepath.Join(configured, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		key  string // top-level key of the JSON document
+	}{
+		// Without an SBOM the image fails verification, which is still a result
+		{"failing result", configured, "status"},
+		// Without acc.yaml the command fails before verifying
+		{"error envelope", t.TempDir(), "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+			cmd.Dir = tt.dir
+			cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS=verify quiet/app:1.0 --json-errors-only --log-level debug")
+			var stdout, stderr strings.Builder
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+			if _, ok := cmd.Run().(*exec.ExitError); !ok {
+				t.Fatal("expected a non-zero exit code")
+			}
+
+			if stderr.Len() != 0 {
+				t.Errorf("expected no stderr output, got %q", stderr.String())
+			}
+			decoder := json.NewDecoder(strings.NewReader(stdout.String()))
+			var doc map[string]json.RawMessage
+			if err := decoder.Decode(&doc); err != nil {
+				t.Fatalf("expected a JSON document on stdout, got %q: %v", stdout.String(), err)
+			}
+			if _, ok := doc[tt.key]; !ok {
+				t.Errorf("expected %q in %q", tt.key, stdout.String())
+			}
+			if decoder.More() {
+				t.Errorf("expected exactly one JSON document on stdout, got %q", stdout.String())
+			}
+		})
+	}
+}
+
+// TestVerify_EnvProfile tests that --env applies the profiles.byEnv profile and an explicit --profile overrides it
+func TestVerify_EnvProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	yaml := config.DefaultConfig("demo").ToYAML() + `
+profiles:
+  byEnv:
+    prod: strict
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	profileDir := filepath.Join(tmpDir, ".acc", "profiles")
+	os.MkdirAll(profileDir, 0755)
+	for _, name := range []string{"strict", "baseline"} {
+		profileYAML := "schemaVersion: 1\nname: " + name + "\ndescription: " + name + " profile\n"
+		os.WriteFile(filepath.Join(profileDir, name+".yaml"), []byte(profileYAML), 0644)
+	}
+
+	tests := []struct {
+		args        string
+		wantEnv     string
+		wantProfile string
+	}{
+		{"verify env/app:1.0 --json --env prod", "prod", "strict"},
+		{"verify env/app:1.0 --json --env prod --profile baseline", "prod", "baseline"},
+		{"verify env/app:1.0 --json --env dev", "dev", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+			cmd.Dir = tmpDir
+			cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS="+tt.args)
+			output, _ := cmd.Output()
+
+			var result verify.VerifyResult
+			if err := json.Unmarshal(output, &result); err != nil {
+				t.Fatalf("expected JSON verify result, got %q: %v", output, err)
+			}
+			if result.Env != tt.wantEnv || result.ProfileUsed != tt.wantProfile {
+				t.Errorf("env/profile = %q/%q, want %q/%q", result.Env, result.ProfileUsed, tt.wantEnv, tt.wantProfile)
+			}
+
+			// The choice is recorded in the state push, promote, and attest read
+			data, err := os.ReadFile(filepath.Join(tmpDir, ".acc", "state", "last_verify.json"))
+			if err != nil {
+				t.Fatalf("expected verify state: %v", err)
+			}
+			var state verify.VerifyState
+			if err := json.Unmarshal(data, &state); err != nil {
+				t.Fatalf("invalid verify state: %v", err)
+			}
+			if state.Env != tt.wantEnv || state.Result.Env != tt.wantEnv || state.ProfileUsed != tt.wantProfile {
+				t.Errorf("state env/profile = %q/%q, want %q/%q", state.Env, state.ProfileUsed, tt.wantEnv, tt.wantProfile)
+			}
+		})
+	}
+}
+
+// TestVersion_CheckUpdate tests that version only contacts the release API with --check-update
+func TestVersion_CheckUpdate(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"tag_name": "v9.9.9", "name": "Release v9.9.9", "assets": []}`))
+	}))
+	defer server.Close()
+
+	run := func(args string) string {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+		cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS="+args, "ACC_UPGRADE_API_BASE="+server.URL)
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("acc %s failed: %v\n%s", args, err, output)
+		}
+		return string(output)
+	}
+
+	if out := run("version"); strings.Contains(out, "newer version") || requests != 0 {
+		t.Errorf("plain version contacted the release API (requests=%d): %q", requests, out)
+	}
+
+	out := run("version --check-update")
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+	if !strings.Contains(out, "A newer version is available: v9.9.9 (current dev)") {
+		t.Errorf("expected newer version message, got %q", out)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(run("version --check-update --json")), &result); err != nil {
+		t.Fatalf("expected JSON version output: %v", err)
+	}
+	if result["latestVersion"] != "v9.9.9" || result["updateAvailable"] != true {
+		t.Errorf("unexpected JSON result: %v", result)
+	}
+}
+
+// TestJSONErrorEnvelope tests that failing commands emit {"error":{"code","message","hint"}} with --json
+func TestJSONErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		code string
+		hint string
+	}{
+		{"missing config", "verify demo:latest --json", "CONFIG_ERROR", "Run 'acc init' to create a configuration file"},
+		{"missing image", "trust status --json", "INVALID_ARGUMENT", "Usage: acc trust status <image>"},
+		{"unknown flag", "trust status --json --no-such-flag", "INVALID_ARGUMENT", "Run 'acc trust status --help' for usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+			cmd.Dir = t.TempDir()
+			cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS="+tt.args)
+			output, err := cmd.Output()
+			if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+				t.Fatalf("expected exit code 1, got %v", err)
+			}
+
+			var envelope struct {
+				Error struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+					Hint    string `json:"hint"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(output, &envelope); err != nil {
+				t.Fatalf("expected JSON error envelope, got %q: %v", output, err)
+			}
+			if envelope.Error.Code != tt.code {
+				t.Errorf("code = %q, want %q", envelope.Error.Code, tt.code)
+			}
+			if envelope.Error.Message == "" {
+				t.Error("expected a message")
+			}
+			if envelope.Error.Hint != tt.hint {
+				t.Errorf("hint = %q, want %q", envelope.Error.Hint, tt.hint)
+			}
+		})
+	}
+}
+
+// TestAttest_VerifyFirst tests that attest --verify-first records a fresh verification and
+// attests it in one invocation, and that a failed verification blocks the attestation
+func TestAttest_VerifyFirst(t *testing.T) {
+	binDir := t.TempDir()
+	dockerScript := `#!/bin/sh
+case "$2" in
+  --format*) echo "sha256:` + strings.Repeat("c3", 32) + `" ;;
+  *) echo '[{"Config":{"User":"1000","Labels":{}}}]' ;;
+esac
+`
+	opaScript := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+echo '{"result":[{"expressions":[{"value":{"violations":['"$FAKE_OPA_VIOLATIONS"']}}]}]}'
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(dockerScript), 0755)
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(opaScript), 0755)
+
+	setup := func(t *testing.T) string {
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		os.MkdirAll(filepath.Join(tmpDir, ".acc", "sbom"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, ".acc", "sbom", "demo.spdx.json"), []byte(`{"spdxVersion":"SPDX-2.3"}`), 0644)
+		os.MkdirAll(filepath.Join(tmpDir, ".acc", "policy"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, ".acc", "policy", "policy.rego"), []byte("package acc.policy\n"), 0644)
+		return tmpDir
+	}
+	run := func(dir, args, violations string) ([]byte, error) {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"ACC_TEST_MAIN=1",
+			"ACC_TEST_ARGS="+args,
+			"FAKE_OPA_VIOLATIONS="+violations,
+			"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		return cmd.Output()
+	}
+
+	t.Run("passing verification is attested", func(t *testing.T) {
+		dir := setup(t)
+		output, err := run(dir, "attest fresh/app:1.0 --verify-first --json", "")
+		if err != nil {
+			t.Fatalf("attest --verify-first failed: %v (output %q)", err, output)
+		}
+
+		var result attest.AttestResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("expected JSON attest result, got %q: %v", output, err)
+		}
+		if result.Attestation.Subject.ImageRef != "fresh/app:1.0" || result.Attestation.Evidence.VerificationStatus != "pass" {
+			t.Errorf("unexpected attestation %+v", result.Attestation)
+		}
+		if _, err := os.Stat(filepath.Join(dir, result.OutputPath)); err != nil {
+			t.Errorf("attestation not written: %v", err)
+		}
+
+		var state verify.VerifyState
+		data, err := os.ReadFile(filepath.Join(dir, ".acc", "state", "last_verify.json"))
+		if err != nil {
+			t.Fatalf("verify did not save state: %v", err)
+		}
+		json.Unmarshal(data, &state)
+		if state.ImageRef != "fresh/app:1.0" || state.Status != "pass" {
+			t.Errorf("state = %s/%s, want fresh/app:1.0/pass", state.ImageRef, state.Status)
+		}
+	})
+
+	t.Run("failed verification blocks the attestation", func(t *testing.T) {
+		dir := setup(t)
+		violation := `{"rule":"no-root-user","severity":"critical","result":"fail","message":"runs as root"}`
+		if output, err := run(dir, "attest fresh/app:1.0 --verify-first --json", violation); err == nil {
+			t.Fatalf("expected attest to fail, got %q", output)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".acc", "attestations")); !os.IsNotExist(err) {
+			t.Error("expected no attestation for a failed verification in enforce mode")
+		}
+	})
+}
+
+func TestAttestSignOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		sign         bool
+		cosignKey    string
+		tlogUpload   bool
+		noTlogUpload bool
+		want         attest.SignOptions
+		wantErr      bool
+	}{
+		{name: "unsigned", want: attest.SignOptions{}},
+		{name: "keyless uploads by default", sign: true, want: attest.SignOptions{Sign: true, TlogUpload: true}},
+		{name: "keyless opt out", sign: true, noTlogUpload: true, want: attest.SignOptions{Sign: true}},
+		{name: "key-based skips upload by default", sign: true, cosignKey: "k", want: attest.SignOptions{Sign: true, CosignKey: "k"}},
+		{name: "key-based opt in", sign: true, cosignKey: "k", tlogUpload: true, want: attest.SignOptions{Sign: true, CosignKey: "k", TlogUpload: true}},
+		{name: "tlog upload implies sign", tlogUpload: true, want: attest.SignOptions{Sign: true, TlogUpload: true}},
+		{name: "conflicting flags", sign: true, tlogUpload: true, noTlogUpload: true, wantErr: true},
+		{name: "key without sign", cosignKey: "k", wantErr: true},
+		{name: "opt out without sign", noTlogUpload: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := attestSignOptions(tt.sign, tt.cosignKey, tt.tlogUpload, tt.noTlogUpload)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("attestSignOptions = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDigestKeying tests that --digest (the image ID) and resolving the tag with a container
+// runtime key the same image's state and attestations alike, and that a --digest the runtime
+// disagrees with is rejected
+func TestDigestKeying(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "acc.yaml"), []byte(config.DefaultConfig("demo").ToYAML()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	imageID := strings.Repeat("ab", 32)
+	binDir := t.TempDir()
+	docker := `#!/bin/sh
+case "$2" in
+  --format={{.Id}}) [ "$3" = app:1.0 ] && echo sha256:` + imageID + ` || exit 1 ;;
+  app:1.0|sha256:` + imageID + `) echo '[{"Config":{"User":"app","Labels":null}}]' ;;
+  *) exit 1 ;;
+esac
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+
+	run := func(args string) []byte {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestMainHelper$")
+		cmd.Dir = tmpDir
+		cmd.Env = append(os.Environ(), "ACC_TEST_MAIN=1", "ACC_TEST_ARGS="+args, "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		output, _ := cmd.CombinedOutput()
+		return output
+	}
+	stateFile := filepath.Join(tmpDir, ".acc", "state", "verify", imageID+".json")
+
+	run("verify app:1.0 --json")
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Fatalf("expected the tag's state under its image ID: %v", err)
+	}
+	os.Remove(stateFile)
+
+	// --digest keys the same file, and attesting the tag finds that verification
+	run("verify app:1.0 --digest " + imageID + " --json")
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Fatalf("expected --digest state under the same image ID: %v", err)
+	}
+	if output := run("attest app:1.0 --json"); !strings.Contains(string(output), imageID) {
+		t.Errorf("expected attesting the tag to match the --digest verification, got %s", output)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".acc", "attestations", imageID[:12])); err != nil {
+		t.Errorf("expected the attestation under the image ID: %v", err)
+	}
+
+	// A registry (manifest) digest is not the image ID
+	if output := run("verify app:1.0 --digest " + strings.Repeat("cd", 32) + " --json"); !strings.Contains(string(output), "is not the image ID") {
+		t.Errorf("expected a --digest other than the image ID to be rejected, got %s", output)
+	}
+}
+
+func TestApplyDigest(t *testing.T) {
+	digest := strings.Repeat("a1", 32)
+
+	tests := []struct {
+		name      string
+		ref       string
+		digest    string
+		algorithm string
+		want      string
+		wantErr   bool
+	}{
+		{name: "tagged ref", ref: "ghcr.io/org/app:1.0", digest: digest, want: "ghcr.io/org/app@sha256:" + digest},
+		{name: "prefixed digest", ref: "ghcr.io/org/app", digest: "sha256:" + digest, want: "ghcr.io/org/app@sha256:" + digest},
+		{name: "registry port", ref: "localhost:5000/app:dev", digest: digest, want: "localhost:5000/app@sha256:" + digest},
+		{name: "replaces existing digest", ref: "ghcr.io/org/app@sha256:" + strings.Repeat("0", 64), digest: digest, want: "ghcr.io/org/app@sha256:" + digest},
+		{name: "uppercase digest", ref: "app", digest: strings.ToUpper(digest), want: "app@sha256:" + digest},
+		{name: "missing ref", ref: "", digest: digest, wantErr: true},
+		{name: "no digest", ref: "ghcr.io/org/app:1.0", want: "ghcr.io/org/app:1.0"},
+		{name: "short digest", ref: "app", digest: "abc123", wantErr: true},
+		{name: "non-hex digest", ref: "app", digest: strings.Repeat("zz", 32), wantErr: true},
+		{name: "sha512 digest", ref: "ghcr.io/org/app:1.0", digest: "sha512:" + digest + digest, want: "ghcr.io/org/app@sha512:" + digest + digest},
+		{name: "sha512 algorithm", ref: "app", digest: digest + digest, algorithm: "sha512", want: "app@sha512:" + digest + digest},
+		{name: "sha256 length for sha512", ref: "app", digest: digest, algorithm: "sha512", wantErr: true},
+		{name: "unsupported algorithm", ref: "app", digest: "md5:" + digest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyDigest(tt.ref, tt.digest, tt.algorithm)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyDigest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("applyDigest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
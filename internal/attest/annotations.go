@@ -0,0 +1,53 @@
+package attest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// reservedAnnotationPrefixes are annotation namespaces acc and the OCI spec own; custom
+// annotations may not set them, so registry queries on acc's own keys stay trustworthy
+var reservedAnnotationPrefixes = []string{"acc.", "org.opencontainers."}
+
+// annotationKeyPattern matches annotation keys such as com.example.team or environment
+var annotationKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._/-]*[a-zA-Z0-9])?$`)
+
+// ParseAnnotations parses repeatable --annotation key=value flags for registry attestations.
+// Keys must be alphanumeric with '.', '_', '-', or '/' separators and outside the reserved
+// acc.* and org.opencontainers.* namespaces; values may be empty but not span lines.
+func ParseAnnotations(flags []string) (map[string]string, error) {
+	annotations := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid annotation %q: expected key=value", flag)
+		}
+		if !annotationKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid annotation key %q: use letters, digits, '.', '_', '-', or '/' (e.g. com.example.team)", key)
+		}
+		for _, prefix := range reservedAnnotationPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				return nil, fmt.Errorf("annotation key %q is reserved (%s* is set by acc)", key, prefix)
+			}
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid annotation %q: value must be a single line", key)
+		}
+		annotations[key] = value
+	}
+	return annotations, nil
+}
+
+// attestationAnnotations returns the annotations of a published attestation: custom
+// annotations first, then acc's own keys, which always win
+func attestationAnnotations(imageRef string, attestation *Attestation, custom map[string]string) map[string]string {
+	annotations := make(map[string]string, len(custom)+3)
+	for key, value := range custom {
+		annotations[key] = value
+	}
+	annotations["org.opencontainers.image.created"] = attestation.Timestamp
+	annotations["acc.attestation.imageRef"] = imageRef
+	annotations["acc.attestation.imageDigest"] = attestation.Subject.ImageDigest
+	return annotations
+}
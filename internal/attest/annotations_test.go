@@ -0,0 +1,68 @@
+package attest
+
+import (
+	"reflect"
+	"testing"
+
+	digest "github.com/opencontainers/go-digest"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	annotations, err := ParseAnnotations([]string{"team=payments", "com.example.environment=prod", "commit="})
+	if err != nil {
+		t.Fatalf("ParseAnnotations failed: %v", err)
+	}
+	want := map[string]string{"team": "payments", "com.example.environment": "prod", "commit": ""}
+	if !reflect.DeepEqual(annotations, want) {
+		t.Errorf("annotations = %v, want %v", annotations, want)
+	}
+
+	for _, flag := range []string{
+		"team", "=payments", "bad key=x", ".team=x", "team=a\nb",
+		"acc.attestation.imageRef=evil:latest", "org.opencontainers.image.created=2000-01-01T00:00:00Z",
+	} {
+		if _, err := ParseAnnotations([]string{flag}); err == nil {
+			t.Errorf("expected error for annotation %q", flag)
+		}
+	}
+}
+
+// TestAttestationManifest_Annotations tests that custom annotations appear on the pushed
+// descriptor and manifest, and that they cannot clobber acc's own keys
+func TestAttestationManifest_Annotations(t *testing.T) {
+	attestation := &Attestation{
+		Timestamp: "2025-01-01T00:00:00Z",
+		Subject:   Subject{ImageRef: "ghcr.io/org/app:1.0", ImageDigest: "abc123"},
+	}
+	payload := []byte(`{"schemaVersion":"v0.1"}`)
+	custom := map[string]string{
+		"team":                     "payments",
+		"acc.attestation.imageRef": "ghcr.io/evil/app:1.0", // bypassing ParseAnnotations still cannot override
+	}
+
+	desc, manifest := attestationManifest("ghcr.io/org/app:1.0", attestation, payload, custom)
+
+	if desc.Digest != digest.FromBytes(payload) || desc.Size != int64(len(payload)) {
+		t.Errorf("descriptor does not describe the payload: %+v", desc)
+	}
+	for name, annotations := range map[string]map[string]string{"descriptor": desc.Annotations, "manifest": manifest.Annotations} {
+		if annotations["team"] != "payments" {
+			t.Errorf("%s is missing the custom annotation: %v", name, annotations)
+		}
+		if annotations["acc.attestation.imageRef"] != "ghcr.io/org/app:1.0" {
+			t.Errorf("%s acc.attestation.imageRef = %q, want the attested image", name, annotations["acc.attestation.imageRef"])
+		}
+		if annotations["org.opencontainers.image.created"] != attestation.Timestamp || annotations["acc.attestation.imageDigest"] != "abc123" {
+			t.Errorf("%s lost acc's annotations: %v", name, annotations)
+		}
+	}
+	if len(manifest.Layers) != 1 || manifest.Layers[0].Digest != desc.Digest {
+		t.Errorf("manifest does not reference the attestation: %+v", manifest.Layers)
+	}
+
+	// Without custom annotations the descriptor carries exactly acc's keys
+	desc, _ = attestationManifest("ghcr.io/org/app:1.0", attestation, payload, nil)
+	if len(desc.Annotations) != 3 {
+		t.Errorf("expected 3 annotations without custom ones, got %v", desc.Annotations)
+	}
+}
@@ -9,21 +9,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/cloudcwfranck/acc/internal/config"
 	"github.com/cloudcwfranck/acc/internal/crypto"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/state"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	digest "github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
-	"oras.land/oras-go/v2/registry/remote"
-	"oras.land/oras-go/v2/registry/remote/auth"
-	"oras.land/oras-go/v2/registry/remote/retry"
 )
 
 // Attestation represents the v0 attestation format
@@ -46,9 +44,11 @@ type Subject struct {
 type Evidence struct {
 	SBOMRef                 string `json:"sbomRef,omitempty"`
 	PolicyPack              string `json:"policyPack"`
+	PolicyHash              string `json:"policyHash,omitempty"` // sha256 over the pack's .rego files at attestation time
 	PolicyMode              string `json:"policyMode"`
 	VerificationStatus      string `json:"verificationStatus"`
 	VerificationResultsHash string `json:"verificationResultsHash"`
+	Phase                   string `json:"phase,omitempty"` // verification phase attested (verify --two-phase)
 }
 
 // AttestationMeta contains tool metadata
@@ -77,29 +77,55 @@ type AttestationWithEnvelope struct {
 
 // AttestResult represents the result of attestation creation
 type AttestResult struct {
-	OutputPath  string      `json:"outputPath"`
-	Attestation Attestation `json:"attestation"`
+	OutputPath  string           `json:"outputPath"`
+	Attestation Attestation      `json:"attestation"`
+	DryRun      bool             `json:"dryRun,omitempty"`    // attestation was built but not written or published
+	Unchanged   bool             `json:"unchanged,omitempty"` // an identical attestation was already stored; it was kept as is
+	Replaced    string           `json:"replaced,omitempty"`  // previous latest attestation removed by --overwrite
+	Signature   *SignatureResult `json:"signature,omitempty"` // cosign signature (--sign / --tlog-upload)
 }
 
 // VerifyState represents the persisted verification state (reused from verify package)
 type VerifyState struct {
-	ImageRef  string                 `json:"imageRef"`
-	Status    string                 `json:"status"`
-	Timestamp string                 `json:"timestamp"`
-	Result    map[string]interface{} `json:"result"`
+	ImageRef   string                 `json:"imageRef"`
+	Status     string                 `json:"status"`
+	Timestamp  string                 `json:"timestamp"`
+	Result     map[string]interface{} `json:"result"`
+	PolicyMode string                 `json:"policyMode,omitempty"` // mode actually used by verify (may be overridden)
+	Phase      string                 `json:"phase,omitempty"`      // build or deploy (verify --two-phase)
+}
+
+// AttestOptions controls how Attest creates an attestation
+type AttestOptions struct {
+	Version     string            // acc version recorded in the attestation
+	Commit      string            // acc commit recorded in the attestation
+	Timestamp   string            // attestation time (RFC3339 or Unix seconds); SOURCE_DATE_EPOCH or now when empty
+	SubjectName string            // subject name (default imageRef), e.g. a canonical name for images pushed to several registries
+	Annotations map[string]string // added to the registry descriptor when Remote is set (see ParseAnnotations)
+	Remote      bool              // v0.3.2: also publish the attestation to the image's registry
+	DryRun      bool              // build and print the attestation (and where it would go) without writing or publishing
+	Overwrite   bool              // replace the image's latest local attestation instead of adding another one
+	OutputJSON  bool
+	Sign        SignOptions // optionally sign the written attestation with cosign
 }
 
-// Attest creates an attestation for an image
-// v0.3.2: optionally publish to remote registry when remote=true
-func Attest(cfg *config.Config, imageRef, version, commit string, remote, outputJSON bool) (*AttestResult, error) {
+// Attest creates an attestation for an image. The subject digest is always the resolved
+// image digest, whatever opts.SubjectName says.
+func Attest(cfg *config.Config, imageRef string, opts AttestOptions) (*AttestResult, error) {
 	if imageRef == "" {
 		return nil, fmt.Errorf("image reference required")
 	}
 
+	// Fixed by --timestamp or SOURCE_DATE_EPOCH for reproducible attestations
+	attestedAt, err := attestationTimestamp(opts.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
 	// Load last verification state
 	verifyState, err := loadVerifyState()
 	if err != nil {
-		return nil, fmt.Errorf("verification state not found\n\nRemediation:\n  Run 'acc verify %s' first to generate verification results", imageRef)
+		return nil, fmt.Errorf("verification state not found\n\nRemediation:\n  Run 'acc verify %s' first to generate verification results (verify --no-state does not write them)", imageRef)
 	}
 
 	// Verify imageRef matches last verified image
@@ -108,19 +134,26 @@ func Attest(cfg *config.Config, imageRef, version, commit string, remote, output
 	}
 
 	// v0.1.5: Only print creation message AFTER validation passes
-	if !outputJSON {
+	if !opts.OutputJSON {
 		ui.PrintInfo(fmt.Sprintf("Creating attestation for %s", imageRef))
 	}
 
 	// Resolve digest
-	digest, err := resolveDigest(imageRef)
+	digest, err := oci.ResolveDigest(imageRef)
 	if err != nil {
-		if !outputJSON {
+		if !opts.OutputJSON {
 			ui.PrintWarning(fmt.Sprintf("Could not resolve digest: %v", err))
 		}
 		digest = ""
 	}
 
+	subjectName := opts.SubjectName
+	if subjectName == "" {
+		subjectName = imageRef
+	} else if err := validateSubjectName(subjectName, digest); err != nil {
+		return nil, err
+	}
+
 	// Compute canonical hash of verification results
 	resultsHash, err := computeCanonicalHash(verifyState)
 	if err != nil {
@@ -130,53 +163,116 @@ func Attest(cfg *config.Config, imageRef, version, commit string, remote, output
 	// Get SBOM reference if available
 	sbomRef := getSBOMRef(cfg)
 
+	// Record the policy mode verify actually ran with (--policy-mode may override config)
+	policyMode := cfg.Policy.Mode
+	if verifyState.PolicyMode != "" {
+		policyMode = verifyState.PolicyMode
+	}
+
+	// Record the policy pack hash so trust verify can detect policy drift
+	policyPack := filepath.Join(".acc", "policy")
+	policyHash, err := policy.PackHash(policyPack)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash policy pack: %w", err)
+	}
+
 	// Create attestation
 	attestation := Attestation{
 		SchemaVersion: "v0.1",
 		Command:       "attest",
-		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Timestamp:     attestedAt,
 		Subject: Subject{
-			ImageRef:    imageRef,
+			ImageRef:    subjectName,
 			ImageDigest: digest,
 		},
 		Evidence: Evidence{
 			SBOMRef:                 sbomRef,
 			PolicyPack:              ".acc/policy",
-			PolicyMode:              cfg.Policy.Mode,
+			PolicyHash:              policyHash,
+			PolicyMode:              policyMode,
 			VerificationStatus:      verifyState.Status,
 			VerificationResultsHash: resultsHash,
+			Phase:                   verifyState.Phase,
 		},
 		Metadata: AttestationMeta{
 			Tool:        "acc",
-			ToolVersion: version,
-			GitCommit:   commit,
+			ToolVersion: opts.Version,
+			GitCommit:   opts.Commit,
 		},
 	}
 
+	// Content-address the attestation so re-attesting identical state dedupes
+	contentHash, err := computeContentHash(&attestation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute attestation content hash: %w", err)
+	}
+
+	// Dry run: show the attestation and its would-be path without touching disk or registry
+	if opts.DryRun {
+		return previewAttestation(imageRef, digest, contentHash, &attestation, opts.Remote, opts.OutputJSON), nil
+	}
+
 	// Determine output path
-	outputPath, err := determineOutputPath(imageRef, digest)
+	outputPath, err := determineOutputPath(imageRef, digest, contentHash)
 	if err != nil {
 		return nil, err
 	}
 
-	// Write attestation file
-	if err := writeAttestation(outputPath, &attestation); err != nil {
+	// --overwrite: the image's latest attestation is replaced rather than kept alongside the new one
+	var replaced string
+	if opts.Overwrite {
+		replaced, err = latestAttestation(filepath.Dir(outputPath))
+		if err != nil {
+			return nil, err
+		}
+		if replaced == outputPath {
+			replaced = ""
+		}
+	}
+
+	// Write attestation file (skipped if identical content is already stored)
+	// The stored attestation is kept as is, so the pointer, result, and any remote copy match the file
+	deduplicated := false
+	if stored, err := readStoredAttestation(outputPath); err == nil {
+		deduplicated = true
+		attestation = *stored
+	} else if err := writeAttestation(outputPath, &attestation); err != nil {
 		return nil, err
 	}
 
+	if replaced != "" {
+		if err := removeAttestation(replaced); err != nil {
+			return nil, err
+		}
+	}
+
+	// Record timestamp ordering in the per-image index
+	if err := appendAttestationIndex(outputPath, attestedAt, contentHash); err != nil {
+		if !opts.OutputJSON {
+			ui.PrintWarning(fmt.Sprintf("Failed to update attestation index: %v", err))
+		}
+	}
+
 	// Update last_attestation.json pointer
 	if err := updateLastAttestationPointer(&attestation, outputPath); err != nil {
-		if !outputJSON {
+		if !opts.OutputJSON {
 			ui.PrintWarning(fmt.Sprintf("Failed to update last attestation pointer: %v", err))
 		}
 	}
 
-	if !outputJSON {
-		ui.PrintSuccess("Attestation created")
+	if !opts.OutputJSON {
+		if deduplicated {
+			ui.PrintSuccess("Attestation unchanged (identical attestation already stored)")
+		} else {
+			ui.PrintSuccess("Attestation created")
+		}
 		fmt.Printf("  Path:    %s\n", outputPath)
+		if replaced != "" {
+			fmt.Printf("  Replaced: %s\n", replaced)
+		}
 		fmt.Printf("  Subject: %s\n", imageRef)
 		if digest != "" {
-			fmt.Printf("  Digest:  sha256:%s\n", digest[:12])
+			fmt.Printf("  Digest:  %s\n", oci.DisplayDigest(digest))
 		}
 		fmt.Printf("  Hash:    %s\n", resultsHash[:16])
 	}
@@ -184,20 +280,38 @@ func Attest(cfg *config.Config, imageRef, version, commit string, remote, output
 	result := &AttestResult{
 		OutputPath:  outputPath,
 		Attestation: attestation,
+		Unchanged:   deduplicated,
+		Replaced:    replaced,
+	}
+
+	// Optionally sign with cosign (and record the signature in the Rekor transparency log)
+	if opts.Sign.Sign {
+		signature, err := signAttestation(outputPath, opts.Sign)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign attestation: %w", err)
+		}
+		result.Signature = signature
+
+		if !opts.OutputJSON {
+			fmt.Printf("  Signature: %s\n", signature.Signature)
+			if signature.Tlog != nil {
+				fmt.Printf("  Rekor:     log index %d\n", signature.Tlog.LogIndex)
+			}
+		}
 	}
 
 	// v0.3.2: Optionally publish attestation to remote registry
-	if remote {
-		if !outputJSON {
+	if opts.Remote {
+		if !opts.OutputJSON {
 			ui.PrintInfo("Publishing attestation to remote registry...")
 		}
 
 		// Publish to remote OCI registry
-		if err := publishAttestationToRegistry(imageRef, &attestation, outputJSON); err != nil {
+		if err := publishAttestationToRegistry(imageRef, &attestation, opts.Annotations, opts.OutputJSON); err != nil {
 			return nil, fmt.Errorf("failed to publish attestation to remote registry: %w", err)
 		}
 
-		if !outputJSON {
+		if !opts.OutputJSON {
 			ui.PrintSuccess("Attestation published to remote registry")
 		}
 	}
@@ -205,6 +319,32 @@ func Attest(cfg *config.Config, imageRef, version, commit string, remote, output
 	return result, nil
 }
 
+// previewAttestation prints the attestation a real run would write (for --dry-run)
+func previewAttestation(imageRef, digest, contentHash string, attestation *Attestation, remote, outputJSON bool) *AttestResult {
+	result := &AttestResult{
+		OutputPath:  attestationPath(imageRef, digest, contentHash),
+		Attestation: *attestation,
+		DryRun:      true,
+	}
+
+	if !outputJSON {
+		ui.PrintInfo("Dry run: attestation not written")
+		fmt.Printf("  Path:    %s\n", result.OutputPath)
+		fmt.Printf("  Subject: %s\n", imageRef)
+		if digest != "" {
+			fmt.Printf("  Digest:  %s\n", oci.QualifiedDigest(digest))
+		}
+		fmt.Printf("  Hash:    %s\n", attestation.Evidence.VerificationResultsHash)
+		if remote {
+			fmt.Println("  Remote:  would publish to registry")
+		}
+		data, _ := json.MarshalIndent(attestation, "", "  ")
+		fmt.Println(string(data))
+	}
+
+	return result
+}
+
 // loadVerifyState loads the last verification state
 func loadVerifyState() (*VerifyState, error) {
 	stateFile := filepath.Join(".acc", "state", "last_verify.json")
@@ -226,8 +366,8 @@ func loadVerifyState() (*VerifyState, error) {
 func validateImageMatch(imageRef string, state *VerifyState) error {
 	// CRITICAL: Always resolve digests for authoritative comparison
 	// Digest comparison is more reliable than ref comparison (refs can alias)
-	currentDigest, err1 := resolveDigest(imageRef)
-	stateDigest, err2 := resolveDigest(state.ImageRef)
+	currentDigest, err1 := oci.ResolveDigest(imageRef)
+	stateDigest, err2 := oci.ResolveDigest(state.ImageRef)
 
 	// If both digests resolved, use digest comparison (authoritative)
 	if err1 == nil && err2 == nil {
@@ -236,8 +376,8 @@ func validateImageMatch(imageRef string, state *VerifyState) error {
 			return nil
 		}
 		// Different digests = different images - MUST fail
-		return fmt.Errorf("image mismatch: attempting to attest '%s' (digest: sha256:%s) but last verified image was '%s' (digest: sha256:%s)\n\nRemediation:\n  Run 'acc verify %s' first",
-			imageRef, currentDigest[:12], state.ImageRef, stateDigest[:12], imageRef)
+		return fmt.Errorf("image mismatch: attempting to attest '%s' (digest: %s) but last verified image was '%s' (digest: %s)\n\nRemediation:\n  Run 'acc verify %s' first",
+			imageRef, oci.DisplayDigest(currentDigest), state.ImageRef, oci.DisplayDigest(stateDigest), imageRef)
 	}
 
 	// Fallback: If digest resolution failed, compare refs as strings
@@ -253,30 +393,38 @@ func validateImageMatch(imageRef string, state *VerifyState) error {
 
 // computeCanonicalHash computes a canonical SHA256 hash of verification results
 func computeCanonicalHash(state *VerifyState) (string, error) {
+	return ResultsHash(state.Status, state.Result)
+}
+
+// ResultsHash computes the verificationResultsHash an attestation records for a verification
+// state's status and result, so trust status can tell whether an attestation is still current
+func ResultsHash(status string, result map[string]interface{}) (string, error) {
+	// Marshal with sorted keys (json.Marshal guarantees map key ordering)
+	data, err := json.Marshal(canonicalResults(status, result))
+	if err != nil {
+		return "", err
+	}
+
+	// Compute SHA256
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// canonicalResults builds the structure ResultsHash hashes: the status, sorted violations and
+// waivers, SBOM presence, and attestations of a verification
+func canonicalResults(status string, result map[string]interface{}) map[string]interface{} {
 	// Extract violations and waivers from state
-	result := state.Result
 	if result == nil {
 		result = make(map[string]interface{})
 	}
 
-	// Build canonical structure for hashing
-	canonical := map[string]interface{}{
-		"status":       state.Status,
+	return map[string]interface{}{
+		"status":       status,
 		"violations":   extractAndSortViolations(result),
 		"waivers":      extractAndSortWaivers(result),
 		"sbomPresent":  result["sbomPresent"],
 		"attestations": result["attestations"],
 	}
-
-	// Marshal with sorted keys (json.Marshal guarantees map key ordering)
-	data, err := json.Marshal(canonical)
-	if err != nil {
-		return "", err
-	}
-
-	// Compute SHA256
-	hash := sha256.Sum256(data)
-	return hex.EncodeToString(hash[:]), nil
 }
 
 // extractAndSortViolations extracts violations and sorts them canonically
@@ -332,8 +480,7 @@ func extractAndSortWaivers(result map[string]interface{}) []map[string]interface
 
 // getSBOMRef returns the SBOM reference if available
 func getSBOMRef(cfg *config.Config) string {
-	sbomDir := filepath.Join(".acc", "sbom")
-	sbomFile := filepath.Join(sbomDir, fmt.Sprintf("%s.%s.json", cfg.Project.Name, cfg.SBOM.Format))
+	sbomFile := filepath.Join(cfg.SBOMDir(), fmt.Sprintf("%s.%s.json", cfg.Project.Name, cfg.SBOM.Format))
 
 	if _, err := os.Stat(sbomFile); err == nil {
 		return sbomFile
@@ -343,27 +490,156 @@ func getSBOMRef(cfg *config.Config) string {
 }
 
 // determineOutputPath determines where to write the attestation
-func determineOutputPath(imageRef, digest string) (string, error) {
+// Attestations are content-addressed: .acc/attestations/<digest12>/<contentHash16>.json
+func determineOutputPath(imageRef, digest, contentHash string) (string, error) {
+	path := attestationPath(imageRef, digest, contentHash)
+
+	// Create directory structure
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create attestation directory: %w", err)
+	}
+
+	return path, nil
+}
+
+// attestationPath returns the content-addressed attestation path without creating directories
+func attestationPath(imageRef, digest, contentHash string) string {
 	// Sanitize imageRef for use as directory name
 	sanitized := sanitizeRef(imageRef)
 
 	// Use digest if available, otherwise sanitized ref
 	dirName := sanitized
 	if digest != "" {
-		dirName = digest[:12] // Use first 12 chars of digest
+		dirName = oci.ShortDigest(digest) // Use first 12 chars of digest
 	}
 
-	// Create directory structure
-	attestDir := filepath.Join(".acc", "attestations", dirName)
-	if err := os.MkdirAll(attestDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create attestation directory: %w", err)
+	// Filename is the content hash (first 16 chars, matching the remote attestation cache)
+	filename := fmt.Sprintf("%s.json", contentHash[:16])
+
+	return filepath.Join(".acc", "attestations", dirName, filename)
+}
+
+// computeContentHash computes the canonical (JCS) hash of an attestation, excluding its timestamp
+// Two attestations of the same verified state for the same image produce the same hash
+func computeContentHash(attestation *Attestation) (string, error) {
+	content := *attestation
+	content.Timestamp = ""
+
+	canonical, err := crypto.CanonicalizeJCS(content)
+	if err != nil {
+		return "", err
 	}
 
-	// Generate filename with timestamp
-	timestamp := time.Now().UTC().Format("20060102-150405")
-	filename := fmt.Sprintf("%s-attestation.json", timestamp)
+	hash := sha256.Sum256(canonical)
+	return hex.EncodeToString(hash[:]), nil
+}
 
-	return filepath.Join(attestDir, filename), nil
+// attestationIndexEntry records when an attestation was (re-)created
+type attestationIndexEntry struct {
+	Path        string `json:"path"`
+	ContentHash string `json:"contentHash"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// appendAttestationIndex appends an entry to <attestation dir>/index.jsonl
+// Content-addressed filenames carry no ordering, so the index preserves timestamp order
+// (.jsonl so attestation discovery, which walks *.json, does not pick it up)
+func appendAttestationIndex(path, timestamp, contentHash string) error {
+	entry := attestationIndexEntry{
+		Path:        path,
+		ContentHash: contentHash,
+		Timestamp:   timestamp,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	indexFile := filepath.Join(filepath.Dir(path), "index.jsonl")
+	return state.AppendFile(indexFile, append(data, '\n'), 0644)
+}
+
+// readAttestationIndex reads the entries of <dir>/index.jsonl in the order they were appended;
+// a missing index has no entries
+func readAttestationIndex(dir string) ([]attestationIndexEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "index.jsonl"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation index: %w", err)
+	}
+
+	var entries []attestationIndexEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry attestationIndexEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse attestation index: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// latestAttestation returns the path of the most recently indexed attestation in dir that
+// still exists, or "" when there is none
+func latestAttestation(dir string) (string, error) {
+	entries, err := readAttestationIndex(dir)
+	if err != nil {
+		return "", err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if _, err := os.Stat(entries[i].Path); err == nil {
+			return entries[i].Path, nil
+		}
+	}
+	return "", nil
+}
+
+// removeAttestation deletes the attestation at path, its cosign signature files, and its
+// index entries, so the index only lists attestations that exist
+func removeAttestation(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove attestation %s: %w", path, err)
+	}
+	for _, ext := range []string{".sig", ".pem", ".bundle"} {
+		os.Remove(path + ext)
+	}
+
+	dir := filepath.Dir(path)
+	entries, err := readAttestationIndex(dir)
+	if err != nil {
+		return err
+	}
+	var kept []byte
+	for _, entry := range entries {
+		if entry.Path == path {
+			continue
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		kept = append(kept, append(data, '\n')...)
+	}
+	return state.WriteFile(filepath.Join(dir, "index.jsonl"), kept, 0644)
+}
+
+// readStoredAttestation reads the attestation (without its envelope) stored at path
+func readStoredAttestation(path string) (*Attestation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var stored AttestationWithEnvelope
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse stored attestation %s: %w", path, err)
+	}
+	return &stored.Attestation, nil
 }
 
 // sanitizeRef sanitizes an image reference for use as a directory name
@@ -481,40 +757,13 @@ func updateLastAttestationPointer(attestation *Attestation, path string) error {
 	}
 
 	pointerFile := filepath.Join(stateDir, "last_attestation.json")
-	return os.WriteFile(pointerFile, data, 0644)
-}
-
-// resolveDigest attempts to resolve the digest for an image reference
-func resolveDigest(imageRef string) (string, error) {
-	tools := []struct {
-		name string
-		args []string
-	}{
-		{"docker", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"podman", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"nerdctl", []string{"inspect", "--format={{.Id}}", imageRef}},
-	}
-
-	for _, tool := range tools {
-		if _, err := exec.LookPath(tool.name); err == nil {
-			cmd := exec.Command(tool.name, tool.args...)
-			output, err := cmd.Output()
-			if err == nil {
-				digest := strings.TrimSpace(string(output))
-				digest = strings.TrimPrefix(digest, "sha256:")
-				if digest != "" {
-					return digest, nil
-				}
-			}
-		}
-	}
-
-	return "", fmt.Errorf("could not resolve digest")
+	return state.WriteFile(pointerFile, data, 0644)
 }
 
 // publishAttestationToRegistry publishes an attestation to a remote OCI registry
 // v0.3.2: Real OCI attestation publishing using oras-go/v2
-func publishAttestationToRegistry(imageRef string, attestation *Attestation, outputJSON bool) error {
+// annotations are custom descriptor annotations (attest --annotation), validated by ParseAnnotations
+func publishAttestationToRegistry(imageRef string, attestation *Attestation, annotations map[string]string, outputJSON bool) error {
 	ctx := context.Background()
 
 	// 1. Marshal attestation to JSON
@@ -523,75 +772,26 @@ func publishAttestationToRegistry(imageRef string, attestation *Attestation, out
 		return fmt.Errorf("failed to marshal attestation: %w", err)
 	}
 
-	// 2. Parse image reference to get registry and repository
-	registry, repository, _, err := parseImageRef(imageRef)
+	// 2. Create OCI repository client with Docker credentials
+	registry, repository, _, err := oci.ParseReference(imageRef)
 	if err != nil {
 		return fmt.Errorf("failed to parse image reference: %w", err)
 	}
-
-	// 3. Create OCI repository client with auth
-	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", registry, repository))
+	repo, err := oci.NewRepository(registry, repository)
 	if err != nil {
-		return fmt.Errorf("failed to create repository client: %w", err)
-	}
-
-	// Configure auth from Docker credentials
-	// Try multiple registry key formats that might be in Docker config
-	var cred auth.Credential
-	var credErr error
-
-	// Try different registry URL formats
-	registryFormats := []string{
-		registry,                       // e.g., "ghcr.io"
-		"https://" + registry,          // e.g., "https://ghcr.io"
-		"https://" + registry + "/v2/", // e.g., "https://ghcr.io/v2/"
-	}
-
-	for _, regFormat := range registryFormats {
-		if c, err := loadDockerCredentials(regFormat); err == nil {
-			cred = c
-			credErr = nil
-			break
-		} else {
-			credErr = err
-		}
-	}
-
-	// Set up auth client with credentials if found
-	repo.Client = &auth.Client{
-		Client: retry.DefaultClient,
-		Cache:  auth.NewCache(),
-		Credential: auth.CredentialFunc(func(ctx context.Context, reg string) (auth.Credential, error) {
-			if credErr != nil {
-				// No credentials found, return empty (might work for public repos or with other auth methods)
-				return auth.Credential{}, nil
-			}
-			return cred, nil
-		}),
+		return err
 	}
-	repo.PlainHTTP = false
-
-	// 4. Create attestation descriptor
-	// Media type for acc attestations
-	const attestationMediaType = "application/vnd.acc.attestation.v1+json"
 
-	// Calculate digest
-	digestBytes := sha256.Sum256(attestationJSON)
-	digestStr := fmt.Sprintf("sha256:%x", digestBytes)
-
-	attestationDesc := ocispec.Descriptor{
-		MediaType: attestationMediaType,
-		Digest:    digest.Digest(digestStr),
-		Size:      int64(len(attestationJSON)),
-		Annotations: map[string]string{
-			"org.opencontainers.image.created": attestation.Timestamp,
-			"acc.attestation.imageRef":         imageRef,
-			"acc.attestation.imageDigest":      attestation.Subject.ImageDigest,
-		},
-	}
+	// 4. Create attestation descriptor and the manifest that references it
+	attestationDesc, manifestContent := attestationManifest(imageRef, attestation, attestationJSON, annotations)
 
 	// 5. Check if attestation already exists (idempotency)
-	exists, err := repo.Exists(ctx, attestationDesc)
+	var exists bool
+	err = oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		var existsErr error
+		exists, existsErr = repo.Exists(ctx, attestationDesc)
+		return existsErr
+	})
 	if err == nil && exists {
 		if !outputJSON {
 			ui.PrintInfo("Attestation already exists remotely (idempotent)")
@@ -599,29 +799,16 @@ func publishAttestationToRegistry(imageRef string, attestation *Attestation, out
 		return nil
 	}
 
-	// 6. Push attestation content
-	if err := repo.Push(ctx, attestationDesc, strings.NewReader(string(attestationJSON))); err != nil {
+	// 6. Push attestation content (retried on transient registry errors)
+	err = oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		return repo.Push(ctx, attestationDesc, strings.NewReader(string(attestationJSON)))
+	})
+	if err != nil {
 		return fmt.Errorf("failed to push attestation: %w", err)
 	}
 
-	// 7. Create and push an OCI manifest that references the attestation
+	// 7. Push the OCI manifest that references the attestation
 	// This is required because tags can only point to manifests, not blobs
-	manifestContent := ocispec.Manifest{
-		MediaType: ocispec.MediaTypeImageManifest,
-		Config: ocispec.Descriptor{
-			MediaType: "application/vnd.oci.empty.v1+json",
-			Digest:    "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", // Empty JSON object
-			Size:      2,
-		},
-		Layers: []ocispec.Descriptor{attestationDesc},
-		Annotations: map[string]string{
-			"org.opencontainers.image.created": attestation.Timestamp,
-			"acc.attestation.imageRef":         imageRef,
-			"acc.attestation.imageDigest":      attestation.Subject.ImageDigest,
-		},
-	}
-	manifestContent.SchemaVersion = 2
-
 	manifestJSON, err := json.Marshal(manifestContent)
 	if err != nil {
 		return fmt.Errorf("failed to marshal manifest: %w", err)
@@ -637,7 +824,10 @@ func publishAttestationToRegistry(imageRef string, attestation *Attestation, out
 	}
 
 	// Push the manifest
-	if err := repo.Push(ctx, manifestDesc, strings.NewReader(string(manifestJSON))); err != nil {
+	err = oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		return repo.Push(ctx, manifestDesc, strings.NewReader(string(manifestJSON)))
+	})
+	if err != nil {
 		return fmt.Errorf("failed to push attestation manifest: %w", err)
 	}
 
@@ -650,15 +840,16 @@ func publishAttestationToRegistry(imageRef string, attestation *Attestation, out
 		return nil
 	}
 
-	attestationTag := fmt.Sprintf("attestation-%s-%s",
-		attestation.Subject.ImageDigest[:12],
-		strings.ReplaceAll(attestation.Timestamp, ":", "-"))
+	attestationTag := remoteAttestationTag(attestation)
 
 	if !outputJSON {
 		ui.PrintInfo(fmt.Sprintf("Tagging attestation manifest as: %s", attestationTag))
 	}
 
-	if err := repo.Tag(ctx, manifestDesc, attestationTag); err != nil {
+	err = oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		return repo.Tag(ctx, manifestDesc, attestationTag)
+	})
+	if err != nil {
 		// Tag failure is non-fatal - attestation is already pushed
 		if !outputJSON {
 			ui.PrintWarning(fmt.Sprintf("Attestation pushed but tagging failed: %v", err))
@@ -672,99 +863,40 @@ func publishAttestationToRegistry(imageRef string, attestation *Attestation, out
 	return nil
 }
 
-// parseImageRef parses an image reference into registry, repository, tag/digest
-func parseImageRef(imageRef string) (registry, repository, reference string, err error) {
-	// Handle image references like:
-	// - localhost:5000/repo:tag
-	// - ghcr.io/org/repo:tag
-	// - ghcr.io/org/repo@sha256:...
-	parts := strings.SplitN(imageRef, "/", 2)
-	if len(parts) < 2 {
-		return "", "", "", fmt.Errorf("invalid image reference format: %s", imageRef)
-	}
-
-	registry = parts[0]
-	rest := parts[1]
-
-	// Split repository and reference (tag or digest)
-	if strings.Contains(rest, "@") {
-		repoParts := strings.SplitN(rest, "@", 2)
-		repository = repoParts[0]
-		reference = repoParts[1]
-	} else if strings.Contains(rest, ":") {
-		repoParts := strings.SplitN(rest, ":", 2)
-		repository = repoParts[0]
-		reference = repoParts[1]
-	} else {
-		repository = rest
-		reference = "latest"
-	}
-
-	return registry, repository, reference, nil
-}
-
-// loadDockerCredentials loads credentials from ~/.docker/config.json
-func loadDockerCredentials(registry string) (auth.Credential, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return auth.Credential{}, err
-	}
-
-	configPath := filepath.Join(homeDir, ".docker", "config.json")
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return auth.Credential{}, err
-	}
-
-	var config struct {
-		Auths map[string]struct {
-			Auth     string `json:"auth"`
-			Username string `json:"username,omitempty"`
-			Password string `json:"password,omitempty"`
-		} `json:"auths"`
-		CredsStore  string            `json:"credsStore,omitempty"`
-		CredHelpers map[string]string `json:"credHelpers,omitempty"`
-	}
+// attestationManifest builds the attestation layer descriptor and the manifest that
+// references it; both carry acc's annotations merged over the custom ones
+func attestationManifest(imageRef string, attestation *Attestation, attestationJSON []byte, custom map[string]string) (ocispec.Descriptor, ocispec.Manifest) {
+	// Media type for acc attestations
+	const attestationMediaType = "application/vnd.acc.attestation.v1+json"
 
-	if err := json.Unmarshal(data, &config); err != nil {
-		return auth.Credential{}, err
+	annotations := attestationAnnotations(imageRef, attestation, custom)
+	attestationDesc := ocispec.Descriptor{
+		MediaType:   attestationMediaType,
+		Digest:      digest.FromBytes(attestationJSON),
+		Size:        int64(len(attestationJSON)),
+		Annotations: annotations,
 	}
 
-	// Look for registry auth
-	if authEntry, ok := config.Auths[registry]; ok {
-		// Try direct username/password first
-		if authEntry.Username != "" && authEntry.Password != "" {
-			return auth.Credential{
-				Username: authEntry.Username,
-				Password: authEntry.Password,
-			}, nil
-		}
-
-		// Try base64-encoded auth
-		if authEntry.Auth != "" {
-			decoded, err := base64.StdEncoding.DecodeString(authEntry.Auth)
-			if err != nil {
-				return auth.Credential{}, fmt.Errorf("failed to decode auth: %w", err)
-			}
-
-			// Auth is in format "username:password"
-			parts := strings.SplitN(string(decoded), ":", 2)
-			if len(parts) != 2 {
-				return auth.Credential{}, fmt.Errorf("invalid auth format")
-			}
-
-			return auth.Credential{
-				Username: parts[0],
-				Password: parts[1],
-			}, nil
-		}
+	manifest := ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config: ocispec.Descriptor{
+			MediaType: "application/vnd.oci.empty.v1+json",
+			Digest:    "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", // Empty JSON object
+			Size:      2,
+		},
+		Layers:      []ocispec.Descriptor{attestationDesc},
+		Annotations: annotations,
 	}
+	manifest.SchemaVersion = 2
+	return attestationDesc, manifest
+}
 
-	// TODO: Support credential helpers via credsStore and credHelpers
-	// For now, if we can't find credentials, try to use external credential helpers
-	// by executing docker-credential-<helper> commands
-
-	return auth.Credential{}, fmt.Errorf("no credentials found for %s", registry)
+// remoteAttestationTag returns attestation-<digest12>-<timestamp> (":" is not allowed in tags),
+// so it is deterministic whenever the attestation timestamp is
+func remoteAttestationTag(attestation *Attestation) string {
+	return fmt.Sprintf("attestation-%s-%s",
+		oci.ShortDigest(attestation.Subject.ImageDigest),
+		strings.ReplaceAll(attestation.Timestamp, ":", "-"))
 }
 
 // FormatJSON formats attestation result as JSON
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cloudcwfranck/acc/internal/config"
@@ -152,7 +153,7 @@ func TestAttestWithoutVerifyState(t *testing.T) {
 	cfg := config.DefaultConfig("test-project")
 
 	// Try to attest without verify state (should fail)
-	_, err = Attest(cfg, "test:latest", "v0.1", "abc123", false, true)
+	_, err = Attest(cfg, "test:latest", AttestOptions{Version: "v0.1", Commit: "abc123", OutputJSON: true})
 	if err == nil {
 		t.Error("expected error when verify state missing, got nil")
 	}
@@ -205,7 +206,7 @@ func TestAttestWithVerifyState(t *testing.T) {
 	}
 
 	// Attest
-	result, err := Attest(cfg, "test:latest", "v0.1.0", "abc123", false, true)
+	result, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
 	if err != nil {
 		t.Fatalf("Attest failed: %v", err)
 	}
@@ -231,6 +232,10 @@ func TestAttestWithVerifyState(t *testing.T) {
 		t.Error("expected verification results hash to be set")
 	}
 
+	if !strings.HasPrefix(result.Attestation.Evidence.PolicyHash, "sha256:") {
+		t.Errorf("expected policy pack hash to be recorded, got %q", result.Attestation.Evidence.PolicyHash)
+	}
+
 	if result.Attestation.Metadata.Tool != "acc" {
 		t.Errorf("expected tool 'acc', got '%s'", result.Attestation.Metadata.Tool)
 	}
@@ -298,7 +303,7 @@ func TestAttestImageMismatch(t *testing.T) {
 	}
 
 	// Try to attest different image (should fail)
-	_, err = Attest(cfg, "test:latest", "v0.1", "abc123", false, true)
+	_, err = Attest(cfg, "test:latest", AttestOptions{Version: "v0.1", Commit: "abc123", OutputJSON: true})
 	if err == nil {
 		t.Error("expected error for image mismatch, got nil")
 	}
@@ -371,7 +376,7 @@ func TestAttest_NoCreationMessageOnFailure(t *testing.T) {
 
 	// Attempt to attest without verify state should fail
 	// The bug was that "Creating attestation..." was printed even on failure
-	_, err = Attest(cfg, "test:image", "v0.1.5", "test-commit", false, false)
+	_, err = Attest(cfg, "test:image", AttestOptions{Version: "v0.1.5", Commit: "test-commit"})
 
 	if err == nil {
 		t.Error("Expected error when verification state missing, got nil")
@@ -432,7 +437,7 @@ func TestAttest_CreationMessageOnlyOnSuccess(t *testing.T) {
 
 	// This should succeed and create an attestation
 	// The "Creating attestation..." message should appear AFTER validation passes
-	result, err := Attest(cfg, "test:image", "v0.1.5", "test-commit", false, true)
+	result, err := Attest(cfg, "test:image", AttestOptions{Version: "v0.1.5", Commit: "test-commit", OutputJSON: true})
 
 	if err != nil {
 		t.Logf("Attest failed (expected if container tools unavailable): %v", err)
@@ -445,6 +450,281 @@ func TestAttest_CreationMessageOnlyOnSuccess(t *testing.T) {
 	}
 }
 
+// TestAttest_UsesVerifiedPolicyMode tests that attestations record the mode verify ran with
+func TestAttest_UsesVerifiedPolicyMode(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "acc-attest-mode-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	// Config says enforce, but verify was run with --policy-mode warn
+	cfg := config.DefaultConfig("test-project")
+
+	stateDir := filepath.Join(".acc", "state")
+	os.MkdirAll(stateDir, 0755)
+	verifyState := VerifyState{
+		ImageRef:   "test:latest",
+		Status:     "pass",
+		Timestamp:  "2025-01-01T00:00:00Z",
+		Result:     map[string]interface{}{"status": "pass"},
+		PolicyMode: "warn",
+	}
+	stateData, _ := json.Marshal(verifyState)
+	os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+
+	result, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("Attest failed: %v", err)
+	}
+
+	if result.Attestation.Evidence.PolicyMode != "warn" {
+		t.Errorf("expected policyMode 'warn' from verify state, got %q", result.Attestation.Evidence.PolicyMode)
+	}
+}
+
+// TestAttest_DeduplicatesIdenticalState tests that attesting identical state twice yields one file
+func TestAttest_DeduplicatesIdenticalState(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	cfg := config.DefaultConfig("test-project")
+
+	stateDir := filepath.Join(".acc", "state")
+	os.MkdirAll(stateDir, 0755)
+	writeState := func(status string) {
+		verifyState := VerifyState{
+			ImageRef:  "test:latest",
+			Status:    status,
+			Timestamp: "2025-01-01T00:00:00Z",
+			Result:    map[string]interface{}{"status": status},
+		}
+		stateData, _ := json.Marshal(verifyState)
+		os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+	}
+
+	writeState("pass")
+	first, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", Timestamp: "2025-01-01T00:00:00Z", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("first Attest failed: %v", err)
+	}
+	second, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", Timestamp: "2025-06-01T00:00:00Z", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("second Attest failed: %v", err)
+	}
+
+	// The identical attestation is reported unchanged, with the stored timestamp
+	if first.Unchanged || !second.Unchanged {
+		t.Errorf("expected only the second attest to be unchanged, got %v and %v", first.Unchanged, second.Unchanged)
+	}
+	if second.Attestation.Timestamp != "2025-01-01T00:00:00Z" {
+		t.Errorf("expected the stored attestation's timestamp, got %s", second.Attestation.Timestamp)
+	}
+
+	if first.OutputPath != second.OutputPath {
+		t.Errorf("expected identical state to map to one path, got %s and %s", first.OutputPath, second.OutputPath)
+	}
+
+	attestDir := filepath.Dir(first.OutputPath)
+	jsonFiles, _ := filepath.Glob(filepath.Join(attestDir, "*.json"))
+	if len(jsonFiles) != 1 {
+		t.Errorf("expected 1 attestation file, got %d: %v", len(jsonFiles), jsonFiles)
+	}
+
+	// Index keeps one entry per attest for timestamp ordering
+	indexData, err := os.ReadFile(filepath.Join(attestDir, "index.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to read attestation index: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(indexData)), "\n")
+	if len(lines) != 2 {
+		t.Errorf("expected 2 index entries, got %d", len(lines))
+	}
+
+	// Pointer still refers to the latest attestation
+	pointerData, _ := os.ReadFile(filepath.Join(stateDir, "last_attestation.json"))
+	var pointer map[string]interface{}
+	json.Unmarshal(pointerData, &pointer)
+	if pointer["attestationPath"] != second.OutputPath {
+		t.Errorf("pointer attestationPath = %v, want %s", pointer["attestationPath"], second.OutputPath)
+	}
+	if pointer["timestamp"] != "2025-01-01T00:00:00Z" {
+		t.Errorf("pointer timestamp = %v, want the stored attestation's", pointer["timestamp"])
+	}
+
+	// Different verified state produces a new file
+	writeState("fail")
+	third, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("third Attest failed: %v", err)
+	}
+	if third.OutputPath == first.OutputPath {
+		t.Error("expected different state to produce a different attestation file")
+	}
+	jsonFiles, _ = filepath.Glob(filepath.Join(attestDir, "*.json"))
+	if len(jsonFiles) != 2 {
+		t.Errorf("expected 2 attestation files, got %d", len(jsonFiles))
+	}
+}
+
+// TestAttest_Overwrite tests that --overwrite replaces the image's latest attestation and
+// keeps the index and pointer in step
+func TestAttest_Overwrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	cfg := config.DefaultConfig("test-project")
+
+	stateDir := filepath.Join(".acc", "state")
+	os.MkdirAll(stateDir, 0755)
+	writeState := func(status string) {
+		verifyState := VerifyState{
+			ImageRef:  "test:latest",
+			Status:    status,
+			Timestamp: "2025-01-01T00:00:00Z",
+			Result:    map[string]interface{}{"status": status},
+		}
+		stateData, _ := json.Marshal(verifyState)
+		os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+	}
+
+	writeState("pass")
+	first, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("first Attest failed: %v", err)
+	}
+	os.WriteFile(first.OutputPath+".sig", []byte("sig"), 0644)
+
+	writeState("fail")
+	second, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", Overwrite: true, OutputJSON: true})
+	if err != nil {
+		t.Fatalf("overwriting Attest failed: %v", err)
+	}
+	if second.Replaced != first.OutputPath {
+		t.Errorf("expected %s to be replaced, got %q", first.OutputPath, second.Replaced)
+	}
+
+	attestDir := filepath.Dir(second.OutputPath)
+	jsonFiles, _ := filepath.Glob(filepath.Join(attestDir, "*.json"))
+	if len(jsonFiles) != 1 || jsonFiles[0] != second.OutputPath {
+		t.Errorf("expected only the new attestation, got %v", jsonFiles)
+	}
+	if _, err := os.Stat(first.OutputPath + ".sig"); !os.IsNotExist(err) {
+		t.Error("expected the replaced attestation's signature to be removed")
+	}
+
+	entries, err := readAttestationIndex(attestDir)
+	if err != nil {
+		t.Fatalf("failed to read attestation index: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != second.OutputPath {
+		t.Errorf("expected the index to list only the new attestation, got %+v", entries)
+	}
+
+	pointerData, _ := os.ReadFile(filepath.Join(stateDir, "last_attestation.json"))
+	var pointer map[string]interface{}
+	json.Unmarshal(pointerData, &pointer)
+	if pointer["attestationPath"] != second.OutputPath || pointer["status"] != "fail" {
+		t.Errorf("expected the pointer to refer to the new attestation, got %v", pointer)
+	}
+
+	// Overwriting with identical content keeps the attestation and replaces nothing
+	third, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", Overwrite: true, OutputJSON: true})
+	if err != nil {
+		t.Fatalf("identical overwriting Attest failed: %v", err)
+	}
+	if !third.Unchanged || third.Replaced != "" || third.OutputPath != second.OutputPath {
+		t.Errorf("expected the identical attestation to be kept, got %+v", third)
+	}
+	if _, err := os.Stat(second.OutputPath); err != nil {
+		t.Errorf("expected the attestation to remain: %v", err)
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && (s[:len(substr)] == substr || contains(s[1:], substr)))
 }
+
+// TestAttest_DryRun tests that --dry-run writes nothing and previews what a real run writes
+func TestAttest_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	cfg := config.DefaultConfig("test-project")
+
+	stateDir := filepath.Join(".acc", "state")
+	os.MkdirAll(stateDir, 0755)
+	verifyState := VerifyState{
+		ImageRef:  "test:latest",
+		Status:    "pass",
+		Timestamp: "2025-01-01T00:00:00Z",
+		Result:    map[string]interface{}{"status": "pass"},
+	}
+	stateData, _ := json.Marshal(verifyState)
+	os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+
+	// Dry run still validates the image against the verified state
+	if _, err := Attest(cfg, "other:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", DryRun: true, OutputJSON: true}); err == nil {
+		t.Error("expected dry run to fail for an image that was not verified")
+	}
+
+	preview, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", DryRun: true, OutputJSON: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !preview.DryRun {
+		t.Error("expected DryRun to be set on result")
+	}
+
+	if _, err := os.Stat(filepath.Join(".acc", "attestations")); !os.IsNotExist(err) {
+		t.Errorf("dry run must not create attestations, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, "last_attestation.json")); !os.IsNotExist(err) {
+		t.Errorf("dry run must not update last_attestation.json, stat err = %v", err)
+	}
+
+	// A real run writes the previewed attestation to the previewed path
+	real, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("Attest failed: %v", err)
+	}
+	if real.OutputPath != preview.OutputPath {
+		t.Errorf("preview path = %s, real path = %s", preview.OutputPath, real.OutputPath)
+	}
+
+	data, err := os.ReadFile(real.OutputPath)
+	if err != nil {
+		t.Fatalf("failed to read attestation: %v", err)
+	}
+	var written AttestationWithEnvelope
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("invalid attestation file: %v", err)
+	}
+
+	// Timestamps differ between runs; everything else must match
+	previewed := preview.Attestation
+	previewed.Timestamp = written.Attestation.Timestamp
+	previewJSON, _ := json.Marshal(previewed)
+	writtenJSON, _ := json.Marshal(written.Attestation)
+	if string(previewJSON) != string(writtenJSON) {
+		t.Errorf("preview does not match written attestation\npreview: %s\nwritten: %s", previewJSON, writtenJSON)
+	}
+}
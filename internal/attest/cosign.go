@@ -0,0 +1,119 @@
+package attest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+// SignOptions configures cosign signing of the written attestation file (acc attest --sign)
+type SignOptions struct {
+	Sign       bool   // If true, sign the attestation with cosign sign-blob
+	CosignKey  string // Path/URL to cosign private key (optional, keyless if empty)
+	TlogUpload bool   // Upload the signature to the Rekor transparency log
+}
+
+// SignatureResult records the cosign signature of an attestation
+type SignatureResult struct {
+	Signature   string     `json:"signature"`             // <attestation>.sig
+	Certificate string     `json:"certificate,omitempty"` // <attestation>.pem (keyless only)
+	Tlog        *TlogEntry `json:"tlog,omitempty"`        // Rekor entry when uploaded
+}
+
+// TlogEntry identifies the Rekor transparency log entry for a signed attestation
+type TlogEntry struct {
+	LogIndex       int64  `json:"logIndex"`
+	LogID          string `json:"logId,omitempty"`
+	IntegratedTime int64  `json:"integratedTime,omitempty"`
+	Bundle         string `json:"bundle"` // <attestation>.bundle, verifiable offline with cosign verify-blob --bundle
+}
+
+// cosignBundle is the subset of cosign's --bundle output acc records
+type cosignBundle struct {
+	RekorBundle *struct {
+		Payload struct {
+			LogIndex       int64  `json:"logIndex"`
+			LogID          string `json:"logID"`
+			IntegratedTime int64  `json:"integratedTime"`
+		} `json:"Payload"`
+	} `json:"rekorBundle"`
+}
+
+// tlogIndexPattern matches cosign's "tlog entry created with index: N" message
+var tlogIndexPattern = regexp.MustCompile(`tlog entry created with index:\s*(\d+)`)
+
+// signAttestation signs the attestation file with cosign sign-blob, writing <path>.sig
+// (and <path>.pem for keyless). With TlogUpload the signature is recorded in Rekor and
+// cosign's bundle is written to <path>.bundle. The sidecar extensions keep these files
+// out of attestation discovery, which only reads *.json.
+func signAttestation(path string, opts SignOptions) (*SignatureResult, error) {
+	cosignPath, err := exec.LookPath("cosign")
+	if err != nil {
+		return nil, fmt.Errorf("cosign is required for --sign but was not found in PATH. Install cosign: https://docs.sigstore.dev/cosign/installation/")
+	}
+
+	result := &SignatureResult{Signature: path + ".sig"}
+	args := []string{"sign-blob", "--yes", "--output-signature", result.Signature,
+		fmt.Sprintf("--tlog-upload=%t", opts.TlogUpload)}
+
+	bundlePath := ""
+	if opts.TlogUpload {
+		bundlePath = path + ".bundle"
+		args = append(args, "--bundle", bundlePath)
+	}
+
+	if opts.CosignKey != "" {
+		// Key-based signing
+		args = append(args, "--key", opts.CosignKey)
+	} else {
+		// Keyless signing (certificate is needed for verification)
+		result.Certificate = path + ".pem"
+		args = append(args, "--output-certificate", result.Certificate)
+	}
+	args = append(args, path)
+
+	cmd := exec.Command(cosignPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("cosign signing failed: %w\nOutput: %s", err, string(output))
+	}
+
+	if opts.TlogUpload {
+		entry, err := readTlogEntry(bundlePath, output)
+		if err != nil {
+			return nil, err
+		}
+		result.Tlog = entry
+	}
+
+	return result, nil
+}
+
+// readTlogEntry extracts the Rekor entry from cosign's bundle, falling back to the
+// log index cosign prints when the bundle carries no Rekor payload
+func readTlogEntry(bundlePath string, cosignOutput []byte) (*TlogEntry, error) {
+	entry := &TlogEntry{Bundle: bundlePath}
+
+	if data, err := os.ReadFile(bundlePath); err == nil {
+		var bundle cosignBundle
+		if err := json.Unmarshal(data, &bundle); err == nil && bundle.RekorBundle != nil {
+			entry.LogIndex = bundle.RekorBundle.Payload.LogIndex
+			entry.LogID = bundle.RekorBundle.Payload.LogID
+			entry.IntegratedTime = bundle.RekorBundle.Payload.IntegratedTime
+			return entry, nil
+		}
+	}
+
+	if match := tlogIndexPattern.FindSubmatch(cosignOutput); match != nil {
+		index, err := strconv.ParseInt(string(match[1]), 10, 64)
+		if err == nil {
+			entry.LogIndex = index
+			return entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cosign did not report a transparency log entry (bundle: %s)", bundlePath)
+}
@@ -0,0 +1,129 @@
+package attest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// ReproduceResult compares an attestation with the verification results hash re-derived from
+// the current verification state (acc attest --reproduce)
+type ReproduceResult struct {
+	Attestation  string                 `json:"attestation"`
+	ImageRef     string                 `json:"imageRef"` // image of the current verification state
+	RecordedHash string                 `json:"recordedHash"`
+	CurrentHash  string                 `json:"currentHash"`
+	Match        bool                   `json:"match"`
+	Differences  []EvidenceDifference   `json:"differences,omitempty"` // attested evidence that differs from current state
+	Canonical    map[string]interface{} `json:"canonical"`             // canonical structure CurrentHash is computed over
+}
+
+// EvidenceDifference is an attested value that no longer matches the current state. The
+// attestation records only a hash of the violations and waivers, so differences are reported
+// for the evidence it records in the clear (status, policy mode, policy hash)
+type EvidenceDifference struct {
+	Field    string `json:"field"`
+	Attested string `json:"attested"`
+	Current  string `json:"current"`
+}
+
+// Reproduce recomputes the canonical verification results hash from the current verification
+// state and compares it with the verificationResultsHash recorded in the attestation at path
+func Reproduce(path string, outputJSON bool) (*ReproduceResult, error) {
+	attestation, err := readAttestationFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if attestation.Evidence.VerificationResultsHash == "" {
+		return nil, fmt.Errorf("attestation %s records no verificationResultsHash", path)
+	}
+
+	verifyState, err := loadVerifyState()
+	if err != nil {
+		return nil, fmt.Errorf("verification state not found\n\nRemediation:\n  Run 'acc verify %s' first to reproduce the verification results (verify --no-state does not write them)", attestation.Subject.ImageRef)
+	}
+
+	currentHash, err := computeCanonicalHash(verifyState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute verification hash: %w", err)
+	}
+
+	result := &ReproduceResult{
+		Attestation:  path,
+		ImageRef:     verifyState.ImageRef,
+		RecordedHash: attestation.Evidence.VerificationResultsHash,
+		CurrentHash:  currentHash,
+		Match:        attestation.Evidence.VerificationResultsHash == currentHash,
+		Canonical:    canonicalResults(verifyState.Status, verifyState.Result),
+	}
+
+	addDifference := func(field, attested, current string) {
+		if attested != "" && current != "" && attested != current {
+			result.Differences = append(result.Differences, EvidenceDifference{Field: field, Attested: attested, Current: current})
+		}
+	}
+	addDifference("verificationStatus", attestation.Evidence.VerificationStatus, verifyState.Status)
+	addDifference("policyMode", attestation.Evidence.PolicyMode, verifyState.PolicyMode)
+	if policyHash, err := policy.PackHash(filepath.Join(".acc", "policy")); err == nil {
+		addDifference("policyHash", attestation.Evidence.PolicyHash, policyHash)
+	}
+
+	if !outputJSON {
+		printReproduceResult(result)
+	}
+
+	return result, nil
+}
+
+// readAttestationFile reads a local attestation, bare or wrapped with its signing envelope
+func readAttestationFile(path string) (*Attestation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation: %w", err)
+	}
+
+	var wrapped AttestationWithEnvelope
+	if err := json.Unmarshal(data, &wrapped); err == nil && wrapped.Attestation.SchemaVersion != "" {
+		return &wrapped.Attestation, nil
+	}
+
+	var attestation Attestation
+	if err := json.Unmarshal(data, &attestation); err != nil {
+		return nil, fmt.Errorf("failed to parse attestation %s: %w", path, err)
+	}
+	return &attestation, nil
+}
+
+// printReproduceResult prints a human-readable --reproduce report
+func printReproduceResult(result *ReproduceResult) {
+	if result.Match {
+		ui.PrintSuccess(fmt.Sprintf("Attestation reproduces from the current verification of %s", result.ImageRef))
+	} else {
+		ui.PrintError(fmt.Sprintf("Attestation does not match the current verification of %s", result.ImageRef))
+	}
+	fmt.Printf("  Recorded: %s\n", result.RecordedHash)
+	fmt.Printf("  Current:  %s\n", result.CurrentHash)
+
+	if len(result.Differences) > 0 {
+		fmt.Println("\nDifferences (attested -> current):")
+		for _, diff := range result.Differences {
+			fmt.Printf("  %s: %s -> %s\n", diff.Field, diff.Attested, diff.Current)
+		}
+	}
+
+	if !result.Match {
+		data, _ := json.MarshalIndent(result.Canonical, "", "  ")
+		fmt.Println("\nCurrent canonical results (violations and waivers are hashed, not recorded, in the attestation):")
+		fmt.Println(string(data))
+	}
+}
+
+// FormatJSON formats the reproduce result as JSON
+func (r *ReproduceResult) FormatJSON() string {
+	data, _ := json.MarshalIndent(r, "", "  ")
+	return string(data)
+}
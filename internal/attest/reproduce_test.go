@@ -0,0 +1,77 @@
+package attest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// TestReproduce tests that an attestation reproduces from the state it was created from and
+// reports a mismatch once the verification state changes
+func TestReproduce(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	cfg := config.DefaultConfig("test-project")
+
+	stateDir := filepath.Join(".acc", "state")
+	os.MkdirAll(stateDir, 0755)
+	writeState := func(status string, violations []interface{}) {
+		verifyState := VerifyState{
+			ImageRef:  "test:latest",
+			Status:    status,
+			Timestamp: "2025-01-01T00:00:00Z",
+			Result:    map[string]interface{}{"status": status, "violations": violations},
+		}
+		stateData, _ := json.Marshal(verifyState)
+		os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+	}
+
+	writeState("pass", []interface{}{})
+	attested, err := Attest(cfg, "test:latest", AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("Attest failed: %v", err)
+	}
+
+	result, err := Reproduce(attested.OutputPath, true)
+	if err != nil {
+		t.Fatalf("Reproduce failed: %v", err)
+	}
+	if !result.Match || result.CurrentHash != attested.Attestation.Evidence.VerificationResultsHash || len(result.Differences) != 0 {
+		t.Errorf("expected the attestation to reproduce, got %+v", result)
+	}
+
+	// Altered state: a violation now fires
+	writeState("fail", []interface{}{map[string]interface{}{"rule": "no-root-user", "severity": "critical"}})
+	result, err = Reproduce(attested.OutputPath, true)
+	if err != nil {
+		t.Fatalf("Reproduce failed: %v", err)
+	}
+	if result.Match || result.RecordedHash == result.CurrentHash {
+		t.Fatalf("expected a mismatch, got %+v", result)
+	}
+	if len(result.Differences) != 1 || result.Differences[0].Field != "verificationStatus" ||
+		result.Differences[0].Attested != "pass" || result.Differences[0].Current != "fail" {
+		t.Errorf("unexpected differences %+v", result.Differences)
+	}
+	if violations, _ := result.Canonical["violations"].([]map[string]interface{}); len(violations) != 1 {
+		t.Errorf("expected the canonical structure to list the new violation, got %v", result.Canonical["violations"])
+	}
+}
+
+// TestReproduce_NoResultsHash tests that an attestation without a results hash is rejected
+func TestReproduce_NoResultsHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attestation.json")
+	os.WriteFile(path, []byte(`{"schemaVersion":"v0.1","evidence":{}}`), 0644)
+
+	if _, err := Reproduce(path, true); err == nil {
+		t.Fatal("expected an error for an attestation without verificationResultsHash")
+	}
+}
@@ -0,0 +1,27 @@
+package attest
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// subjectNamePattern is a (simplified) OCI reference: [registry[:port]/]repository[:tag][@sha256|sha512:<hex>]
+var subjectNamePattern = regexp.MustCompile(`^` +
+	`(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` + // registry
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` + // repository
+	`(?::[\w][\w.-]{0,127})?` + // tag
+	`(?:@(?:sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128}))?$`) // digest
+
+// validateSubjectName checks that --subject-name is a well-formed image reference.
+// The resolved digest stays authoritative: a name pinned to a different digest is rejected.
+func validateSubjectName(name, digest string) error {
+	if !subjectNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid --subject-name %q: must be an image reference like registry.example.com/org/app[:tag]", name)
+	}
+	if _, nameDigest, ok := oci.SplitDigestRef(name); ok && nameDigest != digest {
+		return fmt.Errorf("invalid --subject-name %q: digest does not match the attested image (%s)", name, oci.QualifiedDigest(digest))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package attest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+func TestValidateSubjectName(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"ghcr.io/org/app", false},
+		{"registry.example.com:5000/org/app:v1.0.0", false},
+		{"app", false},
+		{"ghcr.io/org/app@sha256:" + digest, false},
+		{"ghcr.io/org/app@sha256:" + strings.Repeat("cd", 32), true},
+		{"ghcr.io/Org/App", true},
+		{"ghcr.io/org/app:", true},
+		{"ghcr.io/org app", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		if err := validateSubjectName(tt.name, digest); (err != nil) != tt.wantErr {
+			t.Errorf("validateSubjectName(%q) error = %v, wantErr %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// TestAttest_SubjectName tests that --subject-name replaces the subject name but not the digest
+func TestAttest_SubjectName(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	digest := strings.Repeat("ab", 32)
+	ref := "mirror.example.com/app@sha256:" + digest
+	stateDir := filepath.Join(".acc", "state")
+	os.MkdirAll(stateDir, 0755)
+	stateData, _ := json.Marshal(VerifyState{
+		ImageRef:  ref,
+		Status:    "pass",
+		Timestamp: "2025-01-01T00:00:00Z",
+		Result:    map[string]interface{}{"status": "pass"},
+	})
+	os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+
+	cfg := config.DefaultConfig("test-project")
+	result, err := Attest(cfg, ref, AttestOptions{Version: "v0.1.0", Commit: "abc123", SubjectName: "ghcr.io/org/app:1.0", OutputJSON: true})
+	if err != nil {
+		t.Fatalf("Attest failed: %v", err)
+	}
+	if result.Attestation.Subject.ImageRef != "ghcr.io/org/app:1.0" {
+		t.Errorf("subject name = %q, want ghcr.io/org/app:1.0", result.Attestation.Subject.ImageRef)
+	}
+	if result.Attestation.Subject.ImageDigest != digest {
+		t.Errorf("subject digest = %q, want %s", result.Attestation.Subject.ImageDigest, digest)
+	}
+
+	written, _ := os.ReadFile(result.OutputPath)
+	if !strings.Contains(string(written), `"imageRef": "ghcr.io/org/app:1.0"`) {
+		t.Errorf("expected subject name in written attestation:\n%s", written)
+	}
+
+	if _, err := Attest(cfg, ref, AttestOptions{Version: "v0.1.0", Commit: "abc123", SubjectName: "not a reference", OutputJSON: true}); err == nil {
+		t.Error("expected an invalid subject name to be rejected")
+	}
+}
@@ -0,0 +1,42 @@
+package attest
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvSourceDateEpoch is the reproducible-builds variable (https://reproducible-builds.org/specs/source-date-epoch/)
+// fixing the attestation timestamp to a Unix time
+const EnvSourceDateEpoch = "SOURCE_DATE_EPOCH"
+
+// attestationTimestamp returns the RFC3339 (UTC) attestation timestamp. An explicit
+// override (--timestamp, RFC3339 or Unix seconds) wins, then SOURCE_DATE_EPOCH, then now.
+func attestationTimestamp(override string) (string, error) {
+	if override != "" {
+		t, err := parseTimestamp(override)
+		if err != nil {
+			return "", fmt.Errorf("invalid --timestamp %q: use RFC3339 (2025-01-01T00:00:00Z) or Unix seconds", override)
+		}
+		return t.UTC().Format(time.RFC3339), nil
+	}
+
+	if epoch := os.Getenv(EnvSourceDateEpoch); epoch != "" {
+		seconds, err := strconv.ParseInt(strings.TrimSpace(epoch), 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q: must be Unix seconds", EnvSourceDateEpoch, epoch)
+		}
+		return time.Unix(seconds, 0).UTC().Format(time.RFC3339), nil
+	}
+
+	return time.Now().UTC().Format(time.RFC3339), nil
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,104 @@
+package attest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+func TestAttestationTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		epoch    string
+		override string
+		want     string
+		wantErr  bool
+	}{
+		{"source date epoch", "1700000000", "", "2023-11-14T22:13:20Z", false},
+		{"override wins over epoch", "1700000000", "2025-01-01T00:00:00Z", "2025-01-01T00:00:00Z", false},
+		{"override as unix seconds", "", "0", "1970-01-01T00:00:00Z", false},
+		{"override normalized to UTC", "", "2025-01-01T02:00:00+02:00", "2025-01-01T00:00:00Z", false},
+		{"invalid epoch", "yesterday", "", "", true},
+		{"invalid override", "", "01/01/2025", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvSourceDateEpoch, tt.epoch)
+			got, err := attestationTimestamp(tt.override)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("attestationTimestamp(%q) error = %v, wantErr %t", tt.override, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("attestationTimestamp(%q) = %q, want %q", tt.override, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("defaults to now", func(t *testing.T) {
+		t.Setenv(EnvSourceDateEpoch, "")
+		got, err := attestationTimestamp("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts, err := time.Parse(time.RFC3339, got)
+		if err != nil || time.Since(ts) > time.Minute {
+			t.Errorf("expected a current RFC3339 timestamp, got %q", got)
+		}
+	})
+}
+
+// TestAttest_SourceDateEpoch tests that SOURCE_DATE_EPOCH yields a fixed timestamp, path, and remote tag
+func TestAttest_SourceDateEpoch(t *testing.T) {
+	t.Setenv(EnvSourceDateEpoch, "1700000000")
+
+	attestOnce := func() *AttestResult {
+		t.Helper()
+		tmpDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		if err := os.Chdir(tmpDir); err != nil {
+			t.Fatalf("failed to change to temp dir: %v", err)
+		}
+		defer os.Chdir(originalDir)
+
+		digest := strings.Repeat("ab", 32)
+		ref := "test@sha256:" + digest
+		stateDir := filepath.Join(".acc", "state")
+		os.MkdirAll(stateDir, 0755)
+		stateData, _ := json.Marshal(VerifyState{
+			ImageRef:  ref,
+			Status:    "pass",
+			Timestamp: "2025-01-01T00:00:00Z",
+			Result:    map[string]interface{}{"status": "pass"},
+		})
+		os.WriteFile(filepath.Join(stateDir, "last_verify.json"), stateData, 0644)
+
+		result, err := Attest(config.DefaultConfig("test-project"), ref, AttestOptions{Version: "v0.1.0", Commit: "abc123", OutputJSON: true})
+		if err != nil {
+			t.Fatalf("Attest failed: %v", err)
+		}
+		written, err := os.ReadFile(result.OutputPath)
+		if err != nil {
+			t.Fatalf("failed to read attestation: %v", err)
+		}
+		if !strings.Contains(string(written), `"timestamp": "2023-11-14T22:13:20Z"`) {
+			t.Errorf("expected fixed timestamp in written attestation:\n%s", written)
+		}
+		return result
+	}
+
+	first, second := attestOnce(), attestOnce()
+	if first.Attestation.Timestamp != "2023-11-14T22:13:20Z" {
+		t.Errorf("timestamp = %q, want 2023-11-14T22:13:20Z", first.Attestation.Timestamp)
+	}
+	if first.OutputPath != second.OutputPath {
+		t.Errorf("expected identical attestation paths, got %s and %s", first.OutputPath, second.OutputPath)
+	}
+	if got, want := remoteAttestationTag(&first.Attestation), "attestation-abababababab-2023-11-14T22-13-20Z"; got != want {
+		t.Errorf("remote tag = %q, want %q", got, want)
+	}
+}
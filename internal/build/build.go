@@ -2,6 +2,7 @@ package build
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,19 +10,24 @@ import (
 	"strings"
 
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/pool"
 	"github.com/cloudcwfranck/acc/internal/ui"
 )
 
 // BuildResult represents the output of a build operation
 type BuildResult struct {
-	ImageDigest  string   `json:"imageDigest"`
-	ImageTag     string   `json:"imageTag"`
-	SBOMPath     string   `json:"sbomPath"`
-	Attestations []string `json:"attestations"`
+	ImageDigest  string        `json:"imageDigest"`
+	ImageTag     string        `json:"imageTag"`
+	SBOMPath     string        `json:"sbomPath"`
+	Attestations []string      `json:"attestations"`
+	Cache        *CacheOptions `json:"cache,omitempty"` // layer cache sources/destinations passed to the builder
 }
 
 // Build builds an OCI image and generates SBOM (AGENTS.md Section 2 - acc build)
-func Build(cfg *config.Config, tag string, outputJSON bool) (*BuildResult, error) {
+// labels (--label) are added to the automatic OCI labels; accVersion is recorded as a label when set
+// buildArgs (--build-arg) are passed to the build tool and recorded, with secrets redacted, in the build manifest
+// cache (--cache-from/--cache-to) is passed to the build tool, which must support it, and recorded in the result
+func Build(cfg *config.Config, tag string, labels, buildArgs map[string]string, cache CacheOptions, accVersion string, outputJSON bool) (*BuildResult, error) {
 	if !outputJSON {
 		ui.PrintInfo(fmt.Sprintf("Building image for project '%s'", cfg.Project.Name))
 	}
@@ -42,12 +48,25 @@ func Build(cfg *config.Config, tag string, outputJSON bool) (*BuildResult, error
 		imageTag = fmt.Sprintf("%s/%s:%s", cfg.Registry.Default, cfg.Project.Name, cfg.Build.DefaultTag)
 	}
 
-	buildCmd := exec.Command(buildTool, "build", "-t", imageTag, cfg.Build.Context)
+	subcommand, err := buildCommand(buildTool, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	imageLabels := buildLabels(cfg.Build.Context, accVersion, labels)
+	cmdArgs := append(append(subcommand, "-t", imageTag), labelArgs(imageLabels)...)
+	cmdArgs = append(cmdArgs, buildArgFlags(buildArgs)...)
+	cmdArgs = append(cmdArgs, cacheFlags(cache)...)
+	cmdArgs = append(cmdArgs, cfg.Build.Context)
+	buildCmd := exec.Command(buildTool, cmdArgs...)
 	buildCmd.Stdout = os.Stdout
 	buildCmd.Stderr = os.Stderr
 
 	if !outputJSON {
-		ui.PrintInfo(fmt.Sprintf("Running: %s build -t %s %s", buildTool, imageTag, cfg.Build.Context))
+		ui.PrintInfo(fmt.Sprintf("Running: %s %s -t %s %s (%d labels, %d build args)", buildTool, strings.Join(subcommand, " "), imageTag, cfg.Build.Context, len(imageLabels), len(buildArgs)))
+		if cache.enabled() {
+			ui.PrintInfo(fmt.Sprintf("Layer cache: %d source(s), %d destination(s)", len(cache.From), len(cache.To)))
+		}
 	}
 
 	if err := buildCmd.Run(); err != nil {
@@ -87,12 +106,25 @@ func Build(cfg *config.Config, tag string, outputJSON bool) (*BuildResult, error
 		ui.PrintSuccess(fmt.Sprintf("SBOM generated: %s", sbomPath))
 	}
 
+	// Record build-time image config for acc verify --input-from-manifest (non-fatal)
+	manifestPath, err := writeManifest(buildTool, cfg.Build.Context, imageTag, digest, sbomPath, imageLabels, redactBuildArgs(buildArgs))
+	if err != nil {
+		if !outputJSON {
+			ui.PrintWarning(fmt.Sprintf("Failed to write build manifest: %v", err))
+		}
+	} else if !outputJSON {
+		ui.PrintInfo(fmt.Sprintf("Build manifest: %s", manifestPath))
+	}
+
 	result := &BuildResult{
 		ImageDigest:  digest,
 		ImageTag:     imageTag,
 		SBOMPath:     sbomPath,
 		Attestations: []string{},
 	}
+	if cache.enabled() {
+		result.Cache = &cache
+	}
 
 	return result, nil
 }
@@ -124,22 +156,88 @@ func getImageDigest(buildTool, imageTag string) (string, error) {
 
 // generateSBOM generates an SBOM for the image
 func generateSBOM(cfg *config.Config, imageTag, digest string) (string, error) {
+	sbomDir, err := prepareSBOMGeneration(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// Generate SBOM filename
+	sbomFile := filepath.Join(sbomDir, fmt.Sprintf("%s.%s.json", cfg.Project.Name, cfg.SBOM.Format))
+
+	if err := runSyft(imageTag, "", cfg.SBOM.Format, sbomFile); err != nil {
+		return "", err
+	}
+
+	return sbomFile, nil
+}
+
+// generatePlatformSBOMs generates one SBOM per platform concurrently (for multi-platform images)
+// Each platform writes its own file (<sbom.dir>/<project>.<os>-<arch>[-<variant>].<format>.json),
+// so goroutines never share an output path. Failures are aggregated: the returned map holds
+// every SBOM that was produced and the error names each platform that failed.
+func generatePlatformSBOMs(cfg *config.Config, imageTag string, platforms []string) (map[string]string, error) {
+	sbomDir, err := prepareSBOMGeneration(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deduplicate platforms so two workers never write the same file
+	unique := []string{}
+	seen := make(map[string]bool)
+	for _, platform := range platforms {
+		if !seen[platform] {
+			seen[platform] = true
+			unique = append(unique, platform)
+		}
+	}
+
+	paths := make([]string, len(unique))
+	errs := make([]error, len(unique))
+
+	// At most --concurrency syft processes run at once
+	pool.Default().Each(len(unique), func(i int) {
+		platform := unique[i]
+		sbomFile := filepath.Join(sbomDir, fmt.Sprintf("%s.%s.%s.json", cfg.Project.Name, platformSuffix(platform), cfg.SBOM.Format))
+		if err := runSyft(imageTag, platform, cfg.SBOM.Format, sbomFile); err != nil {
+			errs[i] = fmt.Errorf("platform %s: %w", platform, err)
+			return
+		}
+		paths[i] = sbomFile
+	})
+
+	results := make(map[string]string)
+	for i, platform := range unique {
+		if paths[i] != "" {
+			results[platform] = paths[i]
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// platformSuffix converts a platform (linux/arm64/v8) into a filename-safe suffix (linux-arm64-v8)
+func platformSuffix(platform string) string {
+	return strings.ReplaceAll(platform, "/", "-")
+}
+
+// prepareSBOMGeneration checks for syft and creates the SBOM directory (sbom.dir, default .acc/sbom)
+func prepareSBOMGeneration(cfg *config.Config) (string, error) {
 	// Check for syft (SBOM generator)
 	if _, err := exec.LookPath("syft"); err != nil {
 		return "", fmt.Errorf("syft not found - required for SBOM generation\n\nRemediation:\n  - Install syft: https://github.com/anchore/syft#installation\n  - Or use: curl -sSfL https://raw.githubusercontent.com/anchore/syft/main/install.sh | sh -s -- -b /usr/local/bin")
 	}
 
-	// Create .acc/sbom directory if it doesn't exist
-	sbomDir := filepath.Join(".acc", "sbom")
+	// Create the SBOM directory if it doesn't exist
+	sbomDir := cfg.SBOMDir()
 	if err := os.MkdirAll(sbomDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create SBOM directory: %w", err)
 	}
 
-	// Generate SBOM filename
-	sbomFormat := cfg.SBOM.Format
-	sbomFile := filepath.Join(sbomDir, fmt.Sprintf("%s.%s.json", cfg.Project.Name, sbomFormat))
+	return sbomDir, nil
+}
 
-	// Run syft to generate SBOM
+// runSyft runs syft for an image (optionally for a specific platform) and writes the SBOM to sbomFile
+func runSyft(imageTag, platform, sbomFormat, sbomFile string) error {
 	var formatArg string
 	switch sbomFormat {
 	case "spdx":
@@ -150,12 +248,17 @@ func generateSBOM(cfg *config.Config, imageTag, digest string) (string, error) {
 		formatArg = "spdx-json"
 	}
 
-	cmd := exec.Command("syft", imageTag, "-o", fmt.Sprintf("%s=%s", formatArg, sbomFile))
+	args := []string{imageTag, "-o", fmt.Sprintf("%s=%s", formatArg, sbomFile)}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+
+	cmd := exec.Command("syft", args...)
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return "", fmt.Errorf("syft failed: %w\nOutput: %s", err, string(output))
+		return fmt.Errorf("syft failed: %w\nOutput: %s", err, string(output))
 	}
 
-	return sbomFile, nil
+	return nil
 }
 
 // FormatJSON formats build result as JSON
@@ -3,6 +3,7 @@ package build
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cloudcwfranck/acc/internal/config"
@@ -51,7 +52,7 @@ func TestBuild_SBOMVerification(t *testing.T) {
 
 	// Test: Build should fail when container tools are not available
 	// This documents the expected contract: Build MUST produce SBOM or fail
-	_, err = Build(cfg, "test-build:latest", true)
+	_, err = Build(cfg, "test-build:latest", nil, nil, CacheOptions{}, "", true)
 
 	// We expect Build to fail in test environment (no docker/podman)
 	if err == nil {
@@ -150,3 +151,79 @@ func TestSBOMPath_Consistency(t *testing.T) {
 		}
 	}
 }
+
+// TestGeneratePlatformSBOMs tests that per-platform SBOMs are written to distinct files
+// and that one platform's failure is surfaced without dropping the others
+func TestGeneratePlatformSBOMs(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	// Fake syft: writes the output file named by "-o fmt=path", fails for linux/s390x
+	binDir := filepath.Join(tmpDir, "bin")
+	os.MkdirAll(binDir, 0755)
+	script := `#!/bin/sh
+out=""
+platform=""
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -o) out="${2#*=}"; shift ;;
+    --platform) platform="$2"; shift ;;
+  esac
+  shift
+done
+if [ "$platform" = "linux/s390x" ]; then
+  echo "unsupported platform" >&2
+  exit 1
+fi
+echo "{\"platform\":\"$platform\"}" > "$out"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "syft"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake syft: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "demo"},
+		SBOM:    config.SBOMConfig{Format: "spdx"},
+	}
+
+	t.Run("two platforms", func(t *testing.T) {
+		sboms, err := generatePlatformSBOMs(cfg, "demo:latest", []string{"linux/amd64", "linux/arm64"})
+		if err != nil {
+			t.Fatalf("generatePlatformSBOMs failed: %v", err)
+		}
+		for platform, want := range map[string]string{
+			"linux/amd64": filepath.Join(".acc", "sbom", "demo.linux-amd64.spdx.json"),
+			"linux/arm64": filepath.Join(".acc", "sbom", "demo.linux-arm64.spdx.json"),
+		} {
+			if sboms[platform] != want {
+				t.Errorf("SBOM for %s = %q, want %q", platform, sboms[platform], want)
+			}
+			data, err := os.ReadFile(want)
+			if err != nil {
+				t.Fatalf("SBOM for %s not written: %v", platform, err)
+			}
+			if !strings.Contains(string(data), platform) {
+				t.Errorf("SBOM for %s has wrong content: %s", platform, data)
+			}
+		}
+	})
+
+	t.Run("one platform fails", func(t *testing.T) {
+		sboms, err := generatePlatformSBOMs(cfg, "demo:latest", []string{"linux/amd64", "linux/s390x"})
+		if err == nil {
+			t.Fatal("expected error for failing platform")
+		}
+		if !strings.Contains(err.Error(), "linux/s390x") {
+			t.Errorf("error should name the failing platform, got: %v", err)
+		}
+		if _, ok := sboms["linux/amd64"]; !ok {
+			t.Error("successful platform SBOM should still be returned")
+		}
+		if _, ok := sboms["linux/s390x"]; ok {
+			t.Error("failed platform should not have an SBOM")
+		}
+	})
+}
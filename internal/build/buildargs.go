@@ -0,0 +1,80 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactedBuildArg replaces the value of a secret-looking build arg in the build manifest
+const redactedBuildArg = "[REDACTED]"
+
+// buildArgKeyPattern matches Dockerfile ARG names such as GO_VERSION or http_proxy
+var buildArgKeyPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+
+// secretBuildArgMarkers are substrings of build arg names whose values are not recorded
+// (the same markers the CIS policy uses for secret-looking ENV names)
+var secretBuildArgMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "PRIVATE_KEY", "ACCESS_KEY", "CREDENTIAL"}
+
+// ParseBuildArgs parses repeatable --build-arg key=value flags. Keys must be valid ARG
+// names; values may be empty but not span lines.
+func ParseBuildArgs(flags []string) (map[string]string, error) {
+	args := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid build arg %q: expected key=value", flag)
+		}
+		if !buildArgKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid build arg name %q: use letters, digits, '_', '.', or '-', not starting with a digit", key)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid build arg %q: value must be a single line", key)
+		}
+		args[key] = value
+	}
+	return args, nil
+}
+
+// isSecretBuildArg reports whether a build arg name looks like it carries a secret
+func isSecretBuildArg(key string) bool {
+	upper := strings.ToUpper(key)
+	for _, marker := range secretBuildArgMarkers {
+		if strings.Contains(upper, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactBuildArgs returns build args for the build manifest, with secret-looking values
+// replaced by [REDACTED]. The keys are kept so provenance shows which args were set.
+func redactBuildArgs(args map[string]string) map[string]string {
+	if len(args) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(args))
+	for key, value := range args {
+		if isSecretBuildArg(key) {
+			value = redactedBuildArg
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+// buildArgFlags renders build args as --build-arg flags for docker/podman/buildah, sorted by key
+func buildArgFlags(args map[string]string) []string {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		flags = append(flags, "--build-arg", key+"="+args[key])
+	}
+	return flags
+}
@@ -0,0 +1,92 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+func TestParseBuildArgs(t *testing.T) {
+	args, err := ParseBuildArgs([]string{"GO_VERSION=1.22", "http_proxy=", "LDFLAGS=-X main.v=1"})
+	if err != nil {
+		t.Fatalf("ParseBuildArgs failed: %v", err)
+	}
+	want := map[string]string{"GO_VERSION": "1.22", "http_proxy": "", "LDFLAGS": "-X main.v=1"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("build args = %v, want %v", args, want)
+	}
+
+	for _, flag := range []string{"GO_VERSION", "=1.22", "1VERSION=x", "BAD KEY=x", "NOTE=a\nb"} {
+		if _, err := ParseBuildArgs([]string{flag}); err == nil {
+			t.Errorf("expected error for build arg %q", flag)
+		}
+	}
+}
+
+// TestBuild_BuildArgs tests that build args reach the build command unredacted and
+// that secret-looking values are redacted in the build manifest
+func TestBuild_BuildArgs(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	binDir := t.TempDir()
+	argsLog := filepath.Join(binDir, "docker.log")
+	docker := `#!/bin/sh
+echo "$@" >> "` + argsLog + `"
+case "$1" in
+  inspect)
+    if [ "$2" = "--format={{.Id}}" ]; then echo "sha256:` + strings.Repeat("cd", 32) + `"; else echo '[{"Config":{"User":"app","Labels":{}}}]'; fi ;;
+esac
+`
+	syft := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  [ "$1" = "-o" ] && echo '{}' > "${2#*=}"
+  shift
+done
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+	os.WriteFile(filepath.Join(binDir, "syft"), []byte(syft), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.Config{
+		Project:  config.ProjectConfig{Name: "demo"},
+		SBOM:     config.SBOMConfig{Format: "spdx"},
+		Build:    config.BuildConfig{Context: ".", DefaultTag: "latest"},
+		Registry: config.RegistryConfig{Default: "localhost"},
+	}
+	buildArgs := map[string]string{"GO_VERSION": "1.22", "NPM_TOKEN": "s3cr3t", "db_password": "hunter2"}
+	result, err := Build(cfg, "demo:latest", nil, buildArgs, CacheOptions{}, "", true)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	log, _ := os.ReadFile(argsLog)
+	wantArgs := "--build-arg GO_VERSION=1.22 --build-arg NPM_TOKEN=s3cr3t --build-arg db_password=hunter2 ."
+	if !strings.Contains(string(log), wantArgs) {
+		t.Errorf("expected build args %q, docker calls:\n%s", wantArgs, log)
+	}
+
+	manifest, err := LoadManifest(result.ImageDigest)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	wantManifestArgs := map[string]string{
+		"GO_VERSION":  "1.22",
+		"NPM_TOKEN":   redactedBuildArg,
+		"db_password": redactedBuildArg,
+	}
+	if !reflect.DeepEqual(manifest.BuildArgs, wantManifestArgs) {
+		t.Errorf("manifest build args = %v, want %v", manifest.BuildArgs, wantManifestArgs)
+	}
+	data, _ := os.ReadFile(ManifestPath(result.ImageDigest))
+	if strings.Contains(string(data), "s3cr3t") || strings.Contains(string(data), "hunter2") {
+		t.Errorf("secret build arg value leaked into the manifest:\n%s", data)
+	}
+}
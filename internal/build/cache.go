@@ -0,0 +1,73 @@
+package build
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CacheOptions are the layer cache sources and destinations passed to the builder
+// (--cache-from / --cache-to). Docker takes BuildKit cache specs (type=registry,ref=...,
+// type=gha, type=local,src=...); podman and buildah take image repositories.
+type CacheOptions struct {
+	From []string `json:"from,omitempty"`
+	To   []string `json:"to,omitempty"`
+}
+
+// enabled reports whether any cache option is set
+func (c CacheOptions) enabled() bool {
+	return len(c.From) > 0 || len(c.To) > 0
+}
+
+// ValidateCacheOptions checks each cache spec is a non-empty single line
+func ValidateCacheOptions(opts CacheOptions) error {
+	for _, spec := range append(append([]string{}, opts.From...), opts.To...) {
+		if strings.TrimSpace(spec) == "" {
+			return fmt.Errorf("cache spec must not be empty")
+		}
+		if strings.ContainsAny(spec, "\r\n") {
+			return fmt.Errorf("invalid cache spec %q: must be a single line", spec)
+		}
+	}
+	return nil
+}
+
+// buildCommand returns the build subcommand for buildTool. With cache options docker builds
+// with BuildKit (buildx build --load, so the image is in the local store for inspect and syft),
+// after checking the builder supports them.
+func buildCommand(buildTool string, cache CacheOptions) ([]string, error) {
+	if !cache.enabled() {
+		return []string{"build"}, nil
+	}
+
+	switch buildTool {
+	case "docker":
+		if err := exec.Command("docker", "buildx", "version").Run(); err != nil {
+			return nil, fmt.Errorf("--cache-from/--cache-to need BuildKit, but 'docker buildx' is not available\n\nRemediation:\n  - Install the buildx plugin: https://docs.docker.com/build/architecture/#install-buildx\n  - For cache exporters other than inline or registry, use a docker-container builder: docker buildx create --use")
+		}
+		return []string{"buildx", "build", "--load"}, nil
+	default:
+		for _, spec := range append(append([]string{}, cache.From...), cache.To...) {
+			if strings.Contains(spec, "type=") {
+				return nil, fmt.Errorf("%s does not support BuildKit cache spec %q\n\nRemediation:\n  - Pass an image repository instead, e.g. --cache-from registry.example.com/app/cache\n  - Or build with docker buildx", buildTool, spec)
+			}
+		}
+		help, err := exec.Command(buildTool, "build", "--help").CombinedOutput()
+		if err != nil || !strings.Contains(string(help), "--cache-to") {
+			return nil, fmt.Errorf("%s build does not support --cache-from/--cache-to\n\nRemediation:\n  - Upgrade to podman 4.1+ or buildah 1.27+\n  - Or build with docker buildx", buildTool)
+		}
+		return []string{"build"}, nil
+	}
+}
+
+// cacheFlags renders cache options as --cache-from/--cache-to flags, in the order given
+func cacheFlags(cache CacheOptions) []string {
+	flags := make([]string, 0, 2*(len(cache.From)+len(cache.To)))
+	for _, spec := range cache.From {
+		flags = append(flags, "--cache-from", spec)
+	}
+	for _, spec := range cache.To {
+		flags = append(flags, "--cache-to", spec)
+	}
+	return flags
+}
@@ -0,0 +1,120 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// TestBuild_CacheFlags tests that --cache-from/--cache-to reach docker buildx in order and
+// are recorded in the build result
+func TestBuild_CacheFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+
+	binDir := t.TempDir()
+	argsLog := filepath.Join(binDir, "docker.log")
+	docker := `#!/bin/sh
+echo "$@" >> "` + argsLog + `"
+case "$1" in
+  buildx)
+    [ "$2" = "version" ] && [ -n "$NO_BUILDX" ] && exit 1 ;;
+  inspect)
+    if [ "$2" = "--format={{.Id}}" ]; then echo "sha256:` + strings.Repeat("ef", 32) + `"; else echo '[{"Config":{"User":"app","Labels":{}}}]'; fi ;;
+esac
+exit 0
+`
+	syft := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  [ "$1" = "-o" ] && echo '{}' > "${2#*=}"
+  shift
+done
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+	os.WriteFile(filepath.Join(binDir, "syft"), []byte(syft), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.Config{
+		Project:  config.ProjectConfig{Name: "demo"},
+		SBOM:     config.SBOMConfig{Format: "spdx"},
+		Build:    config.BuildConfig{Context: ".", DefaultTag: "latest"},
+		Registry: config.RegistryConfig{Default: "localhost"},
+	}
+	cache := CacheOptions{
+		From: []string{"type=registry,ref=registry.example.com/demo:buildcache", "type=gha"},
+		To:   []string{"type=registry,ref=registry.example.com/demo:buildcache,mode=max"},
+	}
+	result, err := Build(cfg, "demo:latest", nil, nil, cache, "", true)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	log, _ := os.ReadFile(argsLog)
+	wantArgs := "--cache-from type=registry,ref=registry.example.com/demo:buildcache --cache-from type=gha --cache-to type=registry,ref=registry.example.com/demo:buildcache,mode=max ."
+	if !strings.Contains(string(log), "buildx build --load -t demo:latest") || !strings.Contains(string(log), wantArgs) {
+		t.Errorf("expected a buildx build with cache flags %q, docker calls:\n%s", wantArgs, log)
+	}
+	if result.Cache == nil || !reflect.DeepEqual(*result.Cache, cache) {
+		t.Errorf("expected the cache options in the result, got %+v", result.Cache)
+	}
+	if !strings.Contains(result.FormatJSON(), `"cache"`) {
+		t.Errorf("expected cache in the JSON result, got %s", result.FormatJSON())
+	}
+
+	// Without cache options the plain build command is used and nothing is recorded
+	os.Remove(argsLog)
+	result, err = Build(cfg, "demo:latest", nil, nil, CacheOptions{}, "", true)
+	if err != nil || result.Cache != nil {
+		t.Fatalf("expected a build without cache, got %+v, %v", result, err)
+	}
+	if log, _ := os.ReadFile(argsLog); strings.Contains(string(log), "buildx") || strings.Contains(string(log), "--cache") {
+		t.Errorf("expected no buildx or cache flags, docker calls:\n%s", log)
+	}
+
+	// A docker without buildx is rejected before building
+	t.Setenv("NO_BUILDX", "1")
+	if _, err := Build(cfg, "demo:latest", nil, nil, cache, "", true); err == nil || !strings.Contains(err.Error(), "buildx") {
+		t.Errorf("expected an error naming buildx, got %v", err)
+	}
+}
+
+// TestBuildCommand_Podman tests podman cache support detection and BuildKit spec rejection
+func TestBuildCommand_Podman(t *testing.T) {
+	binDir := t.TempDir()
+	podman := `#!/bin/sh
+[ -n "$OLD_PODMAN" ] && { echo "      --cache-from strings"; exit 0; }
+echo "      --cache-from strings"
+echo "      --cache-to strings"
+`
+	os.WriteFile(filepath.Join(binDir, "podman"), []byte(podman), 0755)
+	t.Setenv("PATH", binDir)
+
+	cache := CacheOptions{From: []string{"registry.example.com/demo/cache"}, To: []string{"registry.example.com/demo/cache"}}
+	if args, err := buildCommand("podman", cache); err != nil || !reflect.DeepEqual(args, []string{"build"}) {
+		t.Errorf("expected podman build, got %v, %v", args, err)
+	}
+	if _, err := buildCommand("podman", CacheOptions{From: []string{"type=gha"}}); err == nil || !strings.Contains(err.Error(), "type=gha") {
+		t.Errorf("expected a BuildKit spec to be rejected for podman, got %v", err)
+	}
+	t.Setenv("OLD_PODMAN", "1")
+	if _, err := buildCommand("podman", cache); err == nil {
+		t.Error("expected a podman without --cache-to to be rejected")
+	}
+}
+
+func TestValidateCacheOptions(t *testing.T) {
+	if err := ValidateCacheOptions(CacheOptions{From: []string{"type=gha"}, To: []string{"type=gha,mode=max"}}); err != nil {
+		t.Errorf("expected valid cache options, got %v", err)
+	}
+	for _, spec := range []string{"", " ", "type=gha\nmode=max"} {
+		if err := ValidateCacheOptions(CacheOptions{To: []string{spec}}); err == nil {
+			t.Errorf("expected error for cache spec %q", spec)
+		}
+	}
+}
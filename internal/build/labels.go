@@ -0,0 +1,108 @@
+package build
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Labels acc build adds automatically (OCI image annotation keys, plus the acc version)
+const (
+	LabelRevision   = "org.opencontainers.image.revision"
+	LabelSource     = "org.opencontainers.image.source"
+	LabelCreated    = "org.opencontainers.image.created"
+	LabelACCVersion = "com.github.cloudcwfranck.acc.version"
+)
+
+// labelKeyPattern matches label keys such as org.opencontainers.image.vendor or team
+var labelKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._/-]*[a-zA-Z0-9])?$`)
+
+// ParseLabels parses repeatable --label key=value flags. Keys must be alphanumeric with
+// '.', '_', '-', or '/' separators; values may be empty but not span lines.
+func ParseLabels(flags []string) (map[string]string, error) {
+	labels := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", flag)
+		}
+		if !labelKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid label key %q: use letters, digits, '.', '_', '-', or '/' (e.g. org.example.team)", key)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, fmt.Errorf("invalid label %q: value must be a single line", key)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// buildLabels returns the labels passed to the build: OCI labels derived from git in
+// buildContext (when it is a repository), the build time, and the acc version, overridden
+// by user labels. The build time honors SOURCE_DATE_EPOCH for reproducible builds.
+func buildLabels(buildContext, accVersion string, user map[string]string) map[string]string {
+	labels := map[string]string{LabelCreated: buildTime()}
+	if accVersion != "" {
+		labels[LabelACCVersion] = accVersion
+	}
+	if revision := gitOutput(buildContext, "rev-parse", "HEAD"); revision != "" {
+		labels[LabelRevision] = revision
+	}
+	if source := gitOutput(buildContext, "remote", "get-url", "origin"); source != "" {
+		labels[LabelSource] = redactURL(source)
+	}
+	for key, value := range user {
+		labels[key] = value
+	}
+	return labels
+}
+
+// buildTime returns SOURCE_DATE_EPOCH (if valid) or the current time, as RFC3339 UTC
+func buildTime() string {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+		}
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// gitOutput runs git in dir and returns its trimmed output, or "" if git or the repository is unavailable
+func gitOutput(dir string, args ...string) string {
+	output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// redactURL drops credentials from a remote URL (https://token@host/...) so they are not baked into the image
+func redactURL(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// labelArgs renders labels as --label flags for docker/podman/buildah, sorted by key
+func labelArgs(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		args = append(args, "--label", key+"="+labels[key])
+	}
+	return args
+}
@@ -0,0 +1,126 @@
+package build
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels([]string{"team=payments", "org.example.tier=", "url=https://x.example/?a=b"})
+	if err != nil {
+		t.Fatalf("ParseLabels failed: %v", err)
+	}
+	want := map[string]string{"team": "payments", "org.example.tier": "", "url": "https://x.example/?a=b"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("labels = %v, want %v", labels, want)
+	}
+
+	for _, flag := range []string{"team", "=payments", "bad key=x", ".team=x", "team=a\nb"} {
+		if _, err := ParseLabels([]string{flag}); err == nil {
+			t.Errorf("expected error for label %q", flag)
+		}
+	}
+}
+
+// TestBuild_Labels tests that labels reach the build command and the build manifest
+func TestBuild_Labels(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tmpDir)
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	binDir := t.TempDir()
+	argsLog := filepath.Join(binDir, "docker.log")
+	docker := `#!/bin/sh
+echo "$@" >> "` + argsLog + `"
+case "$1" in
+  inspect)
+    if [ "$2" = "--format={{.Id}}" ]; then echo "sha256:` + strings.Repeat("ab", 32) + `"; else echo '[{"Config":{"User":"app","Labels":{"team":"payments"}}}]'; fi ;;
+esac
+`
+	syft := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  [ "$1" = "-o" ] && echo '{}' > "${2#*=}"
+  shift
+done
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+	os.WriteFile(filepath.Join(binDir, "syft"), []byte(syft), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.Config{
+		Project:  config.ProjectConfig{Name: "demo"},
+		SBOM:     config.SBOMConfig{Format: "spdx"},
+		Build:    config.BuildConfig{Context: ".", DefaultTag: "latest"},
+		Registry: config.RegistryConfig{Default: "localhost"},
+	}
+	labels := map[string]string{"team": "payments", LabelCreated: "2025-01-01T00:00:00Z"}
+	result, err := Build(cfg, "demo:latest", labels, nil, CacheOptions{}, "v1.2.3", true)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	log, _ := os.ReadFile(argsLog)
+	wantArgs := "build -t demo:latest --label com.github.cloudcwfranck.acc.version=v1.2.3 --label org.opencontainers.image.created=2025-01-01T00:00:00Z --label team=payments ."
+	if !strings.Contains(string(log), wantArgs) {
+		t.Errorf("expected build command %q, docker calls:\n%s", wantArgs, log)
+	}
+
+	manifest, err := LoadManifest(result.ImageDigest)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	wantLabels := map[string]string{
+		"team":          "payments",
+		LabelCreated:    "2025-01-01T00:00:00Z",
+		LabelACCVersion: "v1.2.3",
+	}
+	if !reflect.DeepEqual(manifest.Labels, wantLabels) {
+		t.Errorf("manifest labels = %v, want %v", manifest.Labels, wantLabels)
+	}
+}
+
+func TestBuildLabels_Git(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=acc", "-c", "user.email=acc@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	git("init", "-q")
+	git("remote", "add", "origin", "https://token@github.com/org/app.git")
+	git("commit", "-q", "--allow-empty", "-m", "init")
+
+	labels := buildLabels(dir, "", nil)
+	if len(labels[LabelRevision]) != 40 {
+		t.Errorf("expected a commit SHA revision label, got %q", labels[LabelRevision])
+	}
+	if labels[LabelSource] != "https://github.com/org/app.git" {
+		t.Errorf("source = %q, want the remote without credentials", labels[LabelSource])
+	}
+	if labels[LabelCreated] != "2023-11-14T22:13:20Z" {
+		t.Errorf("created = %q, want SOURCE_DATE_EPOCH time", labels[LabelCreated])
+	}
+	if _, ok := labels[LabelACCVersion]; ok {
+		t.Error("expected no acc version label without a version")
+	}
+
+	// Outside a repository there are no git labels
+	if labels := buildLabels(t.TempDir(), "", nil); labels[LabelRevision] != "" || labels[LabelSource] != "" {
+		t.Errorf("expected no git labels outside a repository, got %v", labels)
+	}
+}
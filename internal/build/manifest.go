@@ -0,0 +1,157 @@
+package build
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/state"
+)
+
+// Manifest records what acc build captured about an image, keyed by digest
+// (.acc/state/build/<digest>.json). acc verify --input-from-manifest builds the
+// policy input from it instead of inspecting the image.
+type Manifest struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	ImageRef      string            `json:"imageRef"`
+	ImageDigest   string            `json:"imageDigest"`
+	CreatedAt     string            `json:"createdAt"`
+	BaseImage     string            `json:"baseImage,omitempty"` // final-stage FROM of the Dockerfile
+	SBOMPath      string            `json:"sbomPath,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`    // labels passed to the build (--label and automatic OCI labels)
+	BuildArgs     map[string]string `json:"buildArgs,omitempty"` // --build-arg values, secret-looking ones redacted
+	Config        ManifestConfig    `json:"config"`
+}
+
+// ManifestConfig is the image configuration captured at build time
+type ManifestConfig struct {
+	User         string              `json:"User"`
+	Labels       map[string]string   `json:"Labels"`
+	Env          []string            `json:"Env,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Healthcheck  *oci.HealthConfig   `json:"Healthcheck,omitempty"`
+}
+
+// ManifestPath returns .acc/state/build/<digest>.json (digest with or without the sha256: prefix;
+// other algorithms are kept as <algorithm>-<hex>)
+func ManifestPath(digest string) string {
+	return filepath.Join(".acc", "state", "build", oci.DigestFileName(oci.NormalizeDigest(digest))+".json")
+}
+
+// LoadManifest reads the build manifest for digest
+func LoadManifest(digest string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(digest))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid build manifest %s: %w", ManifestPath(digest), err)
+	}
+	return &manifest, nil
+}
+
+// writeManifest captures the built image's config and base image into its build manifest
+// buildArgs must already be redacted (redactBuildArgs)
+func writeManifest(buildTool, buildContext, imageTag, digest, sbomPath string, labels, buildArgs map[string]string) (string, error) {
+	config, err := inspectBuiltConfig(buildTool, imageTag)
+	if err != nil {
+		return "", err
+	}
+
+	manifest := Manifest{
+		SchemaVersion: "v1",
+		ImageRef:      imageTag,
+		ImageDigest:   digest,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		BaseImage:     baseImageFromDockerfile(filepath.Join(buildContext, "Dockerfile")),
+		SBOMPath:      sbomPath,
+		Labels:        labels,
+		BuildArgs:     buildArgs,
+		Config:        *config,
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal build manifest: %w", err)
+	}
+
+	path := ManifestPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create build state directory: %w", err)
+	}
+	if err := state.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write build manifest: %w", err)
+	}
+	return path, nil
+}
+
+// inspectBuiltConfig reads the image config with the build tool (docker/podman inspect format)
+func inspectBuiltConfig(buildTool, imageTag string) (*ManifestConfig, error) {
+	output, err := exec.Command(buildTool, "inspect", imageTag).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	var inspectOutput []struct {
+		Config ManifestConfig `json:"Config"`
+	}
+	if err := json.Unmarshal(output, &inspectOutput); err != nil || len(inspectOutput) == 0 {
+		return nil, fmt.Errorf("unrecognized %s inspect output", buildTool)
+	}
+
+	config := inspectOutput[0].Config
+	if config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	return &config, nil
+}
+
+// baseImageFromDockerfile returns the image named by the final FROM instruction,
+// following stage aliases (FROM build AS final), or "" when the Dockerfile is
+// missing or the final stage builds FROM scratch
+func baseImageFromDockerfile(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	base := ""
+	stages := make(map[string]string) // stage alias -> image it resolves to
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		// Skip flags such as --platform=linux/amd64
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		base = args[0]
+		if image, ok := stages[strings.ToLower(base)]; ok {
+			base = image
+		}
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = base
+		}
+	}
+
+	if base == "scratch" {
+		return ""
+	}
+	return base
+}
@@ -0,0 +1,79 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaseImageFromDockerfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		want       string
+	}{
+		{name: "single stage", dockerfile: "FROM alpine:3.19\nRUN true\n", want: "alpine:3.19"},
+		{name: "platform flag", dockerfile: "FROM --platform=linux/amd64 debian:12\n", want: "debian:12"},
+		{name: "multi-stage final image", dockerfile: "FROM golang:1.24 AS build\nRUN go build\nFROM gcr.io/distroless/static\nCOPY --from=build /app /app\n", want: "gcr.io/distroless/static"},
+		{name: "final stage alias", dockerfile: "FROM node:20 AS base\nFROM base AS final\n", want: "node:20"},
+		{name: "scratch", dockerfile: "FROM golang:1.24 AS build\nFROM scratch\n", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "Dockerfile")
+			os.WriteFile(path, []byte(tt.dockerfile), 0644)
+			if got := baseImageFromDockerfile(path); got != tt.want {
+				t.Errorf("baseImageFromDockerfile = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := baseImageFromDockerfile(filepath.Join(t.TempDir(), "missing")); got != "" {
+		t.Errorf("expected empty base image for missing Dockerfile, got %q", got)
+	}
+}
+
+// TestWriteManifest tests that the build manifest captures the inspected config and round-trips
+func TestWriteManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	binDir := t.TempDir()
+	dockerScript := `#!/bin/sh
+echo '[{"Config":{"User":"app","Labels":{"team":"payments"},"Env":["PATH=/usr/bin","PORT=8080"],"ExposedPorts":{"8080/tcp":{}}}}]'
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(dockerScript), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	os.WriteFile("Dockerfile", []byte("FROM alpine:3.19\n"), 0644)
+
+	digest := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	path, err := writeManifest("docker", ".", "demo:latest", digest, ".acc/sbom/demo.spdx.json", nil, nil)
+	if err != nil {
+		t.Fatalf("writeManifest failed: %v", err)
+	}
+	if path != filepath.Join(".acc", "state", "build", digest+".json") {
+		t.Errorf("unexpected manifest path %s", path)
+	}
+
+	manifest, err := LoadManifest("sha256:" + digest)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+
+	wantConfig := ManifestConfig{
+		User:         "app",
+		Labels:       map[string]string{"team": "payments"},
+		Env:          []string{"PATH=/usr/bin", "PORT=8080"},
+		ExposedPorts: map[string]struct{}{"8080/tcp": {}},
+	}
+	if !reflect.DeepEqual(manifest.Config, wantConfig) {
+		t.Errorf("manifest config = %+v, want %+v", manifest.Config, wantConfig)
+	}
+	if manifest.BaseImage != "alpine:3.19" || manifest.ImageDigest != digest || manifest.ImageRef != "demo:latest" {
+		t.Errorf("unexpected manifest metadata: %+v", manifest)
+	}
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EnvDir is the environment variable naming a shared cache directory
+const EnvDir = "ACC_CACHE_DIR"
+
+// Namespaces acc stores cache entries under (<dir>/<namespace>/...)
+const (
+	NamespacePolicyEval  = "policy-eval"
+	NamespaceImageConfig = "image-config"
+)
+
+// Namespaces lists every namespace acc writes; acc clean --cache only prunes these in a
+// shared cache directory, which may hold other tools' files
+var Namespaces = []string{NamespacePolicyEval, NamespaceImageConfig}
+
+// staleLockAge is how old a lock file must be before it is treated as
+// abandoned by a crashed run and removed
+const staleLockAge = 30 * time.Second
+
+var (
+	mu  sync.RWMutex
+	dir string // set by --cache-dir; takes precedence over ACC_CACHE_DIR
+)
+
+// SetDir sets the cache directory (--cache-dir). An empty dir falls back to ACC_CACHE_DIR.
+func SetDir(d string) {
+	mu.Lock()
+	defer mu.Unlock()
+	dir = d
+}
+
+// Dir returns the cache directory, or "" when caching is disabled
+// Caching is opt-in: --cache-dir or ACC_CACHE_DIR must be set
+func Dir() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if dir != "" {
+		return dir
+	}
+	return os.Getenv(EnvDir)
+}
+
+// Key returns a content hash over parts, used as the cache key
+// Each part is length-prefixed so ("ab","c") and ("a","bc") differ
+func Key(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// entryPath returns <dir>/<namespace>/<key[:2]>/<key>.json
+func entryPath(cacheDir, namespace, key string) string {
+	return filepath.Join(cacheDir, namespace, key[:2], key+".json")
+}
+
+// Get loads the entry for key into v. It reports false on a miss, when caching
+// is disabled, or when the entry cannot be decoded (treated as a miss).
+func Get(namespace, key string, v interface{}) bool {
+	cacheDir := Dir()
+	if cacheDir == "" || len(key) < 2 {
+		return false
+	}
+
+	data, err := os.ReadFile(entryPath(cacheDir, namespace, key))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Put stores v under key. It is a no-op when caching is disabled.
+// Writers take a per-entry lock file so only one run stores a given key; the
+// entry is written to a temp file and renamed into place, so readers never
+// observe a partial entry. Keys are content hashes, so a writer that finds the
+// lock held can skip the write: the holder is storing identical data.
+func Put(namespace, key string, v interface{}) error {
+	cacheDir := Dir()
+	if cacheDir == "" || len(key) < 2 {
+		return nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	path := entryPath(cacheDir, namespace, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	unlock, ok := lock(path + ".lock")
+	if !ok {
+		return nil
+	}
+	defer unlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	return nil
+}
+
+// lock creates lockPath exclusively. It reports false when another writer
+// holds a fresh lock; stale locks are removed and retried once.
+func lock(lockPath string) (func(), bool) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, true
+		}
+		info, statErr := os.Stat(lockPath)
+		if statErr != nil || time.Since(info.ModTime()) < staleLockAge {
+			return nil, false
+		}
+		os.Remove(lockPath)
+	}
+	return nil, false
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDir_Precedence(t *testing.T) {
+	t.Setenv(EnvDir, "/from/env")
+	defer SetDir("")
+
+	SetDir("")
+	if got := Dir(); got != "/from/env" {
+		t.Errorf("expected env dir, got %q", got)
+	}
+
+	SetDir("/from/flag")
+	if got := Dir(); got != "/from/flag" {
+		t.Errorf("expected flag dir to take precedence, got %q", got)
+	}
+}
+
+func TestKey_LengthPrefixed(t *testing.T) {
+	if Key([]byte("ab"), []byte("c")) == Key([]byte("a"), []byte("bc")) {
+		t.Error("expected keys over different part boundaries to differ")
+	}
+	if Key([]byte("a")) != Key([]byte("a")) {
+		t.Error("expected keys to be deterministic")
+	}
+}
+
+func TestPutGet(t *testing.T) {
+	t.Setenv(EnvDir, "")
+	dir := t.TempDir()
+	SetDir(dir)
+	defer SetDir("")
+
+	key := Key([]byte("input"))
+	var got []string
+	if Get("test", key, &got) {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	if err := Put("test", key, []string{"a", "b"}); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if !Get("test", key, &got) {
+		t.Fatal("expected hit after Put")
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("unexpected cached value: %v", got)
+	}
+
+	// No temp or lock files may be left behind next to the entry
+	entries, _ := os.ReadDir(filepath.Join(dir, "test", key[:2]))
+	if len(entries) != 1 {
+		t.Errorf("expected exactly one entry file, found %d", len(entries))
+	}
+}
+
+func TestPut_LockHeldSkipsWrite(t *testing.T) {
+	t.Setenv(EnvDir, "")
+	dir := t.TempDir()
+	SetDir(dir)
+	defer SetDir("")
+
+	key := Key([]byte("input"))
+	path := filepath.Join(dir, "test", key[:2], key+".json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path+".lock", nil, 0644)
+
+	if err := Put("test", key, "value"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected write to be skipped while another writer holds the lock")
+	}
+
+	// A stale lock left by a crashed run is reclaimed
+	old := time.Now().Add(-2 * staleLockAge)
+	os.Chtimes(path+".lock", old, old)
+	if err := Put("test", key, "value"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	var got string
+	if !Get("test", key, &got) || got != "value" {
+		t.Errorf("expected entry to be written after reclaiming stale lock, got %q", got)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("expected lock file to be removed after write")
+	}
+}
+
+func TestPutGet_Disabled(t *testing.T) {
+	t.Setenv(EnvDir, "")
+	SetDir("")
+
+	key := Key([]byte("input"))
+	if err := Put("test", key, "value"); err != nil {
+		t.Fatalf("Put should be a no-op when disabled: %v", err)
+	}
+	var got string
+	if Get("test", key, &got) {
+		t.Error("expected miss when caching is disabled")
+	}
+}
+
+func TestGet_CorruptEntryIsMiss(t *testing.T) {
+	t.Setenv(EnvDir, "")
+	dir := t.TempDir()
+	SetDir(dir)
+	defer SetDir("")
+
+	key := Key([]byte("input"))
+	path := filepath.Join(dir, "test", key[:2], key+".json")
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte("{not json"), 0644)
+
+	var got map[string]string
+	if Get("test", key, &got) {
+		t.Error("expected corrupt entry to be treated as a miss")
+	}
+}
@@ -0,0 +1,237 @@
+package clean
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/cache"
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// Category names, in the order they are cleaned and reported
+const (
+	CategoryState        = "state"
+	CategoryCache        = "cache"
+	CategoryAttestations = "attestations"
+)
+
+// CleanOptions selects what acc clean prunes
+type CleanOptions struct {
+	State        bool          // .acc/state (verify/build/run state)
+	Cache        bool          // .acc/cache and the shared --cache-dir / ACC_CACHE_DIR cache
+	Attestations bool          // .acc/attestations (local and cached remote attestations)
+	OlderThan    time.Duration // only remove files last modified before now-OlderThan (0 = all)
+	DryRun       bool          // report what would be removed without removing it
+}
+
+// CategoryResult reports what was (or would be) removed from one directory
+type CategoryResult struct {
+	Category string `json:"category"`
+	Path     string `json:"path"`
+	Files    int    `json:"files"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// CleanResult is the result of acc clean
+type CleanResult struct {
+	DryRun     bool             `json:"dryRun"`
+	Categories []CategoryResult `json:"categories"`
+	Files      int              `json:"files"`
+	Bytes      int64            `json:"bytes"`
+}
+
+// Clean prunes the selected categories under .acc. Policies, profiles, SBOMs,
+// waivers, and acc.yaml are never touched: only the category directories are walked.
+func Clean(opts CleanOptions, outputJSON bool) (*CleanResult, error) {
+	if !opts.State && !opts.Cache && !opts.Attestations {
+		return nil, ui.NewError(ui.CodeInvalidArgument, "nothing to clean", "Usage: acc clean [--state] [--cache] [--attestations] [--all] [--older-than <dur>] [--dry-run]")
+	}
+
+	var cutoff time.Time
+	if opts.OlderThan > 0 {
+		cutoff = time.Now().Add(-opts.OlderThan)
+	}
+
+	// A shared cache directory may hold other tools' files, so only acc's namespaces
+	// (and only their *.json entries) are pruned there
+	type target struct {
+		category, path string
+		roots          []string
+		match          func(name string) bool
+	}
+	var targets []target
+	if opts.State {
+		targets = append(targets, target{category: CategoryState, path: filepath.Join(".acc", "state")})
+	}
+	if opts.Cache {
+		targets = append(targets, target{category: CategoryCache, path: filepath.Join(".acc", "cache")})
+		if shared := cache.Dir(); shared != "" {
+			if err := checkSharedCacheDir(shared); err != nil {
+				return nil, err
+			}
+			var roots []string
+			for _, namespace := range cache.Namespaces {
+				roots = append(roots, filepath.Join(shared, namespace))
+			}
+			targets = append(targets, target{category: CategoryCache, path: shared, roots: roots, match: isCacheEntry})
+		}
+	}
+	if opts.Attestations {
+		targets = append(targets, target{category: CategoryAttestations, path: filepath.Join(".acc", "attestations")})
+	}
+
+	result := &CleanResult{DryRun: opts.DryRun, Categories: []CategoryResult{}}
+	for _, t := range targets {
+		roots := t.roots
+		if roots == nil {
+			roots = []string{t.path}
+		}
+		var files int
+		var bytes int64
+		for _, root := range roots {
+			n, size, err := pruneDir(root, cutoff, opts.DryRun, t.match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to clean %s: %w", root, err)
+			}
+			files += n
+			bytes += size
+		}
+		result.Categories = append(result.Categories, CategoryResult{
+			Category: t.category,
+			Path:     t.path,
+			Files:    files,
+			Bytes:    bytes,
+		})
+		result.Files += files
+		result.Bytes += bytes
+	}
+
+	if !outputJSON {
+		printResult(result)
+	}
+	return result, nil
+}
+
+// checkSharedCacheDir refuses a shared cache directory that contains the project's
+// .acc directory (e.g. --cache-dir .), since pruning it would remove policies
+func checkSharedCacheDir(dir string) error {
+	absCache, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	absAcc, err := filepath.Abs(".acc")
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(absCache, absAcc); err == nil && !strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("refusing to clean cache directory %s: it contains the project's .acc directory", dir)
+	}
+	return nil
+}
+
+// isCacheEntry reports whether name is a cache entry (<key>.json)
+func isCacheEntry(name string) bool {
+	return filepath.Ext(name) == ".json"
+}
+
+// pruneDir removes regular files under root last modified before cutoff (all files
+// when cutoff is zero) and accepted by match (all files when nil), then removes
+// directories left empty. root itself is kept. A missing root is not an error.
+// Lock files are never removed: state locks rely on their inode staying in place.
+func pruneDir(root string, cutoff time.Time, dryRun bool, match func(name string) bool) (int, int64, error) {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+
+	var files int
+	var bytes int64
+	var dirs []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != root {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".lock" || (match != nil && !match(info.Name())) {
+			return nil
+		}
+		if !cutoff.IsZero() && !info.ModTime().Before(cutoff) {
+			return nil
+		}
+
+		files++
+		bytes += info.Size()
+		if dryRun {
+			ui.PrintDebug(fmt.Sprintf("would remove %s", path))
+			return nil
+		}
+		ui.PrintDebug(fmt.Sprintf("removing %s", path))
+		return os.Remove(path)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !dryRun {
+		// Deepest first, so parents emptied by their children are removed too;
+		// os.Remove fails (and is ignored) for directories that still have files
+		for i := len(dirs) - 1; i >= 0; i-- {
+			os.Remove(dirs[i])
+		}
+	}
+	return files, bytes, nil
+}
+
+func printResult(result *CleanResult) {
+	verb := "Removed"
+	if result.DryRun {
+		verb = "Would remove"
+	}
+	for _, c := range result.Categories {
+		ui.PrintInfo(fmt.Sprintf("%s: %s %d files (%s) from %s", c.Category, strings.ToLower(verb), c.Files, FormatBytes(c.Bytes), c.Path))
+	}
+	if result.DryRun {
+		ui.PrintInfo(fmt.Sprintf("Dry run: would free %s (%d files)", FormatBytes(result.Bytes), result.Files))
+		return
+	}
+	ui.PrintSuccess(fmt.Sprintf("Freed %s (%d files)", FormatBytes(result.Bytes), result.Files))
+}
+
+// FormatBytes renders a byte count with a binary unit (e.g. "1.5 KiB")
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseAge parses an --older-than value: a Go duration ("36h", "90m") or a
+// number of days ("30d")
+func ParseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --older-than %q: use a duration like 30d or 36h", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --older-than %q: use a duration like 30d or 36h", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,241 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupProject creates a project with files in every .acc category, plus files
+// clean must never touch. The attestation old.json is backdated by 60 days.
+func setupProject(t *testing.T) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+	t.Setenv("ACC_CACHE_DIR", "")
+
+	files := map[string]string{
+		"acc.yaml":                                   "project:\n  name: demo\n",
+		".acc/policy/default.rego":                   "package acc.policy\n",
+		".acc/profiles/strict.yaml":                  "name: strict\n",
+		".acc/sbom/demo.spdx.json":                   "{}",
+		".acc/waivers.yaml":                          "waivers: []\n",
+		".acc/state/last_verify.json":                `{"status":"pass"}`,
+		".acc/state/verify/abc.json":                 `{"status":"pass"}`,
+		".acc/cache/opa/ab/abcd.json":                `{}`,
+		".acc/attestations/abababababab/old.json":    `{"old":true}`,
+		".acc/attestations/abababababab/recent.json": `{"recent":true}`,
+	}
+	for path, content := range files {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+	}
+
+	old := time.Now().Add(-60 * 24 * time.Hour)
+	os.Chtimes(".acc/attestations/abababababab/old.json", old, old)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// protected lists files acc clean must never remove
+var protected = []string{
+	"acc.yaml",
+	".acc/policy/default.rego",
+	".acc/profiles/strict.yaml",
+	".acc/sbom/demo.spdx.json",
+	".acc/waivers.yaml",
+}
+
+func TestClean_OnlySelectedCategories(t *testing.T) {
+	setupProject(t)
+
+	result, err := Clean(CleanOptions{State: true}, true)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if result.Files != 2 || len(result.Categories) != 1 || result.Categories[0].Category != CategoryState {
+		t.Errorf("expected 2 state files removed, got %+v", result)
+	}
+	if result.Bytes == 0 {
+		t.Error("expected freed bytes to be reported")
+	}
+
+	if exists(".acc/state/last_verify.json") || exists(".acc/state/verify") {
+		t.Error("expected state files and emptied subdirectories to be removed")
+	}
+	if !exists(".acc/state") {
+		t.Error("expected .acc/state itself to be kept")
+	}
+	for _, path := range append(protected, ".acc/cache/opa/ab/abcd.json", ".acc/attestations/abababababab/old.json") {
+		if !exists(path) {
+			t.Errorf("%s should not have been removed", path)
+		}
+	}
+}
+
+func TestClean_AttestationsOlderThan(t *testing.T) {
+	setupProject(t)
+
+	result, err := Clean(CleanOptions{Attestations: true, OlderThan: 30 * 24 * time.Hour}, true)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if result.Files != 1 {
+		t.Errorf("expected 1 old attestation removed, got %d", result.Files)
+	}
+	if exists(".acc/attestations/abababababab/old.json") {
+		t.Error("expected old attestation to be removed")
+	}
+	if !exists(".acc/attestations/abababababab/recent.json") {
+		t.Error("expected recent attestation to be kept")
+	}
+}
+
+func TestClean_AllDryRun(t *testing.T) {
+	setupProject(t)
+
+	result, err := Clean(CleanOptions{State: true, Cache: true, Attestations: true, DryRun: true}, true)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if !result.DryRun || result.Files != 5 || len(result.Categories) != 3 {
+		t.Errorf("expected dry run reporting 5 files in 3 categories, got %+v", result)
+	}
+
+	for _, path := range []string{
+		".acc/state/last_verify.json",
+		".acc/state/verify/abc.json",
+		".acc/cache/opa/ab/abcd.json",
+		".acc/attestations/abababababab/old.json",
+		".acc/attestations/abababababab/recent.json",
+	} {
+		if !exists(path) {
+			t.Errorf("dry run removed %s", path)
+		}
+	}
+}
+
+func TestClean_All(t *testing.T) {
+	setupProject(t)
+
+	result, err := Clean(CleanOptions{State: true, Cache: true, Attestations: true}, true)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if result.Files != 5 {
+		t.Errorf("expected 5 files removed, got %d", result.Files)
+	}
+	for _, path := range protected {
+		if !exists(path) {
+			t.Errorf("%s should never be removed", path)
+		}
+	}
+}
+
+func TestClean_SharedCacheDir(t *testing.T) {
+	setupProject(t)
+	shared := t.TempDir()
+	for _, path := range []string{"policy-eval/ab/abcd.json", "policy-eval/ab/abcd.json.lock", "policy-eval/notes.txt", "registry/entry.json"} {
+		os.MkdirAll(filepath.Join(shared, filepath.Dir(path)), 0755)
+		os.WriteFile(filepath.Join(shared, path), []byte("{}"), 0644)
+	}
+	t.Setenv("ACC_CACHE_DIR", shared)
+
+	result, err := Clean(CleanOptions{Cache: true}, true)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if result.Files != 2 || len(result.Categories) != 2 {
+		t.Errorf("expected .acc/cache and the shared cache to be cleaned, got %+v", result)
+	}
+	if !exists(shared) {
+		t.Error("expected the shared cache directory itself to be kept")
+	}
+	if exists(filepath.Join(shared, "policy-eval", "ab", "abcd.json")) {
+		t.Error("expected the acc cache entry to be removed")
+	}
+	// Only acc's namespaced *.json entries are pruned from a shared directory
+	for _, path := range []string{"policy-eval/ab/abcd.json.lock", "policy-eval/notes.txt", "registry/entry.json"} {
+		if !exists(filepath.Join(shared, path)) {
+			t.Errorf("expected %s in the shared cache to be kept", path)
+		}
+	}
+
+	// A cache dir containing the project's .acc would take policies with it
+	t.Setenv("ACC_CACHE_DIR", ".")
+	if _, err := Clean(CleanOptions{Cache: true}, true); err == nil {
+		t.Error("expected a cache dir containing .acc to be refused")
+	}
+	for _, path := range protected {
+		if !exists(path) {
+			t.Errorf("%s should never be removed", path)
+		}
+	}
+}
+
+// TestClean_KeepsStateLocks tests that state lock files survive acc clean --state, since a
+// removed lock file lets two writers lock different inodes
+func TestClean_KeepsStateLocks(t *testing.T) {
+	setupProject(t)
+	os.WriteFile(".acc/state/last_verify.json.lock", nil, 0644)
+
+	result, err := Clean(CleanOptions{State: true}, true)
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+	if result.Files != 2 {
+		t.Errorf("expected 2 state files removed, got %d", result.Files)
+	}
+	if !exists(".acc/state/last_verify.json.lock") {
+		t.Error("expected the state lock file to be kept")
+	}
+}
+
+func TestClean_NothingSelected(t *testing.T) {
+	setupProject(t)
+	if _, err := Clean(CleanOptions{}, true); err == nil {
+		t.Error("expected an error when no category is selected")
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"-1d", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAge(%q) = %v, %v; want %v (error %t)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:           "0 B",
+		1023:        "1023 B",
+		1536:        "1.5 KiB",
+		5 * 1 << 20: "5.0 MiB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
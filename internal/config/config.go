@@ -3,9 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config represents the acc configuration (AGENTS.md Section 5.3)
@@ -17,6 +23,8 @@ type Config struct {
 	Trust        TrustConfig          `mapstructure:"trust"` // v0.3.3: trust requirements
 	Signing      SigningConfig        `mapstructure:"signing"`
 	SBOM         SBOMConfig           `mapstructure:"sbom"`
+	Hooks        HooksConfig          `mapstructure:"hooks"`
+	Profiles     ProfilesConfig       `mapstructure:"profiles"`
 	Environments map[string]EnvConfig `mapstructure:"environments"`
 }
 
@@ -28,6 +36,7 @@ type EnvConfig struct {
 
 type ProjectConfig struct {
 	Name string `mapstructure:"name"`
+	Team string `mapstructure:"team"` // owning team stamped on verify results (verify --team)
 }
 
 type BuildConfig struct {
@@ -36,20 +45,89 @@ type BuildConfig struct {
 }
 
 type RegistryConfig struct {
-	Default string `mapstructure:"default"`
+	Default      string `mapstructure:"default"`
+	RemoteConfig bool   `mapstructure:"remoteConfig"` // read image config from the registry when the image is not local (verify --remote)
 }
 
 type PolicyConfig struct {
-	Mode               string `mapstructure:"mode"`               // enforce|warn
-	RequireAttestation bool   `mapstructure:"requireAttestation"` // v0.3.1: require verified attestations for run/push
+	Mode                   string        `mapstructure:"mode"`                   // enforce|warn
+	Pack                   string        `mapstructure:"pack"`                   // policy pack evaluated instead of .acc/policy: a name in .acc/policy-packs/, a built-in pack (baseline, cis), or a path
+	RequireAttestation     bool          `mapstructure:"requireAttestation"`     // v0.3.1: require verified attestations for run/push
+	RequireProvenance      bool          `mapstructure:"requireProvenance"`      // require SLSA build provenance for the image digest
+	ProvenanceSource       string        `mapstructure:"provenanceSource"`       // glob the provenance source repository must match (provenance-source-mismatch)
+	MaxViolations          int           `mapstructure:"maxViolations"`          // limit violations printed by verify (0 = all; JSON is never truncated)
+	ParallelOPA            bool          `mapstructure:"parallelOpa"`            // evaluate each policy subdirectory in its own OPA invocation
+	InputFromManifest      bool          `mapstructure:"inputFromManifest"`      // build policy input from .acc/state/build/<digest>.json when present
+	Input                  string        `mapstructure:"input"`                  // policy input document (JSON) evaluated instead of inspecting the image (verify --input)
+	CaptureInspect         string        `mapstructure:"captureInspect"`         // write the raw runtime inspect JSON used for the policy input here (verify --capture-inspect)
+	Data                   []string      `mapstructure:"data"`                   // JSON/YAML files loaded under data.acc.external (verify --data)
+	VerifyImageSignature   bool          `mapstructure:"verifyImageSignature"`   // require a valid cosign signature on the image digest
+	RequireSBOMSigned      bool          `mapstructure:"requireSbomSigned"`      // require a cosign signature over the SBOM (<sbom>.sig or registry SBOM attestation)
+	NoWaivers              bool          `mapstructure:"noWaivers"`              // ignore .acc/waivers.yaml (audit view: no suppression, no expiry failures)
+	RegoQuery              string        `mapstructure:"regoQuery"`              // decision document evaluated by OPA (default data.acc.policy.result)
+	FailOnWarningCount     *int          `mapstructure:"failOnWarningCount"`     // fail verify when warnings exceed this many (unset = no budget)
+	RegoTimeout            time.Duration `mapstructure:"regoTimeout"`            // kill opa eval after this long (default 30s)
+	RequiredLabels         []string      `mapstructure:"requiredLabels"`         // image labels that must be present (required-label-missing)
+	RequireDigestPinned    bool          `mapstructure:"requireDigestPinned"`    // fail verify for image refs without @sha256:<digest> (tag-not-digest-pinned)
+	RequireNonRoot         bool          `mapstructure:"requireNonRoot"`         // fail verify when the image runs as root, without a Rego policy (no-root-user)
+	RequireHealthcheck     bool          `mapstructure:"requireHealthcheck"`     // report no-healthcheck when the image defines no HEALTHCHECK
+	HealthcheckSeverity    string        `mapstructure:"healthcheckSeverity"`    // warning (default) or critical (no-healthcheck fails verify)
+	ForbidPrivilegedPorts  bool          `mapstructure:"forbidPrivilegedPorts"`  // report privileged-port for each EXPOSE below 1024
+	PrivilegedPortSeverity string        `mapstructure:"privilegedPortSeverity"` // critical (default, fails verify) or warning
+	DenyEnvSecrets         bool          `mapstructure:"denyEnvSecrets"`         // report env-secret-detected for ENV values that look like credentials
+	EnvSecretPatterns      []string      `mapstructure:"envSecretPatterns"`      // extra env var name regexps (case-insensitive) treated as secrets
+	ReportUnusedWaivers    bool          `mapstructure:"reportUnusedWaivers"`    // report waivers whose rule did not fire (unusedWaivers)
+	FailOnUnusedWaivers    bool          `mapstructure:"failOnUnusedWaivers"`    // fail verify with unused-waiver for each unused waiver
+	NoState                bool          `mapstructure:"noState"`                // verify writes no .acc/state (stateless jobs; attest/push/explain then have nothing to read)
+	RegoPrint              bool          `mapstructure:"regoPrint"`              // capture print() output from policies (verify --rego-print)
+	AllowMissingOPA        bool          `mapstructure:"allowMissingOpa"`        // advisory runs: missing opa yields a policy-unevaluated warning instead of opa-required
+	Phase                  string        `mapstructure:"phase"`                  // build or deploy: run only that phase's checks (verify --two-phase)
+	ExplainDeny            bool          `mapstructure:"explainDeny"`            // attribute each violation to the policy file:line that reported it (source)
+	Env                    string        `mapstructure:"-"`                      // environment the run verifies for (verify --env, promote --to); recorded as env, not read from acc.yaml
 }
 
+// DefaultRegoQuery is the decision document verify evaluates when policy.regoQuery is unset
+const DefaultRegoQuery = "data.acc.policy.result"
+
+// DefaultRegoTimeout bounds a policy evaluation when policy.regoTimeout is unset
+const DefaultRegoTimeout = 30 * time.Second
+
+// DefaultSBOMDir is where SBOMs are written and looked for when sbom.dir is unset
+var DefaultSBOMDir = filepath.Join(".acc", "sbom")
+
+// regoQueryPattern matches a data reference such as data.mycompany.images.decision
+var regoQueryPattern = regexp.MustCompile(`^data(\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
 type SigningConfig struct {
-	Mode string `mapstructure:"mode"` // keyless|key
+	Mode           string `mapstructure:"mode"`           // keyless|key
+	Key            string `mapstructure:"key"`            // cosign public key for verify --verify-image-signature (keyless if empty)
+	IdentityRegexp string `mapstructure:"identityRegexp"` // keyless: certificate identity to accept (default ".*")
+	IssuerRegexp   string `mapstructure:"issuerRegexp"`   // keyless: OIDC issuer to accept (default ".*")
 }
 
 type SBOMConfig struct {
-	Format string `mapstructure:"format"` // spdx|cyclonedx
+	Format          string   `mapstructure:"format"`          // spdx|cyclonedx
+	Watchlist       []string `mapstructure:"watchlist"`       // package names (or globs) highlighted by inspect --sbom-summary
+	MinComponents   int      `mapstructure:"minComponents"`   // verify fails with sbom-too-sparse below this many components (0 = off)
+	Baseline        string   `mapstructure:"baseline"`        // previous build's SBOM; verify exposes the diff as input.sbom.changed
+	DenyNewPackages string   `mapstructure:"denyNewPackages"` // approved SBOM; verify fails with unexpected-package for packages not in it
+	RequireFormat   string   `mapstructure:"requireFormat"`   // spdx|cyclonedx; verify fails with sbom-format-mismatch for an SBOM in another format
+	Dir             string   `mapstructure:"dir"`             // where acc build writes SBOMs and verify looks for them (default .acc/sbom)
+	AcceptedFormats []string `mapstructure:"acceptedFormats"` // formats (detected from content) that satisfy the SBOM presence check (default: any SBOM JSON)
+	SinceBuild      bool     `mapstructure:"sinceBuild"`      // verify fails with sbom-stale when the SBOM predates the image build
+	FromImage       bool     `mapstructure:"fromImage"`       // verify the SBOM attached to the image in its registry (cached under <dir>/image/<digest>/)
+}
+
+// ProfilesConfig selects policy profiles per environment
+type ProfilesConfig struct {
+	ByEnv map[string]string `mapstructure:"byEnv"` // environment -> profile name or path (--env / promote --to)
+}
+
+// HooksConfig lists shell commands run around verification
+// Hooks receive ACC_IMAGE_REF and ACC_VERIFY_RESULT (path to the verification result JSON)
+type HooksConfig struct {
+	PreVerify  []string `mapstructure:"preVerify"`  // non-zero exit aborts verification
+	PostVerify []string `mapstructure:"postVerify"` // run after state is saved; failures are warnings
 }
 
 // TrustConfig represents trust/attestation requirements (v0.3.3)
@@ -145,9 +223,91 @@ func (c *Config) Validate() error {
 	if c.SBOM.Format != "spdx" && c.SBOM.Format != "cyclonedx" {
 		return fmt.Errorf("sbom.format must be 'spdx' or 'cyclonedx'")
 	}
+	if f := c.SBOM.RequireFormat; f != "" && f != "spdx" && f != "cyclonedx" {
+		return fmt.Errorf("sbom.requireFormat must be 'spdx' or 'cyclonedx'")
+	}
+	for _, f := range c.SBOM.AcceptedFormats {
+		if f != "spdx" && f != "cyclonedx" {
+			return fmt.Errorf("sbom.acceptedFormats entries must be 'spdx' or 'cyclonedx', got %q", f)
+		}
+	}
+	if c.SBOM.MinComponents < 0 {
+		return fmt.Errorf("sbom.minComponents must be >= 0")
+	}
+	if s := c.Policy.HealthcheckSeverity; s != "" && s != "warning" && s != "critical" {
+		return fmt.Errorf("policy.healthcheckSeverity must be 'warning' or 'critical'")
+	}
+	if s := c.Policy.PrivilegedPortSeverity; s != "" && s != "warning" && s != "critical" {
+		return fmt.Errorf("policy.privilegedPortSeverity must be 'warning' or 'critical'")
+	}
+	if p := c.Policy.Phase; p != "" && p != "build" && p != "deploy" {
+		return fmt.Errorf("policy.phase must be 'build' or 'deploy'")
+	}
+	if c.Policy.ProvenanceSource != "" {
+		if _, err := path.Match(c.Policy.ProvenanceSource, ""); err != nil {
+			return fmt.Errorf("policy.provenanceSource: invalid pattern %q: %w", c.Policy.ProvenanceSource, err)
+		}
+	}
+	for _, p := range c.Policy.EnvSecretPatterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("policy.envSecretPatterns: invalid pattern %q: %w", p, err)
+		}
+	}
+	if c.Policy.RegoTimeout < 0 {
+		return fmt.Errorf("policy.regoTimeout must not be negative")
+	}
+	if c.Policy.FailOnWarningCount != nil && *c.Policy.FailOnWarningCount < 0 {
+		return fmt.Errorf("policy.failOnWarningCount must be >= 0")
+	}
+	if c.Policy.RegoQuery != "" && !regoQueryPattern.MatchString(c.Policy.RegoQuery) {
+		return fmt.Errorf("policy.regoQuery must be a data reference like %s", DefaultRegoQuery)
+	}
 	return nil
 }
 
+// OverridePolicyMode replaces policy.mode for the current run
+// Used by --policy-mode so CI can force enforce/warn regardless of acc.yaml
+func (c *Config) OverridePolicyMode(mode string) error {
+	if mode != "enforce" && mode != "warn" {
+		return fmt.Errorf("invalid policy mode %q: must be 'enforce' or 'warn'", mode)
+	}
+	c.Policy.Mode = mode
+	return nil
+}
+
+// OverrideRegoQuery replaces policy.regoQuery for the current run (--rego-query)
+func (c *Config) OverrideRegoQuery(query string) error {
+	if !regoQueryPattern.MatchString(query) {
+		return fmt.Errorf("invalid rego query %q: must be a data reference like %s", query, DefaultRegoQuery)
+	}
+	c.Policy.RegoQuery = query
+	return nil
+}
+
+// RegoQuery returns the decision document to evaluate (policy.regoQuery or DefaultRegoQuery)
+func (c *Config) RegoQuery() string {
+	if c.Policy.RegoQuery != "" {
+		return c.Policy.RegoQuery
+	}
+	return DefaultRegoQuery
+}
+
+// RegoTimeout returns how long a policy evaluation may run (policy.regoTimeout or DefaultRegoTimeout)
+func (c *Config) RegoTimeout() time.Duration {
+	if c.Policy.RegoTimeout > 0 {
+		return c.Policy.RegoTimeout
+	}
+	return DefaultRegoTimeout
+}
+
+// SBOMDir returns the SBOM directory (sbom.dir or DefaultSBOMDir)
+func (c *Config) SBOMDir() string {
+	if c.SBOM.Dir != "" {
+		return c.SBOM.Dir
+	}
+	return DefaultSBOMDir
+}
+
 // GetPolicyForEnv returns the policy config for a specific environment
 // If environment-specific policy is defined, it overrides the default
 func (c *Config) GetPolicyForEnv(env string) PolicyConfig {
@@ -161,6 +321,19 @@ func (c *Config) GetPolicyForEnv(env string) PolicyConfig {
 	return c.Policy
 }
 
+// SelectProfile returns the profile to use: an explicit --profile wins, then the
+// profiles.byEnv mapping for env, then "" (no profile)
+func (c *Config) SelectProfile(explicit, env string) string {
+	if explicit != "" || env == "" {
+		return explicit
+	}
+	if name, ok := c.Profiles.ByEnv[env]; ok {
+		return name
+	}
+	// Viper lowercases map keys read from acc.yaml
+	return c.Profiles.ByEnv[strings.ToLower(env)]
+}
+
 // GetRegistryForEnv returns the registry config for a specific environment
 func (c *Config) GetRegistryForEnv(env string) RegistryConfig {
 	if env != "" && c.Environments != nil {
@@ -173,6 +346,16 @@ func (c *Config) GetRegistryForEnv(env string) RegistryConfig {
 	return c.Registry
 }
 
+// EffectiveYAML renders every config field under its acc.yaml key, including fields
+// set by flags for this run; unlike ToYAML the output loads back with Load unchanged
+func (c *Config) EffectiveYAML() ([]byte, error) {
+	var doc map[string]interface{}
+	if err := mapstructure.Decode(c, &doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return yaml.Marshal(doc)
+}
+
 // DefaultConfig returns a default configuration template
 func DefaultConfig(projectName string) *Config {
 	return &Config{
@@ -203,6 +386,7 @@ func (c *Config) ToYAML() string {
 	return fmt.Sprintf(`# acc configuration file
 project:
   name: %s
+  # team: payments  # owning team recorded in verify results and summaries (--team overrides)
 
 build:
   context: %s
@@ -213,13 +397,53 @@ registry:
 
 policy:
   mode: %s
+  # pack: cis  # evaluate .acc/policy-packs/<name>/ or a built-in pack (baseline, cis) instead of .acc/policy
   # requireAttestation: false  # v0.3.1: require verified attestations for run/push
+  # provenanceSource: https://github.com/org/*  # require SLSA provenance built from a matching repository
+  # parallelOpa: false  # evaluate each .acc/policy subdirectory in its own OPA invocation
+  # data: []  # JSON/YAML files available to policies as data.acc.external
+  # verifyImageSignature: false  # require a cosign signature on the image (see signing.key)
+  # requireSbomSigned: false  # require a cosign signature over the SBOM (<sbom>.sig or SBOM attestation)
+  # regoQuery: data.acc.policy.result  # decision document with violations/deny (for policies in another package)
+  # failOnWarningCount: 10  # fail verify when profiles/waivers leave more than this many warnings
+  # regoTimeout: 30s  # stop a policy evaluation that runs longer (policy-evaluation-timeout)
+  # regoPrint: false  # show print() output from policies (debugging)
+  # allowMissingOpa: false  # reporting-only runs: without opa, warn policy-unevaluated instead of failing
+  # explainDeny: false  # record the policy file:line behind each violation as its source
+  # phase: build  # build (SBOM/policy only) or deploy (adds signature, provenance, freshness; needs a passing build phase)
+  # requiredLabels: [org.opencontainers.image.source, org.opencontainers.image.revision]
+  # requireDigestPinned: false  # fail verify for image refs without @sha256:<digest> (tag-not-digest-pinned)
+  # requireNonRoot: false  # built-in no-root-user check: fail when USER is empty, 0, or root (no OPA needed)
+  # requireHealthcheck: false  # built-in no-healthcheck check for images without a HEALTHCHECK
+  # healthcheckSeverity: warning  # warning or critical (no-healthcheck fails verify)
+  # forbidPrivilegedPorts: false  # built-in privileged-port check for EXPOSE below 1024
+  # privilegedPortSeverity: critical  # critical (fails verify) or warning
+  # denyEnvSecrets: false  # built-in env-secret-detected check for credentials in ENV (values are redacted)
+  # envSecretPatterns: [DSN, CONN_STR]  # extra variable name regexps treated as secrets
+  # reportUnusedWaivers: false  # report waivers whose rule did not fire in a run
+  # failOnUnusedWaivers: false  # fail verify (unused-waiver) for waivers that suppress nothing
 
 signing:
   mode: %s
+  # key: cosign.pub  # public key for verifyImageSignature/requireSbomSigned (keyless if unset)
+  # identityRegexp: "^https://github.com/org/"  # keyless: accepted certificate identity
+  # issuerRegexp: "^https://token.actions.githubusercontent.com$"  # keyless: accepted OIDC issuer
 
 sbom:
   format: %s
+  # watchlist: [log4j-core, "openssl*"]  # packages reported by acc inspect --sbom-summary
+  # minComponents: 10  # fail verify (sbom-too-sparse) when the SBOM lists fewer components
+  # baseline: sbom-baseline/app.spdx.json  # previous SBOM; policies see the diff as input.sbom.changed
+  # denyNewPackages: sbom-baseline/approved.spdx.json  # fail verify (unexpected-package) for packages not in this SBOM
+  # dir: .acc/sbom  # where acc build writes SBOMs and verify looks for them
+  # acceptedFormats: [spdx, cyclonedx]  # SBOM formats that satisfy verify's presence check (default: any)
+  # sinceBuild: false  # fail verify (sbom-stale) when the SBOM is older than the image build
+  # fromImage: false  # verify the SBOM attached to the image in its registry instead of the project's
+
+# profiles:
+#   byEnv:  # profile selected by --env (and promote --to); --profile overrides
+#     prod: strict
+#     staging: baseline
 `, c.Project.Name, c.Build.Context, c.Build.DefaultTag,
 		c.Registry.Default, c.Policy.Mode, c.Signing.Mode, c.SBOM.Format)
 }
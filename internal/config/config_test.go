@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -94,6 +97,32 @@ func TestValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "sbom.format must be 'spdx' or 'cyclonedx'",
 		},
+		{
+			name: "invalid sbom requireFormat",
+			cfg: &Config{
+				Project:  ProjectConfig{Name: "test"},
+				Build:    BuildConfig{Context: ".", DefaultTag: "latest"},
+				Registry: RegistryConfig{Default: "localhost:5000"},
+				Policy:   PolicyConfig{Mode: "enforce"},
+				Signing:  SigningConfig{Mode: "keyless"},
+				SBOM:     SBOMConfig{Format: "spdx", RequireFormat: "swid"},
+			},
+			wantErr: true,
+			errMsg:  "sbom.requireFormat must be 'spdx' or 'cyclonedx'",
+		},
+		{
+			name: "invalid sbom acceptedFormats",
+			cfg: &Config{
+				Project:  ProjectConfig{Name: "test"},
+				Build:    BuildConfig{Context: ".", DefaultTag: "latest"},
+				Registry: RegistryConfig{Default: "localhost:5000"},
+				Policy:   PolicyConfig{Mode: "enforce"},
+				Signing:  SigningConfig{Mode: "keyless"},
+				SBOM:     SBOMConfig{Format: "spdx", AcceptedFormats: []string{"spdx", "swid"}},
+			},
+			wantErr: true,
+			errMsg:  `sbom.acceptedFormats entries must be 'spdx' or 'cyclonedx', got "swid"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -219,6 +248,80 @@ func TestGetPolicyForEnv(t *testing.T) {
 	}
 }
 
+func TestSelectProfile(t *testing.T) {
+	cfg := DefaultConfig("test-project")
+	cfg.Profiles.ByEnv = map[string]string{"prod": "strict", "staging": "baseline"}
+
+	tests := []struct {
+		name, explicit, env, want string
+	}{
+		{"no profile", "", "", ""},
+		{"env mapping", "", "prod", "strict"},
+		{"env mapping is case-insensitive", "", "Prod", "strict"},
+		{"explicit overrides env", "custom", "prod", "custom"},
+		{"explicit without env", "custom", "", "custom"},
+		{"unmapped env", "", "dev", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.SelectProfile(tt.explicit, tt.env); got != tt.want {
+				t.Errorf("SelectProfile(%q, %q) = %q, want %q", tt.explicit, tt.env, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_ProfilesByEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "acc.yaml")
+	yaml := DefaultConfig("test-project").ToYAML() + `
+profiles:
+  byEnv:
+    prod: strict
+    staging: ./profiles/baseline.yaml
+`
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.SelectProfile("", "prod"); got != "strict" {
+		t.Errorf("prod profile = %q, want strict", got)
+	}
+	if got := cfg.SelectProfile("", "staging"); got != "./profiles/baseline.yaml" {
+		t.Errorf("staging profile = %q, want ./profiles/baseline.yaml", got)
+	}
+}
+
+func TestLoad_RegoTimeout(t *testing.T) {
+	if got := DefaultConfig("test-project").RegoTimeout(); got != DefaultRegoTimeout {
+		t.Errorf("default rego timeout = %v, want %v", got, DefaultRegoTimeout)
+	}
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "acc.yaml")
+	yaml := strings.Replace(DefaultConfig("test-project").ToYAML(), "policy:\n", "policy:\n  regoTimeout: 2m\n", 1)
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.RegoTimeout(); got != 2*time.Minute {
+		t.Errorf("rego timeout = %v, want 2m", got)
+	}
+
+	cfg.Policy.RegoTimeout = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for a negative policy.regoTimeout")
+	}
+}
+
 func TestGetRegistryForEnv(t *testing.T) {
 	cfg := DefaultConfig("test-project")
 
@@ -254,3 +357,83 @@ func TestGetRegistryForEnv(t *testing.T) {
 		t.Errorf("expected default registry 'localhost:5000' for env without override, got '%s'", registry.Default)
 	}
 }
+
+func TestOverrideRegoQuery(t *testing.T) {
+	cfg := DefaultConfig("test-project")
+	if got := cfg.RegoQuery(); got != DefaultRegoQuery {
+		t.Errorf("default rego query = %q, want %q", got, DefaultRegoQuery)
+	}
+
+	if err := cfg.OverrideRegoQuery("data.mycompany.images.decision"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.RegoQuery(); got != "data.mycompany.images.decision" {
+		t.Errorf("rego query = %q, want data.mycompany.images.decision", got)
+	}
+
+	for _, query := range []string{"mycompany.images", "data", "data.my-company.result", "data.x; input"} {
+		if err := cfg.OverrideRegoQuery(query); err == nil {
+			t.Errorf("expected error for rego query %q", query)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("valid rego query should pass validation: %v", err)
+	}
+	cfg.Policy.RegoQuery = "acc.policy.result"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected validation error for policy.regoQuery without data. prefix")
+	}
+}
+
+func TestOverridePolicyMode(t *testing.T) {
+	cfg := DefaultConfig("test-project")
+
+	// Override enforce -> warn
+	if err := cfg.OverridePolicyMode("warn"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Policy.Mode != "warn" {
+		t.Errorf("expected policy mode 'warn', got '%s'", cfg.Policy.Mode)
+	}
+
+	// Invalid mode is rejected and leaves config untouched
+	if err := cfg.OverridePolicyMode("audit"); err == nil {
+		t.Error("expected error for invalid policy mode")
+	}
+	if cfg.Policy.Mode != "warn" {
+		t.Errorf("invalid override should not change mode, got '%s'", cfg.Policy.Mode)
+	}
+}
+
+// TestEffectiveYAML tests that the rendered config loads back to the same values
+func TestEffectiveYAML(t *testing.T) {
+	cfg := DefaultConfig("test-project")
+	budget := 3
+	cfg.Policy.Mode = "warn"
+	cfg.Policy.RegoTimeout = 90 * time.Second
+	cfg.Policy.FailOnWarningCount = &budget
+	cfg.Policy.Data = []string{"data/external.json"}
+	cfg.Policy.RequiredLabels = []string{"org.opencontainers.image.source"}
+	cfg.Policy.EnvSecretPatterns = []string{"_DSN$"}
+	cfg.SBOM.MinComponents = 5
+	cfg.SBOM.Watchlist = []string{"openssl*"}
+	cfg.SBOM.AcceptedFormats = []string{"spdx", "cyclonedx"}
+	cfg.Profiles.ByEnv = map[string]string{"prod": "strict"}
+
+	data, err := cfg.EffectiveYAML()
+	if err != nil {
+		t.Fatalf("EffectiveYAML failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "acc.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(loaded.Policy, cfg.Policy) || !reflect.DeepEqual(loaded.SBOM, cfg.SBOM) || loaded.Profiles.ByEnv["prod"] != "strict" {
+		t.Errorf("round trip mismatch:\n%+v\nwant\n%+v", loaded.Policy, cfg.Policy)
+	}
+}
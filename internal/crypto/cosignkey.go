@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CosignKeyRef resolves a cosign key given as the name of an environment variable holding
+// the PEM (keyEnv, e.g. an injected CI secret) or as a file path (keyFile, e.g. a secret
+// mount) to the value for cosign --key. At most one may be set; with neither, it returns "".
+// The key is checked before cosign runs: it must be a PEM private key (or public key when
+// private is false), and an encrypted private key needs COSIGN_PASSWORD, which cosign reads
+// instead of prompting. An environment key is passed as env://<VAR>, so it is never written
+// to disk.
+func CosignKeyRef(keyEnv, keyFile string, private bool) (string, error) {
+	switch {
+	case keyEnv != "" && keyFile != "":
+		return "", fmt.Errorf("--key-env and --key-file are mutually exclusive")
+	case keyEnv != "":
+		value := os.Getenv(keyEnv)
+		if value == "" {
+			return "", fmt.Errorf("--key-env %s: environment variable is not set or empty", keyEnv)
+		}
+		if err := checkCosignKey([]byte(value), "$"+keyEnv, private); err != nil {
+			return "", err
+		}
+		return "env://" + keyEnv, nil
+	case keyFile != "":
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return "", fmt.Errorf("--key-file: failed to read key: %w", err)
+		}
+		if err := checkCosignKey(data, keyFile, private); err != nil {
+			return "", err
+		}
+		return keyFile, nil
+	}
+	return "", nil
+}
+
+// checkCosignKey checks that data is a PEM key of the expected kind
+func checkCosignKey(data []byte, source string, private bool) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("%s is not a PEM-encoded cosign key", source)
+	}
+
+	if !private {
+		if !strings.HasSuffix(block.Type, "PUBLIC KEY") {
+			return fmt.Errorf("%s holds a %s, not a public key", source, block.Type)
+		}
+		return nil
+	}
+
+	if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		return fmt.Errorf("%s holds a %s, not a private key", source, block.Type)
+	}
+	if strings.Contains(block.Type, "ENCRYPTED") {
+		if _, ok := os.LookupEnv("COSIGN_PASSWORD"); !ok {
+			return fmt.Errorf("%s is encrypted: set COSIGN_PASSWORD to its password (empty if it has none)", source)
+		}
+	}
+	return nil
+}
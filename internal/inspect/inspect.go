@@ -1,15 +1,19 @@
 package inspect
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/sbom"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	"github.com/cloudcwfranck/acc/internal/waivers"
 )
@@ -28,16 +32,21 @@ type InspectResult struct {
 
 // ArtifactInfo contains artifact-related information
 type ArtifactInfo struct {
-	SBOMPath     string   `json:"sbomPath,omitempty"`
-	SBOMFormat   string   `json:"sbomFormat,omitempty"`
-	Attestations []string `json:"attestations"`
+	SBOMPath     string        `json:"sbomPath,omitempty"`
+	SBOMFormat   string        `json:"sbomFormat,omitempty"`
+	SBOMSummary  *sbom.Summary `json:"sbomSummary,omitempty"` // inspect --sbom-summary
+	Attestations []string      `json:"attestations"`
 }
 
+// sbomTopLicenses is how many licenses --sbom-summary reports
+const sbomTopLicenses = 5
+
 // PolicyInfo contains policy-related information
 type PolicyInfo struct {
-	Mode       string   `json:"mode"`
-	PolicyPack string   `json:"policyPack"`
-	Waivers    []Waiver `json:"waivers"`
+	Mode       string          `json:"mode"`
+	PolicyPack string          `json:"policyPack"`
+	Waivers    []Waiver        `json:"waivers"`
+	Input      json.RawMessage `json:"input,omitempty"` // inspect --show-input: the input the last verify evaluated
 }
 
 // Waiver represents a policy waiver
@@ -49,7 +58,9 @@ type Waiver struct {
 }
 
 // Inspect performs inspection of an image and returns trust summary
-func Inspect(cfg *config.Config, imageRef string, outputJSON bool) (*InspectResult, error) {
+// sbomSummary parses the SBOM and adds its component count, top licenses, and sbom.watchlist matches
+// showInput adds the policy input recorded by the image's last verification
+func Inspect(cfg *config.Config, imageRef string, sbomSummary, showInput, outputJSON bool) (*InspectResult, error) {
 	if imageRef == "" {
 		return nil, fmt.Errorf("image reference required")
 	}
@@ -86,6 +97,20 @@ func Inspect(cfg *config.Config, imageRef string, outputJSON bool) (*InspectResu
 	if sbomPath != "" {
 		result.Artifacts.SBOMPath = sbomPath
 		result.Artifacts.SBOMFormat = sbomFormat
+
+		if sbomSummary {
+			doc, err := sbom.ParseFile(sbomPath)
+			if err != nil {
+				// A summary is informational; an unparseable SBOM does not fail inspect
+				result.Metadata["sbomSummaryError"] = err.Error()
+				if !outputJSON {
+					ui.PrintWarning(fmt.Sprintf("Could not summarize SBOM: %v", err))
+				}
+			} else {
+				result.Artifacts.SBOMFormat = doc.Format
+				result.Artifacts.SBOMSummary = sbom.Summarize(doc, cfg.SBOM.Watchlist, sbomTopLicenses)
+			}
+		}
 	}
 
 	// Check for attestations
@@ -101,6 +126,14 @@ func Inspect(cfg *config.Config, imageRef string, outputJSON bool) (*InspectResu
 		result.Metadata["stateSource"] = "digest-scoped"
 	}
 
+	if showInput {
+		if lastVerify != nil && lastVerify.Result != nil && len(lastVerify.Result.Input) > 0 {
+			result.Policy.Input = lastVerify.Result.Input
+		} else if !outputJSON {
+			ui.PrintWarning("No policy input recorded for this image (run acc verify first)")
+		}
+	}
+
 	// Load waivers and check expiry status
 	loadedWaivers, err := waivers.LoadWaivers()
 	if err == nil && len(loadedWaivers) > 0 {
@@ -128,6 +161,11 @@ func Inspect(cfg *config.Config, imageRef string, outputJSON bool) (*InspectResu
 
 // resolveDigest attempts to resolve the digest for an image reference
 func resolveDigest(imageRef string) (string, error) {
+	// Digest references (including --digest) resolve without a container runtime
+	if _, digest, ok := oci.SplitDigestRef(imageRef); ok {
+		return digest, nil
+	}
+
 	// Try different tools to get the digest
 	tools := []struct {
 		name string
@@ -155,9 +193,9 @@ func resolveDigest(imageRef string) (string, error) {
 	return "", fmt.Errorf("could not resolve digest using available tools\n\nRemediation:\n  - Pull the image first: docker pull %s\n  - Or ensure the image exists locally", imageRef)
 }
 
-// findSBOM looks for SBOM files in .acc/sbom/
+// findSBOM looks for SBOM files in the SBOM directory (sbom.dir, default .acc/sbom/)
 func findSBOM(cfg *config.Config) (string, string) {
-	sbomDir := filepath.Join(".acc", "sbom")
+	sbomDir := cfg.SBOMDir()
 	if _, err := os.Stat(sbomDir); os.IsNotExist(err) {
 		return "", ""
 	}
@@ -177,10 +215,13 @@ func findSBOM(cfg *config.Config) (string, string) {
 	for _, file := range files {
 		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
 			path := filepath.Join(sbomDir, file.Name())
-			// Detect format from filename
-			format := "spdx"
-			if strings.Contains(file.Name(), "cyclonedx") {
-				format = "cyclonedx"
+			// Detect format from content, falling back to the filename
+			format, err := sbom.DetectFileFormat(path)
+			if err != nil {
+				format = "spdx"
+				if strings.Contains(file.Name(), "cyclonedx") {
+					format = "cyclonedx"
+				}
 			}
 			return path, format
 		}
@@ -225,6 +266,9 @@ type LastVerifyStatus struct {
 	Status    string `json:"status"`
 	Timestamp string `json:"timestamp"`
 	ImageRef  string `json:"imageRef"`
+	Result    *struct {
+		Input json.RawMessage `json:"input,omitempty"` // RegoInput, absent when policy was not evaluated
+	} `json:"result,omitempty"`
 }
 
 // loadLastVerifyStatus loads the last verification status from state
@@ -252,7 +296,7 @@ func loadVerifyStatusForImage(digest string) *LastVerifyStatus {
 	}
 
 	// Try loading digest-scoped state first
-	digestFile := filepath.Join(".acc", "state", "verify", digest+".json")
+	digestFile := filepath.Join(".acc", "state", "verify", oci.DigestFileName(digest)+".json")
 	data, err := os.ReadFile(digestFile)
 	if err != nil {
 		// Digest-scoped file not found, fall back to global
@@ -276,7 +320,7 @@ func printHumanInspect(result *InspectResult) {
 	// Image information
 	fmt.Printf("Image:          %s\n", result.ImageRef)
 	if result.Digest != "" {
-		fmt.Printf("Digest:         sha256:%s\n", result.Digest)
+		fmt.Printf("Digest:         %s\n", oci.QualifiedDigest(result.Digest))
 	} else {
 		ui.PrintWarning("Digest:         (not resolved)")
 	}
@@ -302,6 +346,9 @@ func printHumanInspect(result *InspectResult) {
 	fmt.Println("Artifacts:")
 	if result.Artifacts.SBOMPath != "" {
 		ui.PrintSuccess(fmt.Sprintf("  SBOM:         %s (%s)", result.Artifacts.SBOMPath, result.Artifacts.SBOMFormat))
+		if summary := result.Artifacts.SBOMSummary; summary != nil {
+			printSBOMSummary(summary)
+		}
 	} else {
 		ui.PrintWarning("  SBOM:         (not found)")
 	}
@@ -334,6 +381,79 @@ func printHumanInspect(result *InspectResult) {
 			}
 		}
 	}
+
+	if len(result.Policy.Input) > 0 {
+		printPolicyInput(result.Policy.Input)
+	}
+}
+
+// printPolicyInput prints the --show-input policy input: the fields most policies read,
+// then the whole document (including expanded fields such as build and sbom.changed)
+func printPolicyInput(input json.RawMessage) {
+	var summary struct {
+		Config struct {
+			User   string            `json:"User"`
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+		SBOM struct {
+			Present bool `json:"present"`
+		} `json:"sbom"`
+		Attestation struct {
+			Present bool `json:"present"`
+		} `json:"attestation"`
+	}
+	if err := json.Unmarshal(input, &summary); err != nil {
+		ui.PrintWarning(fmt.Sprintf("  Input:        (unreadable: %v)", err))
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Policy Input (last verify):")
+	user := summary.Config.User
+	if user == "" {
+		user = "(unset, runs as root)"
+	}
+	fmt.Printf("  User:         %s\n", user)
+	if len(summary.Config.Labels) == 0 {
+		fmt.Println("  Labels:       (none)")
+	} else {
+		keys := make([]string, 0, len(summary.Config.Labels))
+		for k := range summary.Config.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Println("  Labels:")
+		for _, k := range keys {
+			fmt.Printf("    %s=%s\n", k, summary.Config.Labels[k])
+		}
+	}
+	fmt.Printf("  SBOM:         %t\n", summary.SBOM.Present)
+	fmt.Printf("  Attestation:  %t\n", summary.Attestation.Present)
+
+	var doc bytes.Buffer
+	if err := json.Indent(&doc, input, "    ", "  "); err == nil {
+		fmt.Println("  Document:")
+		fmt.Printf("    %s\n", doc.String())
+	}
+}
+
+// printSBOMSummary prints the --sbom-summary details under the SBOM artifact line
+func printSBOMSummary(summary *sbom.Summary) {
+	fmt.Printf("    Components:   %d\n", summary.Components)
+	if len(summary.TopLicenses) > 0 {
+		var licenses []string
+		for _, l := range summary.TopLicenses {
+			licenses = append(licenses, fmt.Sprintf("%s (%d)", l.License, l.Count))
+		}
+		fmt.Printf("    Licenses:     %s\n", strings.Join(licenses, ", "))
+	}
+	for _, pkg := range summary.Watchlist {
+		name := pkg.Name
+		if pkg.Version != "" {
+			name += "@" + pkg.Version
+		}
+		ui.PrintWarning(fmt.Sprintf("    Watchlist:    %s", name))
+	}
 }
 
 // FormatJSON formats inspection result as JSON
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cloudcwfranck/acc/internal/config"
@@ -28,7 +29,7 @@ func TestInspect(t *testing.T) {
 	cfg := config.DefaultConfig("test-project")
 
 	// Test inspect with no artifacts
-	result, err := Inspect(cfg, "test:latest", true)
+	result, err := Inspect(cfg, "test:latest", false, false, true)
 	if err != nil {
 		t.Fatalf("Inspect() failed: %v", err)
 	}
@@ -406,3 +407,163 @@ func TestInspect_DoesNotLeakLastVerify(t *testing.T) {
 		t.Errorf("Expected imageRef 'test:image', got '%s'", status.ImageRef)
 	}
 }
+
+// TestInspect_DigestRef tests that inspect reads digest-scoped state for a provided digest
+func TestInspect_DigestRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	// No container runtime available
+	t.Setenv("PATH", "")
+
+	digest := strings.Repeat("cd", 32)
+	stateDir := filepath.Join(".acc", "state", "verify")
+	os.MkdirAll(stateDir, 0755)
+	os.WriteFile(filepath.Join(stateDir, digest+".json"), []byte(`{"status":"pass","timestamp":"2025-01-01T00:00:00Z"}`), 0644)
+	// Global state belongs to a different image and must not be used
+	os.WriteFile(filepath.Join(".acc", "state", "last_verify.json"), []byte(`{"status":"fail","timestamp":"2025-01-02T00:00:00Z"}`), 0644)
+
+	cfg := config.DefaultConfig("test-project")
+	result, err := Inspect(cfg, "ghcr.io/example/app@sha256:"+digest, false, false, true)
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if result.Digest != digest {
+		t.Errorf("digest = %q, want %q", result.Digest, digest)
+	}
+	if result.Status != "pass" || result.Metadata["lastVerified"] != "2025-01-01T00:00:00Z" {
+		t.Errorf("expected digest-scoped state, got status=%s metadata=%v", result.Status, result.Metadata)
+	}
+}
+
+// TestInspect_SBOMSummary tests that --sbom-summary embeds the parsed SBOM summary in the artifacts
+func TestInspect_SBOMSummary(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	sbomDoc := `{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": [
+  {"name": "log4j-core", "version": "2.14.1", "licenses": [{"license": {"id": "Apache-2.0"}}]},
+  {"name": "zlib", "version": "1.2.13", "licenses": [{"license": {"name": "Zlib"}}]},
+  {"name": "musl", "version": "1.2.4", "licenses": [{"license": {"id": "MIT"}}]}
+]}`
+	os.MkdirAll(filepath.Join(".acc", "sbom"), 0755)
+	os.WriteFile(filepath.Join(".acc", "sbom", "test-project.cyclonedx.json"), []byte(sbomDoc), 0644)
+
+	cfg := config.DefaultConfig("test-project")
+	cfg.SBOM.Format = "cyclonedx"
+	cfg.SBOM.Watchlist = []string{"log4j-*"}
+
+	result, err := Inspect(cfg, "test:latest", false, false, true)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	if result.Artifacts.SBOMSummary != nil {
+		t.Error("expected no SBOM summary without --sbom-summary")
+	}
+
+	result, err = Inspect(cfg, "test:latest", true, false, true)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	summary := result.Artifacts.SBOMSummary
+	if summary == nil {
+		t.Fatal("expected an SBOM summary")
+	}
+	if summary.Components != 3 {
+		t.Errorf("components = %d, want 3", summary.Components)
+	}
+	if len(summary.TopLicenses) != 3 {
+		t.Errorf("expected 3 licenses, got %+v", summary.TopLicenses)
+	}
+	if len(summary.Watchlist) != 1 || summary.Watchlist[0].Name != "log4j-core" {
+		t.Errorf("expected log4j-core on the watchlist, got %+v", summary.Watchlist)
+	}
+	if !strings.Contains(result.FormatJSON(), `"sbomSummary"`) {
+		t.Error("expected sbomSummary in JSON output")
+	}
+}
+
+// TestInspect_ShowInput tests that --show-input surfaces the policy input saved by the last verify
+func TestInspect_ShowInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(tmpDir); err != nil {
+		t.Fatalf("failed to change to temp dir: %v", err)
+	}
+	defer os.Chdir(originalDir)
+
+	cfg := config.DefaultConfig("test-project")
+
+	// No state yet: nothing to show, and inspect still succeeds
+	result, err := Inspect(cfg, "test:latest", false, true, true)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	if result.Policy.Input != nil {
+		t.Errorf("expected no input without verification state, got %s", result.Policy.Input)
+	}
+
+	state := `{
+  "imageRef": "test:latest",
+  "status": "fail",
+  "timestamp": "2025-01-01T00:00:00Z",
+  "result": {
+    "status": "fail",
+    "input": {
+      "config": {"User": "root", "Labels": {"org.opencontainers.image.source": "https://example.com/app"}},
+      "sbom": {"present": true},
+      "attestation": {"present": false},
+      "promotion": false,
+      "build": {"baseImage": "alpine:3.19", "createdAt": "2025-01-01T00:00:00Z"}
+    }
+  }
+}`
+	os.MkdirAll(filepath.Join(".acc", "state"), 0755)
+	os.WriteFile(filepath.Join(".acc", "state", "last_verify.json"), []byte(state), 0644)
+
+	result, err = Inspect(cfg, "test:latest", false, false, true)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	if result.Policy.Input != nil {
+		t.Error("expected no input without --show-input")
+	}
+
+	result, err = Inspect(cfg, "test:latest", false, true, true)
+	if err != nil {
+		t.Fatalf("Inspect() failed: %v", err)
+	}
+	var input struct {
+		Config struct {
+			User   string            `json:"User"`
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+		SBOM struct {
+			Present bool `json:"present"`
+		} `json:"sbom"`
+		Build struct {
+			BaseImage string `json:"baseImage"`
+		} `json:"build"`
+	}
+	if err := json.Unmarshal(result.Policy.Input, &input); err != nil {
+		t.Fatalf("input is not valid JSON: %v", err)
+	}
+	if input.Config.User != "root" || input.Config.Labels["org.opencontainers.image.source"] == "" || !input.SBOM.Present {
+		t.Errorf("unexpected input %s", result.Policy.Input)
+	}
+	if input.Build.BaseImage != "alpine:3.19" {
+		t.Errorf("expected expanded build fields to be kept, got %s", result.Policy.Input)
+	}
+
+	var parsed map[string]map[string]interface{}
+	json.Unmarshal([]byte(result.FormatJSON()), &parsed)
+	if _, ok := parsed["policy"]["input"]; !ok {
+		t.Errorf("expected policy.input in JSON output:\n%s", result.FormatJSON())
+	}
+}
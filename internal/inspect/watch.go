@@ -0,0 +1,99 @@
+package inspect
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// watchDebounce coalesces the burst of events from one write (temp file, rename, lock)
+// into a single redraw
+var watchDebounce = 200 * time.Millisecond
+
+// Watch calls redraw, then calls it again after each change to the verification state,
+// attestations, SBOMs, or waivers under .acc (and sbom.dir when it is elsewhere), until
+// ctx is done. Directories created while watching, such as a new image's attestations,
+// are watched as they appear.
+func Watch(ctx context.Context, cfg *config.Config, redraw func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	roots := []string{".acc"}
+	if sbomDir := cfg.SBOMDir(); !strings.HasPrefix(filepath.Clean(sbomDir), ".acc") {
+		roots = append(roots, sbomDir)
+	}
+	for _, root := range roots {
+		if err := addWatchTree(watcher, root); err != nil {
+			return err
+		}
+	}
+
+	redraw()
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					addWatchTree(watcher, event.Name)
+				}
+			}
+			if watchRelevant(event.Name) {
+				pending = time.After(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		case <-pending:
+			pending = nil
+			redraw()
+		}
+	}
+}
+
+// addWatchTree watches root and every directory below it except caches; a missing root is skipped
+func addWatchTree(watcher *fsnotify.Watcher, root string) error {
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if d.Name() == "cache" {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// watchRelevant reports whether a change to path can change the trust summary; lock files
+// and temp files written before an atomic rename are not
+func watchRelevant(path string) bool {
+	base := filepath.Base(path)
+	if strings.HasSuffix(base, ".lock") || strings.HasPrefix(base, ".") && strings.Contains(base, ".tmp") {
+		return false
+	}
+	return !strings.Contains(filepath.ToSlash(path), "/cache/")
+}
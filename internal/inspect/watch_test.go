@@ -0,0 +1,77 @@
+package inspect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/state"
+)
+
+// TestWatch tests that a state change and a new image's attestation each trigger one redraw
+func TestWatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+	os.MkdirAll(filepath.Join(".acc", "state", "verify"), 0755)
+
+	redraws := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, config.DefaultConfig("watch-test"), func() { redraws <- struct{}{} })
+	}()
+
+	waitRedraw := func(what string) {
+		t.Helper()
+		select {
+		case <-redraws:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no redraw after %s", what)
+		}
+	}
+	waitRedraw("start")
+
+	// An atomic state write (lock file, temp file, rename) redraws once
+	state.WriteFile(filepath.Join(".acc", "state", "verify", "abc.json"), []byte(`{"status":"pass"}`), 0644)
+	waitRedraw("a state change")
+
+	// A new attestations directory is watched as it appears
+	attDir := filepath.Join(".acc", "attestations", "abc")
+	os.MkdirAll(attDir, 0755)
+	waitRedraw("creating the attestations directory")
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(filepath.Join(attDir, "att.json"), []byte("{}"), 0644)
+	waitRedraw("an attestation")
+
+	select {
+	case <-redraws:
+		t.Error("expected one redraw per change")
+	case <-time.After(2 * watchDebounce):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch returned %v", err)
+	}
+}
+
+func TestWatchRelevant(t *testing.T) {
+	for path, want := range map[string]bool{
+		".acc/state/verify/abc.json":            true,
+		".acc/state/verify/abc.json.lock":       false,
+		".acc/state/verify/.abc.json.tmp-123":   false,
+		".acc/attestations/abc/att.json":        true,
+		".acc/cache/image-config/abc.json":      false,
+		".acc/waivers.yaml":                     true,
+		filepath.Join("sboms", "app.spdx.json"): true,
+	} {
+		if got := watchRelevant(path); got != want {
+			t.Errorf("watchRelevant(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
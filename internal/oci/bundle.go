@@ -0,0 +1,170 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// Media types of a config bundle: one acc.yaml layer plus any number of .rego layers, each
+// named by its org.opencontainers.image.title annotation (as oras push records file names)
+const (
+	MediaTypeConfigBundleConfig = "application/vnd.acc.config.v1+yaml"
+	MediaTypeConfigBundlePolicy = "application/vnd.acc.policy.v1+rego"
+)
+
+// maxBundleLayerSize bounds each config or policy layer read from a registry
+const maxBundleLayerSize = 4 << 20
+
+// ConfigBundle is a central acc.yaml and policy pack pulled from a registry
+type ConfigBundle struct {
+	Ref        string // reference as given (tag or digest)
+	Digest     string // manifest digest the reference resolved to
+	Dir        string // cache directory holding the bundle
+	ConfigPath string // <Dir>/acc.yaml
+	PolicyDir  string // <Dir>/policy
+	Cached     bool   // the bundle was already cached; no layers were fetched
+}
+
+// PullConfigBundle resolves reference in repo and caches its bundle under cacheDir/<digest>.
+// Every blob is checked against the digest in its descriptor, and a digest reference must
+// resolve to that digest, so a pinned bundle cannot be swapped. A bundle already cached for
+// the resolved digest is reused without fetching its layers.
+func PullConfigBundle(ctx context.Context, repo *remote.Repository, reference, cacheDir string) (*ConfigBundle, error) {
+	var desc ocispec.Descriptor
+	var manifestData []byte
+	err := Retry(ctx, DefaultRetryPolicy, func() error {
+		var rc io.ReadCloser
+		var err error
+		desc, rc, err = repo.FetchReference(ctx, reference)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		manifestData, err = content.ReadAll(rc, desc)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config bundle %s: %w", reference, err)
+	}
+	if IsDigest(reference) && desc.Digest.String() != reference {
+		return nil, fmt.Errorf("config bundle digest mismatch: requested %s, registry returned %s", reference, desc.Digest)
+	}
+
+	bundle := cachedBundle(reference, desc.Digest, cacheDir)
+	if bundle.Cached {
+		return bundle, nil
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse config bundle manifest: %w", err)
+	}
+
+	// Layers are written to a temp directory that is renamed into place, so a failed or
+	// concurrent pull never leaves a partial bundle in the cache
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config bundle cache: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(cacheDir, ".pull-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config bundle cache: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "policy"), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config bundle cache: %w", err)
+	}
+
+	configs := 0
+	for _, layer := range manifest.Layers {
+		var path string
+		switch layer.MediaType {
+		case MediaTypeConfigBundleConfig:
+			configs++
+			path = filepath.Join(tmpDir, "acc.yaml")
+		case MediaTypeConfigBundlePolicy:
+			name := layer.Annotations[ocispec.AnnotationTitle]
+			if strings.ContainsAny(name, `/\`) || !strings.HasSuffix(name, ".rego") {
+				return nil, fmt.Errorf("config bundle policy layer %s has an invalid file name %q", layer.Digest, name)
+			}
+			path = filepath.Join(tmpDir, "policy", name)
+		default:
+			continue
+		}
+
+		if layer.Size > maxBundleLayerSize {
+			return nil, fmt.Errorf("config bundle layer %s too large: %d bytes", layer.Digest, layer.Size)
+		}
+		data, err := FetchVerified(ctx, repo, layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch config bundle layer %s: %w", layer.Digest, err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write config bundle: %w", err)
+		}
+	}
+	if configs != 1 {
+		return nil, fmt.Errorf("config bundle %s must have exactly one %s layer, found %d", reference, MediaTypeConfigBundleConfig, configs)
+	}
+
+	if err := os.Rename(tmpDir, bundle.Dir); err != nil {
+		// A concurrent pull of the same digest may have won the rename
+		if _, statErr := os.Stat(bundle.ConfigPath); statErr != nil {
+			return nil, fmt.Errorf("failed to cache config bundle: %w", err)
+		}
+	}
+	return bundle, nil
+}
+
+// CachedConfigBundle returns the bundle cached under cacheDir for a digest reference
+// (sha256:<hex>) without contacting the registry, or nil when it is not cached
+func CachedConfigBundle(reference, cacheDir string) *ConfigBundle {
+	dgst, err := digest.Parse(reference)
+	if err != nil {
+		return nil
+	}
+	if bundle := cachedBundle(reference, dgst, cacheDir); bundle.Cached {
+		return bundle
+	}
+	return nil
+}
+
+// cachedBundle describes the bundle for dgst under cacheDir, marking it Cached when present
+func cachedBundle(reference string, dgst digest.Digest, cacheDir string) *ConfigBundle {
+	dir := filepath.Join(cacheDir, dgst.Encoded())
+	bundle := &ConfigBundle{
+		Ref:        reference,
+		Digest:     dgst.String(),
+		Dir:        dir,
+		ConfigPath: filepath.Join(dir, "acc.yaml"),
+		PolicyDir:  filepath.Join(dir, "policy"),
+	}
+	if _, err := os.Stat(bundle.ConfigPath); err == nil {
+		bundle.Cached = true
+	}
+	return bundle
+}
+
+// FetchVerified fetches a blob or manifest, checking its size and digest against desc
+func FetchVerified(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) ([]byte, error) {
+	var data []byte
+	err := Retry(ctx, DefaultRetryPolicy, func() error {
+		rc, err := repo.Fetch(ctx, desc)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		data, err = content.ReadAll(rc, desc)
+		return err
+	})
+	return data, err
+}
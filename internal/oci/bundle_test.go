@@ -0,0 +1,146 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// newBundleRegistry serves a config bundle tagged "v1" with layers; blobs maps digests to the
+// content served for them (which may not match, to simulate tampering); blobFetches counts blob GETs
+func newBundleRegistry(t *testing.T, layers []ocispec.Descriptor, blobs map[string]string, blobFetches *atomic.Int32) (*httptest.Server, string) {
+	t.Helper()
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers:    layers,
+	})
+	manifestDigest := "sha256:" + sha256Hex(string(manifest))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body, mediaType string
+		switch path := strings.TrimPrefix(r.URL.Path, "/v2/test/repo/"); {
+		case path == "manifests/v1" || path == "manifests/"+manifestDigest:
+			body, mediaType = string(manifest), ocispec.MediaTypeImageManifest
+		case strings.HasPrefix(path, "blobs/"):
+			data, ok := blobs[strings.TrimPrefix(path, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Method == http.MethodGet && blobFetches != nil {
+				blobFetches.Add(1)
+			}
+			body, mediaType = data, "application/octet-stream"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Docker-Content-Digest", "sha256:"+sha256Hex(body))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method == http.MethodGet {
+			w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, manifestDigest
+}
+
+// bundleLayer describes content as a config bundle layer
+func bundleLayer(mediaType, data, title string) ocispec.Descriptor {
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.Digest("sha256:" + sha256Hex(data)), Size: int64(len(data))}
+	if title != "" {
+		desc.Annotations = map[string]string{ocispec.AnnotationTitle: title}
+	}
+	return desc
+}
+
+// TestPullConfigBundle tests pulling acc.yaml and policies into the cache and reusing the cached bundle
+func TestPullConfigBundle(t *testing.T) {
+	configYAML := "project:\n  name: central\n"
+	rego := "package acc.policy\n"
+	config := bundleLayer(MediaTypeConfigBundleConfig, configYAML, "acc.yaml")
+	policy := bundleLayer(MediaTypeConfigBundlePolicy, rego, "central.rego")
+	var fetches atomic.Int32
+	server, manifestDigest := newBundleRegistry(t,
+		[]ocispec.Descriptor{config, policy},
+		map[string]string{config.Digest.String(): configYAML, policy.Digest.String(): rego},
+		&fetches)
+	repo := newTestRepository(t, server)
+	cacheDir := t.TempDir()
+
+	bundle, err := PullConfigBundle(context.Background(), repo, "v1", cacheDir)
+	if err != nil {
+		t.Fatalf("PullConfigBundle failed: %v", err)
+	}
+	if bundle.Digest != manifestDigest || bundle.Cached {
+		t.Errorf("unexpected bundle %+v, want digest %s pulled", bundle, manifestDigest)
+	}
+	if data, _ := os.ReadFile(bundle.ConfigPath); string(data) != configYAML {
+		t.Errorf("acc.yaml = %q, want %q", data, configYAML)
+	}
+	if data, _ := os.ReadFile(filepath.Join(bundle.PolicyDir, "central.rego")); string(data) != rego {
+		t.Errorf("central.rego = %q, want %q", data, rego)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("expected 2 blob fetches, got %d", fetches.Load())
+	}
+
+	// The same digest is served from the cache without fetching layers
+	fetches.Store(0)
+	bundle, err = PullConfigBundle(context.Background(), repo, manifestDigest, cacheDir)
+	if err != nil || !bundle.Cached || fetches.Load() != 0 {
+		t.Errorf("expected a cached bundle without blob fetches, got %+v, %v (%d fetches)", bundle, err, fetches.Load())
+	}
+}
+
+// TestPullConfigBundle_Rejected tests that tampered content, unsafe file names, and bundles
+// without acc.yaml are rejected and nothing is cached
+func TestPullConfigBundle_Rejected(t *testing.T) {
+	configYAML := "project:\n  name: central\n"
+	config := bundleLayer(MediaTypeConfigBundleConfig, configYAML, "acc.yaml")
+	rego := "package acc.policy\n"
+
+	tests := map[string]struct {
+		layers []ocispec.Descriptor
+		blobs  map[string]string
+	}{
+		"tampered config": {
+			layers: []ocispec.Descriptor{config},
+			blobs:  map[string]string{config.Digest.String(): "project:\n  name: evil\n"},
+		},
+		"path in policy name": {
+			layers: []ocispec.Descriptor{config, bundleLayer(MediaTypeConfigBundlePolicy, rego, "../escape.rego")},
+			blobs:  map[string]string{config.Digest.String(): configYAML},
+		},
+		"no config": {
+			layers: []ocispec.Descriptor{bundleLayer(MediaTypeConfigBundlePolicy, rego, "central.rego")},
+			blobs:  map[string]string{"sha256:" + sha256Hex(rego): rego},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, _ := newBundleRegistry(t, tt.layers, tt.blobs, nil)
+			cacheDir := t.TempDir()
+			if _, err := PullConfigBundle(context.Background(), newTestRepository(t, server), "v1", cacheDir); err == nil {
+				t.Fatal("expected the bundle to be rejected")
+			}
+			if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+				t.Errorf("expected nothing cached, got %v", entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// maxConfigSize bounds manifest and config blobs read from a registry
+const maxConfigSize = 4 << 20
+
+// ImageConfig is an image's OCI config plus the Docker extensions acc reads
+type ImageConfig struct {
+	ocispec.ImageConfig
+	Healthcheck *HealthConfig `json:"Healthcheck,omitempty"`
+}
+
+// HealthConfig is an image's HEALTHCHECK as Docker records it. Durations are in nanoseconds;
+// a Test of ["NONE"] disables a healthcheck inherited from the base image.
+type HealthConfig struct {
+	Test        []string `json:"Test,omitempty"`
+	Interval    int64    `json:"Interval,omitempty"`
+	Timeout     int64    `json:"Timeout,omitempty"`
+	StartPeriod int64    `json:"StartPeriod,omitempty"`
+	Retries     int      `json:"Retries,omitempty"`
+}
+
+// Defined reports whether h is a healthcheck that runs (set, and not HEALTHCHECK NONE)
+func (h *HealthConfig) Defined() bool {
+	return h != nil && len(h.Test) > 0 && h.Test[0] != "NONE"
+}
+
+// FetchImageConfig reads an image's config (user, labels, ...) from a registry without pulling layers.
+// reference is a tag or digest; index manifests are not supported (use a platform-specific digest).
+// A digest may also be the config's own digest (the image ID, as --digest takes it).
+func FetchImageConfig(ctx context.Context, repo *remote.Repository, reference string) (*ImageConfig, error) {
+	configDesc, err := imageConfigDescriptor(ctx, repo, reference)
+	if err != nil {
+		return nil, err
+	}
+	if configDesc.Size > maxConfigSize {
+		return nil, fmt.Errorf("image config too large: %d bytes", configDesc.Size)
+	}
+
+	var configData []byte
+	err = Retry(ctx, DefaultRetryPolicy, func() error {
+		rc, err := repo.Fetch(ctx, configDesc)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		configData, err = io.ReadAll(io.LimitReader(rc, maxConfigSize))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image config: %w", err)
+	}
+
+	var image struct {
+		Config ImageConfig `json:"config"`
+	}
+	if err := json.Unmarshal(configData, &image); err != nil {
+		return nil, fmt.Errorf("failed to parse image config: %w", err)
+	}
+
+	return &image.Config, nil
+}
+
+// imageConfigDescriptor returns the config descriptor of the manifest reference names. A digest
+// that is not a manifest in repo is looked up as a config blob: image IDs are config digests.
+func imageConfigDescriptor(ctx context.Context, repo *remote.Repository, reference string) (ocispec.Descriptor, error) {
+	var manifestData []byte
+	err := Retry(ctx, DefaultRetryPolicy, func() error {
+		desc, rc, err := repo.FetchReference(ctx, reference)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		manifestData, err = content.ReadAll(rc, desc)
+		return err
+	})
+	if err != nil {
+		if IsDigest(reference) && errors.Is(err, errdef.ErrNotFound) {
+			if desc, blobErr := repo.Blobs().Resolve(ctx, reference); blobErr == nil {
+				return desc, nil
+			}
+		}
+		return ocispec.Descriptor{}, fmt.Errorf("failed to fetch manifest %s: %w", reference, err)
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Config.Digest == "" {
+		return ocispec.Descriptor{}, fmt.Errorf("manifest %s has no config (media type %s); pass a platform-specific digest", reference, manifest.MediaType)
+	}
+	return manifest.Config, nil
+}
@@ -0,0 +1,85 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// TestFetchImageConfig tests reading the image config by digest without pulling layers
+func TestFetchImageConfig(t *testing.T) {
+	config := `{"architecture":"amd64","os":"linux","config":{"User":"1000","Labels":{"org.example":"yes"}},"rootfs":{"type":"layers","diff_ids":[]}}`
+	manifest := fmt.Sprintf(`{"schemaVersion":2,"mediaType":"%s","config":{"mediaType":"%s","digest":"sha256:%s","size":%d},"layers":[]}`,
+		ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageConfig, sha256Hex(config), len(config))
+	manifestDigest := "sha256:" + sha256Hex(manifest)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body, mediaType string
+		switch r.URL.Path {
+		case "/v2/test/repo/manifests/" + manifestDigest:
+			body, mediaType = manifest, ocispec.MediaTypeImageManifest
+		case "/v2/test/repo/blobs/sha256:" + sha256Hex(config):
+			body, mediaType = config, ocispec.MediaTypeImageConfig
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Docker-Content-Digest", "sha256:"+sha256Hex(body))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method == http.MethodGet {
+			w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	repo := newTestRepository(t, server)
+
+	imageConfig, err := FetchImageConfig(context.Background(), repo, manifestDigest)
+	if err != nil {
+		t.Fatalf("FetchImageConfig failed: %v", err)
+	}
+	if imageConfig.User != "1000" || imageConfig.Labels["org.example"] != "yes" {
+		t.Errorf("unexpected config: %+v", imageConfig)
+	}
+
+	// An image ID (the config digest) reads the config blob directly
+	imageConfig, err = FetchImageConfig(context.Background(), repo, "sha256:"+sha256Hex(config))
+	if err != nil || imageConfig.User != "1000" {
+		t.Errorf("expected the config by image ID, got %+v, %v", imageConfig, err)
+	}
+
+	if _, err := FetchImageConfig(context.Background(), repo, "sha256:"+strings.Repeat("0", 64)); err == nil {
+		t.Error("expected error for unknown digest")
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		ref                           string
+		registry, repository, reftext string
+		wantErr                       bool
+	}{
+		{ref: "ghcr.io/org/app:1.0", registry: "ghcr.io", repository: "org/app", reftext: "1.0"},
+		{ref: "localhost:5000/app@sha256:abc", registry: "localhost:5000", repository: "app", reftext: "sha256:abc"},
+		{ref: "ghcr.io/org/app", registry: "ghcr.io", repository: "org/app", reftext: "latest"},
+		{ref: "app:1.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		registry, repository, reference, err := ParseReference(tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseReference(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			continue
+		}
+		if registry != tt.registry || repository != tt.repository || reference != tt.reftext {
+			t.Errorf("ParseReference(%q) = (%q, %q, %q)", tt.ref, registry, repository, reference)
+		}
+	}
+}
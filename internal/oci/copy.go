@@ -0,0 +1,87 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// ConfigDigests returns the image config digests referenced by a manifest: the manifest's own
+// config, or the config of every platform manifest for an index. The local image ID reported by
+// docker inspect is one of these, which ties a registry manifest to a locally verified image.
+func ConfigDigests(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) ([]string, error) {
+	data, err := fetchManifest(ctx, repo, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest struct {
+		MediaType string               `json:"mediaType"`
+		Config    ocispec.Descriptor   `json:"config"`
+		Manifests []ocispec.Descriptor `json:"manifests"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	if manifest.Config.Digest != "" {
+		return []string{manifest.Config.Digest.String()}, nil
+	}
+
+	var digests []string
+	for _, child := range manifest.Manifests {
+		childDigests, err := ConfigDigests(ctx, repo, child)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, childDigests...)
+	}
+	if len(digests) == 0 {
+		return nil, fmt.Errorf("manifest %s references no image config (media type %s)", desc.Digest, manifest.MediaType)
+	}
+	return digests, nil
+}
+
+// CopyManifest copies the manifest desc and everything it references from src to dst and tags
+// it as tag. The manifest bytes are copied unchanged, so the digest is preserved.
+func CopyManifest(ctx context.Context, src, dst *remote.Repository, desc ocispec.Descriptor, tag string) (ocispec.Descriptor, error) {
+	var copied ocispec.Descriptor
+	err := Retry(ctx, DefaultRetryPolicy, func() error {
+		var err error
+		copied, err = oras.Copy(ctx, src, desc.Digest.String(), dst, tag, oras.DefaultCopyOptions)
+		return err
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to copy %s: %w", desc.Digest, err)
+	}
+	if copied.Digest != desc.Digest {
+		return ocispec.Descriptor{}, fmt.Errorf("copy verification failed: destination digest %s does not match %s", copied.Digest, desc.Digest)
+	}
+	return copied, nil
+}
+
+// fetchManifest reads a manifest's bytes, bounded by maxConfigSize
+func fetchManifest(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxConfigSize {
+		return nil, fmt.Errorf("manifest too large: %d bytes", desc.Size)
+	}
+	var data []byte
+	err := Retry(ctx, DefaultRetryPolicy, func() error {
+		rc, err := repo.Manifests().Fetch(ctx, desc)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		data, err = content.ReadAll(rc, desc)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest %s: %w", desc.Digest, err)
+	}
+	return data, nil
+}
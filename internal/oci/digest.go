@@ -0,0 +1,120 @@
+package oci
+
+import (
+	_ "crypto/sha512" // registers sha512 for go-digest verification of sha512 content
+	"fmt"
+	"strings"
+)
+
+// Digest algorithms acc accepts in image references (@<algorithm>:<hex>) and --digest
+const (
+	DigestSHA256 = "sha256"
+	DigestSHA512 = "sha512"
+)
+
+// digestHexLengths is the hex length of each supported algorithm's digest
+var digestHexLengths = map[string]int{DigestSHA256: 64, DigestSHA512: 128}
+
+// Digests are passed around acc as bare hex for sha256 (the form state and attestations have
+// always used) and as <algorithm>:<hex> for any other algorithm, so the algorithm is kept
+// wherever the digest is stored. DigestFileName, ShortDigest, and QualifiedDigest convert
+// that form for file names, tags, and display.
+
+// ParseDigest parses d as <algorithm>:<hex>, or bare hex of algorithm (sha256 when empty),
+// and returns it in acc's digest form
+func ParseDigest(d, algorithm string) (string, error) {
+	d = strings.ToLower(strings.TrimSpace(d))
+	if alg, hex, ok := strings.Cut(d, ":"); ok {
+		algorithm, d = alg, hex
+	}
+	if algorithm == "" {
+		algorithm = DigestSHA256
+	}
+	length, ok := digestHexLengths[algorithm]
+	if !ok {
+		return "", fmt.Errorf("unsupported digest algorithm %q (use sha256 or sha512)", algorithm)
+	}
+	if len(d) != length || strings.Trim(d, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid %s digest: expected %d hex characters", algorithm, length)
+	}
+	if algorithm == DigestSHA256 {
+		return d, nil
+	}
+	return algorithm + ":" + d, nil
+}
+
+// SplitDigestRef splits a name@<algorithm>:<hex> reference into the name and the digest
+// (in acc's digest form); ok is false for references without a supported digest
+func SplitDigestRef(ref string) (name, digest string, ok bool) {
+	idx := strings.LastIndex(ref, "@")
+	if idx < 0 {
+		return "", "", false
+	}
+	if !IsDigest(ref[idx+1:]) {
+		return "", "", false
+	}
+	digest, _ = ParseDigest(ref[idx+1:], "")
+	return ref[:idx], digest, true
+}
+
+// IsDigest reports whether reference is an <algorithm>:<hex> digest rather than a tag
+func IsDigest(reference string) bool {
+	_, err := ParseDigest(reference, "")
+	return err == nil && strings.Contains(reference, ":")
+}
+
+// DigestAlgorithm returns the algorithm of a digest in acc's form
+func DigestAlgorithm(digest string) string {
+	if alg, _, ok := strings.Cut(digest, ":"); ok {
+		return alg
+	}
+	return DigestSHA256
+}
+
+// QualifiedDigest returns digest as <algorithm>:<hex>
+func QualifiedDigest(digest string) string {
+	if strings.Contains(digest, ":") {
+		return digest
+	}
+	return DigestSHA256 + ":" + digest
+}
+
+// NormalizeDigest returns d, with or without its algorithm prefix, in acc's digest form for
+// comparison: trimmed, lowercased, and without a sha256: prefix. Unlike ParseDigest it does
+// not validate d.
+func NormalizeDigest(d string) string {
+	d = strings.ToLower(strings.TrimSpace(d))
+	return strings.TrimPrefix(d, DigestSHA256+":")
+}
+
+// DigestFileName returns digest in a form usable in file names and tags: bare hex for
+// sha256, <algorithm>-<hex> otherwise
+func DigestFileName(digest string) string {
+	return strings.Replace(digest, ":", "-", 1)
+}
+
+// ShortDigest returns the 12-character prefix used for per-image directories and tags,
+// after an <algorithm>- marker for non-sha256 digests
+func ShortDigest(digest string) string {
+	alg, hex, ok := strings.Cut(digest, ":")
+	if !ok {
+		alg, hex = "", digest
+	}
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	if alg == "" {
+		return hex
+	}
+	return alg + "-" + hex
+}
+
+// DisplayDigest returns <algorithm>:<first 12 hex characters> for human-readable output
+func DisplayDigest(digest string) string {
+	qualified := QualifiedDigest(digest)
+	alg, hex, _ := strings.Cut(qualified, ":")
+	if len(hex) > 12 {
+		hex = hex[:12]
+	}
+	return alg + ":" + hex
+}
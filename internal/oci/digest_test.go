@@ -0,0 +1,83 @@
+package oci
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDigest(t *testing.T) {
+	sha256Hex := strings.Repeat("ab", 32)
+	sha512Hex := strings.Repeat("cd", 64)
+
+	tests := []struct {
+		digest, algorithm string
+		want              string
+		wantErr           bool
+	}{
+		{sha256Hex, "", sha256Hex, false},
+		{"sha256:" + sha256Hex, "", sha256Hex, false},
+		{"SHA256:" + strings.ToUpper(sha256Hex), "", sha256Hex, false},
+		{"sha512:" + sha512Hex, "", "sha512:" + sha512Hex, false},
+		{sha512Hex, DigestSHA512, "sha512:" + sha512Hex, false},
+		{"sha512:" + sha512Hex, DigestSHA256, "sha512:" + sha512Hex, false}, // the prefix wins
+		{sha512Hex, "", "", true},
+		{sha256Hex, DigestSHA512, "", true},
+		{"md5:" + sha256Hex, "", "", true},
+		{strings.Repeat("zz", 32), "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDigest(tt.digest, tt.algorithm)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDigest(%q, %q) = %q, %v; want %q (error %v)", tt.digest, tt.algorithm, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSplitDigestRef(t *testing.T) {
+	sha256Hex := strings.Repeat("ab", 32)
+	sha512Hex := strings.Repeat("cd", 64)
+
+	tests := []struct {
+		ref        string
+		wantName   string
+		wantDigest string
+		wantOK     bool
+	}{
+		{"ghcr.io/org/app@sha256:" + sha256Hex, "ghcr.io/org/app", sha256Hex, true},
+		{"ghcr.io/org/app@sha512:" + sha512Hex, "ghcr.io/org/app", "sha512:" + sha512Hex, true},
+		{"localhost:5000/app:v1@sha512:" + sha512Hex, "localhost:5000/app:v1", "sha512:" + sha512Hex, true},
+		{"ghcr.io/org/app@sha512:" + sha256Hex, "", "", false},
+		{"ghcr.io/org/app@" + sha256Hex, "", "", false},
+		{"ghcr.io/org/app:v1", "", "", false},
+	}
+	for _, tt := range tests {
+		name, digest, ok := SplitDigestRef(tt.ref)
+		if name != tt.wantName || digest != tt.wantDigest || ok != tt.wantOK {
+			t.Errorf("SplitDigestRef(%q) = %q, %q, %v; want %q, %q, %v", tt.ref, name, digest, ok, tt.wantName, tt.wantDigest, tt.wantOK)
+		}
+	}
+}
+
+func TestDigestForms(t *testing.T) {
+	sha256Hex := strings.Repeat("ab", 32)
+	sha512Digest := "sha512:" + strings.Repeat("cd", 64)
+
+	if DigestAlgorithm(sha256Hex) != DigestSHA256 || DigestAlgorithm(sha512Digest) != DigestSHA512 {
+		t.Error("expected DigestAlgorithm to report sha256 for bare hex and sha512 for sha512:<hex>")
+	}
+	if QualifiedDigest(sha256Hex) != "sha256:"+sha256Hex || QualifiedDigest(sha512Digest) != sha512Digest {
+		t.Error("expected QualifiedDigest to prefix bare hex with sha256: only")
+	}
+	if DigestFileName(sha256Hex) != sha256Hex || DigestFileName(sha512Digest) != "sha512-"+strings.Repeat("cd", 64) {
+		t.Errorf("unexpected file names %q, %q", DigestFileName(sha256Hex), DigestFileName(sha512Digest))
+	}
+	if ShortDigest(sha256Hex) != "abababababab" || ShortDigest(sha512Digest) != "sha512-cdcdcdcdcdcd" {
+		t.Errorf("unexpected short digests %q, %q", ShortDigest(sha256Hex), ShortDigest(sha512Digest))
+	}
+	if DisplayDigest(sha256Hex) != "sha256:abababababab" || DisplayDigest(sha512Digest) != "sha512:cdcdcdcdcdcd" {
+		t.Errorf("unexpected display digests %q, %q", DisplayDigest(sha256Hex), DisplayDigest(sha512Digest))
+	}
+	if !IsDigest(sha512Digest) || IsDigest(sha256Hex) || IsDigest("v1.0") {
+		t.Error("expected IsDigest to accept only <algorithm>:<hex>")
+	}
+}
@@ -0,0 +1,113 @@
+package oci
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+// ParseReference splits an image reference into registry, repository, and tag or digest
+// e.g. ghcr.io/org/app:1.0 -> ("ghcr.io", "org/app", "1.0"); the reference defaults to "latest"
+func ParseReference(imageRef string) (registry, repository, reference string, err error) {
+	parts := strings.SplitN(imageRef, "/", 2)
+	if len(parts) < 2 {
+		return "", "", "", fmt.Errorf("invalid image reference format: %s (expected <registry>/<repository>[:tag|@digest])", imageRef)
+	}
+
+	registry = parts[0]
+	rest := parts[1]
+
+	switch {
+	case strings.Contains(rest, "@"):
+		repoParts := strings.SplitN(rest, "@", 2)
+		repository, reference = repoParts[0], repoParts[1]
+	case strings.Contains(rest, ":"):
+		repoParts := strings.SplitN(rest, ":", 2)
+		repository, reference = repoParts[0], repoParts[1]
+	default:
+		repository, reference = rest, "latest"
+	}
+
+	return registry, repository, reference, nil
+}
+
+// NewRepository creates a registry client for registry/repository using Docker credentials if present
+// Without credentials the client is anonymous (public repositories)
+func NewRepository(registry, repository string) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", registry, repository))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository client: %w", err)
+	}
+
+	// Docker config may key the registry in several formats
+	var cred auth.Credential
+	for _, key := range []string{registry, "https://" + registry, "https://" + registry + "/v2/"} {
+		if c, err := dockerCredential(key); err == nil {
+			cred = c
+			break
+		}
+	}
+
+	repo.Client = &auth.Client{
+		Client: retry.DefaultClient,
+		Cache:  auth.NewCache(),
+		Credential: auth.CredentialFunc(func(ctx context.Context, reg string) (auth.Credential, error) {
+			return cred, nil
+		}),
+	}
+
+	return repo, nil
+}
+
+// dockerCredential reads credentials for a registry key from ~/.docker/config.json
+func dockerCredential(registry string) (auth.Credential, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return auth.Credential{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(homeDir, ".docker", "config.json"))
+	if err != nil {
+		return auth.Credential{}, err
+	}
+
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username,omitempty"`
+			Password string `json:"password,omitempty"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return auth.Credential{}, err
+	}
+
+	entry, ok := config.Auths[registry]
+	if !ok {
+		return auth.Credential{}, fmt.Errorf("no credentials found for %s", registry)
+	}
+	if entry.Username != "" && entry.Password != "" {
+		return auth.Credential{Username: entry.Username, Password: entry.Password}, nil
+	}
+	if entry.Auth != "" {
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return auth.Credential{}, fmt.Errorf("failed to decode auth: %w", err)
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			return auth.Credential{}, fmt.Errorf("invalid auth format")
+		}
+		return auth.Credential{Username: parts[0], Password: parts[1]}, nil
+	}
+
+	return auth.Credential{}, fmt.Errorf("no credentials found for %s", registry)
+}
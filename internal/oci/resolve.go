@@ -0,0 +1,49 @@
+package oci
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ResolveDigest resolves an image reference to its digest (acc's form, see QualifiedDigest).
+// Digest references (including --digest) resolve without a container runtime; otherwise the
+// image ID is read from the first of docker, podman, or nerdctl that knows the image.
+func ResolveDigest(imageRef string) (string, error) {
+	if _, digest, ok := SplitDigestRef(imageRef); ok {
+		return digest, nil
+	}
+	return InspectImageID(imageRef)
+}
+
+// InspectImageID returns the ID (config digest) of a local image from the first of docker,
+// podman, or nerdctl that knows imageRef. Unlike ResolveDigest it always asks the runtime,
+// so a name@<manifest digest> reference yields the image ID, as docker tag expects.
+func InspectImageID(imageRef string) (string, error) {
+	for _, tool := range []string{"docker", "podman", "nerdctl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		for _, ref := range InspectRefs(imageRef) {
+			output, err := exec.Command(tool, "inspect", "--format={{.Id}}", ref).Output()
+			if err != nil {
+				continue
+			}
+			if id, err := ParseDigest(strings.TrimSpace(string(output)), ""); err == nil {
+				return id, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("could not resolve digest for %s", imageRef)
+}
+
+// InspectRefs returns the references to try, in order, when asking a container runtime about
+// imageRef. Runtimes look name@<digest> up by registry (manifest) digest, so a digest that is
+// an image ID (--digest) is also tried on its own, which runtimes accept as an image ID.
+func InspectRefs(imageRef string) []string {
+	if _, digest, ok := SplitDigestRef(imageRef); ok {
+		return []string{imageRef, QualifiedDigest(digest)}
+	}
+	return []string{imageRef}
+}
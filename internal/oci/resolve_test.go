@@ -0,0 +1,51 @@
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResolveDigest_DigestRef tests that digest references resolve without a container runtime
+func TestResolveDigest_DigestRef(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	sha256Hex := strings.Repeat("ab", 32)
+	sha512Hex := strings.Repeat("cd", 64)
+	tests := []struct {
+		ref, want string
+	}{
+		{"example.com/app@sha256:" + sha256Hex, sha256Hex},
+		{"example.com/app:v1@sha512:" + sha512Hex, "sha512:" + sha512Hex},
+	}
+	for _, tt := range tests {
+		got, err := ResolveDigest(tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveDigest(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	if _, err := ResolveDigest("example.com/app:v1"); err == nil {
+		t.Error("expected a tag reference to fail without a container runtime")
+	}
+}
+
+// TestInspectImageID tests that the image ID comes from the runtime even for digest references,
+// and that output which is not a digest is ignored
+func TestInspectImageID(t *testing.T) {
+	binDir := t.TempDir()
+	imageID := strings.Repeat("ab", 32)
+	docker := "#!/bin/sh\ncase \"$3\" in\n  *@*) echo sha256:" + imageID + " ;;\n  *) echo '[{}]' ;;\nesac\n"
+	if err := os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755); err != nil {
+		t.Fatalf("failed to write fake docker: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	if got, err := InspectImageID("example.com/app@sha256:" + strings.Repeat("cd", 32)); err != nil || got != imageID {
+		t.Errorf("InspectImageID = %q, %v; want the image ID %q", got, err, imageID)
+	}
+	if _, err := InspectImageID("example.com/app:v1"); err == nil {
+		t.Error("expected output that is not a digest to be rejected")
+	}
+}
@@ -0,0 +1,92 @@
+package oci
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/errcode"
+
+	"github.com/cloudcwfranck/acc/internal/pool"
+)
+
+// RetryPolicy controls operation-level retries for registry calls.
+// The oras HTTP client already retries individual requests; this policy
+// wraps whole operations (resolve, fetch, push, tag) so a flaky registry
+// does not fail a remote attestation flow halfway through.
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts including the first call
+	InitialBackoff time.Duration // delay before the second attempt
+	MaxBackoff     time.Duration // upper bound for the doubling delay
+}
+
+// DefaultRetryPolicy is used by attest and trust for remote registry operations
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// Retry runs fn until it succeeds, returns a non-retryable error, or the
+// policy's attempts are exhausted. The delay doubles after each failure.
+// Every attempt waits for the shared rate limit (--rate-limit) first.
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := policy.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if waitErr := pool.Default().Wait(ctx); waitErr != nil {
+			if err != nil {
+				return err
+			}
+			return waitErr
+		}
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if !IsRetryable(err) || attempt == attempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	return err
+}
+
+// IsRetryable reports whether a registry error is transient.
+// 5xx responses and network timeouts are retried; 4xx responses
+// (auth, not found, invalid manifest) are returned immediately.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var errResp *errcode.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
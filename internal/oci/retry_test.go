@@ -0,0 +1,164 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// testPolicy keeps backoff short so tests stay fast
+var testPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+const testManifest = `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.empty.v1+json","digest":"sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a","size":2},"layers":[]}`
+
+// newFlakyRegistry returns a mock registry that answers manifest requests with
+// failStatus for the first failures calls and with a valid manifest afterwards
+func newFlakyRegistry(t *testing.T, failures int32, failStatus int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v2/test/repo/manifests/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		n := atomic.AddInt32(&calls, 1)
+		if n <= failures {
+			w.WriteHeader(failStatus)
+			return
+		}
+
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", "sha256:"+sha256Hex(testManifest))
+		w.Header().Set("Content-Length", strconv.Itoa(len(testManifest)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write([]byte(testManifest))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func newTestRepository(t *testing.T, server *httptest.Server) *remote.Repository {
+	t.Helper()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	// Plain client so operation-level retry is the only retry layer under test
+	repo.Client = http.DefaultClient
+	return repo
+}
+
+func TestRetry_ResolveSucceedsAfterTransientFailures(t *testing.T) {
+	server, calls := newFlakyRegistry(t, 2, http.StatusServiceUnavailable)
+	repo := newTestRepository(t, server)
+	ctx := context.Background()
+
+	var desc ocispec.Descriptor
+	err := Retry(ctx, testPolicy, func() error {
+		var resolveErr error
+		desc, resolveErr = repo.Resolve(ctx, "v1")
+		return resolveErr
+	})
+	if err != nil {
+		t.Fatalf("expected resolve to succeed after retries, got: %v", err)
+	}
+
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("expected 3 registry calls (2 failures + 1 success), got %d", got)
+	}
+	if desc.MediaType != ocispec.MediaTypeImageManifest {
+		t.Errorf("expected manifest media type, got %q", desc.MediaType)
+	}
+}
+
+func TestRetry_GivesUpOnClientError(t *testing.T) {
+	server, calls := newFlakyRegistry(t, 10, http.StatusForbidden)
+	repo := newTestRepository(t, server)
+	ctx := context.Background()
+
+	err := Retry(ctx, testPolicy, func() error {
+		_, resolveErr := repo.Resolve(ctx, "v1")
+		return resolveErr
+	})
+	if err == nil {
+		t.Fatal("expected error for 403 response")
+	}
+
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("expected 4xx to fail without retry (1 call), got %d calls", got)
+	}
+}
+
+func TestRetry_ExhaustsAttempts(t *testing.T) {
+	server, calls := newFlakyRegistry(t, 10, http.StatusBadGateway)
+	repo := newTestRepository(t, server)
+	ctx := context.Background()
+
+	err := Retry(ctx, testPolicy, func() error {
+		_, resolveErr := repo.Resolve(ctx, "v1")
+		return resolveErr
+	})
+	if err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+
+	if got := atomic.LoadInt32(calls); got != int32(testPolicy.MaxAttempts) {
+		t.Errorf("expected %d calls, got %d", testPolicy.MaxAttempts, got)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"timeout", timeoutError{}, true},
+		{"wrapped timeout", fmt.Errorf("fetch failed: %w", timeoutError{}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,89 @@
+package policy
+
+// CISPolicyContent is the built-in "cis" pack: image checks from the CIS Docker Benchmark
+// (section 4, container images) that the policy input can express
+const CISPolicyContent = `# acc CIS Docker Benchmark pack
+# Image checks from CIS Docker Benchmark section 4 (container images and build files)
+
+package acc.policy
+
+import rego.v1
+
+default allow := false
+
+# CIS 4.1: Ensure that a user for the container has been created
+deny contains msg if {
+	input.config.User in {"", "root", "0", "0:0", "root:root"}
+	msg := {
+		"rule": "cis-4.1-non-root-user",
+		"severity": "high",
+		"result": "fail",
+		"message": "Container runs as root (CIS 4.1)",
+		"remediation": "Create a non-root user and add a USER directive (e.g. USER 1000) to your Dockerfile",
+	}
+}
+
+# CIS 4.10: Ensure secrets are not stored in Dockerfiles
+secret_env_names := {"PASSWORD", "PASSWD", "SECRET", "TOKEN", "API_KEY", "PRIVATE_KEY", "ACCESS_KEY"}
+
+deny contains msg if {
+	some env in input.config.Env
+	name := upper(split(env, "=")[0])
+	some marker in secret_env_names
+	contains(name, marker)
+	msg := {
+		"rule": "cis-4.10-no-secrets-in-env",
+		"severity": "critical",
+		"result": "fail",
+		"message": sprintf("Environment variable %s looks like a secret baked into the image (CIS 4.10)", [split(env, "=")[0]]),
+		"remediation": "Remove the secret from ENV/ARG and provide it at runtime (secrets manager, mounted file, or docker build --secret)",
+	}
+}
+
+# CIS 5.7 (image side): do not expose SSH
+deny contains msg if {
+	some port, _ in input.config.ExposedPorts
+	startswith(port, "22/")
+	msg := {
+		"rule": "cis-5.7-no-ssh-port",
+		"severity": "high",
+		"result": "fail",
+		"message": "Image exposes port 22 (SSH should not run inside containers)",
+		"remediation": "Remove EXPOSE 22 and the SSH daemon; use docker exec or kubectl exec for access",
+	}
+}
+
+# CIS 4.5 / supply chain: the image contents must be inventoried
+deny contains msg if {
+	not input.sbom.present
+	msg := {
+		"rule": "sbom-required",
+		"severity": "critical",
+		"result": "fail",
+		"message": "SBOM is required but not found",
+		"remediation": "Generate an SBOM with acc build or syft",
+	}
+}
+
+# Image labels (recommended metadata, as in the baseline pack)
+warn contains msg if {
+	count(object.get(input.config, "Labels", {})) == 0
+	msg := {
+		"rule": "image-labels",
+		"severity": "low",
+		"result": "warn",
+		"message": "Image has no labels (recommended for metadata)",
+		"remediation": "Add LABEL directives (e.g. org.opencontainers.image.source) to your Dockerfile",
+	}
+}
+
+allow if {
+	count(deny) == 0
+}
+
+result := {
+	"allow": allow,
+	"violations": deny,
+	"warnings": warn,
+}
+`
@@ -22,6 +22,7 @@ deny contains msg if {
 		"severity": "high",
 		"result": "fail",
 		"message": "Container runs as root (no USER directive found)",
+		"remediation": "Add a non-root USER directive (e.g. USER 1000) to your Dockerfile",
 	}
 }
 
@@ -32,6 +33,7 @@ deny contains msg if {
 		"severity": "high",
 		"result": "fail",
 		"message": "Container explicitly runs as root",
+		"remediation": "Replace USER root with a non-root user (e.g. USER 1000)",
 	}
 }
 
@@ -42,6 +44,7 @@ deny contains msg if {
 		"severity": "high",
 		"result": "fail",
 		"message": "Container runs as UID 0 (root)",
+		"remediation": "Replace USER 0 with a non-root UID (e.g. USER 1000)",
 	}
 }
 
@@ -53,6 +56,7 @@ deny contains msg if {
 		"severity": "critical",
 		"result": "fail",
 		"message": "SBOM is required but not found",
+		"remediation": "Generate an SBOM with acc build or syft",
 	}
 }
 
@@ -64,6 +68,7 @@ warn contains msg if {
 		"severity": "low",
 		"result": "warn",
 		"message": "Image has no labels (recommended for metadata)",
+		"remediation": "Add LABEL directives (e.g. org.opencontainers.image.source) to your Dockerfile",
 	}
 }
 
@@ -76,6 +81,7 @@ deny contains msg if {
 		"severity": "critical",
 		"result": "fail",
 		"message": "Attestation required for promotion but not found",
+		"remediation": "Run acc attest <image> before promoting",
 	}
 }
 
@@ -26,7 +26,7 @@ func Explain(outputJSON bool) error {
 	data, err := os.ReadFile(stateFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("no verification history found\n\nHint: Run 'acc verify' first to generate verification results")
+			return fmt.Errorf("no verification history found\n\nHint: Run 'acc verify' first to generate verification results (verify --no-state does not write them)")
 		}
 		return fmt.Errorf("failed to read verification state: %w", err)
 	}
@@ -116,6 +116,12 @@ func printExplanation(state *VerifyState) {
 				message := violation["message"]
 				fmt.Printf("  %d. [%s] %s\n", i+1, severity, rule)
 				fmt.Printf("     %s\n", message)
+				if source, ok := violation["source"].(string); ok && source != "" {
+					fmt.Printf("     Source: %s\n", source)
+				}
+				if remediation, ok := violation["remediation"].(string); ok && remediation != "" {
+					fmt.Printf("     Remediation: %s\n", remediation)
+				}
 			}
 		}
 		fmt.Println()
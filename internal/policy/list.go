@@ -0,0 +1,282 @@
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleInfo describes a rule ID a policy pack can report
+type RuleInfo struct {
+	ID          string   `json:"id"`
+	Severity    string   `json:"severity,omitempty"`
+	Kind        string   `json:"kind,omitempty"` // deny or warn
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Files       []string `json:"files"`
+	Annotated   bool     `json:"annotated"` // described by an OPA # METADATA annotation
+}
+
+// ListResult is the output of acc policy list
+type ListResult struct {
+	Pack  string     `json:"pack"`
+	Rules []RuleInfo `json:"rules"`
+}
+
+// ruleMetadata is the part of an OPA # METADATA annotation acc reads; severity and the
+// rule ID live under custom (custom.rule, or custom.id)
+type ruleMetadata struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Custom      struct {
+		Rule     string `yaml:"rule"`
+		ID       string `yaml:"id"`
+		Severity string `yaml:"severity"`
+	} `yaml:"custom"`
+}
+
+var (
+	ruleLiteralPattern     = regexp.MustCompile(`"rule"\s*:\s*"([^"]+)"`)
+	severityLiteralPattern = regexp.MustCompile(`"severity"\s*:\s*"([^"]+)"`)
+	messageLiteralPattern  = regexp.MustCompile(`"message"\s*:\s*"([^"]+)"`)
+	ruleKindPattern        = regexp.MustCompile(`^(deny|warn)\b`)
+)
+
+// ListRules lists the rule IDs declared by the .rego files under dir, sorted by ID.
+// OPA metadata annotations (# METADATA with title, description, and custom.rule/custom.id
+// and custom.severity) describe a rule where present; otherwise the "rule", "severity",
+// and "message" literals of the violation objects a rule body builds are used.
+func ListRules(dir string) ([]RuleInfo, error) {
+	byID := map[string]*RuleInfo{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".rego" || strings.HasSuffix(path, "_test.rego") {
+			return nil
+		}
+		rules, err := scanRegoFile(path)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			mergeRule(byID, rule, path)
+		}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("policy directory %s not found", dir)
+		}
+		return nil, fmt.Errorf("failed to read policies: %w", err)
+	}
+
+	rules := make([]RuleInfo, 0, len(byID))
+	for _, rule := range byID {
+		sort.Strings(rule.Files)
+		rules = append(rules, *rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	return rules, nil
+}
+
+// mergeRule folds a rule found in path into byID; a rule ID defined by several rule bodies
+// (e.g. no-root-user for each root user spelling) is listed once, annotations taking precedence
+func mergeRule(byID map[string]*RuleInfo, rule RuleInfo, path string) {
+	existing, ok := byID[rule.ID]
+	if !ok {
+		rule.Files = []string{path}
+		byID[rule.ID] = &rule
+		return
+	}
+
+	if rule.Annotated && !existing.Annotated {
+		files := existing.Files
+		*existing = rule
+		existing.Files = files
+	} else {
+		if existing.Severity == "" {
+			existing.Severity = rule.Severity
+		}
+		if existing.Kind == "" {
+			existing.Kind = rule.Kind
+		}
+		if existing.Description == "" {
+			existing.Description = rule.Description
+		}
+	}
+	for _, file := range existing.Files {
+		if file == path {
+			return
+		}
+	}
+	existing.Files = append(existing.Files, path)
+}
+
+// regoBlock is a top-level rule: its head and body lines, and the comment lines before it
+type regoBlock struct {
+	comments []string
+	lines    []string
+}
+
+// scanRegoFile splits a .rego file into top-level blocks (a line starting in column 0
+// begins one) and describes each block that reports a rule ID
+func scanRegoFile(path string) ([]RuleInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var blocks []regoBlock
+	var comments []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#") && line == trimmed:
+			comments = append(comments, line)
+		case trimmed == "":
+			// Annotations must directly precede their rule
+			comments = nil
+		case line == trimmed && !strings.HasPrefix(line, "}"):
+			blocks = append(blocks, regoBlock{comments: comments, lines: []string{line}})
+			comments = nil
+		default:
+			if len(blocks) > 0 {
+				blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var rules []RuleInfo
+	for _, block := range blocks {
+		if rule, ok := describeBlock(block); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// describeBlock returns the rule a block reports, from its annotation or its literals
+func describeBlock(block regoBlock) (RuleInfo, bool) {
+	body := strings.Join(block.lines, "\n")
+	rule := RuleInfo{}
+	if m := ruleKindPattern.FindStringSubmatch(block.lines[0]); m != nil {
+		rule.Kind = m[1]
+	}
+	if m := ruleLiteralPattern.FindStringSubmatch(body); m != nil {
+		rule.ID = m[1]
+	}
+	if m := severityLiteralPattern.FindStringSubmatch(body); m != nil {
+		rule.Severity = m[1]
+	}
+	if m := messageLiteralPattern.FindStringSubmatch(body); m != nil {
+		rule.Description = m[1]
+	}
+
+	if meta, ok := parseMetadata(block.comments); ok {
+		rule.Annotated = true
+		rule.Title = meta.Title
+		if meta.Description != "" {
+			rule.Description = meta.Description
+		}
+		if id := meta.Custom.Rule; id != "" {
+			rule.ID = id
+		} else if id := meta.Custom.ID; id != "" {
+			rule.ID = id
+		}
+		if meta.Custom.Severity != "" {
+			rule.Severity = meta.Custom.Severity
+		}
+	}
+	return rule, rule.ID != ""
+}
+
+// parseMetadata parses the YAML of a # METADATA comment block, if comments contain one
+func parseMetadata(comments []string) (*ruleMetadata, bool) {
+	start := -1
+	for i, line := range comments {
+		if strings.TrimSpace(strings.TrimPrefix(line, "#")) == "METADATA" {
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+
+	var yamlLines []string
+	for _, line := range comments[start:] {
+		line = strings.TrimPrefix(line, "#")
+		yamlLines = append(yamlLines, strings.TrimPrefix(line, " "))
+	}
+	var meta ruleMetadata
+	if err := yaml.Unmarshal([]byte(strings.Join(yamlLines, "\n")), &meta); err != nil {
+		return nil, false
+	}
+	return &meta, true
+}
+
+// ListPack lists the rules of the pack selected by name (see ResolvePack)
+func ListPack(name string) (*ListResult, error) {
+	pack, err := ResolvePack(name)
+	if err != nil {
+		return nil, err
+	}
+	defer pack.Cleanup()
+
+	rules, err := ListRules(pack.Dir)
+	if err != nil {
+		return nil, err
+	}
+	result := &ListResult{Pack: pack.Dir, Rules: rules}
+	if pack.Builtin {
+		// The extracted directory is temporary; report the pack by name
+		result.Pack = "builtin:" + pack.Name
+		for i := range result.Rules {
+			for j, file := range result.Rules[i].Files {
+				result.Rules[i].Files[j] = filepath.Join(result.Pack, filepath.Base(file))
+			}
+		}
+	}
+	return result, nil
+}
+
+// Print writes the rule listing in human format
+func (r *ListResult) Print() {
+	if len(r.Rules) == 0 {
+		ui.PrintInfo(fmt.Sprintf("No rules found in %s", r.Pack))
+		return
+	}
+
+	ui.PrintInfo(fmt.Sprintf("%d rule(s) in %s:", len(r.Rules), r.Pack))
+	for _, rule := range r.Rules {
+		severity := rule.Severity
+		if severity == "" {
+			severity = "-"
+		}
+		line := fmt.Sprintf("  [%s] %s", severity, rule.ID)
+		if rule.Kind == "warn" {
+			line += " (warning)"
+		}
+		fmt.Println(line)
+		if rule.Title != "" {
+			fmt.Printf("      %s\n", rule.Title)
+		}
+		if rule.Description != "" && rule.Description != rule.Title {
+			fmt.Printf("      %s\n", rule.Description)
+		}
+	}
+}
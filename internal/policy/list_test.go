@@ -0,0 +1,96 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// annotatedPolicy declares one rule with OPA metadata and one with only violation literals
+const annotatedPolicy = `package acc.policy
+
+import rego.v1
+
+# METADATA
+# title: No latest tag
+# description: Images must be pinned to a version, not latest
+# custom:
+#   rule: no-latest-tag
+#   severity: medium
+deny contains msg if {
+	endswith(input.image, ":latest")
+	msg := {"rule": sprintf("%s", ["no-latest-tag"]), "severity": "medium", "message": "latest tag"}
+}
+
+# Rule: registry allowlist
+deny contains msg if {
+	not startswith(input.image, "ghcr.io/")
+	msg := {
+		"rule": "registry-allowlist",
+		"severity": "high",
+		"result": "fail",
+		"message": "Image must come from ghcr.io",
+	}
+}
+
+warn contains msg if {
+	count(input.config.Labels) == 0
+	msg := {"rule": "image-labels", "severity": "low", "message": "Image has no labels"}
+}
+
+result := {"allow": count(deny) == 0, "violations": deny, "warnings": warn}
+`
+
+func TestListRules(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "policy.rego"), []byte(annotatedPolicy), 0644)
+	os.MkdirAll(filepath.Join(dir, "network"), 0755)
+	os.WriteFile(filepath.Join(dir, "network", "egress.rego"), []byte(`package acc.policy
+
+deny contains msg if {
+	input.config.ExposedPorts["22/tcp"]
+	msg := {"rule": "no-ssh", "severity": "high", "message": "SSH exposed"}
+}
+`), 0644)
+	// Tests are not listed
+	os.WriteFile(filepath.Join(dir, "policy_test.rego"), []byte(`test_x if { {"rule": "test-only"} }`), 0644)
+
+	rules, err := ListRules(dir)
+	if err != nil {
+		t.Fatalf("ListRules failed: %v", err)
+	}
+
+	policyFile := filepath.Join(dir, "policy.rego")
+	want := []RuleInfo{
+		{ID: "image-labels", Severity: "low", Kind: "warn", Description: "Image has no labels", Files: []string{policyFile}},
+		{ID: "no-latest-tag", Severity: "medium", Kind: "deny", Title: "No latest tag", Description: "Images must be pinned to a version, not latest", Files: []string{policyFile}, Annotated: true},
+		{ID: "no-ssh", Severity: "high", Kind: "deny", Description: "SSH exposed", Files: []string{filepath.Join(dir, "network", "egress.rego")}},
+		{ID: "registry-allowlist", Severity: "high", Kind: "deny", Description: "Image must come from ghcr.io", Files: []string{policyFile}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("ListRules() =\n%+v\nwant\n%+v", rules, want)
+	}
+}
+
+func TestListPack_Builtin(t *testing.T) {
+	result, err := ListPack("baseline")
+	if err != nil {
+		t.Fatalf("ListPack failed: %v", err)
+	}
+	if result.Pack != "builtin:baseline" {
+		t.Errorf("pack = %q, want builtin:baseline", result.Pack)
+	}
+
+	ids := map[string]RuleInfo{}
+	for _, rule := range result.Rules {
+		ids[rule.ID] = rule
+	}
+	// no-root-user is defined by three rule bodies but listed once
+	if len(result.Rules) != 4 || ids["no-root-user"].Severity != "high" || len(ids["no-root-user"].Files) != 1 {
+		t.Errorf("unexpected baseline rules %+v", result.Rules)
+	}
+	if ids["no-root-user"].Files[0] != filepath.Join("builtin:baseline", "policy.rego") {
+		t.Errorf("built-in files should not point at the temporary directory, got %v", ids["no-root-user"].Files)
+	}
+}
@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultPackDir is the project policy evaluated when no pack is selected
+var DefaultPackDir = filepath.Join(".acc", "policy")
+
+// PacksDir holds named project packs, one subdirectory each (.acc/policy-packs/<name>/)
+var PacksDir = filepath.Join(".acc", "policy-packs")
+
+// builtinPacks are the packs shipped with acc, selectable by name without any files
+var builtinPacks = map[string]string{
+	"baseline": DefaultPolicyContent,
+	"cis":      CISPolicyContent,
+}
+
+// Pack is a resolved policy pack
+type Pack struct {
+	Name    string // as selected (empty for the default .acc/policy)
+	Dir     string // directory evaluated by OPA
+	Builtin bool   // extracted from acc's built-in packs into a temporary Dir
+}
+
+// Cleanup removes the temporary directory of a built-in pack
+func (p *Pack) Cleanup() {
+	if p.Builtin {
+		os.RemoveAll(p.Dir)
+	}
+}
+
+// ResolvePack resolves a --policy-pack / policy.pack value: "" is .acc/policy; a name is
+// .acc/policy-packs/<name>/ or, failing that, a built-in pack (baseline, cis); a value
+// containing a path separator is used as a directory. Built-in packs are extracted to a
+// temporary directory the caller must Cleanup.
+func ResolvePack(name string) (*Pack, error) {
+	if name == "" {
+		return &Pack{Dir: DefaultPackDir}, nil
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		if info, err := os.Stat(name); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("policy pack directory %s not found", name)
+		}
+		return &Pack{Name: name, Dir: name}, nil
+	}
+
+	dir := filepath.Join(PacksDir, name)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return &Pack{Name: name, Dir: dir}, nil
+	}
+
+	content, ok := builtinPacks[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown policy pack %q (available: %s)", name, strings.Join(AvailablePacks(), ", "))
+	}
+	tmpDir, err := os.MkdirTemp("", "acc-pack-"+name+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract policy pack %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "policy.rego"), []byte(content), 0644); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("failed to extract policy pack %s: %w", name, err)
+	}
+	return &Pack{Name: name, Dir: tmpDir, Builtin: true}, nil
+}
+
+// AvailablePacks lists the named packs in .acc/policy-packs and the built-in packs, sorted
+func AvailablePacks() []string {
+	seen := map[string]bool{}
+	for name := range builtinPacks {
+		seen[name] = true
+	}
+	if entries, err := os.ReadDir(PacksDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				seen[entry.Name()] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
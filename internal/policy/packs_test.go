@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolvePack(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	os.MkdirAll(filepath.Join(PacksDir, "strict"), 0755)
+	os.WriteFile(filepath.Join(PacksDir, "strict", "strict.rego"), []byte("package acc.policy\n"), 0644)
+
+	// Default: the project policy
+	pack, err := ResolvePack("")
+	if err != nil || pack.Dir != DefaultPackDir || pack.Builtin {
+		t.Fatalf("ResolvePack(\"\") = %+v, %v; want %s", pack, err, DefaultPackDir)
+	}
+
+	// A named project pack
+	pack, err = ResolvePack("strict")
+	if err != nil || pack.Dir != filepath.Join(".acc", "policy-packs", "strict") || pack.Builtin {
+		t.Fatalf("ResolvePack(strict) = %+v, %v", pack, err)
+	}
+
+	// A built-in pack is extracted and removed by Cleanup
+	pack, err = ResolvePack("cis")
+	if err != nil || !pack.Builtin {
+		t.Fatalf("ResolvePack(cis) = %+v, %v", pack, err)
+	}
+	data, err := os.ReadFile(filepath.Join(pack.Dir, "policy.rego"))
+	if err != nil || !strings.Contains(string(data), "cis-4.1-non-root-user") {
+		t.Fatalf("built-in cis pack not extracted: %v", err)
+	}
+	pack.Cleanup()
+	if _, err := os.Stat(pack.Dir); !os.IsNotExist(err) {
+		t.Errorf("Cleanup left %s behind", pack.Dir)
+	}
+
+	// A project pack shadows a built-in pack of the same name
+	os.MkdirAll(filepath.Join(PacksDir, "baseline"), 0755)
+	if pack, err := ResolvePack("baseline"); err != nil || pack.Builtin {
+		t.Errorf("expected .acc/policy-packs/baseline to win over the built-in pack, got %+v, %v", pack, err)
+	}
+
+	// A path is used as-is
+	if pack, err := ResolvePack("./" + filepath.Join(PacksDir, "strict")); err != nil || pack.Builtin {
+		t.Errorf("ResolvePack(path) = %+v, %v", pack, err)
+	}
+}
+
+func TestResolvePack_Unknown(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+	os.MkdirAll(filepath.Join(PacksDir, "strict"), 0755)
+
+	_, err := ResolvePack("nope")
+	if err == nil {
+		t.Fatal("expected error for an unknown pack")
+	}
+	if !strings.Contains(err.Error(), `unknown policy pack "nope"`) || !strings.Contains(err.Error(), "baseline, cis, strict") {
+		t.Errorf("error should name the pack and list the available ones, got: %v", err)
+	}
+
+	if _, err := ResolvePack("./missing/dir"); err == nil {
+		t.Error("expected error for a missing pack directory")
+	}
+}
@@ -0,0 +1,134 @@
+package pool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// maxDefaultConcurrency caps the CPU-derived default so large CI nodes do not
+// open more registry connections or OPA processes than a registry will tolerate
+const maxDefaultConcurrency = 16
+
+// Limiter bounds how many operations run at once (concurrency) and how fast
+// registry requests start (rate). A zero rate is unlimited.
+type Limiter struct {
+	slots    chan struct{}
+	interval time.Duration // minimum spacing between requests; 0 is unlimited
+
+	mu   sync.Mutex
+	next time.Time // earliest start of the next request
+}
+
+// NewLimiter returns a limiter allowing concurrency operations in flight and rate
+// requests per second. A concurrency below 1 uses DefaultConcurrency; a rate of 0 or
+// less is unlimited.
+func NewLimiter(concurrency int, rate float64) *Limiter {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency()
+	}
+	l := &Limiter{slots: make(chan struct{}, concurrency)}
+	if rate > 0 {
+		l.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return l
+}
+
+// DefaultConcurrency is the CPU count, at least 2 and at most 16
+func DefaultConcurrency() int {
+	return min(max(runtime.NumCPU(), 2), maxDefaultConcurrency)
+}
+
+// Concurrency returns the number of operations allowed in flight
+func (l *Limiter) Concurrency() int {
+	return cap(l.slots)
+}
+
+// Acquire waits for a free slot, or until ctx is done. Each successful Acquire
+// must be paired with a Release.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *Limiter) Release() {
+	<-l.slots
+}
+
+// Wait blocks until the rate limit allows another request, or until ctx is done.
+// Requests are spaced evenly at 1/rate seconds; with no rate it returns at once.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Each calls fn(i) for i in [0, n), running at most Concurrency calls at once,
+// and returns when all have finished. fn must not call Each on the same limiter,
+// since the outer calls hold the slots the inner ones would wait for.
+func (l *Limiter) Each(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		l.slots <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer l.Release()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   *Limiter // built from --concurrency and --rate-limit
+)
+
+// Configure sets the process-wide limiter used by batch and registry operations
+// (--concurrency, --rate-limit). A concurrency of 0 uses DefaultConcurrency; a rate
+// of 0 is unlimited.
+func Configure(concurrency int, rate float64) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	shared = NewLimiter(concurrency, rate)
+}
+
+// Default returns the process-wide limiter, with default settings if Configure
+// was not called
+func Default() *Limiter {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+	if shared == nil {
+		shared = NewLimiter(0, 0)
+	}
+	return shared
+}
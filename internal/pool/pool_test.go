@@ -0,0 +1,112 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLimiter_CapsConcurrency tests that Each never runs more than Concurrency calls at once
+func TestLimiter_CapsConcurrency(t *testing.T) {
+	l := NewLimiter(3, 0)
+
+	var inFlight, peak, calls atomic.Int32
+	l.Each(20, func(i int) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		calls.Add(1)
+	})
+
+	if calls.Load() != 20 {
+		t.Errorf("expected 20 calls, got %d", calls.Load())
+	}
+	if peak.Load() > 3 {
+		t.Errorf("expected at most 3 calls in flight, got %d", peak.Load())
+	}
+	if peak.Load() < 2 {
+		t.Errorf("expected calls to run in parallel, peak was %d", peak.Load())
+	}
+}
+
+// TestLimiter_Acquire tests that Acquire blocks while all slots are taken
+func TestLimiter_Acquire(t *testing.T) {
+	l := NewLimiter(1, 0)
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Acquire(ctx); err == nil {
+		t.Fatal("expected Acquire to wait while the only slot is held")
+	}
+
+	l.Release()
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Errorf("expected Acquire to succeed after Release: %v", err)
+	}
+}
+
+// TestLimiter_Wait tests that requests are spaced by the rate limit
+func TestLimiter_Wait(t *testing.T) {
+	l := NewLimiter(1, 50) // one request every 20ms
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected 6 requests at 50/s to take at least 100ms, took %v", elapsed)
+	}
+
+	// A cancelled context stops waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := NewLimiter(1, 0.1)
+	slow.Wait(ctx) // the first request starts at once
+	if err := slow.Wait(ctx); err == nil {
+		t.Error("expected Wait to return the context error")
+	}
+
+	// No rate limit never waits
+	unlimited := NewLimiter(1, 0)
+	start = time.Now()
+	for i := 0; i < 100; i++ {
+		unlimited.Wait(context.Background())
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected no waiting without a rate limit, took %v", elapsed)
+	}
+}
+
+// TestConfigure tests the process-wide limiter and its CPU-based default
+func TestConfigure(t *testing.T) {
+	defer Configure(0, 0)
+
+	if c := DefaultConcurrency(); c < 2 || c > maxDefaultConcurrency {
+		t.Errorf("default concurrency %d outside [2, %d]", c, maxDefaultConcurrency)
+	}
+
+	Configure(0, 0)
+	if Default().Concurrency() != DefaultConcurrency() {
+		t.Errorf("expected the default concurrency, got %d", Default().Concurrency())
+	}
+
+	Configure(5, 10)
+	if Default().Concurrency() != 5 {
+		t.Errorf("expected concurrency 5, got %d", Default().Concurrency())
+	}
+	if Default().interval != 100*time.Millisecond {
+		t.Errorf("expected 100ms between requests at 10/s, got %v", Default().interval)
+	}
+}
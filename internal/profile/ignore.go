@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultIgnoreFile is the ignore file read from the project root when --ignore-file is not set
+const DefaultIgnoreFile = ".accignore"
+
+// LoadIgnoreFile loads a .accignore file as a lightweight inline profile
+// Format: one rule ID or severity per line; blank lines and lines starting with # are skipped.
+// Matching violations are downgraded to warnings (same semantics as violations.ignore).
+func LoadIgnoreFile(path string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("ignore file not found: %s", path)
+		}
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	entries := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Allow trailing comments: "no-root-user  # tracked in JIRA-123"
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", path, err)
+	}
+
+	return &Profile{
+		SchemaVersion: 1,
+		Name:          path,
+		Description:   "inline profile from " + path,
+		Violations:    ViolationConfig{Ignore: entries},
+		Warnings:      WarningConfig{Show: true},
+	}, nil
+}
+
+// Select returns the profile to apply for verification
+// An explicit profile always takes precedence over an ignore file profile
+func Select(explicit, ignore *Profile) *Profile {
+	if explicit != nil {
+		return explicit
+	}
+	return ignore
+}
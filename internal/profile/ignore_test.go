@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeIgnoreFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".accignore")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write ignore file: %v", err)
+	}
+	return path
+}
+
+// TestLoadIgnoreFile tests parsing of entries, comments, and blank lines
+func TestLoadIgnoreFile(t *testing.T) {
+	path := writeIgnoreFile(t, "# quick exceptions\n\nno-root-user\nlow  # cosmetic only\n")
+
+	prof, err := LoadIgnoreFile(path)
+	if err != nil {
+		t.Fatalf("LoadIgnoreFile failed: %v", err)
+	}
+
+	want := []string{"no-root-user", "low"}
+	if strings.Join(prof.Violations.Ignore, ",") != strings.Join(want, ",") {
+		t.Errorf("ignore = %v, want %v", prof.Violations.Ignore, want)
+	}
+	if !prof.Warnings.Show {
+		t.Error("warnings.show = false, want true (ignored violations are downgraded to warnings)")
+	}
+	if err := Validate(prof); err != nil {
+		t.Errorf("ignore file profile should be valid: %v", err)
+	}
+}
+
+// TestLoadIgnoreFile_NotFound tests that a missing ignore file returns an error
+func TestLoadIgnoreFile_NotFound(t *testing.T) {
+	_, err := LoadIgnoreFile(filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.Contains(err.Error(), "ignore file not found") {
+		t.Errorf("expected 'ignore file not found' error, got: %v", err)
+	}
+}
+
+// TestIgnoreFile_SuppressesViolation tests that a .accignore entry downgrades a matching violation
+func TestIgnoreFile_SuppressesViolation(t *testing.T) {
+	prof, err := LoadIgnoreFile(writeIgnoreFile(t, "no-root-user\n"))
+	if err != nil {
+		t.Fatalf("LoadIgnoreFile failed: %v", err)
+	}
+
+	violations := []Violation{
+		{Rule: "no-root-user", Severity: "high", Message: "runs as root"},
+	}
+
+	result := ResolveViolations(Select(nil, prof), violations)
+
+	if len(result.Violations) != 0 {
+		t.Errorf("violations = %d, want 0", len(result.Violations))
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("warnings = %d, want 1", len(result.Warnings))
+	}
+	if !result.Allow {
+		t.Error("allow = false, want true")
+	}
+}
+
+// TestIgnoreFile_OverriddenByProfile tests that an explicit profile takes precedence over .accignore
+func TestIgnoreFile_OverriddenByProfile(t *testing.T) {
+	ignoreProf, err := LoadIgnoreFile(writeIgnoreFile(t, "no-root-user\n"))
+	if err != nil {
+		t.Fatalf("LoadIgnoreFile failed: %v", err)
+	}
+
+	explicit := &Profile{
+		SchemaVersion: 1,
+		Name:          "strict",
+		Description:   "Strict profile",
+		Warnings:      WarningConfig{Show: true},
+	}
+
+	selected := Select(explicit, ignoreProf)
+	if selected != explicit {
+		t.Fatalf("selected profile = %q, want explicit profile", selected.Name)
+	}
+
+	violations := []Violation{
+		{Rule: "no-root-user", Severity: "high", Message: "runs as root"},
+	}
+
+	result := ResolveViolations(selected, violations)
+
+	if len(result.Violations) != 1 {
+		t.Errorf("violations = %d, want 1 (profile does not ignore no-root-user)", len(result.Violations))
+	}
+	if result.Allow {
+		t.Error("allow = true, want false")
+	}
+}
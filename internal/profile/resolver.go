@@ -7,10 +7,12 @@ import (
 // Violation represents a policy violation (mirrors verify.PolicyViolation)
 // We use this interface to avoid circular dependencies
 type Violation struct {
-	Rule     string `json:"rule"`
-	Severity string `json:"severity"`
-	Result   string `json:"result"`
-	Message  string `json:"message"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Result      string `json:"result"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+	Source      string `json:"source,omitempty"`
 }
 
 // ResolutionResult represents the result of profile-based violation filtering
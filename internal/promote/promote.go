@@ -1,11 +1,17 @@
 package promote
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/profile"
+	"github.com/cloudcwfranck/acc/internal/trust"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	"github.com/cloudcwfranck/acc/internal/verify"
 )
@@ -17,11 +23,19 @@ type PromoteResult struct {
 	Digest    string `json:"digest"`
 	Env       string `json:"env"`
 	Status    string `json:"status"`
+	// AttestationStatus is "verified" when an attestation was required and found,
+	// or "not-required" when promotion did not require one
+	AttestationStatus string `json:"attestationStatus"`
+	Profile           string `json:"profile,omitempty"` // profile applied by the verification gate
+	// Copy records the source and destination of a cross-registry promotion (--to-registry)
+	Copy *RegistryCopy `json:"copy,omitempty"`
 }
 
 // Promote promotes an image to an environment (AGENTS.md Section 2 - acc promote)
 // CRITICAL: This MUST call verify internally and block on failure
-func Promote(cfg *config.Config, imageRef, targetEnv string, outputJSON bool) (*PromoteResult, error) {
+// prof is the profile for the verification gate (--profile, or profiles.byEnv for the target env); nil for none
+// toRegistry (--to-registry) copies the verified image to another registry instead of re-tagging locally
+func Promote(cfg *config.Config, imageRef, targetEnv, toRegistry string, prof *profile.Profile, outputJSON bool) (*PromoteResult, error) {
 	if imageRef == "" {
 		return nil, fmt.Errorf("image reference required")
 	}
@@ -29,10 +43,26 @@ func Promote(cfg *config.Config, imageRef, targetEnv string, outputJSON bool) (*
 		return nil, fmt.Errorf("target environment required\n\nUsage: acc promote <image> --to <env>")
 	}
 
+	// A cross-registry copy reads the source from its registry, so the reference must name one
+	if toRegistry != "" {
+		if _, _, _, err := oci.ParseReference(imageRef); err != nil {
+			return nil, ui.NewError(ui.CodeInvalidArgument, fmt.Sprintf("--to-registry requires a registry image reference: %v", err),
+				"Promote from the source registry, e.g. acc promote staging.example.com/app:1.0 --to prod --to-registry prod.example.com")
+		}
+	}
+
 	if !outputJSON {
 		ui.PrintInfo(fmt.Sprintf("Promoting %s to environment: %s", imageRef, targetEnv))
 	}
 
+	// Pin the source digest before verification, so the tag cannot be moved to
+	// unverified content between verify and re-tag. The pin is the image ID (config digest)
+	// even for name@<manifest digest> sources: it is what docker tag and copyVerified compare.
+	digest, err := oci.InspectImageID(imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve digest: %w\n\nRemediation:\n  - Ensure image exists locally: docker pull %s\n  - Or build the image first: acc build", err, imageRef)
+	}
+
 	// CRITICAL: Verification gates execution (AGENTS.md Section 1.1)
 	if !outputJSON {
 		ui.PrintTrust("Running verification before promotion...")
@@ -44,35 +74,55 @@ func Promote(cfg *config.Config, imageRef, targetEnv string, outputJSON bool) (*
 	// Create a temporary config with environment-specific policy for verification
 	tempCfg := *cfg
 	tempCfg.Policy = envPolicy
+	tempCfg.Policy.Env = targetEnv
 
-	// Verify with promotion flag set (requires attestations)
-	verifyResult, err := verify.Verify(&tempCfg, imageRef, true, outputJSON, nil)
+	// Verify with promotion flag set (requires attestations). The pinned reference is
+	// verified, not the floating tag, so the result describes exactly what is re-tagged.
+	verifyResult, err := verify.Verify(&tempCfg, pinnedRef(imageRef, digest), true, outputJSON, prof)
 	if err != nil {
 		// RED OUTPUT MEANS STOP (AGENTS.md Section 0)
 		if !outputJSON {
 			ui.PrintError(fmt.Sprintf("Verification failed for environment '%s' - promotion BLOCKED", targetEnv))
 		}
-		return nil, fmt.Errorf("verification failed: %w", err)
+		return nil, ui.WrapError(ui.CodeVerificationFailed, fmt.Errorf("verification failed: %w", err), "")
 	}
 
 	if verifyResult.Status == "fail" {
 		if !outputJSON {
 			ui.PrintError(fmt.Sprintf("Verification failed for environment '%s' - promotion BLOCKED", targetEnv))
 		}
-		return nil, fmt.Errorf("verification failed with status: %s", verifyResult.Status)
+		return nil, ui.NewError(ui.CodeVerificationFailed, fmt.Sprintf("verification failed with status: %s", verifyResult.Status), "")
+	}
+
+	// The last verified image must be the pinned one
+	if err := validateVerifiedDigest(imageRef, digest, targetEnv); err != nil {
+		return nil, err
 	}
 
 	if !outputJSON {
 		ui.PrintSuccess("Verification passed - proceeding with promotion")
 	}
 
+	// Attestation enforcement: required by the base policy (or --require-attestation)
+	// or by the target environment's policy
+	attestationStatus := "not-required"
+	if cfg.Policy.RequireAttestation || envPolicy.RequireAttestation {
+		if err := checkAttestation(imageRef, targetEnv, outputJSON); err != nil {
+			return nil, err
+		}
+		attestationStatus = "verified"
+	}
+
 	// Get environment-specific registry
 	envRegistry := cfg.GetRegistryForEnv(targetEnv)
 
-	// Resolve digest
-	digest, err := resolveDigest(imageRef)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve digest: %w\n\nRemediation:\n  - Ensure image exists locally: docker pull %s\n  - Or build the image first: acc build", err, imageRef)
+	// The verified image must still be the pinned one
+	if err := validateSourceDigest(imageRef, digest, targetEnv); err != nil {
+		return nil, err
+	}
+
+	if toRegistry != "" {
+		return promoteAcrossRegistries(imageRef, targetEnv, toRegistry, digest, attestationStatus, prof, outputJSON)
 	}
 
 	// Determine target reference
@@ -80,11 +130,11 @@ func Promote(cfg *config.Config, imageRef, targetEnv string, outputJSON bool) (*
 
 	if !outputJSON {
 		ui.PrintInfo(fmt.Sprintf("Promoting: %s -> %s", imageRef, targetRef))
-		ui.PrintInfo(fmt.Sprintf("Digest: sha256:%s", digest))
+		ui.PrintInfo(fmt.Sprintf("Digest: %s", oci.QualifiedDigest(digest)))
 	}
 
-	// Promote (re-tag without rebuild)
-	if err := retagImage(imageRef, targetRef, digest); err != nil {
+	// Promote (re-tag the pinned digest without rebuild)
+	if err := retagImage(targetRef, digest); err != nil {
 		return nil, err
 	}
 
@@ -93,42 +143,150 @@ func Promote(cfg *config.Config, imageRef, targetEnv string, outputJSON bool) (*
 	}
 
 	result := &PromoteResult{
-		SourceRef: imageRef,
-		TargetRef: targetRef,
-		Digest:    digest,
-		Env:       targetEnv,
-		Status:    "success",
+		SourceRef:         imageRef,
+		TargetRef:         targetRef,
+		Digest:            digest,
+		Env:               targetEnv,
+		Status:            "success",
+		AttestationStatus: attestationStatus,
+	}
+	if prof != nil {
+		result.Profile = prof.Name
 	}
 
 	return result, nil
 }
 
-// resolveDigest attempts to resolve the digest for an image reference
-func resolveDigest(imageRef string) (string, error) {
-	tools := []struct {
-		name string
-		args []string
-	}{
-		{"docker", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"podman", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"nerdctl", []string{"inspect", "--format={{.Id}}", imageRef}},
+// promoteAcrossRegistries copies the verified digest to toRegistry (after the verification gate)
+func promoteAcrossRegistries(imageRef, targetEnv, toRegistry, digest, attestationStatus string, prof *profile.Profile, outputJSON bool) (*PromoteResult, error) {
+	if !outputJSON {
+		ui.PrintInfo(fmt.Sprintf("Copying %s to registry %s", imageRef, toRegistry))
+		ui.PrintInfo(fmt.Sprintf("Digest: %s", oci.QualifiedDigest(digest)))
 	}
 
-	for _, tool := range tools {
-		if _, err := exec.LookPath(tool.name); err == nil {
-			cmd := exec.Command(tool.name, tool.args...)
-			output, err := cmd.Output()
-			if err == nil {
-				digest := strings.TrimSpace(string(output))
-				digest = strings.TrimPrefix(digest, "sha256:")
-				if digest != "" {
-					return digest, nil
-				}
-			}
+	copied, err := promoteToRegistry(imageRef, toRegistry, targetEnv, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	if !outputJSON {
+		ui.PrintSuccess(fmt.Sprintf("Promoted to %s (%s)", copied.Destination, copied.ManifestDigest))
+	}
+
+	result := &PromoteResult{
+		SourceRef:         imageRef,
+		TargetRef:         copied.Destination,
+		Digest:            digest,
+		Env:               targetEnv,
+		Status:            "success",
+		AttestationStatus: attestationStatus,
+		Copy:              copied,
+	}
+	if prof != nil {
+		result.Profile = prof.Name
+	}
+	return result, nil
+}
+
+// checkAttestation blocks promotion unless a valid local attestation exists for imageRef
+func checkAttestation(imageRef, targetEnv string, outputJSON bool) error {
+	if !outputJSON {
+		ui.PrintTrust("Checking attestation requirement...")
+	}
+
+	// Use local attestations only for enforcement check (remote=false)
+	attestResult, err := trust.VerifyAttestations(imageRef, false, false, outputJSON)
+	if err != nil || attestResult.VerificationStatus != "verified" {
+		// Attestation enforcement blocks promotion (same exit code as verification gate)
+		if !outputJSON {
+			ui.PrintError(fmt.Sprintf("Attestation requirement not met for environment '%s' - promotion BLOCKED", targetEnv))
+			fmt.Fprintf(os.Stderr, "\nRemediation:\n")
+			fmt.Fprintf(os.Stderr, "  1. Verify the workload: acc verify %s\n", imageRef)
+			fmt.Fprintf(os.Stderr, "  2. Create attestation: acc attest %s\n", imageRef)
+			fmt.Fprintf(os.Stderr, "  3. Re-promote: acc promote %s --to %s\n", imageRef, targetEnv)
 		}
+		return ui.NewError(ui.CodeVerificationFailed, fmt.Sprintf("attestation requirement not met: %s", attestResult.VerificationStatus), "")
 	}
 
-	return "", fmt.Errorf("could not resolve digest using available tools")
+	if !outputJSON {
+		ui.PrintSuccess(fmt.Sprintf("Attestation verified (%d found)", attestResult.AttestationCount))
+	}
+	return nil
+}
+
+// validateSourceDigest re-resolves imageRef and fails if it no longer points at the
+// pinned (verified) digest, e.g. because the tag was moved during verification
+func validateSourceDigest(imageRef, pinnedDigest, targetEnv string) error {
+	currentDigest, err := oci.InspectImageID(imageRef)
+	if err != nil {
+		return fmt.Errorf("failed to resolve digest: %w\n\nRemediation:\n  - Ensure image exists locally: docker pull %s", err, imageRef)
+	}
+	if currentDigest != pinnedDigest {
+		return ui.NewError(ui.CodeVerificationFailed,
+			fmt.Sprintf("source digest mismatch: '%s' now points at %s but %s was verified - promotion BLOCKED", imageRef, oci.QualifiedDigest(currentDigest), oci.QualifiedDigest(pinnedDigest)),
+			fmt.Sprintf("The source tag moved during promotion. Re-run: acc promote %s --to %s", imageRef, targetEnv))
+	}
+	return nil
+}
+
+// pinnedRef returns imageRef's repository pinned to digest (name@<algorithm>:<hex>),
+// replacing any tag or digest imageRef carries
+func pinnedRef(imageRef, digest string) string {
+	repository := imageRef
+	if idx := strings.Index(repository, "@"); idx >= 0 {
+		repository = repository[:idx]
+	}
+	// A tag colon comes after the last "/" (a registry port colon does not)
+	if idx := strings.LastIndex(repository, ":"); idx > strings.LastIndex(repository, "/") {
+		repository = repository[:idx]
+	}
+	return repository + "@" + oci.QualifiedDigest(digest)
+}
+
+// VerifyState is the part of the last verification state promote checks
+type VerifyState struct {
+	ImageRef    string `json:"imageRef"`
+	ImageDigest string `json:"imageDigest,omitempty"`
+}
+
+// loadVerifyState loads the last verification state
+func loadVerifyState() (*VerifyState, error) {
+	stateFile := filepath.Join(".acc", "state", "last_verify.json")
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var state VerifyState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse verification state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// validateVerifiedDigest ensures the last verified image is the pinned digest
+// Testing Contract: promote MUST fail when the pinned image != last verified image
+func validateVerifiedDigest(imageRef, pinnedDigest, targetEnv string) error {
+	state, err := loadVerifyState()
+	if err != nil {
+		return ui.NewError(ui.CodeVerificationFailed,
+			fmt.Sprintf("no verification state for '%s' (%v) - promotion BLOCKED", imageRef, err),
+			fmt.Sprintf("Promotion checks the verification it ran against .acc/state; re-run without --no-state: acc promote %s --to %s", imageRef, targetEnv))
+	}
+
+	stateDigest := oci.NormalizeDigest(state.ImageDigest)
+	if stateDigest != pinnedDigest {
+		verified := "(unknown)"
+		if stateDigest != "" {
+			verified = oci.QualifiedDigest(stateDigest)
+		}
+		return ui.NewError(ui.CodeVerificationFailed,
+			fmt.Sprintf("image mismatch: promoting '%s' (digest: %s) but last verified image was '%s' (digest: %s) - promotion BLOCKED",
+				imageRef, oci.QualifiedDigest(pinnedDigest), state.ImageRef, verified),
+			fmt.Sprintf("Run 'acc verify %s' first, then re-run: acc promote %s --to %s", imageRef, imageRef, targetEnv))
+	}
+	return nil
 }
 
 // buildTargetRef builds the target reference for promotion
@@ -147,15 +305,15 @@ func buildTargetRef(sourceRef, env, registry string) string {
 	return fmt.Sprintf("%s/%s:%s", registry, imageName, env)
 }
 
-// retagImage re-tags an image without rebuild
-func retagImage(sourceRef, targetRef, digest string) error {
+// retagImage re-tags an image by digest (never the floating source tag) without rebuild
+func retagImage(targetRef, digest string) error {
 	// Try different tools for re-tagging
 	tools := []string{"docker", "podman", "nerdctl"}
 
 	for _, tool := range tools {
 		if _, err := exec.LookPath(tool); err == nil {
 			// Tag the image
-			cmd := exec.Command(tool, "tag", sourceRef, targetRef)
+			cmd := exec.Command(tool, "tag", oci.QualifiedDigest(digest), targetRef)
 			if err := cmd.Run(); err != nil {
 				continue
 			}
@@ -167,8 +325,7 @@ func retagImage(sourceRef, targetRef, digest string) error {
 				continue
 			}
 
-			newDigest := strings.TrimSpace(string(output))
-			newDigest = strings.TrimPrefix(newDigest, "sha256:")
+			newDigest := oci.NormalizeDigest(string(output))
 
 			if newDigest != digest {
 				return fmt.Errorf("tag verification failed: digest mismatch")
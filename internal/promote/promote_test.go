@@ -0,0 +1,216 @@
+package promote
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+var testDigest = strings.Repeat("ab", 32)
+
+// setupPromoteProject creates a project whose verification passes, with fake docker and opa
+// on PATH. docker resolves the source tag to testDigest and logs its arguments to docker.log
+// in the returned bin dir. A non-empty driftDigest moves the tag while OPA runs.
+func setupPromoteProject(t *testing.T, driftDigest string) (*config.Config, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	os.MkdirAll(filepath.Join(".acc", "sbom"), 0755)
+	os.WriteFile(filepath.Join(".acc", "sbom", "promote-test.spdx.json"), []byte("{}"), 0644)
+	os.MkdirAll(filepath.Join(".acc", "policy"), 0755)
+	os.WriteFile(filepath.Join(".acc", "policy", "policy.rego"), []byte("package acc.policy\n"), 0644)
+
+	binDir := t.TempDir()
+	digestFile := filepath.Join(binDir, "digest")
+	os.WriteFile(digestFile, []byte(testDigest), 0644)
+	docker := `#!/bin/sh
+echo "$@" >> "` + filepath.Join(binDir, "docker.log") + `"
+case "$1" in
+  inspect)
+    if [ "$2" = "--format={{.Id}}" ]; then echo "sha256:$(cat "` + digestFile + `")"; else echo '[{"Config":{"User":"app","Labels":null}}]'; fi ;;
+  tag) exit 0 ;;
+esac
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+	opa := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+`
+	if driftDigest != "" {
+		opa += `echo "` + driftDigest + `" > "` + digestFile + `"
+`
+	}
+	opa += `echo '{"result":[{"expressions":[{"value":{"violations":[]}}]}]}'
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(opa), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return config.DefaultConfig("promote-test"), binDir
+}
+
+// writeAttestation records a valid attestation for testDigest
+func writeAttestation(t *testing.T) {
+	t.Helper()
+	attestation := map[string]interface{}{
+		"schemaVersion": "v0.1",
+		"timestamp":     "2025-01-01T12:00:00Z",
+		"subject":       map[string]interface{}{"imageRef": "app:1.0", "imageDigest": "sha256:" + testDigest},
+		"evidence": map[string]interface{}{
+			"verificationStatus":      "pass",
+			"verificationResultsHash": "sha256:xyz789",
+		},
+	}
+	attestDir := filepath.Join(".acc", "attestations", testDigest[:12])
+	os.MkdirAll(attestDir, 0755)
+	data, _ := json.MarshalIndent(attestation, "", "  ")
+	if err := os.WriteFile(filepath.Join(attestDir, "attestation.json"), data, 0644); err != nil {
+		t.Fatalf("failed to write attestation: %v", err)
+	}
+}
+
+// TestPromote_RequireAttestation tests that promotion is gated on attestation presence
+func TestPromote_RequireAttestation(t *testing.T) {
+	t.Run("not required", func(t *testing.T) {
+		cfg, _ := setupPromoteProject(t, "")
+
+		result, err := Promote(cfg, "app:1.0", "prod", "", nil, true)
+		if err != nil {
+			t.Fatalf("expected promotion to succeed: %v", err)
+		}
+		if result.AttestationStatus != "not-required" {
+			t.Errorf("attestationStatus = %q, want not-required", result.AttestationStatus)
+		}
+	})
+
+	t.Run("blocked without attestation", func(t *testing.T) {
+		cfg, _ := setupPromoteProject(t, "")
+		cfg.Policy.RequireAttestation = true
+
+		result, err := Promote(cfg, "app:1.0", "prod", "", nil, true)
+		if err == nil {
+			t.Fatalf("expected promotion to be blocked, got %+v", result)
+		}
+		if !strings.Contains(err.Error(), "attestation requirement not met") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("allowed with attestation", func(t *testing.T) {
+		cfg, _ := setupPromoteProject(t, "")
+		cfg.Policy.RequireAttestation = true
+		writeAttestation(t)
+
+		result, err := Promote(cfg, "app:1.0", "prod", "", nil, true)
+		if err != nil {
+			t.Fatalf("expected promotion to succeed with attestation: %v", err)
+		}
+		if result.AttestationStatus != "verified" || result.Status != "success" {
+			t.Errorf("expected verified success, got %+v", result)
+		}
+	})
+
+	t.Run("required by environment policy", func(t *testing.T) {
+		cfg, _ := setupPromoteProject(t, "")
+		envPolicy := cfg.Policy
+		envPolicy.RequireAttestation = true
+		cfg.Environments = map[string]config.EnvConfig{"prod": {Policy: &envPolicy}}
+
+		if _, err := Promote(cfg, "app:1.0", "prod", "", nil, true); err == nil {
+			t.Fatal("expected prod policy to require an attestation")
+		}
+		if _, err := Promote(cfg, "app:1.0", "staging", "", nil, true); err != nil {
+			t.Errorf("expected staging promotion without attestation to succeed: %v", err)
+		}
+	})
+}
+
+// TestPromote_SourceDigest tests that promotion re-tags the verified digest and blocks if the tag drifts
+func TestPromote_SourceDigest(t *testing.T) {
+	t.Run("matching digest", func(t *testing.T) {
+		cfg, binDir := setupPromoteProject(t, "")
+
+		result, err := Promote(cfg, "app:1.0", "prod", "", nil, true)
+		if err != nil {
+			t.Fatalf("expected promotion to succeed: %v", err)
+		}
+		if result.Digest != testDigest {
+			t.Errorf("digest = %s, want %s", result.Digest, testDigest)
+		}
+
+		log, _ := os.ReadFile(filepath.Join(binDir, "docker.log"))
+		if !strings.Contains(string(log), "tag sha256:"+testDigest+" "+result.TargetRef) {
+			t.Errorf("expected re-tag by digest, docker calls:\n%s", log)
+		}
+		if strings.Contains(string(log), "tag app:1.0") {
+			t.Errorf("expected floating tag not to be re-tagged, docker calls:\n%s", log)
+		}
+		if !strings.Contains(string(log), "inspect app@sha256:"+testDigest) {
+			t.Errorf("expected the pinned reference to be verified, docker calls:\n%s", log)
+		}
+	})
+
+	t.Run("digest source reference", func(t *testing.T) {
+		cfg, binDir := setupPromoteProject(t, "")
+		manifestDigest := strings.Repeat("ef", 32)
+
+		result, err := Promote(cfg, "app@sha256:"+manifestDigest, "prod", "", nil, true)
+		if err != nil {
+			t.Fatalf("expected promotion by digest to succeed: %v", err)
+		}
+		if result.Digest != testDigest {
+			t.Errorf("digest = %s, want the image ID %s", result.Digest, testDigest)
+		}
+
+		log, _ := os.ReadFile(filepath.Join(binDir, "docker.log"))
+		if !strings.Contains(string(log), "tag sha256:"+testDigest+" ") || strings.Contains(string(log), "tag sha256:"+manifestDigest) {
+			t.Errorf("expected re-tag by image ID, not the manifest digest, docker calls:\n%s", log)
+		}
+	})
+
+	t.Run("last verified digest differs", func(t *testing.T) {
+		cfg, binDir := setupPromoteProject(t, "")
+		// --no-state leaves an earlier verification of another image as the last verified one
+		cfg.Policy.NoState = true
+		stale := strings.Repeat("cd", 32)
+		os.MkdirAll(filepath.Join(".acc", "state"), 0755)
+		state := `{"imageRef":"app:0.9","status":"pass","imageDigest":"sha256:` + stale + `"}`
+		os.WriteFile(filepath.Join(".acc", "state", "last_verify.json"), []byte(state), 0644)
+
+		_, err := Promote(cfg, "app:1.0", "prod", "", nil, true)
+		if err == nil {
+			t.Fatal("expected promotion to be blocked when the last verified image differs")
+		}
+		if !strings.Contains(err.Error(), "image mismatch") || !strings.Contains(err.Error(), stale) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		log, _ := os.ReadFile(filepath.Join(binDir, "docker.log"))
+		if strings.Contains(string(log), "tag ") {
+			t.Errorf("expected no re-tag after a digest mismatch, docker calls:\n%s", log)
+		}
+	})
+
+	t.Run("drifted digest", func(t *testing.T) {
+		drifted := strings.Repeat("cd", 32)
+		cfg, binDir := setupPromoteProject(t, drifted)
+
+		_, err := Promote(cfg, "app:1.0", "prod", "", nil, true)
+		if err == nil {
+			t.Fatal("expected promotion to be blocked after the source tag moved")
+		}
+		if !strings.Contains(err.Error(), "source digest mismatch") || !strings.Contains(err.Error(), drifted) {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		log, _ := os.ReadFile(filepath.Join(binDir, "docker.log"))
+		if strings.Contains(string(log), "tag ") {
+			t.Errorf("expected no re-tag after drift, docker calls:\n%s", log)
+		}
+	})
+}
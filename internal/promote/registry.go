@@ -0,0 +1,91 @@
+package promote
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/ui"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// RegistryCopy records a cross-registry promotion (--to-registry)
+type RegistryCopy struct {
+	Source         string `json:"source"`         // <registry>/<repository>@<manifest digest> that was copied
+	Destination    string `json:"destination"`    // <registry>/<repository>:<tag> it was copied to
+	ManifestDigest string `json:"manifestDigest"` // identical in both registries
+}
+
+// destinationFor returns the registry, repository, and tag imageRef is copied to.
+// toRegistry is a host (the source repository path is kept) or host/repository; the tag is the environment.
+func destinationFor(toRegistry, sourceRepository, targetEnv string) (registry, repository, tag string) {
+	toRegistry = strings.TrimSuffix(toRegistry, "/")
+	registry, repository, found := strings.Cut(toRegistry, "/")
+	if !found || repository == "" {
+		repository = sourceRepository
+	}
+	return registry, repository, targetEnv
+}
+
+// promoteToRegistry copies the verified image from its source registry to toRegistry
+func promoteToRegistry(imageRef, toRegistry, targetEnv, verifiedDigest string) (*RegistryCopy, error) {
+	registry, repository, reference, err := oci.ParseReference(imageRef)
+	if err != nil {
+		return nil, err
+	}
+	src, err := oci.NewRepository(registry, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	dstRegistry, dstRepository, tag := destinationFor(toRegistry, repository, targetEnv)
+	dst, err := oci.NewRepository(dstRegistry, dstRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	copied, err := copyVerified(context.Background(), src, dst, reference, tag, verifiedDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegistryCopy{
+		Source:         fmt.Sprintf("%s/%s@%s", registry, repository, copied),
+		Destination:    fmt.Sprintf("%s/%s:%s", dstRegistry, dstRepository, tag),
+		ManifestDigest: copied,
+	}, nil
+}
+
+// copyVerified resolves reference in src, blocks unless the manifest is the verified image
+// (its config digest is the verified image ID), and copies that manifest by digest to dst as tag.
+// Copying by digest means a tag moved after the check cannot change what is promoted.
+func copyVerified(ctx context.Context, src, dst *remote.Repository, reference, tag, verifiedDigest string) (string, error) {
+	desc, err := src.Resolve(ctx, reference)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s in source registry: %w", reference, err)
+	}
+
+	configDigests, err := oci.ConfigDigests(ctx, src, desc)
+	if err != nil {
+		return "", err
+	}
+	matched := false
+	for _, d := range configDigests {
+		if oci.NormalizeDigest(d) == verifiedDigest {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return "", ui.NewError(ui.CodeVerificationFailed,
+			fmt.Sprintf("source registry manifest %s is not the verified image %s - promotion BLOCKED", desc.Digest, oci.QualifiedDigest(verifiedDigest)),
+			"The registry tag and the local image differ. Pull the source image and re-run acc promote.")
+	}
+
+	copied, err := oci.CopyManifest(ctx, src, dst, desc, tag)
+	if err != nil {
+		return "", err
+	}
+	return copied.Digest.String(), nil
+}
@@ -0,0 +1,181 @@
+package promote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// memoryRegistry is a minimal OCI distribution registry (pull and monolithic push) backed by maps
+type memoryRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte // by tag and by digest
+	server    *httptest.Server
+}
+
+func newMemoryRegistry(t *testing.T) *memoryRegistry {
+	t.Helper()
+	reg := &memoryRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+	reg.server = httptest.NewServer(http.HandlerFunc(reg.serveHTTP))
+	t.Cleanup(reg.server.Close)
+	return reg
+}
+
+func (reg *memoryRegistry) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	path := r.URL.Path
+	switch {
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPost:
+		w.Header().Set("Location", strings.TrimSuffix(path, "/")+"/upload-1")
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/uploads/") && r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		reg.blobs[r.URL.Query().Get("digest")] = data
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/blobs/"):
+		data, ok := reg.blobs[path[strings.LastIndex(path, "/")+1:]]
+		reg.write(w, r, data, ok, "application/octet-stream")
+	case strings.Contains(path, "/manifests/") && r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		dgst := sha256Digest(data)
+		reg.manifests[path[strings.LastIndex(path, "/")+1:]] = data
+		reg.manifests[dgst] = data
+		w.Header().Set("Docker-Content-Digest", dgst)
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/manifests/"):
+		data, ok := reg.manifests[path[strings.LastIndex(path, "/")+1:]]
+		reg.write(w, r, data, ok, ocispec.MediaTypeImageManifest)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (reg *memoryRegistry) write(w http.ResponseWriter, r *http.Request, data []byte, ok bool, mediaType string) {
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", sha256Digest(data))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if r.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
+
+// repository returns a plain-HTTP client for repo on this registry
+func (reg *memoryRegistry) repository(t *testing.T, repo string) *remote.Repository {
+	t.Helper()
+	r, err := remote.NewRepository(strings.TrimPrefix(reg.server.URL, "http://") + "/" + repo)
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	r.PlainHTTP = true
+	return r
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// seedImage stores an image (config, one layer, manifest tagged tag) and returns the
+// config digest (the local image ID) and the manifest digest
+func (reg *memoryRegistry) seedImage(tag string) (configDigest, manifestDigest string) {
+	config := []byte(`{"architecture":"amd64","os":"linux","config":{"User":"app"},"rootfs":{"type":"layers","diff_ids":[]}}`)
+	layer := []byte("layer contents")
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: digest.Digest(sha256Digest(config)), Size: int64(len(config))},
+		Layers:    []ocispec.Descriptor{{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.Digest(sha256Digest(layer)), Size: int64(len(layer))}},
+	})
+
+	reg.blobs[sha256Digest(config)] = config
+	reg.blobs[sha256Digest(layer)] = layer
+	reg.manifests[tag] = manifest
+	reg.manifests[sha256Digest(manifest)] = manifest
+	return sha256Digest(config), sha256Digest(manifest)
+}
+
+// TestCopyVerified tests copying the verified manifest between two registries
+func TestCopyVerified(t *testing.T) {
+	staging := newMemoryRegistry(t)
+	prod := newMemoryRegistry(t)
+	configDigest, manifestDigest := staging.seedImage("1.0")
+	verified := strings.TrimPrefix(configDigest, "sha256:")
+
+	t.Run("copies manifest preserving digest", func(t *testing.T) {
+		copied, err := copyVerified(context.Background(), staging.repository(t, "team/app"), prod.repository(t, "team/app"), "1.0", "prod", verified)
+		if err != nil {
+			t.Fatalf("copyVerified failed: %v", err)
+		}
+		if copied != manifestDigest {
+			t.Errorf("copied digest = %s, want %s", copied, manifestDigest)
+		}
+		if got := sha256Digest(prod.manifests["prod"]); got != manifestDigest {
+			t.Errorf("destination tag 'prod' points at %s, want %s", got, manifestDigest)
+		}
+		if len(prod.blobs) != 2 {
+			t.Errorf("expected config and layer blobs copied, got %d", len(prod.blobs))
+		}
+	})
+
+	t.Run("blocks when the registry image is not the verified one", func(t *testing.T) {
+		_, err := copyVerified(context.Background(), staging.repository(t, "team/app"), prod.repository(t, "team/other"), "1.0", "prod", strings.Repeat("cd", 32))
+		if err == nil || !strings.Contains(err.Error(), "not the verified image") {
+			t.Fatalf("expected verification block, got %v", err)
+		}
+	})
+
+	t.Run("unknown source tag", func(t *testing.T) {
+		if _, err := copyVerified(context.Background(), staging.repository(t, "team/app"), prod.repository(t, "team/app"), "missing", "prod", verified); err == nil {
+			t.Error("expected error for unknown source tag")
+		}
+	})
+}
+
+func TestDestinationFor(t *testing.T) {
+	tests := []struct {
+		toRegistry, registry, repository string
+	}{
+		{toRegistry: "prod.example.com", registry: "prod.example.com", repository: "team/app"},
+		{toRegistry: "prod.example.com/", registry: "prod.example.com", repository: "team/app"},
+		{toRegistry: "prod.example.com:5000/release/app", registry: "prod.example.com:5000", repository: "release/app"},
+	}
+	for _, tt := range tests {
+		registry, repository, tag := destinationFor(tt.toRegistry, "team/app", "prod")
+		if registry != tt.registry || repository != tt.repository || tag != "prod" {
+			t.Errorf("destinationFor(%q) = (%q, %q, %q)", tt.toRegistry, registry, repository, tag)
+		}
+	}
+}
+
+// TestPromote_ToRegistryRequiresRegistryRef tests that a local reference is rejected before verification
+func TestPromote_ToRegistryRequiresRegistryRef(t *testing.T) {
+	cfg, binDir := setupPromoteProject(t, "")
+	if _, err := Promote(cfg, "app:1.0", "prod", "prod.example.com", nil, true); err == nil {
+		t.Fatal("expected error for reference without a registry")
+	}
+	if _, err := os.Stat(filepath.Join(binDir, "docker.log")); err == nil {
+		t.Error("verification should not run for an invalid --to-registry source")
+	}
+}
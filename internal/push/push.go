@@ -24,6 +24,8 @@ type PushResult struct {
 	Pushed             bool   `json:"pushed"`
 	Timestamp          string `json:"timestamp"`
 	AttestationRef     string `json:"attestationRef,omitempty"`
+	Env                string `json:"env,omitempty"`     // environment selected with --env
+	Profile            string `json:"profile,omitempty"` // profile the verification was required to use
 }
 
 // VerifyState represents the persisted verification state
@@ -32,6 +34,8 @@ type VerifyState struct {
 	Status    string                 `json:"status"`
 	Timestamp string                 `json:"timestamp"`
 	Result    map[string]interface{} `json:"result"`
+	// ProfileUsed is the profile name verify applied ("" when none)
+	ProfileUsed string `json:"profileUsed,omitempty"`
 }
 
 // AttestationPointer represents the last attestation pointer
@@ -42,7 +46,9 @@ type AttestationPointer struct {
 
 // Push pushes a verified image to a registry (AGENTS.md - verify gates execution)
 // CRITICAL: Must verify before push - no bypass flags allowed
-func Push(cfg *config.Config, imageRef string, outputJSON bool) (*PushResult, error) {
+// requiredProfile, when set (--profile or the profiles.byEnv mapping for --env), is the
+// profile name the last verification must have applied
+func Push(cfg *config.Config, imageRef, requiredProfile string, outputJSON bool) (*PushResult, error) {
 	if imageRef == "" {
 		return nil, fmt.Errorf("image reference required\n\nUsage: acc push <image>")
 	}
@@ -54,12 +60,12 @@ func Push(cfg *config.Config, imageRef string, outputJSON bool) (*PushResult, er
 
 	state, err := loadVerifyState()
 	if err != nil {
-		return nil, fmt.Errorf("verification state not found: %w\n\nRemediation:\n  Run 'acc verify %s' first to create verification state", err, imageRef)
+		return nil, fmt.Errorf("verification state not found: %w\n\nRemediation:\n  Run 'acc verify %s' first to create verification state (verify --no-state does not write it)", err, imageRef)
 	}
 
 	// Verify status is not "fail"
 	if state.Status == "fail" {
-		return nil, fmt.Errorf("verification failed - push BLOCKED\n\nLast verification status: %s\nVerified at: %s\n\nRemediation:\n  Fix policy violations and re-run: acc verify %s", state.Status, state.Timestamp, imageRef)
+		return nil, ui.WrapError(ui.CodeVerificationFailed, fmt.Errorf("verification failed - push BLOCKED\n\nLast verification status: %s\nVerified at: %s\n\nRemediation:\n  Fix policy violations and re-run: acc verify %s", state.Status, state.Timestamp, imageRef), "")
 	}
 
 	if !outputJSON {
@@ -71,6 +77,11 @@ func Push(cfg *config.Config, imageRef string, outputJSON bool) (*PushResult, er
 		return nil, err
 	}
 
+	// The verification must have been run with the profile this push requires
+	if err := validateProfileMatch(imageRef, requiredProfile, state); err != nil {
+		return nil, err
+	}
+
 	// v0.3.1: Optional attestation enforcement
 	if cfg.Policy.RequireAttestation {
 		if !outputJSON {
@@ -78,7 +89,7 @@ func Push(cfg *config.Config, imageRef string, outputJSON bool) (*PushResult, er
 		}
 
 		// Use local attestations only for enforcement check (remote=false)
-		attestResult, err := trust.VerifyAttestations(imageRef, false, outputJSON)
+		attestResult, err := trust.VerifyAttestations(imageRef, false, false, outputJSON)
 		if err != nil || attestResult.VerificationStatus != "verified" {
 			// Attestation enforcement blocks push (same exit code as verification gate)
 			if !outputJSON {
@@ -134,6 +145,7 @@ func Push(cfg *config.Config, imageRef string, outputJSON bool) (*PushResult, er
 		Pushed:             true,
 		Timestamp:          time.Now().UTC().Format(time.RFC3339),
 		AttestationRef:     attestationRef,
+		Profile:            requiredProfile,
 	}
 
 	return result, nil
@@ -189,6 +201,21 @@ func validateImageMatch(imageRef string, state *VerifyState) error {
 	return fmt.Errorf("image mismatch: attempting to push '%s' but last verified image was '%s'\n\nRemediation:\n  Run 'acc verify %s' first", imageRef, state.ImageRef, imageRef)
 }
 
+// validateProfileMatch ensures the last verification applied requiredProfile (if any)
+func validateProfileMatch(imageRef, requiredProfile string, state *VerifyState) error {
+	if requiredProfile == "" || state.ProfileUsed == requiredProfile {
+		return nil
+	}
+
+	used := state.ProfileUsed
+	if used == "" {
+		used = "(none)"
+	}
+	return ui.NewError(ui.CodeVerificationFailed,
+		fmt.Sprintf("profile mismatch: last verification used profile '%s' but '%s' is required - push BLOCKED", used, requiredProfile),
+		fmt.Sprintf("Re-run: acc verify %s --profile %s", imageRef, requiredProfile))
+}
+
 // resolveDigest attempts to resolve the digest for an image reference
 func resolveDigest(imageRef string) (string, error) {
 	tools := []struct {
@@ -269,3 +269,29 @@ func TestPushWithPassedVerification(t *testing.T) {
 		t.Error("pass status should not be blocked")
 	}
 }
+
+// TestValidateProfileMatch tests that push requires the profile selected by --profile/--env
+func TestValidateProfileMatch(t *testing.T) {
+	tests := []struct {
+		name, required, used string
+		wantErr              bool
+	}{
+		{"no requirement", "", "", false},
+		{"no requirement with profile", "", "strict", false},
+		{"matching profile", "strict", "strict", false},
+		{"different profile", "strict", "baseline", true},
+		{"verified without profile", "strict", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &VerifyState{ImageRef: "test:latest", Status: "pass", ProfileUsed: tt.used}
+			err := validateProfileMatch("test:latest", tt.required, state)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateProfileMatch() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "acc verify test:latest --profile "+tt.required) {
+				t.Errorf("expected re-verify remediation, got: %v", err)
+			}
+		})
+	}
+}
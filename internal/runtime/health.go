@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// DefaultHealthTimeout bounds the health check when --health-timeout is not set
+const DefaultHealthTimeout = 30 * time.Second
+
+// healthPollInterval is the delay between health probes
+var healthPollInterval = time.Second
+
+// HealthCheck configures the readiness check of acc run --health-check / --health-url
+type HealthCheck struct {
+	Command string        // run inside the container with sh -c; healthy when it exits 0
+	URL     string        // fetched from the host with HTTP GET; healthy on a 2xx response
+	Timeout time.Duration // give up (and stop the container) after this long
+}
+
+// runWithHealthCheck starts the workload detached, polls its health check until it passes
+// or times out, and stops the container either way. A timeout or a container that exits
+// before becoming healthy is an error.
+func runWithHealthCheck(runtime string, opts *RunOptions, outputJSON bool) error {
+	cmdArgs := buildRunCommand(runtime, opts)
+	if !outputJSON {
+		ui.PrintInfo(fmt.Sprintf("Running: %s", strings.Join(cmdArgs, " ")))
+	}
+
+	output, err := exec.Command(cmdArgs[0], cmdArgs[1:]...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("failed to start workload: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("failed to start workload: %w", err)
+	}
+	containerID := strings.TrimSpace(string(output))
+	if containerID == "" {
+		return fmt.Errorf("failed to start workload: %s printed no container ID", runtime)
+	}
+	defer stopContainer(runtime, containerID, outputJSON)
+
+	timeout := opts.Health.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+	if !outputJSON {
+		ui.PrintInfo(fmt.Sprintf("Waiting up to %s for the workload to become healthy...", timeout))
+	}
+
+	start := time.Now()
+	if err := waitHealthy(runtime, containerID, opts.Health, timeout); err != nil {
+		if !outputJSON {
+			ui.PrintError(fmt.Sprintf("Health check failed: %v", err))
+		}
+		return ui.NewError(ui.CodeError, fmt.Sprintf("health check failed: %v", err), "Check the workload's logs (docker logs), or raise --health-timeout")
+	}
+
+	if !outputJSON {
+		ui.PrintSuccess(fmt.Sprintf("Workload healthy after %s", time.Since(start).Round(time.Millisecond)))
+	}
+	return nil
+}
+
+// waitHealthy probes the container until the check passes, the container exits, or timeout
+func waitHealthy(runtime, containerID string, check *HealthCheck, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		probeErr := probeHealth(runtime, containerID, check)
+		if probeErr == nil {
+			return nil
+		}
+		ui.PrintDebug(fmt.Sprintf("health probe: %v", probeErr))
+
+		if !containerRunning(runtime, containerID) {
+			return fmt.Errorf("container exited before becoming healthy (last probe: %v)", probeErr)
+		}
+		if time.Now().Add(healthPollInterval).After(deadline) {
+			return fmt.Errorf("not healthy within %s (last probe: %v)", timeout, probeErr)
+		}
+		time.Sleep(healthPollInterval)
+	}
+}
+
+// probeHealth runs the health check once
+func probeHealth(runtime, containerID string, check *HealthCheck) error {
+	if check.URL != "" {
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Get(check.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%s returned %s", check.URL, resp.Status)
+		}
+		return nil
+	}
+
+	output, err := exec.Command(runtime, "exec", containerID, "sh", "-c", check.Command).CombinedOutput()
+	if err != nil {
+		if out := strings.TrimSpace(string(output)); out != "" {
+			return fmt.Errorf("%q failed: %v: %s", check.Command, err, out)
+		}
+		return fmt.Errorf("%q failed: %v", check.Command, err)
+	}
+	return nil
+}
+
+// containerRunning reports whether the container is still running
+func containerRunning(runtime, containerID string) bool {
+	output, err := exec.Command(runtime, "inspect", "--format", "{{.State.Running}}", containerID).Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// stopContainer stops (and, with --rm, removes) the health-checked container
+func stopContainer(runtime, containerID string, outputJSON bool) {
+	if output, err := exec.Command(runtime, "stop", containerID).CombinedOutput(); err != nil && !outputJSON {
+		ui.PrintWarning(fmt.Sprintf("Failed to stop container %s: %v %s", containerID, err, strings.TrimSpace(string(output))))
+	}
+}
@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeHealthRuntime puts a fake docker on PATH that starts container "cid123", runs exec
+// commands on the host, reports the container running unless ACC_FAKE_EXITED is set,
+// and logs every invocation to the returned file
+func fakeHealthRuntime(t *testing.T) string {
+	t.Helper()
+	binDir := t.TempDir()
+	calls := filepath.Join(t.TempDir(), "calls.log")
+	script := `#!/bin/sh
+echo "$*" >> "` + calls + `"
+case "$1" in
+  run) echo cid123 ;;
+  exec) shift 3; exec sh -c "$2" ;;
+  inspect) [ -n "$ACC_FAKE_EXITED" ] && echo false || echo true ;;
+  stop) echo cid123 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake docker: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	interval := healthPollInterval
+	healthPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { healthPollInterval = interval })
+	return calls
+}
+
+func readCalls(t *testing.T, path string) string {
+	t.Helper()
+	data, _ := os.ReadFile(path)
+	return string(data)
+}
+
+func TestRunWithHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		exited  bool
+		wantErr string
+	}{
+		{name: "healthy immediately", command: "true"},
+		{name: "healthy after retries", command: `n=$(cat "$COUNT" 2>/dev/null || echo 0); echo $((n+1)) > "$COUNT"; [ "$n" -ge 2 ]`},
+		{name: "never healthy", command: "echo not ready; exit 1", wantErr: "not healthy within"},
+		{name: "container exited", command: "false", exited: true, wantErr: "container exited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := fakeHealthRuntime(t)
+			t.Setenv("COUNT", filepath.Join(t.TempDir(), "count"))
+			if tt.exited {
+				t.Setenv("ACC_FAKE_EXITED", "1")
+			}
+
+			opts := &RunOptions{
+				ImageRef: "demo-app:v1",
+				Health:   &HealthCheck{Command: tt.command, Timeout: 200 * time.Millisecond},
+			}
+			err := runWithHealthCheck("docker", opts, true)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("expected healthy workload, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+
+			log := readCalls(t, calls)
+			if !strings.Contains(log, "run --rm -d ") {
+				t.Errorf("expected a detached run, got calls:\n%s", log)
+			}
+			// The container is stopped whether or not it became healthy
+			if !strings.HasSuffix(log, "stop cid123\n") {
+				t.Errorf("expected the container to be stopped last, got calls:\n%s", log)
+			}
+		})
+	}
+}
+
+func TestRunWithHealthCheck_URL(t *testing.T) {
+	fakeHealthRuntime(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	opts := &RunOptions{
+		ImageRef:    "demo-app:v1",
+		NetworkMode: "bridge",
+		Health:      &HealthCheck{URL: server.URL + "/healthz", Timeout: time.Second},
+	}
+	if err := runWithHealthCheck("docker", opts, true); err != nil {
+		t.Fatalf("expected healthy workload, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 probes, got %d", requests)
+	}
+}
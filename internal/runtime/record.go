@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/state"
+)
+
+// RunRecord is a single entry in the local run audit trail
+// Records are appended to .acc/state/runs/<digest>.jsonl after each successful run
+type RunRecord struct {
+	Timestamp   string   `json:"timestamp"`
+	ImageRef    string   `json:"imageRef"`
+	ImageDigest string   `json:"imageDigest"`
+	Args        []string `json:"args"`
+	NetworkMode string   `json:"networkMode"`
+	User        string   `json:"user"`
+	Profile     string   `json:"profile,omitempty"`
+}
+
+// newRunRecord builds a run record from run options
+func newRunRecord(opts *RunOptions, digest string) *RunRecord {
+	networkMode := opts.NetworkMode
+	if networkMode == "" {
+		networkMode = "none"
+	}
+
+	args := opts.Args
+	if args == nil {
+		args = []string{}
+	}
+
+	record := &RunRecord{
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		ImageRef:    opts.ImageRef,
+		ImageDigest: digest,
+		Args:        args,
+		NetworkMode: networkMode,
+		User:        opts.User,
+	}
+	if opts.Profile != nil {
+		record.Profile = opts.Profile.Name
+	}
+
+	return record
+}
+
+// appendRunRecord appends a run record to .acc/state/runs/<digest>.jsonl
+func appendRunRecord(record *RunRecord) error {
+	if record.ImageDigest == "" {
+		return fmt.Errorf("image digest required to record run")
+	}
+
+	runsDir := filepath.Join(".acc", "state", "runs")
+	if err := os.MkdirAll(runsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run record: %w", err)
+	}
+
+	runFile := filepath.Join(runsDir, oci.DigestFileName(oci.NormalizeDigest(record.ImageDigest))+".jsonl")
+	if err := state.AppendFile(runFile, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write run record: %w", err)
+	}
+
+	return nil
+}
+
+// LoadRunRecords loads the run history for an image digest
+// Returns an empty slice if no runs have been recorded
+func LoadRunRecords(digest string) ([]RunRecord, error) {
+	runFile := filepath.Join(".acc", "state", "runs", oci.DigestFileName(oci.NormalizeDigest(digest))+".jsonl")
+	data, err := os.ReadFile(runFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []RunRecord{}, nil
+		}
+		return nil, fmt.Errorf("failed to read run history: %w", err)
+	}
+
+	records := []RunRecord{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var record RunRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse run record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/profile"
 	"github.com/cloudcwfranck/acc/internal/trust"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	"github.com/cloudcwfranck/acc/internal/verify"
@@ -21,6 +23,8 @@ type RunOptions struct {
 	ReadOnly     bool
 	User         string
 	Capabilities []string
+	Profile      *profile.Profile // optional profile for the verification gate (--verify-profile)
+	Health       *HealthCheck     // start detached and wait for readiness (--health-check / --health-url)
 }
 
 // Run runs a workload locally with verification gates (AGENTS.md Section 2 - acc run)
@@ -31,13 +35,13 @@ func Run(cfg *config.Config, opts *RunOptions, outputJSON bool) error {
 		ui.PrintTrust("Verifying workload before execution...")
 	}
 
-	verifyResult, err := verify.Verify(cfg, opts.ImageRef, false, outputJSON, nil)
+	verifyResult, err := verify.Verify(cfg, opts.ImageRef, false, outputJSON, opts.Profile)
 	if err != nil {
 		// RED OUTPUT MEANS STOP (AGENTS.md Section 0)
 		if !outputJSON {
 			ui.PrintError("Verification failed - workload will NOT run")
 		}
-		return fmt.Errorf("verification failed: %w", err)
+		return ui.WrapError(ui.CodeVerificationFailed, fmt.Errorf("verification failed: %w", err), "")
 	}
 
 	if verifyResult.Status == "fail" {
@@ -45,7 +49,7 @@ func Run(cfg *config.Config, opts *RunOptions, outputJSON bool) error {
 		if !outputJSON {
 			ui.PrintError("Verification failed - workload will NOT run")
 		}
-		return fmt.Errorf("verification failed with status: %s", verifyResult.Status)
+		return ui.NewError(ui.CodeVerificationFailed, fmt.Sprintf("verification failed with status: %s", verifyResult.Status), "")
 	}
 
 	if !outputJSON {
@@ -59,7 +63,7 @@ func Run(cfg *config.Config, opts *RunOptions, outputJSON bool) error {
 		}
 
 		// Use local attestations only for enforcement check (remote=false)
-		attestResult, err := trust.VerifyAttestations(opts.ImageRef, false, outputJSON)
+		attestResult, err := trust.VerifyAttestations(opts.ImageRef, false, false, outputJSON)
 		if err != nil || attestResult.VerificationStatus != "verified" {
 			// Attestation enforcement blocks execution (same exit code as verification gate)
 			if !outputJSON {
@@ -86,6 +90,10 @@ func Run(cfg *config.Config, opts *RunOptions, outputJSON bool) error {
 
 	// Detect runtime tool
 	runtime, err := detectRuntime()
+	if err != nil && opts.Health != nil {
+		// A requested smoke test that cannot run must not look like a pass
+		return fmt.Errorf("cannot run the health check: %w", err)
+	}
 	if err != nil {
 		// Runtime not available is a warning, not a trust failure
 		if !outputJSON {
@@ -100,6 +108,15 @@ func Run(cfg *config.Config, opts *RunOptions, outputJSON bool) error {
 		ui.PrintInfo(fmt.Sprintf("Using runtime: %s", runtime))
 	}
 
+	// --health-check / --health-url: smoke-test the workload instead of attaching to it
+	if opts.Health != nil {
+		if err := runWithHealthCheck(runtime, opts, outputJSON); err != nil {
+			return err
+		}
+		recordRun(opts, outputJSON)
+		return nil
+	}
+
 	// Build run command with security defaults (AGENTS.md Section 8)
 	cmdArgs := buildRunCommand(runtime, opts)
 
@@ -124,9 +141,27 @@ func Run(cfg *config.Config, opts *RunOptions, outputJSON bool) error {
 		return nil
 	}
 
+	// Record run provenance for audit (failure to record does not fail the run)
+	recordRun(opts, outputJSON)
+
 	return nil
 }
 
+// recordRun appends a run record to the local audit trail for the image digest
+func recordRun(opts *RunOptions, outputJSON bool) {
+	digest, err := oci.ResolveDigest(opts.ImageRef)
+	if err != nil {
+		if !outputJSON {
+			ui.PrintWarning(fmt.Sprintf("Could not record run provenance: %v", err))
+		}
+		return
+	}
+
+	if err := appendRunRecord(newRunRecord(opts, digest)); err != nil && !outputJSON {
+		ui.PrintWarning(fmt.Sprintf("Could not record run provenance: %v", err))
+	}
+}
+
 // detectRuntime detects which container runtime is available
 func detectRuntime() (string, error) {
 	runtimes := []string{"docker", "podman", "nerdctl"}
@@ -155,16 +190,21 @@ func buildRunCommand(runtime string, opts *RunOptions) []string {
 	// Remove container after run
 	args = append(args, "--rm")
 
-	// Add -i (interactive) only if stdin is a TTY
-	// This allows piped input in CI while preserving interactive mode in terminals
-	if isStdinTTY() {
-		args = append(args, "-i")
-	}
+	if opts.Health != nil {
+		// Health-checked workloads run detached; the runtime prints the container ID
+		args = append(args, "-d")
+	} else {
+		// Add -i (interactive) only if stdin is a TTY
+		// This allows piped input in CI while preserving interactive mode in terminals
+		if isStdinTTY() {
+			args = append(args, "-i")
+		}
 
-	// Add -t (TTY) only if stdout is a TTY
-	// This prevents "the input device is not a TTY" errors in CI environments
-	if isStdoutTTY() {
-		args = append(args, "-t")
+		// Add -t (TTY) only if stdout is a TTY
+		// This prevents "the input device is not a TTY" errors in CI environments
+		if isStdoutTTY() {
+			args = append(args, "-t")
+		}
 	}
 
 	// Apply security defaults (AGENTS.md Section 8)
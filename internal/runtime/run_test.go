@@ -1,8 +1,14 @@
 package runtime
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/profile"
 )
 
 func TestBuildRunCommand(t *testing.T) {
@@ -191,3 +197,114 @@ func contains(slice []string, str string) bool {
 	}
 	return false
 }
+
+// TestAppendRunRecord tests that each run appends a correctly-shaped record
+func TestAppendRunRecord(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	digest := "abc123def456"
+	opts := &RunOptions{
+		ImageRef:    "demo-app:v1",
+		Args:        []string{"echo", "hello"},
+		NetworkMode: "",
+		User:        "1000:1000",
+		Profile:     &profile.Profile{Name: "baseline"},
+	}
+
+	if err := appendRunRecord(newRunRecord(opts, digest)); err != nil {
+		t.Fatalf("appendRunRecord failed: %v", err)
+	}
+	if err := appendRunRecord(newRunRecord(&RunOptions{ImageRef: "demo-app:v1"}, digest)); err != nil {
+		t.Fatalf("appendRunRecord failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(".acc", "state", "runs", digest+".jsonl"))
+	if err != nil {
+		t.Fatalf("failed to read run history: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSONL lines, got %d", len(lines))
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &raw); err != nil {
+		t.Fatalf("run record is not valid JSON: %v", err)
+	}
+	for _, field := range []string{"timestamp", "imageRef", "imageDigest", "args", "networkMode", "user", "profile"} {
+		if _, ok := raw[field]; !ok {
+			t.Errorf("run record missing field %q", field)
+		}
+	}
+
+	records, err := LoadRunRecords("sha256:" + digest)
+	if err != nil {
+		t.Fatalf("LoadRunRecords failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	first := records[0]
+	if _, err := time.Parse(time.RFC3339, first.Timestamp); err != nil {
+		t.Errorf("timestamp not RFC3339: %q", first.Timestamp)
+	}
+	if first.ImageDigest != digest || first.ImageRef != "demo-app:v1" {
+		t.Errorf("unexpected image fields: %+v", first)
+	}
+	if strings.Join(first.Args, " ") != "echo hello" {
+		t.Errorf("expected args 'echo hello', got %v", first.Args)
+	}
+	if first.NetworkMode != "none" {
+		t.Errorf("expected default network mode 'none', got %q", first.NetworkMode)
+	}
+	if first.User != "1000:1000" || first.Profile != "baseline" {
+		t.Errorf("unexpected user/profile: %q/%q", first.User, first.Profile)
+	}
+	if records[1].Profile != "" || records[1].Args == nil {
+		t.Errorf("expected empty profile and non-nil args for second record, got %+v", records[1])
+	}
+}
+
+func TestLoadRunRecords_NoHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	records, err := LoadRunRecords("missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %d", len(records))
+	}
+}
+
+// TestRunRecords_SHA512 tests that run history for a sha512 image is read back from the
+// file it was written to
+func TestRunRecords_SHA512(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	digest := "sha512:" + strings.Repeat("cd", 64)
+	if err := appendRunRecord(newRunRecord(&RunOptions{ImageRef: "demo-app:v1"}, digest)); err != nil {
+		t.Fatalf("appendRunRecord failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(".acc", "state", "runs", "sha512-"+strings.Repeat("cd", 64)+".jsonl")); err != nil {
+		t.Fatalf("expected run history under the digest's file name: %v", err)
+	}
+
+	records, err := LoadRunRecords(digest)
+	if err != nil {
+		t.Fatalf("LoadRunRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].ImageDigest != digest {
+		t.Errorf("expected the sha512 run record back, got %+v", records)
+	}
+}
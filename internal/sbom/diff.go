@@ -0,0 +1,139 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+)
+
+// VersionChange is a package whose version differs between two SBOMs
+// (downgrades are reported the same way as upgrades)
+type VersionChange struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"oldVersion"`
+	NewVersion string `json:"newVersion"`
+}
+
+// LicenseChange is a package present in both SBOMs whose licenses differ
+type LicenseChange struct {
+	Name        string   `json:"name"`
+	OldLicenses []string `json:"oldLicenses"`
+	NewLicenses []string `json:"newLicenses"`
+}
+
+// Delta is the package-level difference between two SBOMs, sorted by package name
+type Delta struct {
+	Added          []Package       `json:"added"`
+	Removed        []Package       `json:"removed"`
+	Upgraded       []VersionChange `json:"upgraded"`
+	LicenseChanged []LicenseChange `json:"licenseChanged"`
+}
+
+// Empty reports whether the two SBOMs list the same packages, versions, and licenses
+func (d *Delta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Upgraded) == 0 && len(d.LicenseChanged) == 0
+}
+
+// Diff compares two SBOMs by package name. A name whose single version changed is an
+// upgrade; when a name has several versions, versions only in newDoc are added and versions
+// only in oldDoc are removed. Licenses are compared per name across all its versions, so
+// an upgrade that also relicenses the package appears in both Upgraded and LicenseChanged.
+func Diff(oldDoc, newDoc *Document) *Delta {
+	delta := &Delta{
+		Added:          []Package{},
+		Removed:        []Package{},
+		Upgraded:       []VersionChange{},
+		LicenseChanged: []LicenseChange{},
+	}
+
+	oldByName, newByName := groupByName(oldDoc), groupByName(newDoc)
+	for _, name := range unionNames(oldByName, newByName) {
+		oldPkgs, newPkgs := oldByName[name], newByName[name]
+		if len(oldPkgs) == 0 {
+			delta.Added = append(delta.Added, newPkgs...)
+			continue
+		}
+		if len(newPkgs) == 0 {
+			delta.Removed = append(delta.Removed, oldPkgs...)
+			continue
+		}
+
+		added, removed := versionDifference(newPkgs, oldPkgs), versionDifference(oldPkgs, newPkgs)
+		if len(added) == 1 && len(removed) == 1 {
+			delta.Upgraded = append(delta.Upgraded, VersionChange{
+				Name:       name,
+				OldVersion: removed[0].Version,
+				NewVersion: added[0].Version,
+			})
+		} else {
+			delta.Added = append(delta.Added, added...)
+			delta.Removed = append(delta.Removed, removed...)
+		}
+
+		oldLicenses, newLicenses := licenseSet(oldPkgs), licenseSet(newPkgs)
+		if strings.Join(oldLicenses, "\x00") != strings.Join(newLicenses, "\x00") {
+			delta.LicenseChanged = append(delta.LicenseChanged, LicenseChange{
+				Name:        name,
+				OldLicenses: oldLicenses,
+				NewLicenses: newLicenses,
+			})
+		}
+	}
+	return delta
+}
+
+// groupByName indexes a document's packages by name, each name's packages sorted by version
+func groupByName(doc *Document) map[string][]Package {
+	byName := map[string][]Package{}
+	for _, pkg := range doc.Packages {
+		byName[pkg.Name] = append(byName[pkg.Name], pkg)
+	}
+	for _, pkgs := range byName {
+		sort.SliceStable(pkgs, func(i, j int) bool { return pkgs[i].Version < pkgs[j].Version })
+	}
+	return byName
+}
+
+func unionNames(a, b map[string][]Package) []string {
+	names := make([]string, 0, len(a)+len(b))
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// versionDifference returns the packages in a whose version does not appear in b
+func versionDifference(a, b []Package) []Package {
+	versions := map[string]bool{}
+	for _, pkg := range b {
+		versions[pkg.Version] = true
+	}
+	var diff []Package
+	for _, pkg := range a {
+		if !versions[pkg.Version] {
+			diff = append(diff, pkg)
+		}
+	}
+	return diff
+}
+
+// licenseSet returns the distinct licenses of pkgs, sorted
+func licenseSet(pkgs []Package) []string {
+	seen := map[string]bool{}
+	licenses := []string{}
+	for _, pkg := range pkgs {
+		for _, license := range pkg.Licenses {
+			if !seen[license] {
+				seen[license] = true
+				licenses = append(licenses, license)
+			}
+		}
+	}
+	sort.Strings(licenses)
+	return licenses
+}
@@ -0,0 +1,75 @@
+package sbom
+
+import (
+	"reflect"
+	"testing"
+)
+
+// spdxNextFixture is spdxFixture one build later: musl upgraded, busybox relicensed,
+// alpine-baselayout removed, curl added, and two versions of zlib vendored
+const spdxNextFixture = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {"name": "musl", "versionInfo": "1.2.5", "licenseConcluded": "MIT"},
+    {"name": "busybox", "versionInfo": "1.36.1", "licenseConcluded": "GPL-2.0-or-later"},
+    {"name": "curl", "versionInfo": "8.5.0", "licenseConcluded": "curl"},
+    {"name": "zlib", "versionInfo": "1.3.1", "licenseConcluded": "Zlib"},
+    {"name": "zlib", "versionInfo": "1.2.13", "licenseConcluded": "Zlib"}
+  ]
+}`
+
+func TestDiff(t *testing.T) {
+	oldDoc, err := Parse([]byte(spdxFixture))
+	if err != nil {
+		t.Fatalf("Parse(old) failed: %v", err)
+	}
+	newDoc, err := Parse([]byte(spdxNextFixture))
+	if err != nil {
+		t.Fatalf("Parse(new) failed: %v", err)
+	}
+
+	delta := Diff(oldDoc, newDoc)
+	want := &Delta{
+		Added: []Package{
+			{Name: "curl", Version: "8.5.0", Licenses: []string{"curl"}},
+			{Name: "zlib", Version: "1.2.13", Licenses: []string{"Zlib"}},
+			{Name: "zlib", Version: "1.3.1", Licenses: []string{"Zlib"}},
+		},
+		Removed: []Package{
+			{Name: "alpine-baselayout", Version: "3.4.3"},
+		},
+		Upgraded: []VersionChange{
+			{Name: "musl", OldVersion: "1.2.4", NewVersion: "1.2.5"},
+		},
+		LicenseChanged: []LicenseChange{
+			{Name: "busybox", OldLicenses: []string{"GPL-2.0-only"}, NewLicenses: []string{"GPL-2.0-or-later"}},
+		},
+	}
+	if !reflect.DeepEqual(delta, want) {
+		t.Errorf("Diff() =\n%+v\nwant\n%+v", delta, want)
+	}
+	if delta.Empty() {
+		t.Error("expected a non-empty delta")
+	}
+}
+
+func TestDiff_MultipleVersions(t *testing.T) {
+	oldDoc := &Document{Packages: []Package{{Name: "lodash", Version: "4.17.20"}, {Name: "lodash", Version: "3.10.1"}}}
+	newDoc := &Document{Packages: []Package{{Name: "lodash", Version: "4.17.21"}}}
+
+	delta := Diff(oldDoc, newDoc)
+	// Two versions collapsed into one is not a single upgrade: report both sides
+	if len(delta.Upgraded) != 0 || len(delta.Added) != 1 || len(delta.Removed) != 2 {
+		t.Errorf("unexpected delta %+v", delta)
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	doc, err := Parse([]byte(cycloneDXFixture))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if delta := Diff(doc, doc); !delta.Empty() {
+		t.Errorf("expected no changes comparing an SBOM with itself, got %+v", delta)
+	}
+}
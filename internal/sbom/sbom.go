@@ -0,0 +1,230 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SBOM formats, matching sbom.format in acc.yaml
+const (
+	FormatSPDX      = "spdx"
+	FormatCycloneDX = "cyclonedx"
+)
+
+// Package is a component listed in an SBOM
+type Package struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version,omitempty"`
+	PURL     string   `json:"purl,omitempty"`
+	Licenses []string `json:"licenses,omitempty"`
+}
+
+// Document is the format-independent content of an SPDX or CycloneDX JSON SBOM
+type Document struct {
+	Format   string    `json:"format"`
+	Packages []Package `json:"packages"`
+}
+
+// ParseFile reads and parses an SBOM file
+func ParseFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SBOM %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// Parse parses an SPDX or CycloneDX JSON SBOM, detecting the format from its content
+func Parse(data []byte) (*Document, error) {
+	format, err := DetectFormat(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatSPDX {
+		return parseSPDX(data)
+	}
+	return parseCycloneDX(data)
+}
+
+// DetectFormat returns the format (FormatSPDX or FormatCycloneDX) of an SBOM from its content
+func DetectFormat(data []byte) (string, error) {
+	var probe struct {
+		SPDXVersion string `json:"spdxVersion"`
+		BOMFormat   string `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch {
+	case probe.SPDXVersion != "":
+		return FormatSPDX, nil
+	case strings.EqualFold(probe.BOMFormat, "CycloneDX"):
+		return FormatCycloneDX, nil
+	default:
+		return "", fmt.Errorf("unrecognized SBOM format (expected SPDX or CycloneDX JSON)")
+	}
+}
+
+// DetectFileFormat returns the format of the SBOM file at path (see DetectFormat)
+func DetectFileFormat(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	return DetectFormat(data)
+}
+
+// spdxNoLicense are SPDX license values that carry no license information
+var spdxNoLicense = map[string]bool{"": true, "NOASSERTION": true, "NONE": true}
+
+func parseSPDX(data []byte) (*Document, error) {
+	var spdx struct {
+		Packages []struct {
+			Name             string `json:"name"`
+			VersionInfo      string `json:"versionInfo"`
+			LicenseConcluded string `json:"licenseConcluded"`
+			LicenseDeclared  string `json:"licenseDeclared"`
+			ExternalRefs     []struct {
+				ReferenceType    string `json:"referenceType"`
+				ReferenceLocator string `json:"referenceLocator"`
+			} `json:"externalRefs"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &spdx); err != nil {
+		return nil, fmt.Errorf("invalid SPDX document: %w", err)
+	}
+
+	doc := &Document{Format: FormatSPDX, Packages: []Package{}}
+	for _, p := range spdx.Packages {
+		pkg := Package{Name: p.Name, Version: p.VersionInfo}
+		// The concluded license wins; the declared one is used when it was not concluded
+		if license := p.LicenseConcluded; !spdxNoLicense[license] {
+			pkg.Licenses = []string{license}
+		} else if license := p.LicenseDeclared; !spdxNoLicense[license] {
+			pkg.Licenses = []string{license}
+		}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				pkg.PURL = ref.ReferenceLocator
+				break
+			}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+	}
+	return doc, nil
+}
+
+// cdxComponent is a CycloneDX component; components may nest
+type cdxComponent struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	PURL     string `json:"purl"`
+	Licenses []struct {
+		License struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []cdxComponent `json:"components"`
+}
+
+func parseCycloneDX(data []byte) (*Document, error) {
+	var cdx struct {
+		Components []cdxComponent `json:"components"`
+	}
+	if err := json.Unmarshal(data, &cdx); err != nil {
+		return nil, fmt.Errorf("invalid CycloneDX document: %w", err)
+	}
+
+	doc := &Document{Format: FormatCycloneDX, Packages: []Package{}}
+	var walk func([]cdxComponent)
+	walk = func(components []cdxComponent) {
+		for _, c := range components {
+			pkg := Package{Name: c.Name, Version: c.Version, PURL: c.PURL}
+			for _, l := range c.Licenses {
+				switch {
+				case l.License.ID != "":
+					pkg.Licenses = append(pkg.Licenses, l.License.ID)
+				case l.License.Name != "":
+					pkg.Licenses = append(pkg.Licenses, l.License.Name)
+				case l.Expression != "":
+					pkg.Licenses = append(pkg.Licenses, l.Expression)
+				}
+			}
+			doc.Packages = append(doc.Packages, pkg)
+			walk(c.Components)
+		}
+	}
+	walk(cdx.Components)
+	return doc, nil
+}
+
+// LicenseCount is the number of packages carrying a license
+type LicenseCount struct {
+	License string `json:"license"`
+	Count   int    `json:"count"`
+}
+
+// Summary is an at-a-glance view of an SBOM's contents
+type Summary struct {
+	Components  int            `json:"components"`
+	TopLicenses []LicenseCount `json:"topLicenses"`
+	Watchlist   []Package      `json:"watchlist"` // packages matching sbom.watchlist
+}
+
+// Summarize counts the document's packages, its topN most common licenses (ties by name),
+// and the packages whose name matches a watchlist pattern (exact or glob, e.g. "log4j-*")
+func Summarize(doc *Document, watchlist []string, topN int) *Summary {
+	summary := &Summary{
+		Components:  len(doc.Packages),
+		TopLicenses: []LicenseCount{},
+		Watchlist:   []Package{},
+	}
+
+	counts := map[string]int{}
+	for _, pkg := range doc.Packages {
+		for _, license := range pkg.Licenses {
+			counts[license]++
+		}
+		if matchesWatchlist(pkg.Name, watchlist) {
+			summary.Watchlist = append(summary.Watchlist, pkg)
+		}
+	}
+
+	for license, count := range counts {
+		summary.TopLicenses = append(summary.TopLicenses, LicenseCount{License: license, Count: count})
+	}
+	sort.Slice(summary.TopLicenses, func(i, j int) bool {
+		a, b := summary.TopLicenses[i], summary.TopLicenses[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.License < b.License
+	})
+	if topN > 0 && len(summary.TopLicenses) > topN {
+		summary.TopLicenses = summary.TopLicenses[:topN]
+	}
+	return summary
+}
+
+func matchesWatchlist(name string, watchlist []string) bool {
+	for _, pattern := range watchlist {
+		if strings.EqualFold(name, pattern) {
+			return true
+		}
+		if ok, _ := filepath.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package sbom
+
+import (
+	"reflect"
+	"testing"
+)
+
+// cycloneDXFixture has four components, one nested, with id, name, and expression licenses
+const cycloneDXFixture = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "components": [
+    {"name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+     "licenses": [{"license": {"id": "Apache-2.0"}}]},
+    {"name": "openssl", "version": "3.0.2", "licenses": [{"license": {"id": "Apache-2.0"}}],
+     "components": [{"name": "libcrypto", "version": "3.0.2", "licenses": [{"expression": "Apache-2.0 OR MIT"}]}]},
+    {"name": "zlib", "version": "1.2.13", "licenses": [{"license": {"name": "Zlib"}}]}
+  ]
+}`
+
+const spdxFixture = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {"name": "musl", "versionInfo": "1.2.4", "licenseConcluded": "MIT",
+     "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:apk/alpine/musl@1.2.4"}]},
+    {"name": "busybox", "versionInfo": "1.36.1", "licenseConcluded": "NOASSERTION", "licenseDeclared": "GPL-2.0-only"},
+    {"name": "alpine-baselayout", "versionInfo": "3.4.3", "licenseConcluded": "NOASSERTION"}
+  ]
+}`
+
+func TestParse_CycloneDX(t *testing.T) {
+	doc, err := Parse([]byte(cycloneDXFixture))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if doc.Format != FormatCycloneDX {
+		t.Errorf("format = %q, want %q", doc.Format, FormatCycloneDX)
+	}
+	if len(doc.Packages) != 4 {
+		t.Fatalf("expected 4 components including the nested one, got %d: %+v", len(doc.Packages), doc.Packages)
+	}
+	want := Package{Name: "log4j-core", Version: "2.14.1", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Licenses: []string{"Apache-2.0"}}
+	if !reflect.DeepEqual(doc.Packages[0], want) {
+		t.Errorf("first package = %+v, want %+v", doc.Packages[0], want)
+	}
+}
+
+func TestParse_SPDX(t *testing.T) {
+	doc, err := Parse([]byte(spdxFixture))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if doc.Format != FormatSPDX || len(doc.Packages) != 3 {
+		t.Fatalf("expected 3 SPDX packages, got %+v", doc)
+	}
+	if doc.Packages[0].PURL != "pkg:apk/alpine/musl@1.2.4" {
+		t.Errorf("purl = %q", doc.Packages[0].PURL)
+	}
+	if got := doc.Packages[1].Licenses; !reflect.DeepEqual(got, []string{"GPL-2.0-only"}) {
+		t.Errorf("expected declared license when not concluded, got %v", got)
+	}
+	if got := doc.Packages[2].Licenses; got != nil {
+		t.Errorf("expected NOASSERTION to yield no license, got %v", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, data := range []string{`not json`, `{"name": "neither"}`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("expected error for %q", data)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	doc, err := Parse([]byte(cycloneDXFixture))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	summary := Summarize(doc, []string{"LOG4J-CORE", "libcrypto*"}, 2)
+	if summary.Components != 4 {
+		t.Errorf("components = %d, want 4", summary.Components)
+	}
+	wantLicenses := []LicenseCount{{"Apache-2.0", 2}, {"Apache-2.0 OR MIT", 1}}
+	if !reflect.DeepEqual(summary.TopLicenses, wantLicenses) {
+		t.Errorf("top licenses = %+v, want %+v", summary.TopLicenses, wantLicenses)
+	}
+	var watched []string
+	for _, pkg := range summary.Watchlist {
+		watched = append(watched, pkg.Name)
+	}
+	if !reflect.DeepEqual(watched, []string{"log4j-core", "libcrypto"}) {
+		t.Errorf("watchlist matches = %v, want [log4j-core libcrypto]", watched)
+	}
+}
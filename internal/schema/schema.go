@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/attest"
+	"github.com/cloudcwfranck/acc/internal/build"
+	"github.com/cloudcwfranck/acc/internal/inspect"
+	"github.com/cloudcwfranck/acc/internal/promote"
+	"github.com/cloudcwfranck/acc/internal/push"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+	"github.com/cloudcwfranck/acc/internal/trust"
+	"github.com/cloudcwfranck/acc/internal/verify"
+)
+
+// Draft is the JSON Schema dialect of generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // a type name, or a list of them
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // false, or a schema for map values
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// results maps acc commands to the result type printed with --json
+var results = map[string]interface{}{
+	"attest":       attest.AttestResult{},
+	"build":        build.BuildResult{},
+	"inspect":      inspect.InspectResult{},
+	"promote":      promote.PromoteResult{},
+	"push":         push.PushResult{},
+	"sbom diff":    sbom.Delta{},
+	"trust status": trust.StatusResult{},
+	"trust verify": trust.VerifyResult{},
+	"verify":       verify.VerifyResult{},
+}
+
+// Commands lists the commands with a result schema, sorted
+func Commands() []string {
+	commands := make([]string, 0, len(results))
+	for command := range results {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// For returns the schema for a command's --json result ("trust" is "trust status")
+func For(command string) (*Schema, error) {
+	command = strings.Join(strings.Fields(command), " ")
+	if command == "trust" {
+		command = "trust status"
+	}
+	result, ok := results[command]
+	if !ok {
+		return nil, fmt.Errorf("no result schema for command %q (available: %s)", command, strings.Join(Commands(), ", "))
+	}
+	s := Generate(result)
+	s.Title = fmt.Sprintf("acc %s result", command)
+	return s, nil
+}
+
+// Generate builds a schema for v's type from its json struct tags, so it follows the Go
+// types as they change. Named struct types other than the root are emitted once under
+// $defs (as <package>.<Type>) and referenced, which also handles recursive types.
+func Generate(v interface{}) *Schema {
+	g := &generator{defs: map[string]*Schema{}}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	root := g.structSchema(t)
+	root.Schema = Draft
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor returns the schema for t. encoding/json writes nil pointers, slices, and maps
+// as null, so those also accept null.
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Ptr:
+		return nullable(g.schemaFor(t.Elem()))
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"} // []byte is base64
+		}
+		s := &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+		if t.Kind() == reflect.Slice {
+			return nullable(s)
+		}
+		return s
+	case reflect.Map:
+		return nullable(&Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())})
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := defName(t)
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // placeholder, so recursive references stop here
+			g.defs[name] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	default:
+		return &Schema{} // interface{}: any value
+	}
+}
+
+// structSchema describes a struct's JSON fields; embedded structs are flattened as encoding/json does
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	g.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		s.Properties[name] = g.schemaFor(field.Type)
+		if !hasOption(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable allows null in addition to s
+func nullable(s *Schema) *Schema {
+	if typ, ok := s.Type.(string); ok && s.Ref == "" {
+		s.Type = []string{typ, "null"}
+		return s
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+// defName names a struct type by its package and type, e.g. verify.PolicyViolation
+func defName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
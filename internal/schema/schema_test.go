@@ -0,0 +1,210 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validate checks doc against the subset of JSON Schema that Generate emits
+// (type, properties, required, additionalProperties, items, anyOf, $ref into $defs)
+func validate(root map[string]interface{}, s map[string]interface{}, doc interface{}, at string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		defs, _ := root["$defs"].(map[string]interface{})
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: unresolved $ref %s", at, ref)
+		}
+		return validate(root, def, doc, at)
+	}
+
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		for _, sub := range anyOf {
+			if validate(root, sub.(map[string]interface{}), doc, at) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: matches no anyOf alternative", at)
+	}
+
+	if typ, ok := s["type"]; ok {
+		var types []string
+		switch typ := typ.(type) {
+		case string:
+			types = []string{typ}
+		case []interface{}:
+			for _, t := range typ {
+				types = append(types, t.(string))
+			}
+		}
+		matched := false
+		for _, t := range types {
+			if jsonType(doc) == t || (t == "number" && jsonType(doc) == "integer") {
+				matched = true
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: got %s, want %v", at, jsonType(doc), types)
+		}
+	}
+
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		properties, _ := s["properties"].(map[string]interface{})
+		required, _ := s["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := doc[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		for name, value := range doc {
+			if sub, ok := properties[name].(map[string]interface{}); ok {
+				if err := validate(root, sub, value, at+"."+name); err != nil {
+					return err
+				}
+				continue
+			}
+			switch extra := s["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s: unexpected property %q", at, name)
+				}
+			case map[string]interface{}:
+				if err := validate(root, extra, value, at+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			for i, item := range doc {
+				if err := validate(root, items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// emitted returns the schema for command as a consumer sees it (marshaled JSON)
+func emitted(t *testing.T, command string) map[string]interface{} {
+	t.Helper()
+	s, err := For(command)
+	if err != nil {
+		t.Fatalf("For(%q) failed: %v", command, err)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+	return m
+}
+
+// TestSchema_ValidatesGoldenResults tests that the emitted schemas validate real result documents
+func TestSchema_ValidatesGoldenResults(t *testing.T) {
+	for command, dir := range map[string]string{"verify": "verify", "trust status": "trust", "inspect": "inspect"} {
+		files, _ := filepath.Glob(filepath.Join("..", "..", "testdata", "golden", dir, "*.json"))
+		if len(files) == 0 {
+			t.Fatalf("no golden files for %s", command)
+		}
+		s := emitted(t, command)
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", file, err)
+			}
+			var doc interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatalf("invalid golden JSON %s: %v", file, err)
+			}
+			if err := validate(s, s, doc, "$"); err != nil {
+				t.Errorf("%s does not validate against the %s schema: %v", file, command, err)
+			}
+		}
+	}
+}
+
+// TestSchema_RejectsInvalidResult tests that the schema catches missing, mistyped, and unknown fields
+func TestSchema_RejectsInvalidResult(t *testing.T) {
+	s := emitted(t, "verify")
+	tests := map[string]string{
+		"missing status":  `{"sbomPresent":true,"policyResult":null,"attestations":[],"violations":[]}`,
+		"mistyped field":  `{"status":"pass","sbomPresent":"yes","policyResult":null,"attestations":[],"violations":[]}`,
+		"unknown field":   `{"status":"pass","sbomPresent":true,"policyResult":null,"attestations":[],"violations":[],"extra":1}`,
+		"bad violation":   `{"status":"fail","sbomPresent":true,"policyResult":null,"attestations":[],"violations":[{"rule":"r"}]}`,
+		"nested mismatch": `{"status":"fail","sbomPresent":true,"policyResult":{"allow":"no","violations":[],"warnings":[]},"attestations":[],"violations":[]}`,
+	}
+	for name, doc := range tests {
+		var v interface{}
+		json.Unmarshal([]byte(doc), &v)
+		if err := validate(s, s, v, "$"); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	for _, command := range Commands() {
+		s, err := For(command)
+		if err != nil {
+			t.Fatalf("For(%q) failed: %v", command, err)
+		}
+		if s.Schema != Draft || s.Type != "object" || len(s.Properties) == 0 {
+			t.Errorf("For(%q) returned an incomplete schema: %+v", command, s)
+		}
+	}
+
+	if s, err := For("trust"); err != nil || s.Title != "acc trust status result" {
+		t.Errorf("For(trust) = %v, %v; want the trust status schema", s, err)
+	}
+	if _, err := For("run"); err == nil {
+		t.Error("expected error for a command without a result schema")
+	}
+}
+
+func TestGenerate_RecursiveType(t *testing.T) {
+	type node struct {
+		Name     string  `json:"name"`
+		Children []node  `json:"children,omitempty"`
+		Parent   *node   `json:"-"`
+		Weight   float64 `json:"weight"`
+	}
+	s := Generate(node{})
+	if s.Properties["children"].Items.Ref != "#/$defs/schema.node" {
+		t.Errorf("expected children to reference $defs, got %+v", s.Properties["children"].Items)
+	}
+	if _, ok := s.Properties["Parent"]; ok {
+		t.Error(`json:"-" field should be skipped`)
+	}
+	if strings.Join(s.Required, ",") != "name,weight" {
+		t.Errorf("required = %v, want [name weight]", s.Required)
+	}
+}
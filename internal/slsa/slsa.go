@@ -0,0 +1,263 @@
+package slsa
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// inTotoPayloadType is the DSSE payload type for in-toto statements
+const inTotoPayloadType = "application/vnd.in-toto+json"
+
+// Statement represents an in-toto statement carrying SLSA provenance
+type Statement struct {
+	Type          string
+	PredicateType string
+	Subject       []Subject
+	Predicate     map[string]interface{}
+}
+
+// Subject is an in-toto statement subject
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// IsProvenancePredicate reports whether a predicateType denotes SLSA provenance
+func IsProvenancePredicate(predicateType string) bool {
+	return strings.Contains(predicateType, "slsa") || strings.Contains(predicateType, "provenance")
+}
+
+// DecodeEnvelope returns the in-toto statement inside a DSSE envelope
+// Data that is not a DSSE envelope is returned unchanged
+func DecodeEnvelope(data []byte) []byte {
+	var envelope struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.PayloadType != inTotoPayloadType || envelope.Payload == "" {
+		return data
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return data
+	}
+	return decoded
+}
+
+// ParseStatement parses SLSA provenance and performs the structural checks:
+// valid JSON, SLSA predicateType, and a predicate object
+// NOTE: This is structure validation only, not cryptographic verification
+func ParseStatement(data []byte) (*Statement, error) {
+	var provenance map[string]interface{}
+	if err := json.Unmarshal(data, &provenance); err != nil {
+		return nil, fmt.Errorf("provenance file is not valid JSON: %w", err)
+	}
+
+	predicateType, ok := provenance["predicateType"].(string)
+	if !ok || predicateType == "" {
+		return nil, fmt.Errorf("provenance missing predicateType field")
+	}
+
+	if !IsProvenancePredicate(predicateType) {
+		return nil, fmt.Errorf("provenance predicateType is not SLSA provenance: %s", predicateType)
+	}
+
+	predicate, ok := provenance["predicate"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("provenance missing predicate field")
+	}
+
+	statement := &Statement{
+		PredicateType: predicateType,
+		Predicate:     predicate,
+	}
+	statement.Type, _ = provenance["_type"].(string)
+
+	// Subjects are optional for structure validation; malformed entries are skipped
+	if subjects, ok := provenance["subject"].([]interface{}); ok {
+		for _, s := range subjects {
+			sm, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			subject := Subject{Digest: map[string]string{}}
+			subject.Name, _ = sm["name"].(string)
+			if digests, ok := sm["digest"].(map[string]interface{}); ok {
+				for alg, value := range digests {
+					if v, ok := value.(string); ok {
+						subject.Digest[alg] = v
+					}
+				}
+			}
+			statement.Subject = append(statement.Subject, subject)
+		}
+	}
+
+	return statement, nil
+}
+
+// ValidateStatement parses SLSA provenance and checks that it describes the expected artifact
+// Empty expectedDigest / expectedSubjectName skip the corresponding subject check
+func ValidateStatement(data []byte, expectedDigest, expectedSubjectName string) (*Statement, error) {
+	statement, err := ParseStatement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedDigest != "" && !statement.HasSubjectDigest(expectedDigest) {
+		return nil, fmt.Errorf("provenance subject does not match digest %s", qualifiedDigest(expectedDigest))
+	}
+
+	if expectedSubjectName != "" && !statement.HasSubjectName(expectedSubjectName) {
+		return nil, fmt.Errorf("provenance subject does not match artifact: %s", expectedSubjectName)
+	}
+
+	return statement, nil
+}
+
+// BuilderID returns the builder identity from the predicate
+// Supports SLSA v0.2 (predicate.builder.id) and v1 (predicate.runDetails.builder.id)
+func (s *Statement) BuilderID() string {
+	if builder, ok := s.Predicate["builder"].(map[string]interface{}); ok {
+		if id, ok := builder["id"].(string); ok {
+			return id
+		}
+	}
+	if runDetails, ok := s.Predicate["runDetails"].(map[string]interface{}); ok {
+		if builder, ok := runDetails["builder"].(map[string]interface{}); ok {
+			if id, ok := builder["id"].(string); ok {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// SourceRepository returns the repository the artifact was built from, normalized to a plain
+// URL (no git+ prefix, @ref suffix, or .git). Supports SLSA v0.2 invocation.configSource
+// (repository, or uri as written by slsa-github-generator) and v1
+// buildDefinition.externalParameters.workflow.repository.
+func (s *Statement) SourceRepository() string {
+	if invocation, ok := s.Predicate["invocation"].(map[string]interface{}); ok {
+		if configSource, ok := invocation["configSource"].(map[string]interface{}); ok {
+			for _, key := range []string{"repository", "uri"} {
+				if repo, ok := configSource[key].(string); ok && repo != "" {
+					return normalizeRepository(repo)
+				}
+			}
+		}
+	}
+	if definition, ok := s.Predicate["buildDefinition"].(map[string]interface{}); ok {
+		if params, ok := definition["externalParameters"].(map[string]interface{}); ok {
+			if workflow, ok := params["workflow"].(map[string]interface{}); ok {
+				if repo, ok := workflow["repository"].(string); ok && repo != "" {
+					return normalizeRepository(repo)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// MatchSource reports whether the statement's source repository matches pattern, a glob
+// where * matches within one path segment (https://github.com/org/*)
+func (s *Statement) MatchSource(pattern string) (bool, error) {
+	repo := s.SourceRepository()
+	if repo == "" {
+		return false, nil
+	}
+	return path.Match(strings.TrimSuffix(pattern, ".git"), repo)
+}
+
+// ValidateSourcePattern checks that pattern is a well-formed source repository glob
+func ValidateSourcePattern(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("source repository pattern must not be empty")
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid source repository pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// normalizeRepository strips git+ prefixes, @ref suffixes, and .git from a repository URI
+func normalizeRepository(repo string) string {
+	repo = strings.TrimPrefix(strings.TrimSpace(repo), "git+")
+	// The ref follows the last @ in the path; an @ in the host part is user info
+	pathStart := 0
+	if i := strings.Index(repo, "://"); i >= 0 {
+		pathStart = i + 3
+	}
+	if i := strings.Index(repo[pathStart:], "/"); i >= 0 {
+		pathStart += i
+	}
+	if i := strings.LastIndex(repo, "@"); i > pathStart {
+		repo = repo[:i]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(repo, "/"), ".git")
+}
+
+// HasSubjectDigest reports whether any subject has the given digest: sha256 unless prefixed
+// with another algorithm (e.g. "sha512:<hex>"). The "sha256:" prefix is optional on both sides
+func (s *Statement) HasSubjectDigest(digest string) bool {
+	want := normalizeDigest(digest)
+	if want == "" {
+		return false
+	}
+	algorithm := "sha256"
+	if alg, hex, ok := strings.Cut(want, ":"); ok {
+		algorithm, want = alg, hex
+	}
+	for _, subject := range s.Subject {
+		if normalizeDigest(subject.Digest[algorithm]) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSubjectName reports whether any subject has the given name
+func (s *Statement) HasSubjectName(name string) bool {
+	for _, subject := range s.Subject {
+		if subject.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBuilderGitHub checks that buildType and builder identity refer to GitHub Actions
+// Empty values are accepted (not all provenance generators set them)
+func ValidateBuilderGitHub(s *Statement) error {
+	buildType, _ := s.Predicate["buildType"].(string)
+	if buildType != "" && !strings.Contains(buildType, "github") {
+		return fmt.Errorf("provenance buildType is not GitHub Actions: %s", buildType)
+	}
+
+	if builder, ok := s.Predicate["builder"].(map[string]interface{}); ok {
+		builderID, _ := builder["id"].(string)
+		if builderID != "" && !strings.Contains(builderID, "github") {
+			return fmt.Errorf("provenance builder is not GitHub: %s", builderID)
+		}
+	}
+
+	return nil
+}
+
+// qualifiedDigest returns a digest as <algorithm>:<hex> for messages
+func qualifiedDigest(s string) string {
+	if s = normalizeDigest(s); strings.Contains(s, ":") {
+		return s
+	}
+	return "sha256:" + s
+}
+
+// normalizeDigest lowercases and strips an optional "sha256:" prefix
+func normalizeDigest(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.TrimPrefix(s, "sha256:")
+}
@@ -0,0 +1,239 @@
+package slsa
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const validProvenance = `{
+	"_type": "https://in-toto.io/Statement/v0.1",
+	"predicateType": "https://slsa.dev/provenance/v0.2",
+	"subject": [
+		{
+			"name": "acc_0.2.7_linux_amd64.tar.gz",
+			"digest": {"sha256": "abcd1234"}
+		}
+	],
+	"predicate": {
+		"builder": {
+			"id": "https://github.com/actions/runner"
+		},
+		"buildType": "https://github.com/Attestations/GitHubActionsWorkflow@v1",
+		"invocation": {
+			"configSource": {
+				"repository": "https://github.com/cloudcwfranck/acc"
+			}
+		}
+	}
+}`
+
+// TestValidateStatement_Success mirrors upgrade's successful provenance case
+func TestValidateStatement_Success(t *testing.T) {
+	statement, err := ValidateStatement([]byte(validProvenance), "", "")
+	if err != nil {
+		t.Fatalf("Expected valid provenance, got error: %v", err)
+	}
+	if err := ValidateBuilderGitHub(statement); err != nil {
+		t.Fatalf("Expected GitHub builder, got error: %v", err)
+	}
+	if statement.BuilderID() != "https://github.com/actions/runner" {
+		t.Errorf("BuilderID() = %q", statement.BuilderID())
+	}
+}
+
+func TestValidateStatement_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "invalid JSON",
+			data:    "not json",
+			wantErr: "not valid JSON",
+		},
+		{
+			name:    "missing predicateType",
+			data:    `{"predicate": {}}`,
+			wantErr: "missing predicateType",
+		},
+		{
+			name: "invalid predicateType",
+			data: `{
+				"predicateType": "https://example.com/custom-attestation/v1.0",
+				"predicate": {
+					"builder": {"id": "https://github.com/actions"},
+					"buildType": "https://github.com/build"
+				}
+			}`,
+			wantErr: "not SLSA",
+		},
+		{
+			name:    "missing predicate",
+			data:    `{"predicateType": "https://slsa.dev/provenance/v0.2"}`,
+			wantErr: "missing predicate field",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateStatement([]byte(tt.data), "", "")
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestValidateBuilderGitHub_NonGitHub mirrors upgrade's non-GitHub builder case
+func TestValidateBuilderGitHub_NonGitHub(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "non-GitHub builder",
+			data: `{
+				"predicateType": "https://slsa.dev/provenance/v0.2",
+				"predicate": {
+					"builder": {"id": "https://example.com/malicious-builder"}
+				}
+			}`,
+			wantErr: "builder is not GitHub",
+		},
+		{
+			name: "non-GitHub buildType",
+			data: `{
+				"predicateType": "https://slsa.dev/provenance/v0.2",
+				"predicate": {
+					"builder": {"id": "https://example.com/malicious-builder"},
+					"buildType": "https://example.com/build"
+				}
+			}`,
+			wantErr: "not GitHub",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, err := ValidateStatement([]byte(tt.data), "", "")
+			if err != nil {
+				t.Fatalf("Expected structurally valid provenance, got: %v", err)
+			}
+			err = ValidateBuilderGitHub(statement)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateStatement_Subject(t *testing.T) {
+	tests := []struct {
+		name        string
+		digest      string
+		subjectName string
+		wantErr     bool
+	}{
+		{"matching digest", "abcd1234", "", false},
+		{"matching digest with prefix", "sha256:ABCD1234", "", false},
+		{"mismatched digest", "ffff0000", "", true},
+		{"other algorithm", "sha512:abcd1234", "", true},
+		{"matching name", "", "acc_0.2.7_linux_amd64.tar.gz", false},
+		{"mismatched name", "", "acc_0.2.7_darwin_arm64.tar.gz", true},
+		{"matching digest and name", "abcd1234", "acc_0.2.7_linux_amd64.tar.gz", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateStatement([]byte(validProvenance), tt.digest, tt.subjectName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStatement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	envelope := `{"payloadType":"application/vnd.in-toto+json","payload":"` +
+		base64.StdEncoding.EncodeToString([]byte(validProvenance)) + `"}`
+
+	decoded := DecodeEnvelope([]byte(envelope))
+	if string(decoded) != validProvenance {
+		t.Errorf("DecodeEnvelope() did not return the statement payload")
+	}
+
+	// Non-envelope data is returned unchanged
+	if string(DecodeEnvelope([]byte(validProvenance))) != validProvenance {
+		t.Error("DecodeEnvelope() modified a raw statement")
+	}
+}
+
+func TestBuilderID_SLSAv1(t *testing.T) {
+	data := `{
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"predicate": {
+			"runDetails": {"builder": {"id": "https://github.com/slsa-framework/slsa-github-generator"}}
+		}
+	}`
+
+	statement, err := ParseStatement([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseStatement() error = %v", err)
+	}
+	if statement.BuilderID() != "https://github.com/slsa-framework/slsa-github-generator" {
+		t.Errorf("BuilderID() = %q", statement.BuilderID())
+	}
+}
+
+func TestSourceRepository(t *testing.T) {
+	statement, err := ParseStatement([]byte(validProvenance))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := statement.SourceRepository(); got != "https://github.com/cloudcwfranck/acc" {
+		t.Errorf("SourceRepository() = %q", got)
+	}
+
+	tests := []struct {
+		predicate string
+		want      string
+	}{
+		{`{"invocation":{"configSource":{"uri":"git+https://github.com/org/app@refs/heads/main"}}}`, "https://github.com/org/app"},
+		{`{"invocation":{"configSource":{"uri":"https://github.com/org/app.git"}}}`, "https://github.com/org/app"},
+		{`{"buildDefinition":{"externalParameters":{"workflow":{"repository":"https://github.com/org/app","ref":"refs/heads/main"}}}}`, "https://github.com/org/app"},
+		{`{"builder":{"id":"https://github.com/actions/runner"}}`, ""},
+	}
+	for _, tt := range tests {
+		statement, err := ParseStatement([]byte(`{"predicateType":"https://slsa.dev/provenance/v1","predicate":` + tt.predicate + `}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := statement.SourceRepository(); got != tt.want {
+			t.Errorf("SourceRepository() for %s = %q, want %q", tt.predicate, got, tt.want)
+		}
+	}
+}
+
+func TestMatchSource(t *testing.T) {
+	statement, _ := ParseStatement([]byte(validProvenance))
+	for pattern, want := range map[string]bool{
+		"https://github.com/cloudcwfranck/acc":     true,
+		"https://github.com/cloudcwfranck/*":       true,
+		"https://github.com/cloudcwfranck/acc.git": true,
+		"https://github.com/other/*":               false,
+		"https://github.com/*":                     false,
+	} {
+		if got, err := statement.MatchSource(pattern); err != nil || got != want {
+			t.Errorf("MatchSource(%q) = %v, %v; want %v", pattern, got, err, want)
+		}
+	}
+	if err := ValidateSourcePattern("https://github.com/[org/*"); err == nil {
+		t.Error("expected a malformed pattern to be rejected")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package state
+
+import "sync"
+
+var processLock sync.Mutex
+
+// lockFile serializes writers within this process only. Platforms without
+// flock still get atomic replacement from WriteFile's rename.
+func lockFile(lockPath string) (func(), error) {
+	processLock.Lock()
+	return processLock.Unlock, nil
+}
@@ -0,0 +1,144 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// payload returns a JSON document large enough that a torn write would be visible
+func payload(writer int) []byte {
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"writer": writer,
+		"filler": strings.Repeat(fmt.Sprintf("%d", writer), 64*1024),
+	}, "", "  ")
+	return data
+}
+
+// TestWriteFile_ConcurrentWriters tests that concurrent writers never leave or expose invalid JSON
+func TestWriteFile_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last_verify.json")
+	const writers = 16
+	const rounds = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*rounds)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			data := payload(w)
+			for r := 0; r < rounds; r++ {
+				if err := WriteFile(path, data, 0644); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+
+	// Readers must only ever observe a complete document
+	done := make(chan struct{})
+	var readerErr error
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			data, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil || !json.Valid(data) {
+				readerErr = fmt.Errorf("observed torn or unreadable state (err=%v, %d bytes)", err, len(data))
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	<-done
+	close(errs)
+	for err := range errs {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if readerErr != nil {
+		t.Fatal(readerErr)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read final state: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Fatal("final state is not valid JSON")
+	}
+
+	// No temp files may be left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temp file left behind: %s", entry.Name())
+		}
+	}
+}
+
+// TestWriteFile_Permissions tests that the replaced file gets the requested mode
+func TestWriteFile_Permissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+// TestAppendFile_ConcurrentWriters tests that concurrent appends produce one valid JSON line each
+func TestAppendFile_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	const writers = 16
+	const rounds = 10
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			line, _ := json.Marshal(map[string]interface{}{
+				"writer": w,
+				"filler": strings.Repeat("x", 16*1024),
+			})
+			for r := 0; r < rounds; r++ {
+				if err := AppendFile(path, append(line, '\n'), 0644); err != nil {
+					t.Errorf("AppendFile failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read appended file: %v", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+		if !json.Valid(scanner.Bytes()) {
+			t.Fatalf("line %d is not valid JSON", lines)
+		}
+	}
+	if lines != writers*rounds {
+		t.Errorf("expected %d lines, got %d", writers*rounds, lines)
+	}
+}
@@ -0,0 +1,69 @@
+package trust
+
+import (
+	"fmt"
+
+	"github.com/cloudcwfranck/acc/internal/attest"
+)
+
+// AttestationComparison compares the latest attestation's verificationResultsHash with the
+// hash of the current verification state (trust status --compare-attestation)
+type AttestationComparison struct {
+	Attestation  string `json:"attestation,omitempty"` // latest attestation recording a results hash
+	RecordedHash string `json:"recordedHash,omitempty"`
+	CurrentHash  string `json:"currentHash"`
+	Stale        bool   `json:"stale"`             // the attested results no longer match the current verification
+	Message      string `json:"message,omitempty"` // why no comparison was possible
+}
+
+// compareAttestation recomputes the results hash of state and compares it with the latest of
+// attestPaths (by timestamp) that records one. A stale attestation adds an attestation-stale
+// violation and fails the status: the attested trust no longer reflects the current verification.
+func compareAttestation(result *StatusResult, state *VerifyState, attestPaths []string, digest string) error {
+	currentHash, err := attest.ResultsHash(state.Status, state.Result)
+	if err != nil {
+		return fmt.Errorf("cannot hash verification results: %w", err)
+	}
+	comparison := &AttestationComparison{CurrentHash: currentHash}
+	result.AttestationComparison = comparison
+
+	var latest *AttestationDetail
+	for _, path := range attestPaths {
+		detail := validateAttestation(path, digest)
+		if detail.VerificationResultsHash == "" {
+			continue
+		}
+		if latest == nil || detail.Timestamp > latest.Timestamp ||
+			(detail.Timestamp == latest.Timestamp && detail.Path > latest.Path) {
+			latest = &detail
+		}
+	}
+	if latest == nil {
+		comparison.Message = "no attestation records a verification results hash"
+		return nil
+	}
+
+	comparison.Attestation = latest.Path
+	comparison.RecordedHash = latest.VerificationResultsHash
+	if comparison.RecordedHash == currentHash {
+		return nil
+	}
+
+	comparison.Stale = true
+	result.Violations = append(result.Violations, Violation{
+		Rule:     "attestation-stale",
+		Severity: "high",
+		Result:   "fail",
+		Message:  fmt.Sprintf("Latest attestation (%s) records results hash %s, but the current verification hashes to %s", latest.Timestamp, shortHash(comparison.RecordedHash), shortHash(currentHash)),
+	})
+	result.Status = "fail"
+	return nil
+}
+
+// shortHash abbreviates a results hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 16 {
+		return hash[:16]
+	}
+	return hash
+}
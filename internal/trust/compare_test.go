@@ -0,0 +1,102 @@
+package trust
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/attest"
+)
+
+// TestStatus_CompareAttestation tests --compare-attestation with a matching and a drifted results hash
+func TestStatus_CompareAttestation(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	digest := strings.Repeat("ab", 32)
+	imageRef := "test@sha256:" + digest
+
+	os.MkdirAll(filepath.Join(".acc", "state"), 0755)
+	writeState := func(status string, result map[string]interface{}) string {
+		data, _ := json.Marshal(map[string]interface{}{
+			"imageRef":  imageRef,
+			"status":    status,
+			"timestamp": "2025-01-15T10:00:00Z",
+			"result":    result,
+		})
+		os.WriteFile(filepath.Join(".acc", "state", "last_verify.json"), data, 0644)
+		hash, err := attest.ResultsHash(status, result)
+		if err != nil {
+			t.Fatalf("ResultsHash failed: %v", err)
+		}
+		return hash
+	}
+
+	attestDir := filepath.Join(".acc", "attestations", digest[:12])
+	os.MkdirAll(attestDir, 0755)
+	writeAttestation := func(name, timestamp, resultsHash string) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"schemaVersion": "v0.1",
+			"timestamp":     timestamp,
+			"subject":       map[string]interface{}{"imageRef": imageRef, "imageDigest": "sha256:" + digest},
+			"evidence":      map[string]interface{}{"verificationStatus": "pass", "verificationResultsHash": resultsHash},
+		})
+		os.WriteFile(filepath.Join(attestDir, name), data, 0644)
+	}
+
+	// Without an attestation recording a hash there is nothing to compare
+	currentHash := writeState("pass", map[string]interface{}{"sbomPresent": true, "violations": []interface{}{}})
+	result, err := Status(imageRef, false, false, true, true)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if cmp := result.AttestationComparison; cmp == nil || cmp.Stale || cmp.Message == "" || result.Status != "pass" {
+		t.Fatalf("expected an uncompared pass, got %+v (%s)", cmp, result.Status)
+	}
+
+	// The latest attestation matches; an older, outdated one is ignored
+	writeAttestation("old.json", "2025-01-14T10:00:00Z", "0000")
+	writeAttestation("new.json", "2025-01-15T10:01:00Z", currentHash)
+	result, err = Status(imageRef, false, false, true, true)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	cmp := result.AttestationComparison
+	if cmp == nil || cmp.Stale || filepath.Base(cmp.Attestation) != "new.json" || cmp.RecordedHash != currentHash || cmp.CurrentHash != currentHash {
+		t.Fatalf("expected the latest attestation to match, got %+v", cmp)
+	}
+	if result.Status != "pass" || len(result.Violations) != 0 || result.ExitCode() != 0 {
+		t.Errorf("expected pass, got %s %+v", result.Status, result.Violations)
+	}
+
+	// Re-verification (in warn mode, still passing) now reports a violation: the attested results have drifted
+	driftedHash := writeState("pass", map[string]interface{}{
+		"sbomPresent": true,
+		"violations":  []interface{}{map[string]interface{}{"rule": "no-root-user", "severity": "critical"}},
+	})
+	result, err = Status(imageRef, false, false, true, true)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	cmp = result.AttestationComparison
+	if cmp == nil || !cmp.Stale || cmp.RecordedHash != currentHash || cmp.CurrentHash != driftedHash {
+		t.Fatalf("expected a stale attestation, got %+v", cmp)
+	}
+	if result.Status != "fail" || result.ExitCode() != 1 {
+		t.Errorf("expected a stale attestation to fail the status, got %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "attestation-stale" {
+		t.Errorf("expected one attestation-stale violation, got %+v", result.Violations)
+	}
+
+	// Not compared unless asked
+	writeState("pass", map[string]interface{}{"sbomPresent": true, "violations": []interface{}{}})
+	result, _ = Status(imageRef, false, false, false, true)
+	if result.AttestationComparison != nil || result.Status != "pass" {
+		t.Errorf("expected no comparison without the flag, got %+v", result.AttestationComparison)
+	}
+}
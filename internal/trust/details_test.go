@@ -0,0 +1,83 @@
+package trust
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStatus_AttestationDetails tests per-attestation validation in trust status
+func TestStatus_AttestationDetails(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	digest := strings.Repeat("ab", 32)
+	imageRef := "test@sha256:" + digest
+
+	os.MkdirAll(filepath.Join(".acc", "state"), 0755)
+	state, _ := json.Marshal(map[string]interface{}{
+		"imageRef":  imageRef,
+		"status":    "pass",
+		"timestamp": "2025-01-15T10:00:00Z",
+		"result":    map[string]interface{}{"sbomPresent": true},
+	})
+	os.WriteFile(filepath.Join(".acc", "state", "last_verify.json"), state, 0644)
+
+	attestDir := filepath.Join(".acc", "attestations", digest[:12])
+	os.MkdirAll(attestDir, 0755)
+	writeAttestation := func(name, subjectDigest string) string {
+		data, _ := json.Marshal(map[string]interface{}{
+			"schemaVersion": "v0.1",
+			"timestamp":     "2025-01-15T10:01:00Z",
+			"subject":       map[string]interface{}{"imageRef": imageRef, "imageDigest": subjectDigest},
+			"evidence":      map[string]interface{}{"verificationStatus": "pass", "verificationResultsHash": "sha256:abc"},
+		})
+		path := filepath.Join(attestDir, name)
+		os.WriteFile(path, data, 0644)
+		return path
+	}
+	valid := writeAttestation("valid.json", "sha256:"+digest)
+	os.WriteFile(valid+".sig", []byte("signature"), 0644)
+	writeAttestation("mismatched.json", "sha256:"+strings.Repeat("cd", 32))
+
+	// Without the flag, attestations are only listed
+	result, err := Status(imageRef, false, false, false, true)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if len(result.Attestations) != 2 || result.AttestationDetails != nil {
+		t.Fatalf("expected 2 attestations without details, got %+v", result)
+	}
+
+	result, err = Status(imageRef, false, true, false, true)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if len(result.AttestationDetails) != 2 {
+		t.Fatalf("expected 2 attestation details, got %+v", result.AttestationDetails)
+	}
+	details := map[string]AttestationDetail{}
+	for _, d := range result.AttestationDetails {
+		details[filepath.Base(d.Path)] = d
+	}
+
+	if d := details["valid.json"]; !d.ValidSchema || !d.DigestMatch || d.SignatureStatus != "present" {
+		t.Errorf("valid attestation: got %+v", d)
+	}
+	if d := details["mismatched.json"]; !d.ValidSchema || d.DigestMatch || d.SignatureStatus != "" {
+		t.Errorf("mismatched attestation: got %+v", d)
+	}
+
+	// Details are informational: the verification status is unchanged
+	if result.Status != "pass" {
+		t.Errorf("Status = %s, want pass", result.Status)
+	}
+	data, _ := json.Marshal(result)
+	if !strings.Contains(string(data), `"attestationDetails":[`) {
+		t.Errorf("expected attestationDetails in JSON, got %s", data)
+	}
+}
@@ -0,0 +1,182 @@
+package trust
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/cloudcwfranck/acc/internal/build"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// sbomMediaTypes are the artifact and layer media types of SBOMs attached to an image
+var sbomMediaTypes = map[string]bool{
+	"application/spdx+json":          true,
+	"text/spdx+json":                 true, // cosign attach sbom
+	"application/vnd.cyclonedx+json": true,
+}
+
+// maxSBOMSize bounds an SBOM manifest or blob read from a registry
+const maxSBOMSize = 64 << 20
+
+// SBOMExport records the SBOM written by trust status --export-sbom
+type SBOMExport struct {
+	Path     string `json:"path"`
+	Format   string `json:"format"`   // spdx or cyclonedx, detected from the content
+	Source   string `json:"source"`   // registry (an SBOM referrer of the image) or local (the build manifest's SBOM)
+	Location string `json:"location"` // referrer manifest (<registry>/<repository>@<digest>) or local SBOM path
+	Digest   string `json:"digest"`   // sha256 of the SBOM
+}
+
+// ExportSBOM writes the SBOM of imageRef to path. With fromRegistry, the image's SBOM referrers
+// (OCI referrers API, or cosign's sha256-<digest>.sbom tag) are tried first; otherwise, or
+// when the registry has none, the SBOM recorded in the image's build manifest is used. The
+// SBOM must parse as SPDX or CycloneDX JSON before anything is written.
+func ExportSBOM(imageRef, path string, fromRegistry, outputJSON bool) (*SBOMExport, error) {
+	imageDigest, err := oci.ResolveDigest(imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("cannot export SBOM: %w", err)
+	}
+
+	var data []byte
+	export := &SBOMExport{Path: path}
+	if fromRegistry {
+		data, export.Location, err = fetchRegistrySBOM(imageRef, imageDigest)
+		if err == nil {
+			export.Source = "registry"
+		} else if !outputJSON {
+			ui.PrintWarning(fmt.Sprintf("No SBOM fetched from the registry (%v); using the local SBOM", err))
+		}
+	}
+
+	if data == nil {
+		manifest, err := build.LoadManifest(imageDigest)
+		if err != nil || manifest.SBOMPath == "" {
+			return nil, fmt.Errorf("no SBOM found for %s: no registry SBOM referrer and no SBOM in its build manifest", imageRef)
+		}
+		if data, err = os.ReadFile(manifest.SBOMPath); err != nil {
+			return nil, fmt.Errorf("failed to read SBOM: %w", err)
+		}
+		export.Source = "local"
+		export.Location = manifest.SBOMPath
+	}
+
+	if err := writeSBOMExport(export, data); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// writeSBOMExport validates data as an SBOM and writes it to export.Path, recording its format and digest
+func writeSBOMExport(export *SBOMExport, data []byte) error {
+	doc, err := sbom.Parse(data)
+	if err != nil {
+		return fmt.Errorf("SBOM from %s is not valid: %w", export.Location, err)
+	}
+	if err := os.WriteFile(export.Path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	export.Format = doc.Format
+	export.Digest = "sha256:" + hex.EncodeToString(sum[:])
+	return nil
+}
+
+// fetchRegistrySBOM fetches the SBOM referrer of imageRef's repository, returning it and the
+// referrer manifest it came from
+func fetchRegistrySBOM(imageRef, imageDigest string) ([]byte, string, error) {
+	registry, repository, _, err := oci.ParseReference(imageRef)
+	if err != nil {
+		return nil, "", err
+	}
+	repo, err := oci.NewRepository(registry, repository)
+	if err != nil {
+		return nil, "", err
+	}
+	data, manifestDigest, err := FetchSBOMReferrer(context.Background(), repo, imageDigest)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, fmt.Sprintf("%s/%s@%s", registry, repository, manifestDigest), nil
+}
+
+// FetchSBOMReferrer fetches the newest SBOM attached to imageDigest (acc's digest form), returning
+// the SBOM and the digest of the manifest that carried it. Content is checked against its digest.
+func FetchSBOMReferrer(ctx context.Context, repo *remote.Repository, imageDigest string) ([]byte, string, error) {
+	subject := ocispec.Descriptor{Digest: digest.Digest(oci.QualifiedDigest(imageDigest))}
+
+	// oras falls back to the referrers tag schema for registries without the referrers API
+	var referrers []ocispec.Descriptor
+	err := oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		referrers = nil
+		return repo.Referrers(ctx, subject, "", func(descs []ocispec.Descriptor) error {
+			for _, desc := range descs {
+				if sbomMediaTypes[desc.ArtifactType] {
+					referrers = append(referrers, desc)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list referrers: %w", err)
+	}
+
+	if len(referrers) == 0 {
+		// cosign attach sbom tags the SBOM as <algorithm>-<digest>.sbom
+		var desc ocispec.Descriptor
+		err := oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+			var resolveErr error
+			desc, resolveErr = repo.Resolve(ctx, oci.DigestFileName(oci.QualifiedDigest(imageDigest))+".sbom")
+			return resolveErr
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("no SBOM referrer for %s", oci.QualifiedDigest(imageDigest))
+		}
+		referrers = append(referrers, desc)
+	}
+
+	// Newest first, by the creation time its publisher recorded
+	sort.SliceStable(referrers, func(i, j int) bool {
+		return referrers[i].Annotations[ocispec.AnnotationCreated] > referrers[j].Annotations[ocispec.AnnotationCreated]
+	})
+	referrer := referrers[0]
+	if referrer.Size > maxSBOMSize {
+		return nil, "", fmt.Errorf("SBOM manifest %s too large: %d bytes", referrer.Digest, referrer.Size)
+	}
+
+	manifestData, err := oci.FetchVerified(ctx, repo, referrer)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch SBOM manifest %s: %w", referrer.Digest, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse SBOM manifest %s: %w", referrer.Digest, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if !sbomMediaTypes[layer.MediaType] && !(sbomMediaTypes[manifest.ArtifactType] && len(manifest.Layers) == 1) {
+			continue
+		}
+		if layer.Size > maxSBOMSize {
+			return nil, "", fmt.Errorf("SBOM %s too large: %d bytes", layer.Digest, layer.Size)
+		}
+		data, err := oci.FetchVerified(ctx, repo, layer)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to fetch SBOM %s: %w", layer.Digest, err)
+		}
+		return data, referrer.Digest.String(), nil
+	}
+	return nil, "", fmt.Errorf("SBOM manifest %s has no SBOM layer", referrer.Digest)
+}
@@ -0,0 +1,135 @@
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/cloudcwfranck/acc/internal/build"
+)
+
+const testSPDX = `{"spdxVersion":"SPDX-2.3","name":"app","packages":[{"name":"openssl","versionInfo":"3.0.13"}]}`
+
+// TestExportSBOM_Referrer tests fetching an image's SBOM referrer from a registry and writing it
+func TestExportSBOM_Referrer(t *testing.T) {
+	sbomData := []byte(testSPDX)
+	layer := ocispec.Descriptor{
+		MediaType: "application/spdx+json",
+		Digest:    digest.Digest(sha256Digest(sbomData)),
+		Size:      int64(len(sbomData)),
+	}
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/spdx+json",
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers:       []ocispec.Descriptor{layer},
+		Subject:      &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.Digest("sha256:" + testImageDigest), Size: 2},
+	})
+	manifestDigest := sha256Digest(manifest)
+	manifestContent := mockContent{mediaType: ocispec.MediaTypeImageManifest, data: manifest}
+
+	registry := newMockRegistry(t, nil,
+		map[string]mockContent{manifestDigest: manifestContent},
+		map[string]mockContent{sha256Digest(sbomData): {mediaType: "application/spdx+json", data: sbomData}},
+		nil,
+	)
+	// The referrers API lists the SBOM manifest for the image digest
+	index, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{
+			{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/vnd.dev.sigstore.bundle.v0.3+json", Digest: digest.Digest(sha256Digest([]byte("sig"))), Size: 3},
+			{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/spdx+json", Digest: digest.Digest(manifestDigest), Size: int64(len(manifest))},
+		},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/test/repo/referrers/sha256:"+testImageDigest {
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			w.Write(index)
+			return
+		}
+		registry.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	repo.Client = http.DefaultClient
+
+	data, fromManifest, err := FetchSBOMReferrer(context.Background(), repo, testImageDigest)
+	if err != nil {
+		t.Fatalf("FetchSBOMReferrer failed: %v", err)
+	}
+	if fromManifest != manifestDigest {
+		t.Errorf("expected the SBOM from manifest %s, got %s", manifestDigest, fromManifest)
+	}
+
+	export := &SBOMExport{Path: filepath.Join(t.TempDir(), "app.spdx.json"), Source: "registry", Location: fromManifest}
+	if err := writeSBOMExport(export, data); err != nil {
+		t.Fatalf("writeSBOMExport failed: %v", err)
+	}
+	written, _ := os.ReadFile(export.Path)
+	if string(written) != testSPDX {
+		t.Errorf("unexpected SBOM written: %s", written)
+	}
+	if export.Format != "spdx" || export.Digest != sha256Digest(sbomData) {
+		t.Errorf("unexpected export record %+v", export)
+	}
+
+	// An image with no SBOM referrer (and no cosign .sbom tag) has nothing to export
+	if _, _, err := FetchSBOMReferrer(context.Background(), repo, strings.Repeat("2", 64)); err == nil {
+		t.Error("expected an error for an image without an SBOM referrer")
+	}
+}
+
+// TestExportSBOM_Local tests exporting the SBOM recorded in the image's build manifest, and
+// that an invalid SBOM is not written
+func TestExportSBOM_Local(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	sbomPath := filepath.Join(".acc", "sbom", "app.spdx.json")
+	os.MkdirAll(filepath.Dir(sbomPath), 0755)
+	os.WriteFile(sbomPath, []byte(testSPDX), 0644)
+	manifestPath := build.ManifestPath(testImageDigest)
+	os.MkdirAll(filepath.Dir(manifestPath), 0755)
+	data, _ := json.Marshal(build.Manifest{ImageDigest: "sha256:" + testImageDigest, SBOMPath: sbomPath})
+	os.WriteFile(manifestPath, data, 0644)
+
+	imageRef := "ghcr.io/org/app@sha256:" + testImageDigest
+	export, err := ExportSBOM(imageRef, "out.json", false, true)
+	if err != nil {
+		t.Fatalf("ExportSBOM failed: %v", err)
+	}
+	if export.Source != "local" || export.Location != sbomPath || export.Format != "spdx" {
+		t.Errorf("unexpected export record %+v", export)
+	}
+	if written, _ := os.ReadFile("out.json"); string(written) != testSPDX {
+		t.Errorf("unexpected SBOM written: %s", written)
+	}
+
+	os.WriteFile(sbomPath, []byte(`{"not":"an sbom"}`), 0644)
+	if _, err := ExportSBOM(imageRef, "invalid.json", false, true); err == nil || !strings.Contains(err.Error(), "not valid") {
+		t.Errorf("expected an invalid SBOM to be rejected, got %v", err)
+	}
+	if _, err := os.Stat("invalid.json"); !os.IsNotExist(err) {
+		t.Error("an invalid SBOM must not be written")
+	}
+}
@@ -0,0 +1,132 @@
+package trust
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// Attestation media types recognized during remote discovery
+const (
+	// accAttestationMediaType is the layer media type used by 'acc attest --remote'
+	accAttestationMediaType = "application/vnd.acc.attestation.v1+json"
+
+	// cosignDSSEMediaType is the layer media type used by 'cosign attest' (DSSE envelope)
+	cosignDSSEMediaType = "application/vnd.dsse.envelope.v1+json"
+
+	// inTotoMediaType is a raw in-toto statement (e.g. 'cosign attach attestation')
+	inTotoMediaType = "application/vnd.in-toto+json"
+)
+
+// Attestation formats
+const (
+	formatAcc    = "acc"
+	formatCosign = "cosign"
+)
+
+// attestationFormat classifies a layer media type as an acc or cosign attestation
+// Returns "" for media types that are not recognized attestations
+func attestationFormat(mediaType string) string {
+	switch mediaType {
+	case accAttestationMediaType:
+		return formatAcc
+	case cosignDSSEMediaType, inTotoMediaType:
+		return formatCosign
+	default:
+		return ""
+	}
+}
+
+// cosignAttestationTag returns the tag cosign uses for attestations of a digest
+// Pattern: <algorithm>-<digest>.att (sha256-<digest>.att for sha256 digests)
+func cosignAttestationTag(digest string) string {
+	return fmt.Sprintf("%s.att", oci.DigestFileName(oci.QualifiedDigest(normalizeDigest(digest))))
+}
+
+// isExternalAttestationFile reports whether path holds a cosign-style attestation (DSSE
+// envelope or raw in-toto statement) rather than an acc attestation
+func isExternalAttestationFile(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var topLevel map[string]interface{}
+	if err := json.Unmarshal(data, &topLevel); err != nil {
+		return false
+	}
+	_, isEnvelope := topLevel["payloadType"].(string)
+	_, isStatement := topLevel["_type"].(string)
+	return isEnvelope || isStatement
+}
+
+// inTotoStatement is the subset of an in-toto statement used for validation
+type inTotoStatement struct {
+	Type          string `json:"_type"`
+	PredicateType string `json:"predicateType"`
+	Subject       []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+}
+
+// validateExternalAttestation converts a cosign-style attestation (DSSE envelope or raw
+// in-toto statement) into an AttestationDetail. Returns false if topLevel is not one.
+// The DSSE signature is NOT verified (acc has no access to the signer's key); such
+// attestations are reported with verificationStatus "external".
+func validateExternalAttestation(path string, topLevel map[string]interface{}, expectedDigest string) (AttestationDetail, bool) {
+	detail := AttestationDetail{
+		Path:        path,
+		ValidSchema: false,
+		DigestMatch: false,
+	}
+
+	payloadType, isEnvelope := topLevel["payloadType"].(string)
+	_, isStatement := topLevel["_type"].(string)
+	if !isEnvelope && !isStatement {
+		return detail, false
+	}
+
+	detail.Format = formatCosign
+	detail.VerificationStatus = "external"
+
+	var statementData []byte
+	if isEnvelope {
+		// DSSE envelope: payload is a base64-encoded in-toto statement
+		detail.MediaType = cosignDSSEMediaType
+		payload, _ := topLevel["payload"].(string)
+		if payloadType != inTotoMediaType || payload == "" {
+			return detail, true
+		}
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return detail, true
+		}
+		statementData = decoded
+	} else {
+		// Raw in-toto statement
+		detail.MediaType = inTotoMediaType
+		statementData, _ = json.Marshal(topLevel)
+	}
+
+	var statement inTotoStatement
+	if err := json.Unmarshal(statementData, &statement); err != nil {
+		return detail, true
+	}
+
+	detail.ValidSchema = statement.Type != "" && statement.PredicateType != "" && len(statement.Subject) > 0
+	want := normalizeDigest(expectedDigest)
+	algorithm := oci.DigestAlgorithm(want)
+	want = strings.TrimPrefix(want, algorithm+":")
+	for _, subject := range statement.Subject {
+		if sha, ok := subject.Digest[algorithm]; ok && normalizeDigest(sha) == want {
+			detail.DigestMatch = true
+			break
+		}
+	}
+
+	return detail, true
+}
@@ -0,0 +1,232 @@
+package trust
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+const testImageDigest = "1111111111111111111111111111111111111111111111111111111111111111"
+
+// cosignEnvelope builds a DSSE envelope wrapping an in-toto statement for subjectDigest
+func cosignEnvelope(t *testing.T, subjectDigest string) []byte {
+	t.Helper()
+	statement := map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"subject": []map[string]interface{}{
+			{"name": "example.com/test/repo", "digest": map[string]string{"sha256": subjectDigest}},
+		},
+		"predicate": map[string]interface{}{},
+	}
+	statementJSON, _ := json.Marshal(statement)
+	envelope, _ := json.Marshal(map[string]interface{}{
+		"payloadType": inTotoMediaType,
+		"payload":     base64.StdEncoding.EncodeToString(statementJSON),
+		"signatures":  []map[string]string{{"keyid": "", "sig": "MEUCIQ=="}},
+	})
+	return envelope
+}
+
+type mockContent struct {
+	mediaType string
+	data      []byte
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// newMockRegistry serves a single repository with the given tags, manifests, and blobs
+// When fetches is non-nil, it counts manifest and blob requests (resolves and pulls)
+func newMockRegistry(t *testing.T, tags []string, manifests map[string]mockContent, blobs map[string]mockContent, fetches *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/v2/test/repo/"
+		path := strings.TrimPrefix(r.URL.Path, prefix)
+		if fetches != nil && (strings.HasPrefix(path, "manifests/") || strings.HasPrefix(path, "blobs/")) {
+			fetches.Add(1)
+		}
+
+		var content mockContent
+		var ok bool
+		switch {
+		case path == "tags/list":
+			json.NewEncoder(w).Encode(map[string]interface{}{"name": "test/repo", "tags": tags})
+			return
+		case strings.HasPrefix(path, "manifests/"):
+			content, ok = manifests[strings.TrimPrefix(path, "manifests/")]
+		case strings.HasPrefix(path, "blobs/"):
+			content, ok = blobs[strings.TrimPrefix(path, "blobs/")]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", content.mediaType)
+		w.Header().Set("Docker-Content-Digest", sha256Digest(content.data))
+		w.Header().Set("Content-Length", strconv.Itoa(len(content.data)))
+		if r.Method != http.MethodHead {
+			w.Write(content.data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestFetchAttestationsFromRepo_Cosign tests discovery of a cosign-typed attestation artifact
+func TestFetchAttestationsFromRepo_Cosign(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	envelope := cosignEnvelope(t, testImageDigest)
+	layer := ocispec.Descriptor{
+		MediaType: cosignDSSEMediaType,
+		Digest:    digest.Digest(sha256Digest(envelope)),
+		Size:      int64(len(envelope)),
+	}
+	// Unrelated layer that must be skipped by media type
+	other := []byte("not an attestation")
+	otherLayer := ocispec.Descriptor{
+		MediaType: "application/octet-stream",
+		Digest:    digest.Digest(sha256Digest(other)),
+		Size:      int64(len(other)),
+	}
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers:    []ocispec.Descriptor{layer, otherLayer},
+	})
+	manifestContent := mockContent{mediaType: ocispec.MediaTypeImageManifest, data: manifest}
+
+	tag := cosignAttestationTag(testImageDigest)
+	server := newMockRegistry(t,
+		[]string{"latest", tag},
+		map[string]mockContent{tag: manifestContent, sha256Digest(manifest): manifestContent},
+		map[string]mockContent{
+			sha256Digest(envelope): {mediaType: cosignDSSEMediaType, data: envelope},
+			sha256Digest(other):    {mediaType: "application/octet-stream", data: other},
+		},
+		nil,
+	)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	repo.Client = http.DefaultClient
+
+	if _, err := fetchAttestationsFromRepo(context.Background(), repo, host, "test/repo", testImageDigest, true); err != nil {
+		t.Fatalf("fetchAttestationsFromRepo failed: %v", err)
+	}
+
+	paths := findAttestationsForImage(testImageDigest)
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 cached attestation, got %d: %v", len(paths), paths)
+	}
+
+	detail := validateAttestation(paths[0], testImageDigest)
+	if detail.Format != formatCosign {
+		t.Errorf("format = %q, want %q", detail.Format, formatCosign)
+	}
+	if detail.MediaType != cosignDSSEMediaType {
+		t.Errorf("mediaType = %q, want %q", detail.MediaType, cosignDSSEMediaType)
+	}
+	if !detail.ValidSchema || !detail.DigestMatch {
+		t.Errorf("expected valid schema and digest match, got schema=%t digest=%t", detail.ValidSchema, detail.DigestMatch)
+	}
+	if detail.VerificationStatus != "external" {
+		t.Errorf("verificationStatus = %q, want external", detail.VerificationStatus)
+	}
+}
+
+func TestAttestationFormat(t *testing.T) {
+	tests := []struct {
+		mediaType string
+		want      string
+	}{
+		{accAttestationMediaType, formatAcc},
+		{cosignDSSEMediaType, formatCosign},
+		{inTotoMediaType, formatCosign},
+		{"application/octet-stream", ""},
+		{ocispec.MediaTypeImageLayer, ""},
+	}
+
+	for _, tt := range tests {
+		if got := attestationFormat(tt.mediaType); got != tt.want {
+			t.Errorf("attestationFormat(%q) = %q, want %q", tt.mediaType, got, tt.want)
+		}
+	}
+}
+
+func TestValidateExternalAttestation(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	write := func(name string, data []byte) string {
+		path := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("DSSE envelope with mismatched subject", func(t *testing.T) {
+		path := write("mismatch.json", cosignEnvelope(t, strings.Repeat("2", 64)))
+		detail := validateAttestation(path, testImageDigest)
+		if detail.Format != formatCosign || !detail.ValidSchema {
+			t.Errorf("expected valid cosign attestation, got %+v", detail)
+		}
+		if detail.DigestMatch {
+			t.Error("digestMatch = true, want false")
+		}
+	})
+
+	t.Run("raw in-toto statement", func(t *testing.T) {
+		statement, _ := json.Marshal(map[string]interface{}{
+			"_type":         "https://in-toto.io/Statement/v1",
+			"predicateType": "https://spdx.dev/Document",
+			"subject": []map[string]interface{}{
+				{"name": "img", "digest": map[string]string{"sha256": "sha256:" + testImageDigest}},
+			},
+		})
+		detail := validateAttestation(write("statement.json", statement), testImageDigest)
+		if detail.MediaType != inTotoMediaType || !detail.ValidSchema || !detail.DigestMatch {
+			t.Errorf("expected valid in-toto statement with digest match, got %+v", detail)
+		}
+	})
+
+	t.Run("acc attestation is not external", func(t *testing.T) {
+		acc, _ := json.Marshal(map[string]interface{}{
+			"schemaVersion": "v0.1",
+			"timestamp":     "2025-01-01T00:00:00Z",
+			"subject":       map[string]interface{}{"imageDigest": testImageDigest},
+			"evidence":      map[string]interface{}{"verificationStatus": "pass"},
+		})
+		detail := validateAttestation(write("acc.json", acc), testImageDigest)
+		if detail.Format != "" || !detail.ValidSchema || !detail.DigestMatch {
+			t.Errorf("expected valid acc attestation without external format, got %+v", detail)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// AttestationListing is one attestation of an image (acc attest --list): its validation
+// details plus where it came from
+type AttestationListing struct {
+	AttestationDetail
+	Source string `json:"source"`        // local, or remote (fetched from the registry with --remote)
+	Tag    string `json:"tag,omitempty"` // <registry>/<repository>:<tag> a remote attestation was fetched from
+	Digest string `json:"digest"`        // sha256 of the attestation file
+}
+
+// ListAttestations lists the attestations of imageRef, newest first. With remote, the
+// repository's attestation tags are fetched first (as trust status --remote does), so
+// remote attestations are listed with the tag they came from. Each attestation is
+// validated like trust verify; the listing itself never fails on an invalid one.
+func ListAttestations(imageRef string, remote, outputJSON bool) ([]AttestationListing, error) {
+	digest, err := oci.ResolveDigest(imageRef)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve digest for %s: %w", imageRef, err)
+	}
+
+	if remote {
+		fetch, err := fetchRemoteAttestations(imageRef, digest, outputJSON)
+		if err != nil {
+			if !outputJSON {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to fetch remote attestations: %v\n", err)
+			}
+		} else if !fetch.Complete && !outputJSON {
+			fmt.Fprintf(os.Stderr, "Warning: %d of %d remote attestation tag(s) could not be fetched; attestations may be incomplete\n", fetch.Failed, fetch.Attempted)
+		}
+	}
+
+	// Remote attestations are cached as <hash[:16]>.json; the index maps them back to their tag
+	tags := map[string]string{}
+	for tag, entry := range loadRemoteIndex(digest).Tags {
+		for _, hash := range entry.ContentHashes {
+			if len(hash) >= 16 {
+				tags[hash[:16]] = tag
+			}
+		}
+	}
+
+	listings := []AttestationListing{}
+	for _, path := range findAttestationsForImage(digest) {
+		listing := AttestationListing{
+			AttestationDetail: validateAttestation(path, digest),
+			Source:            "local",
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			sum := sha256.Sum256(data)
+			listing.Digest = "sha256:" + hex.EncodeToString(sum[:])
+		}
+		if isRemoteAttestation(path) {
+			listing.Source = "remote"
+			listing.Tag = tags[strings.TrimSuffix(filepath.Base(path), ".json")]
+		}
+		listings = append(listings, listing)
+	}
+
+	sort.SliceStable(listings, func(i, j int) bool {
+		if listings[i].Timestamp != listings[j].Timestamp {
+			return listings[i].Timestamp > listings[j].Timestamp
+		}
+		return listings[i].Path < listings[j].Path
+	})
+	return listings, nil
+}
+
+// isRemoteAttestation reports whether path is under an attestation directory's remote/
+// cache (.acc/attestations/<digest12>/remote/...)
+func isRemoteAttestation(path string) bool {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i := 0; i+3 < len(parts); i++ {
+		if parts[i] == "attestations" && parts[i+2] == "remote" {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteAttestationList renders listings as aligned columns (acc attest --list)
+func WriteAttestationList(w io.Writer, listings []AttestationListing) error {
+	if len(listings) == 0 {
+		_, err := fmt.Fprintln(w, "No attestations found")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tATTESTATION\tCREATED\tSTATUS\tSIGNATURE\tDIGEST MATCH")
+	for _, l := range listings {
+		name := l.Path
+		if l.Tag != "" {
+			name = l.Tag
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", l.Source, name, orDash(l.Timestamp), orDash(l.VerificationStatus), orDash(l.SignatureStatus), yesNo(l.DigestMatch))
+	}
+	return tw.Flush()
+}
+
+// orDash returns s, or "-" for an empty table cell
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// yesNo renders a boolean table cell
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,169 @@
+package trust
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// writeLocalAttestation writes a legacy acc attestation for subjectDigest under .acc/attestations
+func writeLocalAttestation(t *testing.T, name, timestamp, subjectDigest string) string {
+	t.Helper()
+	dir := filepath.Join(".acc", "attestations", testImageDigest[:12])
+	os.MkdirAll(dir, 0755)
+	data, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": "v0.1",
+		"timestamp":     timestamp,
+		"subject":       map[string]string{"imageRef": "ghcr.io/org/app", "imageDigest": "sha256:" + subjectDigest},
+		"evidence":      map[string]string{"verificationStatus": "pass", "verificationResultsHash": "sha256:abc"},
+	})
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write attestation: %v", err)
+	}
+	return path
+}
+
+// TestListAttestations_Local tests that local attestations are listed newest first with
+// their timestamp, status, and digest match
+func TestListAttestations_Local(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	imageRef := "ghcr.io/org/app@sha256:" + testImageDigest
+	older := writeLocalAttestation(t, "older.json", "2025-01-01T10:00:00Z", testImageDigest)
+	newer := writeLocalAttestation(t, "newer.json", "2025-02-01T10:00:00Z", strings.Repeat("2", 64))
+	os.WriteFile(older+".sig", []byte("sig"), 0644)
+
+	listings, err := ListAttestations(imageRef, false, true)
+	if err != nil {
+		t.Fatalf("ListAttestations failed: %v", err)
+	}
+	if len(listings) != 2 {
+		t.Fatalf("expected 2 attestations, got %d: %+v", len(listings), listings)
+	}
+	if listings[0].Path != newer || listings[1].Path != older {
+		t.Errorf("expected newest first, got %s then %s", listings[0].Path, listings[1].Path)
+	}
+
+	got := listings[1]
+	if got.Source != "local" || got.Tag != "" || got.Timestamp != "2025-01-01T10:00:00Z" || got.VerificationStatus != "pass" {
+		t.Errorf("unexpected listing %+v", got)
+	}
+	if !got.DigestMatch || got.SignatureStatus != "present" {
+		t.Errorf("expected a digest match and a signature sidecar, got %+v", got)
+	}
+	data, _ := os.ReadFile(older)
+	if got.Digest != sha256Digest(data) {
+		t.Errorf("digest = %s, want %s", got.Digest, sha256Digest(data))
+	}
+	// An attestation for another digest is listed, but as a mismatch
+	if listings[0].DigestMatch {
+		t.Error("expected the attestation for another digest not to match")
+	}
+
+	// --json is an array, empty when there are no attestations
+	out, _ := json.Marshal(listings)
+	if !strings.HasPrefix(string(out), "[{") || !strings.Contains(string(out), `"source":"local"`) {
+		t.Errorf("unexpected JSON %s", out)
+	}
+	os.RemoveAll(".acc")
+	listings, _ = ListAttestations(imageRef, false, true)
+	if out, _ := json.Marshal(listings); string(out) != "[]" {
+		t.Errorf("expected an empty JSON array, got %s", out)
+	}
+
+	if _, err := ListAttestations("unresolvable-image-for-list-test:1.0", false, true); err == nil {
+		t.Error("expected an error for an image whose digest cannot be resolved")
+	}
+}
+
+// TestListAttestations_Remote tests that attestations fetched from a registry are listed
+// with the tag they came from
+func TestListAttestations_Remote(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	envelope := cosignEnvelope(t, testImageDigest)
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{{
+			MediaType: cosignDSSEMediaType,
+			Digest:    digest.Digest(sha256Digest(envelope)),
+			Size:      int64(len(envelope)),
+		}},
+	})
+	manifestContent := mockContent{mediaType: ocispec.MediaTypeImageManifest, data: manifest}
+	tag := cosignAttestationTag(testImageDigest)
+	server := newMockRegistry(t,
+		[]string{"latest", tag},
+		map[string]mockContent{tag: manifestContent, sha256Digest(manifest): manifestContent},
+		map[string]mockContent{sha256Digest(envelope): {mediaType: cosignDSSEMediaType, data: envelope}},
+		nil,
+	)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	repo.Client = http.DefaultClient
+
+	// The discovery step of --remote, against the mock registry
+	if _, err := fetchAttestationsFromRepo(context.Background(), repo, host, "test/repo", testImageDigest, true); err != nil {
+		t.Fatalf("fetchAttestationsFromRepo failed: %v", err)
+	}
+	local := writeLocalAttestation(t, "local.json", "2025-01-01T10:00:00Z", testImageDigest)
+
+	listings, err := ListAttestations(host+"/test/repo@sha256:"+testImageDigest, false, true)
+	if err != nil {
+		t.Fatalf("ListAttestations failed: %v", err)
+	}
+	if len(listings) != 2 {
+		t.Fatalf("expected a local and a remote attestation, got %+v", listings)
+	}
+
+	var remoteListing, localListing *AttestationListing
+	for i := range listings {
+		if listings[i].Source == "remote" {
+			remoteListing = &listings[i]
+		} else {
+			localListing = &listings[i]
+		}
+	}
+	if remoteListing == nil || localListing == nil || localListing.Path != local {
+		t.Fatalf("expected one remote and one local attestation, got %+v", listings)
+	}
+	if want := host + "/test/repo:" + tag; remoteListing.Tag != want {
+		t.Errorf("tag = %q, want %q", remoteListing.Tag, want)
+	}
+	if remoteListing.Digest != sha256Digest(envelope) || !remoteListing.DigestMatch || remoteListing.Format != formatCosign {
+		t.Errorf("unexpected remote listing %+v", remoteListing)
+	}
+
+	var table bytes.Buffer
+	if err := WriteAttestationList(&table, listings); err != nil {
+		t.Fatalf("WriteAttestationList failed: %v", err)
+	}
+	out := table.String()
+	if !strings.HasPrefix(out, "SOURCE") || !strings.Contains(out, "remote  "+host+"/test/repo:"+tag) || !strings.Contains(out, "local   "+local) {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+}
@@ -0,0 +1,68 @@
+package trust
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// RemoteFetch reports how completely --remote fetched a registry's attestations. When it is
+// not complete, the attestation list may be missing attestations that exist remotely.
+type RemoteFetch struct {
+	Attempted int            `json:"attempted"`        // attestation tags found in the registry
+	Fetched   int            `json:"fetched"`          // tags whose attestations were all fetched
+	Reused    int            `json:"reused"`           // tags not fetched again because a recent fetch is cached
+	Failed    int            `json:"failed"`           // tags that could not be fetched, or only in part
+	Errors    map[string]int `json:"errors,omitempty"` // failures per category (unauthorized, not-found, server-error, network, invalid, other)
+	Complete  bool           `json:"complete"`
+	Error     string         `json:"error,omitempty"` // why the fetch failed as a whole (e.g. listing tags)
+}
+
+// recordFailure counts a tag that could not be fetched under its error category
+func (f *RemoteFetch) recordFailure(category string) {
+	f.Failed++
+	if f.Errors == nil {
+		f.Errors = make(map[string]int)
+	}
+	f.Errors[category]++
+}
+
+// abort records an error that stopped the fetch as a whole and returns it
+func (f *RemoteFetch) abort(err error) (*RemoteFetch, error) {
+	f.Error = err.Error()
+	f.Complete = false
+	if f.Errors == nil {
+		f.Errors = make(map[string]int)
+	}
+	f.Errors[remoteErrorCategory(err)]++
+	return f, err
+}
+
+// remoteErrorCategory classifies a registry error by HTTP status or network failure
+func remoteErrorCategory(err error) string {
+	if errors.Is(err, errdef.ErrNotFound) {
+		return "not-found"
+	}
+
+	var errResp *errcode.ErrorResponse
+	if errors.As(err, &errResp) {
+		switch {
+		case errResp.StatusCode == http.StatusUnauthorized || errResp.StatusCode == http.StatusForbidden:
+			return "unauthorized"
+		case errResp.StatusCode == http.StatusNotFound:
+			return "not-found"
+		case errResp.StatusCode >= http.StatusInternalServerError:
+			return "server-error"
+		}
+		return "other"
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return "network"
+	}
+	return "other"
+}
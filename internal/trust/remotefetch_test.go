@@ -0,0 +1,91 @@
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// TestFetchAttestationsFromRepo_PartialFailure tests that tags the registry refuses or no longer
+// has are counted by category while the readable tag is still fetched
+func TestFetchAttestationsFromRepo_PartialFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	envelope := cosignEnvelope(t, testImageDigest)
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{{
+			MediaType: cosignDSSEMediaType,
+			Digest:    digest.Digest(sha256Digest(envelope)),
+			Size:      int64(len(envelope)),
+		}},
+	})
+	manifestContent := mockContent{mediaType: ocispec.MediaTypeImageManifest, data: manifest}
+
+	okTag := cosignAttestationTag(testImageDigest)
+	deniedTag := "attestation-" + testImageDigest[:12] + "-denied"
+	missingTag := "attestation-" + testImageDigest[:12] + "-missing"
+	registry := newMockRegistry(t,
+		[]string{"latest", okTag, deniedTag, missingTag},
+		map[string]mockContent{okTag: manifestContent, sha256Digest(manifest): manifestContent},
+		map[string]mockContent{sha256Digest(envelope): {mediaType: cosignDSSEMediaType, data: envelope}},
+		nil,
+	)
+	// The denied tag needs credentials the client does not have
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/manifests/"+deniedTag) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		registry.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	repo.Client = http.DefaultClient
+
+	fetch, err := fetchAttestationsFromRepo(context.Background(), repo, host, "test/repo", testImageDigest, true)
+	if err != nil {
+		t.Fatalf("fetchAttestationsFromRepo failed: %v", err)
+	}
+	want := &RemoteFetch{
+		Attempted: 3,
+		Fetched:   1,
+		Failed:    2,
+		Errors:    map[string]int{"unauthorized": 1, "not-found": 1},
+		Complete:  false,
+	}
+	if !reflect.DeepEqual(fetch, want) {
+		t.Errorf("remote fetch = %+v, want %+v", fetch, want)
+	}
+	if paths := findAttestationsForImage(testImageDigest); len(paths) != 1 {
+		t.Errorf("expected the readable attestation to be cached, got %v", paths)
+	}
+
+	// A registry that cannot be listed fails the fetch as a whole
+	server.Close()
+	fetch, err = fetchAttestationsFromRepo(context.Background(), repo, host, "test/repo", testImageDigest, true)
+	if err == nil || fetch.Complete || fetch.Error == "" || fetch.Errors["network"] != 1 {
+		t.Errorf("expected a network failure for an unreachable registry, got %+v (%v)", fetch, err)
+	}
+}
@@ -0,0 +1,102 @@
+package trust
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// remoteIndexTTL is how long a fetched attestation tag is reused without resolving it again.
+// Mutable tags (e.g. cosign's sha256-<digest>.att gaining attestations) are re-fetched once it expires
+const remoteIndexTTL = 24 * time.Hour
+
+// remoteIndexEntry records the attestations cached from one remote tag
+type remoteIndexEntry struct {
+	ManifestDigest string   `json:"manifestDigest"`
+	ContentHashes  []string `json:"contentHashes"` // sha256 of each cached attestation (file <hash[:16]>.json)
+	FetchedAt      string   `json:"fetchedAt"`
+}
+
+// remoteIndex maps <registry>/<repository>:<tag> to the attestations fetched from it
+type remoteIndex struct {
+	Tags map[string]remoteIndexEntry `json:"tags"`
+}
+
+// remoteIndexPath returns .acc/cache/attestations/<digest>/index.json
+func remoteIndexPath(digest string) string {
+	return filepath.Join(".acc", "cache", "attestations", digest, "index.json")
+}
+
+// loadRemoteIndex reads the index for digest. A missing or unreadable index is empty,
+// so the worst case is fetching everything again.
+func loadRemoteIndex(digest string) *remoteIndex {
+	index := &remoteIndex{Tags: map[string]remoteIndexEntry{}}
+	data, err := os.ReadFile(remoteIndexPath(digest))
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, index); err != nil || index.Tags == nil {
+		return &remoteIndex{Tags: map[string]remoteIndexEntry{}}
+	}
+	return index
+}
+
+// fresh reports whether key was fetched within remoteIndexTTL and all of its attestations
+// are still cached in cacheDir (acc clean --attestations may have removed them)
+func (idx *remoteIndex) fresh(key, cacheDir string, now time.Time) bool {
+	entry, ok := idx.Tags[key]
+	if !ok {
+		return false
+	}
+	fetchedAt, err := time.Parse(time.RFC3339, entry.FetchedAt)
+	if err != nil || now.Sub(fetchedAt) > remoteIndexTTL {
+		return false
+	}
+	for _, hash := range entry.ContentHashes {
+		if len(hash) < 16 {
+			return false
+		}
+		if _, err := os.Stat(filepath.Join(cacheDir, hash[:16]+".json")); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// recordRemoteTag adds key to the index for digest and writes it atomically.
+// The on-disk index is re-read first so entries recorded by a concurrent run are kept;
+// the write goes to a temp file renamed into place, so readers never see a partial index.
+// Saving after every tag lets an interrupted fetch resume where it stopped.
+func recordRemoteTag(digest, key string, entry remoteIndexEntry) error {
+	index := loadRemoteIndex(digest)
+	index.Tags[key] = entry
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal attestation index: %w", err)
+	}
+
+	path := remoteIndexPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create attestation index directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".index-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write attestation index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write attestation index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write attestation index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store attestation index: %w", err)
+	}
+	return nil
+}
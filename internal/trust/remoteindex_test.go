@@ -0,0 +1,111 @@
+package trust
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// TestFetchAttestationsFromRepo_Index tests that a second run against an unchanged registry
+// fetches nothing, and that an expired index entry is fetched again
+func TestFetchAttestationsFromRepo_Index(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	envelope := cosignEnvelope(t, testImageDigest)
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{{
+			MediaType: cosignDSSEMediaType,
+			Digest:    digest.Digest(sha256Digest(envelope)),
+			Size:      int64(len(envelope)),
+		}},
+	})
+	manifestContent := mockContent{mediaType: ocispec.MediaTypeImageManifest, data: manifest}
+
+	var fetches atomic.Int32
+	tag := cosignAttestationTag(testImageDigest)
+	server := newMockRegistry(t,
+		[]string{"latest", tag},
+		map[string]mockContent{tag: manifestContent, sha256Digest(manifest): manifestContent},
+		map[string]mockContent{sha256Digest(envelope): {mediaType: cosignDSSEMediaType, data: envelope}},
+		&fetches,
+	)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	repo.Client = http.DefaultClient
+
+	fetch := func() int32 {
+		t.Helper()
+		fetches.Store(0)
+		if _, err := fetchAttestationsFromRepo(context.Background(), repo, host, "test/repo", testImageDigest, true); err != nil {
+			t.Fatalf("fetchAttestationsFromRepo failed: %v", err)
+		}
+		return fetches.Load()
+	}
+
+	if n := fetch(); n == 0 {
+		t.Fatal("expected the first run to fetch from the registry")
+	}
+	index := loadRemoteIndex(testImageDigest)
+	key := host + "/test/repo:" + tag
+	entry, ok := index.Tags[key]
+	if !ok || len(entry.ContentHashes) != 1 || entry.ManifestDigest != sha256Digest(manifest) {
+		t.Fatalf("expected index entry for %s, got %+v", key, index.Tags)
+	}
+
+	if n := fetch(); n != 0 {
+		t.Errorf("expected no fetches with an unchanged registry, got %d", n)
+	}
+	if paths := findAttestationsForImage(testImageDigest); len(paths) != 1 {
+		t.Errorf("expected 1 cached attestation, got %d: %v", len(paths), paths)
+	}
+
+	// An entry older than the TTL is resolved and fetched again
+	entry.FetchedAt = time.Now().Add(-remoteIndexTTL - time.Minute).UTC().Format(time.RFC3339)
+	if err := recordRemoteTag(testImageDigest, key, entry); err != nil {
+		t.Fatalf("recordRemoteTag failed: %v", err)
+	}
+	if n := fetch(); n == 0 {
+		t.Error("expected an expired index entry to be fetched again")
+	}
+}
+
+func TestRemoteIndex_Fresh(t *testing.T) {
+	cacheDir := t.TempDir()
+	hash := strings.Repeat("a", 64)
+	os.WriteFile(filepath.Join(cacheDir, hash[:16]+".json"), []byte("{}"), 0644)
+
+	now := time.Now()
+	index := &remoteIndex{Tags: map[string]remoteIndexEntry{
+		"fresh":   {ContentHashes: []string{hash}, FetchedAt: now.Add(-time.Hour).Format(time.RFC3339)},
+		"expired": {ContentHashes: []string{hash}, FetchedAt: now.Add(-remoteIndexTTL - time.Hour).Format(time.RFC3339)},
+		"removed": {ContentHashes: []string{strings.Repeat("b", 64)}, FetchedAt: now.Format(time.RFC3339)},
+	}}
+	tests := map[string]bool{"fresh": true, "expired": false, "removed": false, "unknown": false}
+	for key, want := range tests {
+		if got := index.fresh(key, cacheDir, now); got != want {
+			t.Errorf("fresh(%q) = %t, want %t", key, got, want)
+		}
+	}
+}
@@ -3,20 +3,20 @@ package trust
 import (
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/pool"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"oras.land/oras-go/v2/registry/remote"
-	"oras.land/oras-go/v2/registry/remote/auth"
-	"oras.land/oras-go/v2/registry/remote/retry"
 )
 
 // StatusResult represents the trust status output
@@ -30,6 +30,14 @@ type StatusResult struct {
 	SBOMPresent   bool        `json:"sbomPresent"`
 	Attestations  []string    `json:"attestations"`
 	Timestamp     string      `json:"timestamp"`
+	// AttestationDetails validates each attestation (--attestation-details)
+	AttestationDetails []AttestationDetail `json:"attestationDetails,omitempty"`
+	// AttestationComparison checks the latest attestation against the current state (--compare-attestation)
+	AttestationComparison *AttestationComparison `json:"attestationComparison,omitempty"`
+	// RemoteFetch reports how completely --remote fetched registry attestations
+	RemoteFetch *RemoteFetch `json:"remoteFetch,omitempty"`
+	// SBOMExport records the SBOM written by --export-sbom
+	SBOMExport *SBOMExport `json:"sbomExport,omitempty"`
 }
 
 // Violation represents a policy violation
@@ -51,7 +59,10 @@ type VerifyState struct {
 
 // Status loads and displays the trust status for an image
 // v0.3.2: optionally fetch attestations from remote registry when remote=true
-func Status(imageRef string, remote, outputJSON bool) (*StatusResult, error) {
+// attestationDetails validates each attestation (schema, digest match, signature) into AttestationDetails
+// compareAttest fails the status with attestation-stale when the latest attestation's results
+// hash differs from the current verification state's
+func Status(imageRef string, remote, attestationDetails, compareAttest, outputJSON bool) (*StatusResult, error) {
 	// Load verification state
 	state, err := loadVerifyState(imageRef)
 	if err != nil {
@@ -77,7 +88,7 @@ func Status(imageRef string, remote, outputJSON bool) (*StatusResult, error) {
 	}
 
 	// Resolve digest for per-image attestation lookup
-	digest, _ := resolveImageDigest(imageRef)
+	digest, _ := oci.ResolveDigest(imageRef)
 
 	// Build result from state (v0.2.7: ensure all fields initialized)
 	result := &StatusResult{
@@ -129,18 +140,37 @@ func Status(imageRef string, remote, outputJSON bool) (*StatusResult, error) {
 
 	// v0.3.2: Optionally fetch remote attestations before finding local ones
 	if remote && digest != "" {
-		if err := fetchRemoteAttestations(imageRef, digest, outputJSON); err != nil {
+		fetch, err := fetchRemoteAttestations(imageRef, digest, outputJSON)
+		// remoteFetch tells consumers when the attestation list may be incomplete
+		result.RemoteFetch = fetch
+		if err != nil {
 			// Remote fetch failed - log warning but don't fail
 			// This preserves local-only workflow when network unavailable
 			if !outputJSON {
 				fmt.Fprintf(os.Stderr, "Warning: Failed to fetch remote attestations: %v\n", err)
 			}
+		} else if !fetch.Complete && !outputJSON {
+			fmt.Fprintf(os.Stderr, "Warning: %d of %d remote attestation tag(s) could not be fetched; attestations may be incomplete\n", fetch.Failed, fetch.Attempted)
 		}
 	}
 
 	// v0.2.7: Find attestations for this specific image (per-image isolation)
-	// v0.3.2: This now includes both local and remote-cached attestations
-	result.Attestations = findAttestationsForImage(digest)
+	// v0.3.2: This now includes both local and remote-cached attestations (acc's only)
+	result.Attestations = accAttestations(findAttestationsForImage(digest))
+
+	// Validation is read-only and reported per attestation; it does not change the status
+	if attestationDetails {
+		result.AttestationDetails = []AttestationDetail{}
+		for _, path := range result.Attestations {
+			result.AttestationDetails = append(result.AttestationDetails, validateAttestation(path, digest))
+		}
+	}
+
+	if compareAttest {
+		if err := compareAttestation(result, state, result.Attestations, digest); err != nil {
+			return nil, err
+		}
+	}
 
 	// Output results
 	if outputJSON {
@@ -156,9 +186,9 @@ func Status(imageRef string, remote, outputJSON bool) (*StatusResult, error) {
 // First tries digest-scoped state, falls back to global state
 func loadVerifyState(imageRef string) (*VerifyState, error) {
 	// Try to resolve digest for digest-scoped lookup
-	digest, _ := resolveImageDigest(imageRef)
+	digest, _ := oci.ResolveDigest(imageRef)
 	if digest != "" {
-		digestFile := filepath.Join(".acc", "state", "verify", digest+".json")
+		digestFile := filepath.Join(".acc", "state", "verify", oci.DigestFileName(digest)+".json")
 		if data, err := os.ReadFile(digestFile); err == nil {
 			var state VerifyState
 			if err := json.Unmarshal(data, &state); err == nil {
@@ -188,42 +218,23 @@ func loadVerifyState(imageRef string) (*VerifyState, error) {
 	return &state, nil
 }
 
-// resolveImageDigest tries to resolve image digest using container tools
-// v0.2.1: Fixed to properly query Docker/Podman/nerdctl for digest
-func resolveImageDigest(imageRef string) (string, error) {
-	// First, try to extract from imageRef if it contains @sha256:
-	if strings.Contains(imageRef, "@sha256:") {
-		parts := strings.Split(imageRef, "@sha256:")
-		if len(parts) == 2 {
-			return parts[1], nil
-		}
-	}
-
-	// Try different container tools to get the digest
-	tools := []struct {
-		name string
-		args []string
-	}{
-		{"docker", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"podman", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"nerdctl", []string{"inspect", "--format={{.Id}}", imageRef}},
-	}
-
-	for _, tool := range tools {
-		if _, err := exec.LookPath(tool.name); err == nil {
-			cmd := exec.Command(tool.name, tool.args...)
-			output, err := cmd.Output()
-			if err == nil {
-				digest := strings.TrimSpace(string(output))
-				digest = strings.TrimPrefix(digest, "sha256:")
-				if digest != "" {
-					return digest, nil
-				}
-			}
+// HasAttestations reports whether any acc attestation is stored locally (any image), for the
+// policy input's attestation.present
+func HasAttestations() bool {
+	return len(accAttestations(findAttestations())) > 0
+}
+
+// accAttestations drops external (cosign) attestations cached by trust verify --remote from
+// paths: acc does not check their signatures, so they are listed (trust list, trust verify)
+// but never counted as the image's attestations
+func accAttestations(paths []string) []string {
+	acc := []string{}
+	for _, path := range paths {
+		if !isExternalAttestationFile(path) {
+			acc = append(acc, path)
 		}
 	}
-
-	return "", fmt.Errorf("could not resolve digest for %s", imageRef)
+	return acc
 }
 
 // findAttestations looks for attestation files (all images)
@@ -255,11 +266,8 @@ func findAttestationsForImage(digest string) []string {
 		return findAttestations()
 	}
 
-	// Use first 12 chars of digest to match directory structure
-	digestPrefix := digest
-	if len(digest) > 12 {
-		digestPrefix = digest[:12]
-	}
+	// Use the short digest (first 12 chars) to match directory structure
+	digestPrefix := oci.ShortDigest(digest)
 
 	attestDir := filepath.Join(".acc", "attestations", digestPrefix)
 	if _, err := os.Stat(attestDir); os.IsNotExist(err) {
@@ -321,6 +329,28 @@ func printHumanStatus(result *StatusResult) {
 	} else {
 		ui.PrintWarning("  Attestations: none")
 	}
+	if cmp := result.AttestationComparison; cmp != nil {
+		switch {
+		case cmp.Stale:
+			ui.PrintError(fmt.Sprintf("  Attestation:  stale (recorded %s, current %s)", shortHash(cmp.RecordedHash), shortHash(cmp.CurrentHash)))
+		case cmp.Message != "":
+			ui.PrintWarning(fmt.Sprintf("  Attestation:  not compared (%s)", cmp.Message))
+		default:
+			ui.PrintSuccess(fmt.Sprintf("  Attestation:  current (results hash %s)", shortHash(cmp.CurrentHash)))
+		}
+	}
+	for _, att := range result.AttestationDetails {
+		signature := att.SignatureStatus
+		if signature == "" {
+			signature = "none"
+		}
+		line := fmt.Sprintf("    %s: schema=%t, digest=%t, signature=%s", filepath.Base(att.Path), att.ValidSchema, att.DigestMatch, signature)
+		if att.ValidSchema && att.DigestMatch && att.SignatureStatus != "invalid" {
+			ui.PrintSuccess(line)
+		} else {
+			ui.PrintError(line)
+		}
+	}
 	fmt.Println()
 
 	// v0.2.0: Show violations and warnings separately
@@ -354,12 +384,29 @@ func (sr *StatusResult) FormatJSON() string {
 // ExitCode returns appropriate exit code
 // PRESERVED BEHAVIOR: 0=pass, 1=fail/warn, 2=unknown
 func (sr *StatusResult) ExitCode() int {
-	if sr.Status == "unknown" {
-		return 2
-	}
+	return sr.ExitCodeWith(ExitCodeOptions{FailOnUnknown: true})
+}
+
+// ExitCodeOptions controls how trust status maps to exit codes (--fail-on-unknown, --require-pass)
+type ExitCodeOptions struct {
+	FailOnUnknown bool // true (default): unknown exits 2; false: unknown exits 0 (advisory checks)
+	RequirePass   bool // any status other than pass exits 1 (overrides FailOnUnknown)
+}
+
+// ExitCodeWith returns the exit code for the status under the given options
+func (sr *StatusResult) ExitCodeWith(opts ExitCodeOptions) int {
 	if sr.Status == "pass" {
 		return 0
 	}
+	if opts.RequirePass {
+		return 1
+	}
+	if sr.Status == "unknown" {
+		if opts.FailOnUnknown {
+			return 2
+		}
+		return 0
+	}
 	// fail or warn
 	return 1
 }
@@ -374,84 +421,64 @@ func getString(m map[string]interface{}, key string) string {
 
 // fetchRemoteAttestations fetches attestations from a remote OCI registry and caches them locally
 // v0.3.2: Real OCI attestation fetching using oras-go/v2
-func fetchRemoteAttestations(imageRef, digest string, outputJSON bool) error {
+// The returned RemoteFetch is never nil and reports how complete the fetch was, even on error
+func fetchRemoteAttestations(imageRef, digest string, outputJSON bool) (*RemoteFetch, error) {
 	ctx := context.Background()
 
 	// 1. Parse image reference to get registry and repository
-	registry, repository, _, err := parseImageRef(imageRef)
+	registry, repository, _, err := oci.ParseReference(imageRef)
 	if err != nil {
-		return fmt.Errorf("failed to parse image reference: %w", err)
+		return (&RemoteFetch{}).abort(fmt.Errorf("failed to parse image reference: %w", err))
 	}
 
 	// 2. Create OCI repository client with auth
-	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", registry, repository))
+	repo, err := oci.NewRepository(registry, repository)
 	if err != nil {
-		return fmt.Errorf("failed to create repository client: %w", err)
-	}
-
-	// Configure auth from Docker credentials
-	// Try multiple registry key formats that might be in Docker config
-	var cred auth.Credential
-	var credErr error
-
-	// Try different registry URL formats
-	registryFormats := []string{
-		registry,                       // e.g., "ghcr.io"
-		"https://" + registry,          // e.g., "https://ghcr.io"
-		"https://" + registry + "/v2/", // e.g., "https://ghcr.io/v2/"
-	}
-
-	for _, regFormat := range registryFormats {
-		if c, err := loadDockerCredentials(regFormat); err == nil {
-			cred = c
-			credErr = nil
-			break
-		} else {
-			credErr = err
-		}
+		return (&RemoteFetch{}).abort(err)
 	}
 
-	// Set up auth client with credentials if found
-	repo.Client = &auth.Client{
-		Client: retry.DefaultClient,
-		Cache:  auth.NewCache(),
-		Credential: auth.CredentialFunc(func(ctx context.Context, reg string) (auth.Credential, error) {
-			if credErr != nil {
-				// No credentials found, return empty (might work for public repos or with other auth methods)
-				return auth.Credential{}, nil
-			}
-			return cred, nil
-		}),
-	}
-	repo.PlainHTTP = false
+	return fetchAttestationsFromRepo(ctx, repo, registry, repository, digest, outputJSON)
+}
 
-	// 3. List tags matching our attestation naming pattern
-	// Pattern: attestation-<digest-prefix>-*
-	digestPrefix := digest
-	if len(digest) > 12 {
-		digestPrefix = digest[:12]
-	}
+// fetchAttestationsFromRepo discovers attestations for a digest in a repository and caches them locally
+// Recognizes acc attestation tags (attestation-<digest12>-*) and cosign attestation tags (sha256-<digest>.att);
+// layers are selected by media type (see attestationFormat). A tag that fails is skipped and
+// counted in the returned RemoteFetch, so one unreadable tag does not hide the others.
+func fetchAttestationsFromRepo(ctx context.Context, repo *remote.Repository, registry, repository, digest string, outputJSON bool) (*RemoteFetch, error) {
+	fetch := &RemoteFetch{}
+
+	// 3. List tags matching acc or cosign attestation naming patterns
+	// acc pattern: attestation-<digest-prefix>-*
+	// cosign pattern: sha256-<digest>.att
+	digestPrefix := oci.ShortDigest(digest)
 	attestationPrefix := fmt.Sprintf("attestation-%s-", digestPrefix)
+	cosignTag := cosignAttestationTag(digest)
 
 	if !outputJSON {
-		ui.PrintInfo(fmt.Sprintf("Looking for attestation tags with prefix: %s", attestationPrefix))
+		ui.PrintInfo(fmt.Sprintf("Looking for attestation tags with prefix: %s (or cosign tag %s)", attestationPrefix, cosignTag))
 	}
 
 	// List all tags
 	var attestationTags []string
 	var allTags []string
-	err = repo.Tags(ctx, "", func(tags []string) error {
-		for _, tag := range tags {
-			allTags = append(allTags, tag)
-			if strings.HasPrefix(tag, attestationPrefix) {
-				attestationTags = append(attestationTags, tag)
+	err := oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		// Reset on each attempt so a partial listing is not duplicated
+		attestationTags = nil
+		allTags = nil
+		return repo.Tags(ctx, "", func(tags []string) error {
+			for _, tag := range tags {
+				allTags = append(allTags, tag)
+				if strings.HasPrefix(tag, attestationPrefix) || tag == cosignTag {
+					attestationTags = append(attestationTags, tag)
+				}
 			}
-		}
-		return nil
+			return nil
+		})
 	})
 	if err != nil {
-		return fmt.Errorf("failed to list tags: %w", err)
+		return fetch.abort(fmt.Errorf("failed to list tags: %w", err))
 	}
+	fetch.Attempted = len(attestationTags)
 
 	if !outputJSON {
 		ui.PrintInfo(fmt.Sprintf("Total tags found in repository: %d", len(allTags)))
@@ -466,189 +493,190 @@ func fetchRemoteAttestations(imageRef, digest string, outputJSON bool) error {
 		if !outputJSON {
 			ui.PrintWarning(fmt.Sprintf("No remote attestations found with prefix: %s", attestationPrefix))
 		}
-		return nil
+		fetch.Complete = true
+		return fetch, nil
 	}
 
 	// 4. Pull each attestation and cache it
-	fetchedCount := 0
+	// Path: .acc/attestations/<digest-prefix>/remote/<registry>/<repo>/<hash>.json
+	cacheDir := filepath.Join(".acc", "attestations", digestPrefix, "remote", registry, repository)
+	// Tags fetched recently (per the cache index) are not resolved or fetched again
+	index := loadRemoteIndex(digest)
+	now := time.Now()
+	fetchedCount, skippedCount := 0, 0
+	var pending []string
 	for _, tag := range attestationTags {
-		// Resolve tag to descriptor
-		manifestDesc, err := repo.Resolve(ctx, tag)
-		if err != nil {
-			if !outputJSON {
-				ui.PrintWarning(fmt.Sprintf("Failed to resolve tag %s: %v", tag, err))
-			}
+		indexKey := fmt.Sprintf("%s/%s:%s", registry, repository, tag)
+		if index.fresh(indexKey, cacheDir, now) {
+			ui.PrintDebug(fmt.Sprintf("attestation tag %s already fetched, skipping", tag))
+			skippedCount++
+			fetch.Reused++
 			continue
 		}
+		pending = append(pending, tag)
+	}
 
-		// Fetch the tagged content (which is now an OCI manifest)
-		manifestReader, err := repo.Fetch(ctx, manifestDesc)
-		if err != nil {
-			if !outputJSON {
-				ui.PrintWarning(fmt.Sprintf("Failed to fetch manifest %s: %v", tag, err))
-			}
-			continue
-		}
+	// Tags are fetched in parallel (at most --concurrency at once), then cached and recorded
+	// one at a time, so the index is still saved after every tag
+	var mu sync.Mutex
+	var abortErr error
+	pool.Default().Each(len(pending), func(i int) {
+		tag := pending[i]
+		tagFetch := fetchAttestationTag(ctx, repo, tag, attestationPrefix)
 
-		manifestData, err := io.ReadAll(manifestReader)
-		manifestReader.Close()
-		if err != nil {
-			continue
+		mu.Lock()
+		defer mu.Unlock()
+		if abortErr != nil {
+			return
 		}
-
-		// Parse as OCI manifest to extract the attestation blob descriptor
-		var manifest ocispec.Manifest
-		var attestationData []byte
-
-		if err := json.Unmarshal(manifestData, &manifest); err == nil {
-			// This is an OCI manifest - extract the attestation blob from layers
-			if len(manifest.Layers) > 0 {
-				attestationDesc := manifest.Layers[0]
-				attestationReader, err := repo.Fetch(ctx, attestationDesc)
-				if err != nil {
-					if !outputJSON {
-						ui.PrintWarning(fmt.Sprintf("Failed to fetch attestation blob from manifest %s: %v", tag, err))
-					}
-					continue
-				}
-				attestationData, err = io.ReadAll(attestationReader)
-				attestationReader.Close()
-				if err != nil {
-					continue
-				}
-			} else {
-				if !outputJSON {
-					ui.PrintWarning(fmt.Sprintf("Manifest %s has no layers", tag))
-				}
-				continue
+		if !outputJSON {
+			for _, warning := range tagFetch.warnings {
+				ui.PrintWarning(warning)
 			}
-		} else {
-			// Not a manifest, treat as raw attestation data (backward compatibility)
-			attestationData = manifestData
+		}
+		if tagFetch.failure != "" {
+			fetch.recordFailure(tagFetch.failure)
+			return
 		}
 
-		// 5. Cache attestation locally
-		// Path: .acc/attestations/<digest-prefix>/remote/<registry>/<repo>/<hash>.json
-		cacheDir := filepath.Join(".acc", "attestations", digestPrefix, "remote", registry, repository)
+		// 5. Cache attestations locally
 		if err := os.MkdirAll(cacheDir, 0755); err != nil {
-			return fmt.Errorf("failed to create cache directory: %w", err)
+			abortErr = fmt.Errorf("failed to create cache directory: %w", err)
+			return
 		}
 
-		// Use hash of attestation content as filename for deduplication
-		attestationHash := fmt.Sprintf("%x", sha256.Sum256(attestationData))
-		cachePath := filepath.Join(cacheDir, attestationHash[:16]+".json")
+		entry := remoteIndexEntry{ManifestDigest: tagFetch.manifestDesc.Digest.String(), FetchedAt: now.UTC().Format(time.RFC3339)}
+		for _, attestationData := range tagFetch.attestations {
+			// Use hash of attestation content as filename for deduplication
+			attestationHash := fmt.Sprintf("%x", sha256.Sum256(attestationData))
+			cachePath := filepath.Join(cacheDir, attestationHash[:16]+".json")
+			entry.ContentHashes = append(entry.ContentHashes, attestationHash)
 
-		// Check if already cached
-		if _, err := os.Stat(cachePath); err == nil {
-			continue // Already cached
-		}
+			// Check if already cached
+			if _, err := os.Stat(cachePath); err == nil {
+				continue // Already cached
+			}
+
+			// Write to cache
+			if err := os.WriteFile(cachePath, attestationData, 0644); err != nil {
+				abortErr = fmt.Errorf("failed to write attestation cache: %w", err)
+				return
+			}
 
-		// Write to cache
-		if err := os.WriteFile(cachePath, attestationData, 0644); err != nil {
-			return fmt.Errorf("failed to write attestation cache: %w", err)
+			fetchedCount++
 		}
 
-		fetchedCount++
+		// Only fully cached tags are recorded, so a partial fetch is retried next run
+		if tagFetch.partialErr != nil {
+			fetch.recordFailure(remoteErrorCategory(tagFetch.partialErr))
+			return
+		}
+		fetch.Fetched++
+		indexKey := fmt.Sprintf("%s/%s:%s", registry, repository, tag)
+		if err := recordRemoteTag(digest, indexKey, entry); err != nil {
+			ui.PrintDebug(fmt.Sprintf("failed to update attestation index: %v", err))
+		}
+	})
+	if abortErr != nil {
+		return fetch.abort(abortErr)
 	}
 
 	if !outputJSON && fetchedCount > 0 {
 		ui.PrintSuccess(fmt.Sprintf("Fetched %d remote attestation(s)", fetchedCount))
 	}
+	if !outputJSON && skippedCount > 0 {
+		ui.PrintInfo(fmt.Sprintf("Reused %d previously fetched attestation tag(s)", skippedCount))
+	}
 
-	return nil
+	fetch.Complete = fetch.Failed == 0
+	return fetch, nil
 }
 
-// parseImageRef parses an image reference into registry, repository, and reference
-func parseImageRef(imageRef string) (registry, repository, reference string, err error) {
-	// Handle image references like:
-	// - localhost:5000/repo:tag
-	// - ghcr.io/org/repo:tag
-	// - ghcr.io/org/repo@sha256:...
-	parts := strings.SplitN(imageRef, "/", 2)
-	if len(parts) < 2 {
-		return "", "", "", fmt.Errorf("invalid image reference format: %s", imageRef)
-	}
-
-	registry = parts[0]
-	rest := parts[1]
-
-	// Split repository and reference (tag or digest)
-	if strings.Contains(rest, "@") {
-		repoParts := strings.SplitN(rest, "@", 2)
-		repository = repoParts[0]
-		reference = repoParts[1]
-	} else if strings.Contains(rest, ":") {
-		repoParts := strings.SplitN(rest, ":", 2)
-		repository = repoParts[0]
-		reference = repoParts[1]
-	} else {
-		repository = rest
-		reference = "latest"
-	}
-
-	return registry, repository, reference, nil
+// attestationTagFetch is the registry content of one attestation tag
+type attestationTagFetch struct {
+	manifestDesc ocispec.Descriptor
+	attestations [][]byte
+	warnings     []string // printed by the caller, so parallel fetches do not interleave output
+	failure      string   // failure category when the tag could not be read at all
+	partialErr   error    // the first attestation layer that could not be fetched
 }
 
-// loadDockerCredentials loads credentials from ~/.docker/config.json
-func loadDockerCredentials(registry string) (auth.Credential, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return auth.Credential{}, err
-	}
+// fetchAttestationTag resolves an attestation tag and fetches its attestation layers
+func fetchAttestationTag(ctx context.Context, repo *remote.Repository, tag, attestationPrefix string) *attestationTagFetch {
+	tagFetch := &attestationTagFetch{}
 
-	configPath := filepath.Join(homeDir, ".docker", "config.json")
-	data, err := os.ReadFile(configPath)
+	// Resolve tag to descriptor (retried on transient registry errors)
+	err := oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		var resolveErr error
+		tagFetch.manifestDesc, resolveErr = repo.Resolve(ctx, tag)
+		return resolveErr
+	})
 	if err != nil {
-		return auth.Credential{}, err
+		tagFetch.warnings = append(tagFetch.warnings, fmt.Sprintf("Failed to resolve tag %s: %v", tag, err))
+		tagFetch.failure = remoteErrorCategory(err)
+		return tagFetch
 	}
 
-	var config struct {
-		Auths map[string]struct {
-			Auth     string `json:"auth"`
-			Username string `json:"username,omitempty"`
-			Password string `json:"password,omitempty"`
-		} `json:"auths"`
-		CredsStore  string            `json:"credsStore,omitempty"`
-		CredHelpers map[string]string `json:"credHelpers,omitempty"`
+	// Fetch the tagged content (which is now an OCI manifest)
+	manifestData, err := fetchContent(ctx, repo, tagFetch.manifestDesc)
+	if err != nil {
+		tagFetch.warnings = append(tagFetch.warnings, fmt.Sprintf("Failed to fetch manifest %s: %v", tag, err))
+		tagFetch.failure = remoteErrorCategory(err)
+		return tagFetch
 	}
 
-	if err := json.Unmarshal(data, &config); err != nil {
-		return auth.Credential{}, err
+	// Parse as OCI manifest to extract the attestation blob descriptors
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		// Not a manifest, treat as raw attestation data (backward compatibility)
+		tagFetch.attestations = append(tagFetch.attestations, manifestData)
+		return tagFetch
 	}
 
-	// Look for registry auth
-	if authEntry, ok := config.Auths[registry]; ok {
-		// Try direct username/password first
-		if authEntry.Username != "" && authEntry.Password != "" {
-			return auth.Credential{
-				Username: authEntry.Username,
-				Password: authEntry.Password,
-			}, nil
+	// This is an OCI manifest - select attestation layers by media type
+	// cosign stores one attestation per layer, so all recognized layers are fetched
+	layers := []ocispec.Descriptor{}
+	for _, layer := range manifest.Layers {
+		if attestationFormat(layer.MediaType) != "" {
+			layers = append(layers, layer)
 		}
+	}
+	// Backward compatibility: acc tags whose layer media type is not recognized
+	if len(layers) == 0 && strings.HasPrefix(tag, attestationPrefix) && len(manifest.Layers) > 0 {
+		layers = manifest.Layers[:1]
+	}
+	if len(layers) == 0 {
+		tagFetch.warnings = append(tagFetch.warnings, fmt.Sprintf("Manifest %s has no attestation layers", tag))
+		tagFetch.failure = "invalid"
+		return tagFetch
+	}
 
-		// Try base64-encoded auth
-		if authEntry.Auth != "" {
-			decoded, err := base64.StdEncoding.DecodeString(authEntry.Auth)
-			if err != nil {
-				return auth.Credential{}, fmt.Errorf("failed to decode auth: %w", err)
-			}
-
-			// Auth is in format "username:password"
-			parts := strings.SplitN(string(decoded), ":", 2)
-			if len(parts) != 2 {
-				return auth.Credential{}, fmt.Errorf("invalid auth format")
+	for _, layer := range layers {
+		data, err := fetchContent(ctx, repo, layer)
+		if err != nil {
+			tagFetch.warnings = append(tagFetch.warnings, fmt.Sprintf("Failed to fetch attestation blob from manifest %s: %v", tag, err))
+			if tagFetch.partialErr == nil {
+				tagFetch.partialErr = err
 			}
-
-			return auth.Credential{
-				Username: parts[0],
-				Password: parts[1],
-			}, nil
+			continue
 		}
+		tagFetch.attestations = append(tagFetch.attestations, data)
 	}
+	return tagFetch
+}
 
-	// TODO: Support credential helpers via credsStore and credHelpers
-	// For now, if we can't find credentials, try to use external credential helpers
-	// by executing docker-credential-<helper> commands
+// fetchContent fetches and reads a descriptor's content, retrying transient registry errors
+func fetchContent(ctx context.Context, repo *remote.Repository, desc ocispec.Descriptor) ([]byte, error) {
+	var data []byte
+	err := oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		reader, err := repo.Fetch(ctx, desc)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
 
-	return auth.Credential{}, fmt.Errorf("no credentials found for %s", registry)
+		data, err = io.ReadAll(reader)
+		return err
+	})
+	return data, err
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +31,50 @@ func TestStatusResultExitCode(t *testing.T) {
 	}
 }
 
+// TestStatusResultExitCodeWith tests exit-code mapping under --fail-on-unknown and --require-pass
+func TestStatusResultExitCodeWith(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		failOnUnknown bool
+		requirePass   bool
+		expected      int
+	}{
+		// Defaults (--fail-on-unknown=true, --require-pass=false) match ExitCode()
+		{"default pass", "pass", true, false, 0},
+		{"default fail", "fail", true, false, 1},
+		{"default warn", "warn", true, false, 1},
+		{"default unknown", "unknown", true, false, 2},
+
+		// --fail-on-unknown=false: unknown is advisory
+		{"advisory pass", "pass", false, false, 0},
+		{"advisory fail", "fail", false, false, 1},
+		{"advisory warn", "warn", false, false, 1},
+		{"advisory unknown", "unknown", false, false, 0},
+
+		// --require-pass: anything but pass fails
+		{"require-pass pass", "pass", true, true, 0},
+		{"require-pass fail", "fail", true, true, 1},
+		{"require-pass warn", "warn", true, true, 1},
+		{"require-pass unknown", "unknown", true, true, 1},
+
+		// --require-pass overrides --fail-on-unknown=false
+		{"require-pass advisory pass", "pass", false, true, 0},
+		{"require-pass advisory warn", "warn", false, true, 1},
+		{"require-pass advisory unknown", "unknown", false, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &StatusResult{Status: tt.status}
+			got := result.ExitCodeWith(ExitCodeOptions{FailOnUnknown: tt.failOnUnknown, RequirePass: tt.requirePass})
+			if got != tt.expected {
+				t.Errorf("ExitCodeWith() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
 // TestStatusUnknown tests that Status returns unknown when no state found
 func TestStatusUnknown(t *testing.T) {
 	// Create temporary directory
@@ -45,7 +90,7 @@ func TestStatusUnknown(t *testing.T) {
 	os.Chdir(tmpDir)
 
 	// Status for non-existent image should return unknown
-	result, err := Status("never-verified:latest", false, true)
+	result, err := Status("never-verified:latest", false, false, false, true)
 	if err != nil {
 		t.Errorf("Status() error = %v, want nil", err)
 	}
@@ -143,7 +188,7 @@ func TestStatusWithVerifyState(t *testing.T) {
 	}
 
 	// Load status
-	result, err := Status("test:latest", false, true)
+	result, err := Status("test:latest", false, false, false, true)
 	if err != nil {
 		t.Errorf("Status() error = %v, want nil", err)
 	}
@@ -214,7 +259,7 @@ func TestStatusWithViolations(t *testing.T) {
 	}
 
 	// Load status
-	result, err := Status("test:root", false, true)
+	result, err := Status("test:root", false, false, false, true)
 	if err != nil {
 		t.Errorf("Status() error = %v, want nil", err)
 	}
@@ -376,3 +421,40 @@ func TestGetString(t *testing.T) {
 		})
 	}
 }
+
+// TestStatus_ExternalAttestationsNotCounted tests that cosign attestations cached by
+// trust verify --remote are not counted as the image's attestations
+func TestStatus_ExternalAttestationsNotCounted(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tmpDir)
+
+	digest := strings.Repeat("cd", 32)
+	ref := "app@sha256:" + digest
+	os.MkdirAll(filepath.Join(".acc", "state"), 0755)
+	state, _ := json.Marshal(map[string]interface{}{"imageRef": ref, "status": "pass", "timestamp": "2025-01-15T10:00:00Z", "result": map[string]interface{}{}})
+	os.WriteFile(filepath.Join(".acc", "state", "last_verify.json"), state, 0644)
+
+	statement, _ := json.Marshal(map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v1",
+		"predicateType": "https://slsa.dev/provenance/v1",
+		"subject":       []map[string]interface{}{{"name": "app", "digest": map[string]string{"sha256": digest}}},
+	})
+	remoteDir := filepath.Join(".acc", "attestations", digest[:12], "remote")
+	os.MkdirAll(remoteDir, 0755)
+	os.WriteFile(filepath.Join(remoteDir, "statement.json"), statement, 0644)
+
+	result, err := Status(ref, false, false, false, true)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if len(result.Attestations) != 0 || HasAttestations() {
+		t.Errorf("expected a cosign-only directory to report 0 acc attestations, got %v", result.Attestations)
+	}
+
+	os.WriteFile(filepath.Join(".acc", "attestations", digest[:12], "attestation.json"), []byte(`{"schemaVersion":"v0.1"}`), 0644)
+	if result, _ = Status(ref, false, false, false, true); len(result.Attestations) != 1 || !HasAttestations() {
+		t.Errorf("expected the acc attestation to be counted, got %v", result.Attestations)
+	}
+}
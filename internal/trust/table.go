@@ -0,0 +1,95 @@
+package trust
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// tableMessageWidth is the message column width for trust status --format table
+const tableMessageWidth = 60
+
+// WriteTable renders the status as aligned columns (trust status --format table).
+// Long messages are truncated to tableMessageWidth, or wrapped onto continuation
+// rows when wrap is set. No color is used so the output is stable for scripts and diffs.
+func (sr *StatusResult) WriteTable(w io.Writer, wrap bool) error {
+	sbom := "not found"
+	if sr.SBOMPresent {
+		sbom = "present"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "IMAGE\t%s\n", sr.ImageRef)
+	fmt.Fprintf(tw, "STATUS\t%s\n", strings.ToUpper(sr.Status))
+	if sr.ProfileUsed != "" {
+		fmt.Fprintf(tw, "PROFILE\t%s\n", sr.ProfileUsed)
+	}
+	fmt.Fprintf(tw, "SBOM\t%s\n", sbom)
+	fmt.Fprintf(tw, "ATTESTATIONS\t%d\n", len(sr.Attestations))
+	fmt.Fprintf(tw, "VERIFIED\t%s\n", sr.Timestamp)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(sr.Violations) == 0 && len(sr.Warnings) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo policy violations")
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tSEVERITY\tRULE\tMESSAGE")
+	writeRows := func(kind string, violations []Violation) {
+		for _, v := range violations {
+			lines := tableMessage(v.Message, wrap)
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", kind, v.Severity, v.Rule, lines[0])
+			for _, line := range lines[1:] {
+				fmt.Fprintf(tw, "\t\t\t%s\n", line)
+			}
+		}
+	}
+	writeRows("violation", sr.Violations)
+	writeRows("warning", sr.Warnings)
+	return tw.Flush()
+}
+
+// tableMessage fits a message to the message column: truncated with "..." by default,
+// or split on word boundaries into lines of at most tableMessageWidth when wrap is set
+func tableMessage(message string, wrap bool) []string {
+	message = strings.Join(strings.Fields(message), " ")
+	if utf8.RuneCountInString(message) <= tableMessageWidth {
+		return []string{message}
+	}
+	if !wrap {
+		return []string{string([]rune(message)[:tableMessageWidth-3]) + "..."}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(message) {
+		// Words longer than the column are split so no row overflows
+		for runes := []rune(word); len(runes) > tableMessageWidth; runes = []rune(word) {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			lines = append(lines, string(runes[:tableMessageWidth]))
+			word = string(runes[tableMessageWidth:])
+		}
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= tableMessageWidth:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,107 @@
+package trust
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTrustStatusTableGolden tests --format table output against golden files
+func TestTrustStatusTableGolden(t *testing.T) {
+	scenarios := []struct {
+		name       string
+		wrap       bool
+		goldenFile string
+	}{
+		{name: "truncated", wrap: false, goldenFile: "fail-table.txt"},
+		{name: "wrapped", wrap: true, goldenFile: "fail-table-wrap.txt"},
+	}
+
+	for _, tc := range scenarios {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			oldWd, _ := os.Getwd()
+			defer os.Chdir(oldWd)
+			os.Chdir(tmpDir)
+
+			stateDir := filepath.Join(".acc", "state")
+			if err := os.MkdirAll(stateDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			stateData := map[string]interface{}{
+				"imageRef":  "demo-app:root",
+				"status":    "fail",
+				"timestamp": "2025-01-15T10:00:00Z",
+				"result": map[string]interface{}{
+					"sbomPresent": true,
+					"policyResult": map[string]interface{}{
+						"allow": false,
+						"violations": []interface{}{
+							map[string]interface{}{
+								"rule":     "no-root-user",
+								"severity": "high",
+								"result":   "fail",
+								"message":  "Image runs as root user",
+							},
+							map[string]interface{}{
+								"rule":     "require-healthcheck",
+								"severity": "medium",
+								"result":   "fail",
+								"message":  "Image does not define a HEALTHCHECK instruction; orchestrators cannot detect a hung process and will keep routing traffic to it",
+							},
+						},
+						"warnings": []interface{}{},
+					},
+				},
+			}
+			data, _ := json.MarshalIndent(stateData, "", "  ")
+			if err := os.WriteFile(filepath.Join(stateDir, "last_verify.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Status("demo-app:root", false, false, false, true)
+			if err != nil {
+				t.Fatalf("Status() error = %v, want nil", err)
+			}
+
+			var buf bytes.Buffer
+			if err := result.WriteTable(&buf, tc.wrap); err != nil {
+				t.Fatalf("WriteTable() error = %v", err)
+			}
+
+			goldenPath := filepath.Join(oldWd, "..", "..", "testdata", "golden", "trust", tc.goldenFile)
+			golden, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Failed to read golden file %s: %v", goldenPath, err)
+			}
+			if buf.String() != string(golden) {
+				t.Errorf("table output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), golden)
+			}
+		})
+	}
+}
+
+func TestTableMessage(t *testing.T) {
+	short := "Image runs as root user"
+	if got := tableMessage(short, false); len(got) != 1 || got[0] != short {
+		t.Errorf("tableMessage(short) = %q, want [%q]", got, short)
+	}
+
+	long := bytes.Repeat([]byte("x"), tableMessageWidth*2+5)
+	truncated := tableMessage(string(long), false)
+	if len(truncated) != 1 || len(truncated[0]) != tableMessageWidth {
+		t.Errorf("truncated = %q, want one line of %d chars", truncated, tableMessageWidth)
+	}
+
+	wrapped := tableMessage(string(long), true)
+	if len(wrapped) != 3 {
+		t.Fatalf("wrapped into %d lines, want 3", len(wrapped))
+	}
+	for _, line := range wrapped {
+		if len(line) > tableMessageWidth {
+			t.Errorf("wrapped line %q exceeds %d chars", line, tableMessageWidth)
+		}
+	}
+}
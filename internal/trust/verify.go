@@ -8,9 +8,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/cloudcwfranck/acc/internal/crypto"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/policy"
 	"github.com/cloudcwfranck/acc/internal/ui"
 )
 
@@ -33,12 +34,20 @@ type AttestationDetail struct {
 	VerificationResultsHash string `json:"verificationResultsHash"`
 	ValidSchema             bool   `json:"validSchema"`
 	DigestMatch             bool   `json:"digestMatch"`
+	Format                  string `json:"format,omitempty"`     // "cosign" for external attestations (empty for acc)
+	MediaType               string `json:"mediaType,omitempty"`  // media type of external attestations
+	PolicyHash              string `json:"policyHash,omitempty"` // policy pack hash recorded at attestation time
+	PolicyDrift             bool   `json:"policyDrift"`          // recorded policy hash differs from the live pack (--check-policy-drift)
+	// SignatureStatus is "verified" or "invalid" for signed envelopes, "present" when a cosign
+	// signature sidecar (<path>.sig from acc attest --sign) exists but is not checked; empty if unsigned
+	SignatureStatus string `json:"signatureStatus,omitempty"`
 }
 
 // VerifyAttestations verifies attestations for an image
 // v0.3.0: Local-only, read-only attestation verification
 // v0.3.2: optionally fetch from remote registry when remote=true
-func VerifyAttestations(imageRef string, remote, outputJSON bool) (*VerifyResult, error) {
+// checkPolicyDrift fails verification unless an attestation was created with the current policy pack
+func VerifyAttestations(imageRef string, remote, checkPolicyDrift, outputJSON bool) (*VerifyResult, error) {
 	result := &VerifyResult{
 		SchemaVersion:      "v0.3",
 		ImageRef:           imageRef,
@@ -50,7 +59,7 @@ func VerifyAttestations(imageRef string, remote, outputJSON bool) (*VerifyResult
 	}
 
 	// Step 1: Resolve image digest
-	digest, err := resolveImageDigest(imageRef)
+	digest, err := oci.ResolveDigest(imageRef)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("Cannot resolve digest: %v", err))
 		result.VerificationStatus = "unknown"
@@ -64,7 +73,7 @@ func VerifyAttestations(imageRef string, remote, outputJSON bool) (*VerifyResult
 
 	// v0.3.2: Optionally fetch remote attestations before finding local ones
 	if remote {
-		if err := fetchRemoteAttestations(imageRef, digest, outputJSON); err != nil {
+		if _, err := fetchRemoteAttestations(imageRef, digest, outputJSON); err != nil {
 			// Remote fetch failed - log warning but don't fail
 			// This preserves local-only workflow when network unavailable
 			if !outputJSON {
@@ -89,11 +98,18 @@ func VerifyAttestations(imageRef string, remote, outputJSON bool) (*VerifyResult
 	}
 
 	// Step 3: Validate each attestation
+	// External (cosign) attestations are listed but never count toward "verified": acc does not
+	// check their signatures, and their predicate need not be an acc verification at all
 	allValid := true
+	accCount := 0
 	for _, path := range attestPaths {
 		detail := validateAttestation(path, digest)
 		result.Attestations = append(result.Attestations, detail)
 
+		if detail.Format == formatCosign {
+			continue
+		}
+		accCount++
 		if !detail.ValidSchema || !detail.DigestMatch {
 			allValid = false
 			result.Errors = append(result.Errors,
@@ -101,6 +117,23 @@ func VerifyAttestations(imageRef string, remote, outputJSON bool) (*VerifyResult
 					filepath.Base(path), detail.ValidSchema, detail.DigestMatch))
 		}
 	}
+	if accCount == 0 {
+		allValid = false
+		result.Errors = append(result.Errors, "No acc attestations found (external attestations are not signature-checked and do not verify the image)")
+	}
+
+	// Step 3b: Optionally compare recorded policy hashes with the live policy pack
+	if checkPolicyDrift && allValid {
+		if err := checkAttestationPolicyDrift(result); err != nil {
+			result.VerificationStatus = "unverified"
+			result.Errors = append(result.Errors, err.Error())
+			if !outputJSON {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				fmt.Fprintf(os.Stderr, "Remediation: Re-run 'acc verify %s && acc attest %s' with the current policy pack\n", imageRef, imageRef)
+			}
+			return result, err
+		}
+	}
 
 	// Step 4: Determine overall status
 	if allValid {
@@ -117,6 +150,38 @@ func VerifyAttestations(imageRef string, remote, outputJSON bool) (*VerifyResult
 	return result, nil
 }
 
+// checkAttestationPolicyDrift marks attestations whose recorded policy hash differs from
+// the live .acc/policy pack. Attestations accumulate per digest, so verification only
+// fails when none of them was created with the current pack.
+func checkAttestationPolicyDrift(result *VerifyResult) error {
+	liveHash, err := policy.PackHash(filepath.Join(".acc", "policy"))
+	if err != nil {
+		return fmt.Errorf("cannot hash policy pack: %w", err)
+	}
+
+	recorded, current := 0, 0
+	for i := range result.Attestations {
+		detail := &result.Attestations[i]
+		if detail.PolicyHash == "" {
+			continue
+		}
+		recorded++
+		if detail.PolicyHash == liveHash {
+			current++
+		} else {
+			detail.PolicyDrift = true
+		}
+	}
+
+	if recorded == 0 {
+		return fmt.Errorf("policy drift cannot be checked: no attestation records a policy hash")
+	}
+	if current == 0 {
+		return fmt.Errorf("policy drift: policy pack changed since attestation (live %s)", liveHash)
+	}
+	return nil
+}
+
 // validateAttestation validates a single attestation file
 // Supports both legacy (v0.1) and envelope (v0.3.3) formats
 func validateAttestation(path, expectedDigest string) AttestationDetail {
@@ -138,6 +203,11 @@ func validateAttestation(path, expectedDigest string) AttestationDetail {
 		return detail
 	}
 
+	// Cosign-style attestations (DSSE envelope or in-toto statement) pulled from a registry
+	if external, ok := validateExternalAttestation(path, topLevel, expectedDigest); ok {
+		return external
+	}
+
 	// Check if this is envelope format (has "attestation" and "envelope" fields)
 	var attest map[string]interface{}
 	var envelope map[string]interface{}
@@ -176,6 +246,9 @@ func validateAttestation(path, expectedDigest string) AttestationDetail {
 		if hash, ok := evidence["verificationResultsHash"].(string); ok {
 			detail.VerificationResultsHash = hash
 		}
+		if hash, ok := evidence["policyHash"].(string); ok {
+			detail.PolicyHash = hash
+		}
 	}
 
 	// Validate schema (basic check for required fields in attestation object)
@@ -196,14 +269,21 @@ func validateAttestation(path, expectedDigest string) AttestationDetail {
 		}
 	}
 
+	// A cosign sidecar is only reported; checking it needs cosign and the signer's identity
+	if _, err := os.Stat(path + ".sig"); err == nil {
+		detail.SignatureStatus = "present"
+	}
+
 	// If envelope exists, verify signature
 	if envelope != nil {
 		if !verifyEnvelopeSignature(attest, envelope) {
 			// Signature verification failed - mark as invalid
 			detail.ValidSchema = false
+			detail.SignatureStatus = "invalid"
 			return detail
 		}
 		// Signature verified - attestation is valid
+		detail.SignatureStatus = "verified"
 	}
 
 	return detail
@@ -274,12 +354,9 @@ func verifyEnvelopeSignature(attestation, envelope map[string]interface{}) bool
 // normalizeDigest normalizes a digest string for comparison
 // - Trims whitespace
 // - Lowercases
-// - Strips optional "sha256:" prefix
+// - Strips optional "sha256:" prefix; other algorithms keep theirs (acc's digest form)
 func normalizeDigest(s string) string {
-	s = strings.TrimSpace(s)
-	s = strings.ToLower(s)
-	s = strings.TrimPrefix(s, "sha256:")
-	return s
+	return oci.NormalizeDigest(s)
 }
 
 // printHumanVerifyResult prints human-readable verification result
@@ -290,7 +367,7 @@ func printHumanVerifyResult(result *VerifyResult) {
 	// Image information
 	fmt.Printf("Image:       %s\n", result.ImageRef)
 	if len(result.ImageDigest) >= 12 {
-		fmt.Printf("Digest:      sha256:%s...\n", result.ImageDigest[:12])
+		fmt.Printf("Digest:      %s...\n", oci.DisplayDigest(result.ImageDigest))
 	} else {
 		fmt.Printf("Digest:      %s\n", result.ImageDigest)
 	}
@@ -317,6 +394,15 @@ func printHumanVerifyResult(result *VerifyResult) {
 			fmt.Printf("\n  [%d] %s\n", i+1, filepath.Base(att.Path))
 			fmt.Printf("      Timestamp:   %s\n", att.Timestamp)
 			fmt.Printf("      Status:      %s\n", att.VerificationStatus)
+			if att.Format != "" {
+				fmt.Printf("      Format:      %s (%s, signature not verified by acc; not counted)\n", att.Format, att.MediaType)
+			}
+			if att.PolicyDrift {
+				ui.PrintWarning("      Policy:      drifted (policy pack changed since attestation)")
+			}
+			if att.SignatureStatus != "" {
+				fmt.Printf("      Signature:   %s\n", att.SignatureStatus)
+			}
 			if att.ValidSchema && att.DigestMatch {
 				ui.PrintSuccess(fmt.Sprintf("      Valid:       ✓ (schema=%t, digest=%t)",
 					att.ValidSchema, att.DigestMatch))
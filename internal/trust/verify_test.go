@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/policy"
 )
 
 func TestVerifyResultExitCodes(t *testing.T) {
@@ -289,6 +292,11 @@ func TestNormalizeDigest(t *testing.T) {
 			input:    "SHA256:ABC123DEF456",
 			expected: "abc123def456",
 		},
+		{
+			name:     "sha512 digest keeps its prefix",
+			input:    "SHA512:ABC123DEF456",
+			expected: "sha512:abc123def456",
+		},
 		{
 			name:     "digest with whitespace",
 			input:    "  abc123def456  ",
@@ -411,3 +419,114 @@ func TestDigestMatchingWithNormalization(t *testing.T) {
 		})
 	}
 }
+
+// setupDriftProject writes a policy pack and an attestation recording its hash, returning the image ref
+func setupDriftProject(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	policyDir := filepath.Join(".acc", "policy")
+	os.MkdirAll(policyDir, 0755)
+	os.WriteFile(filepath.Join(policyDir, "default.rego"), []byte("package acc.policy\n"), 0644)
+	policyHash, err := policy.PackHash(policyDir)
+	if err != nil {
+		t.Fatalf("failed to hash policy pack: %v", err)
+	}
+
+	digest := strings.Repeat("ab", 32)
+	attestation := map[string]interface{}{
+		"schemaVersion": "v0.1",
+		"timestamp":     "2025-01-01T12:00:00Z",
+		"subject":       map[string]interface{}{"imageRef": "app:1.0", "imageDigest": "sha256:" + digest},
+		"evidence": map[string]interface{}{
+			"verificationStatus":      "pass",
+			"verificationResultsHash": "sha256:xyz789",
+			"policyHash":              policyHash,
+		},
+	}
+	attestDir := filepath.Join(".acc", "attestations", digest[:12])
+	os.MkdirAll(attestDir, 0755)
+	data, _ := json.MarshalIndent(attestation, "", "  ")
+	os.WriteFile(filepath.Join(attestDir, "attestation.json"), data, 0644)
+
+	return "app@sha256:" + digest
+}
+
+// TestVerifyAttestations_ExternalNotCounted tests that a cached, unsigned cosign statement whose
+// subject matches the image is listed but does not verify it
+func TestVerifyAttestations_ExternalNotCounted(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	digest := strings.Repeat("cd", 32)
+	statement, _ := json.Marshal(map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v1",
+		"predicateType": "https://cyclonedx.org/vex",
+		"subject": []map[string]interface{}{
+			{"name": "app", "digest": map[string]string{"sha256": digest}},
+		},
+	})
+	remoteDir := filepath.Join(".acc", "attestations", digest[:12], "remote")
+	os.MkdirAll(remoteDir, 0755)
+	os.WriteFile(filepath.Join(remoteDir, "statement.json"), statement, 0644)
+
+	result, err := VerifyAttestations("app@sha256:"+digest, false, false, true)
+	if err == nil {
+		t.Fatal("expected verification to fail with only an external attestation")
+	}
+	if result.VerificationStatus != "unverified" {
+		t.Errorf("expected unverified, got %s", result.VerificationStatus)
+	}
+	if len(result.Attestations) != 1 || result.Attestations[0].Format != formatCosign || !result.Attestations[0].DigestMatch {
+		t.Errorf("expected the external attestation to be listed, got %+v", result.Attestations)
+	}
+}
+
+// TestVerifyAttestations_PolicyDrift tests --check-policy-drift with matching and drifted policy packs
+func TestVerifyAttestations_PolicyDrift(t *testing.T) {
+	t.Run("matching policy", func(t *testing.T) {
+		ref := setupDriftProject(t)
+
+		result, err := VerifyAttestations(ref, false, true, true)
+		if err != nil {
+			t.Fatalf("expected verification to pass with unchanged policy: %v", err)
+		}
+		if result.VerificationStatus != "verified" {
+			t.Errorf("expected verified, got %s", result.VerificationStatus)
+		}
+		if len(result.Attestations) != 1 || result.Attestations[0].PolicyDrift {
+			t.Errorf("expected one attestation without drift, got %+v", result.Attestations)
+		}
+	})
+
+	t.Run("drifted policy", func(t *testing.T) {
+		ref := setupDriftProject(t)
+		os.WriteFile(filepath.Join(".acc", "policy", "default.rego"), []byte("package acc.policy\n# changed\n"), 0644)
+
+		result, err := VerifyAttestations(ref, false, true, true)
+		if err == nil {
+			t.Fatal("expected verification to fail after policy changed")
+		}
+		if result.VerificationStatus != "unverified" || result.ExitCode() != 1 {
+			t.Errorf("expected unverified with exit 1, got %s (%d)", result.VerificationStatus, result.ExitCode())
+		}
+		if len(result.Attestations) != 1 || !result.Attestations[0].PolicyDrift {
+			t.Errorf("expected attestation to be flagged as drifted, got %+v", result.Attestations)
+		}
+	})
+
+	t.Run("drift ignored without flag", func(t *testing.T) {
+		ref := setupDriftProject(t)
+		os.WriteFile(filepath.Join(".acc", "policy", "default.rego"), []byte("package acc.policy\n# changed\n"), 0644)
+
+		result, err := VerifyAttestations(ref, false, false, true)
+		if err != nil || result.VerificationStatus != "verified" {
+			t.Errorf("expected drift to be ignored without --check-policy-drift, got %s (%v)", result.VerificationStatus, err)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Error codes for the --json error envelope. Codes are stable identifiers for
+// scripts; messages and hints are for humans and may change.
+const (
+	CodeError              = "ERROR"               // unclassified failure
+	CodeInvalidArgument    = "INVALID_ARGUMENT"    // bad flags, arguments, or flag combinations
+	CodeConfig             = "CONFIG_ERROR"        // acc.yaml missing or invalid
+	CodeVerificationFailed = "VERIFICATION_FAILED" // verification did not pass
+	CodeUpgradeFailed      = "UPGRADE_FAILED"      // acc upgrade could not complete
+	CodeInternal           = "INTERNAL"            // unexpected internal state
+)
+
+// Error is a failure with a stable code and an optional hint for the user
+type Error struct {
+	Code    string
+	Message string
+	Hint    string
+	Err     error // underlying error, if any
+}
+
+// Error renders the message with its hint after a blank line, matching the plain-text
+// form used across commands. Hints get a "Hint: " label unless they carry their own
+// ("Usage: ...").
+func (e *Error) Error() string {
+	switch {
+	case e.Hint == "":
+		return e.Message
+	case strings.HasPrefix(e.Hint, "Usage:"):
+		return e.Message + "\n\n" + e.Hint
+	default:
+		return e.Message + "\n\nHint: " + e.Hint
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewError creates a coded error
+func NewError(code, message, hint string) *Error {
+	return &Error{Code: code, Message: message, Hint: hint}
+}
+
+// WrapError attaches a code and hint to err, keeping it available to errors.Is/As
+func WrapError(code string, err error, hint string) *Error {
+	return &Error{Code: code, Message: err.Error(), Hint: hint, Err: err}
+}
+
+// AsError returns err as an *Error. Errors without a code get CodeError; text after
+// the first blank line (the "Hint:"/"Usage:" convention) becomes the hint.
+func AsError(err error) *Error {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded
+	}
+
+	message, hint, _ := strings.Cut(err.Error(), "\n\n")
+	return &Error{
+		Code:    CodeError,
+		Message: message,
+		Hint:    strings.TrimSpace(strings.TrimPrefix(hint, "Hint:")),
+		Err:     err,
+	}
+}
+
+// FormatErrorJSON renders err as the --json error envelope:
+// {"error":{"code":"...","message":"...","hint":"..."}}
+func FormatErrorJSON(err error) string {
+	coded := AsError(err)
+	envelope := struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Hint    string `json:"hint"`
+		} `json:"error"`
+	}{}
+	envelope.Error.Code = coded.Code
+	envelope.Error.Message = coded.Message
+	envelope.Error.Hint = coded.Hint
+
+	// Hints contain usage text like "acc push <image>"; keep it readable
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(envelope)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFormatErrorJSON(t *testing.T) {
+	base := errors.New("open acc.yaml: no such file")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "coded",
+			err:  NewError(CodeInvalidArgument, "image reference required", "Usage: acc push <image>"),
+			want: `{"error":{"code":"INVALID_ARGUMENT","message":"image reference required","hint":"Usage: acc push <image>"}}`,
+		},
+		{
+			name: "wrapped coded",
+			err:  fmt.Errorf("push: %w", WrapError(CodeConfig, base, "Run 'acc init'")),
+			want: `{"error":{"code":"CONFIG_ERROR","message":"open acc.yaml: no such file","hint":"Run 'acc init'"}}`,
+		},
+		{
+			name: "plain with hint",
+			err:  errors.New("registry unreachable\n\nHint: Check your network"),
+			want: `{"error":{"code":"ERROR","message":"registry unreachable","hint":"Check your network"}}`,
+		},
+		{
+			name: "plain",
+			err:  errors.New("boom"),
+			want: `{"error":{"code":"ERROR","message":"boom","hint":""}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatErrorJSON(tt.err)
+			if got != tt.want {
+				t.Errorf("FormatErrorJSON() = %s, want %s", got, tt.want)
+			}
+			var parsed map[string]map[string]string
+			if err := json.Unmarshal([]byte(got), &parsed); err != nil {
+				t.Errorf("envelope is not valid JSON: %v", err)
+			}
+		})
+	}
+}
+
+func TestErrorText(t *testing.T) {
+	base := errors.New("not found")
+	tests := []struct {
+		err  *Error
+		want string
+	}{
+		{NewError(CodeError, "failed", ""), "failed"},
+		{NewError(CodeConfig, "failed", "Run 'acc init'"), "failed\n\nHint: Run 'acc init'"},
+		{NewError(CodeInvalidArgument, "image reference required", "Usage: acc push <image>"), "image reference required\n\nUsage: acc push <image>"},
+		{WrapError(CodeConfig, base, ""), "not found"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+	if !errors.Is(WrapError(CodeConfig, base, ""), base) {
+		t.Error("WrapError should keep the underlying error for errors.Is")
+	}
+}
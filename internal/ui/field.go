@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatField extracts a single value from a result for --field
+// Path syntax: dot-separated keys with [n] array indexes and ["key"] for keys containing dots,
+// e.g. "status", "artifacts.attestations[0]", `metadata["acc.version"]`
+// Strings are returned as-is; other values are returned as compact JSON
+func FormatField(v interface{}, path string) (string, error) {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	// Walk the marshaled form so paths use the same names as --json output
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal result: %w", err)
+	}
+	var current interface{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return "", fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	walked := ""
+	for _, seg := range segments {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			if seg.isIndex {
+				return "", fmt.Errorf("field %q: %s is an object, not an array", path, describeField(walked))
+			}
+			value, ok := node[seg.key]
+			if !ok {
+				return "", fmt.Errorf("field %q: unknown key %q in %s", path, seg.key, describeField(walked))
+			}
+			current = value
+			walked = joinField(walked, seg)
+		case []interface{}:
+			if !seg.isIndex {
+				return "", fmt.Errorf("field %q: %s is an array, not an object", path, describeField(walked))
+			}
+			if seg.index < 0 || seg.index >= len(node) {
+				return "", fmt.Errorf("field %q: index %d out of range for %s (length %d)", path, seg.index, describeField(walked), len(node))
+			}
+			current = node[seg.index]
+			walked = joinField(walked, seg)
+		default:
+			return "", fmt.Errorf("field %q: %s is not an object or array", path, describeField(walked))
+		}
+	}
+
+	if s, ok := current.(string); ok {
+		return s, nil
+	}
+	out, err := json.Marshal(current)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal field: %w", err)
+	}
+	return string(out), nil
+}
+
+// fieldSegment is one step of a field path: an object key or an array index
+type fieldSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseFieldPath parses a dot/bracket path into segments
+func parseFieldPath(path string) ([]fieldSegment, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("field path is empty")
+	}
+
+	var segments []fieldSegment
+	i := 0
+	expectKey := true
+	for i < len(path) {
+		switch c := path[i]; {
+		case c == '.':
+			if expectKey {
+				return nil, fmt.Errorf("invalid field path %q: empty key at position %d", path, i)
+			}
+			expectKey = true
+			i++
+		case c == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid field path %q: unclosed '['", path)
+			}
+			inner := path[i+1 : i+end]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				segments = append(segments, fieldSegment{key: unquoted})
+			} else if n, err := strconv.Atoi(inner); err == nil {
+				segments = append(segments, fieldSegment{index: n, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("invalid field path %q: bracket must hold an index or quoted key, got [%s]", path, inner)
+			}
+			expectKey = false
+			i += end + 1
+		default:
+			if !expectKey {
+				return nil, fmt.Errorf("invalid field path %q: expected '.' or '[' at position %d", path, i)
+			}
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segments = append(segments, fieldSegment{key: path[i : i+end]})
+			expectKey = false
+			i += end
+		}
+	}
+	if expectKey {
+		return nil, fmt.Errorf("invalid field path %q: trailing '.'", path)
+	}
+
+	return segments, nil
+}
+
+// joinField appends a segment to a path for error messages
+func joinField(walked string, seg fieldSegment) string {
+	if seg.isIndex {
+		return fmt.Sprintf("%s[%d]", walked, seg.index)
+	}
+	if walked == "" {
+		return seg.key
+	}
+	return walked + "." + seg.key
+}
+
+// describeField names a walked path for error messages
+func describeField(walked string) string {
+	if walked == "" {
+		return "result"
+	}
+	return walked
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+type fieldTestResult struct {
+	Status    string            `json:"status"`
+	Artifacts fieldTestArtifact `json:"artifacts"`
+	Metadata  map[string]string `json:"metadata"`
+	Count     int               `json:"count"`
+}
+
+type fieldTestArtifact struct {
+	SBOMFormat   string   `json:"sbomFormat"`
+	Attestations []string `json:"attestations"`
+}
+
+func TestFormatField(t *testing.T) {
+	result := fieldTestResult{
+		Status: "pass",
+		Artifacts: fieldTestArtifact{
+			SBOMFormat:   "spdx",
+			Attestations: []string{".acc/attestations/a.json", ".acc/attestations/b.json"},
+		},
+		Metadata: map[string]string{"acc.version": "v0.3.3"},
+		Count:    2,
+	}
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr string
+	}{
+		{path: "status", want: "pass"},
+		{path: "artifacts.sbomFormat", want: "spdx"},
+		{path: "artifacts.attestations[0]", want: ".acc/attestations/a.json"},
+		{path: "artifacts.attestations[1]", want: ".acc/attestations/b.json"},
+		{path: "artifacts.attestations", want: `[".acc/attestations/a.json",".acc/attestations/b.json"]`},
+		{path: `metadata["acc.version"]`, want: "v0.3.3"},
+		{path: "count", want: "2"},
+		{path: "digest", wantErr: `unknown key "digest"`},
+		{path: "artifacts.attestations[2]", wantErr: "out of range"},
+		{path: "artifacts[0]", wantErr: "is an object"},
+		{path: "artifacts.attestations.first", wantErr: "is an array"},
+		{path: "status.value", wantErr: "not an object or array"},
+		{path: "artifacts.", wantErr: "trailing"},
+		{path: "artifacts..sbomFormat", wantErr: "empty key"},
+		{path: "artifacts.attestations[x]", wantErr: "bracket"},
+		{path: "", wantErr: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := FormatField(result, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FormatField(%q) error = %v, want error containing %q", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FormatField(%q) error = %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("FormatField(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
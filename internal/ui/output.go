@@ -28,6 +28,8 @@ var (
 	// Global UI settings
 	colorEnabled = true
 	emojiEnabled = true
+	debugEnabled = false
+	silent       = false
 )
 
 // SetColorMode sets the color output mode
@@ -47,6 +49,30 @@ func SetColorMode(mode string) {
 	}
 }
 
+// SetLogLevel sets the log level (info|debug)
+func SetLogLevel(level string) error {
+	switch level {
+	case "info", "":
+		debugEnabled = false
+	case "debug":
+		debugEnabled = true
+	default:
+		return fmt.Errorf("invalid log level %q (must be info or debug)", level)
+	}
+	return nil
+}
+
+// DebugEnabled reports whether debug output is enabled
+func DebugEnabled() bool {
+	return debugEnabled
+}
+
+// SetSilent suppresses all Print* output, so a command's stdout carries only its JSON
+// result or error envelope (verify --json-errors-only)
+func SetSilent(enabled bool) {
+	silent = enabled
+}
+
 // SetEmojiEnabled sets whether emojis should be displayed
 func SetEmojiEnabled(enabled bool) {
 	emojiEnabled = enabled
@@ -114,25 +140,47 @@ func FormatTrust(msg string) string {
 
 // PrintSuccess prints a success message to stdout
 func PrintSuccess(msg string) {
+	if silent {
+		return
+	}
 	fmt.Println(FormatSuccess(msg))
 }
 
 // PrintWarning prints a warning message to stdout
 func PrintWarning(msg string) {
+	if silent {
+		return
+	}
 	fmt.Println(FormatWarning(msg))
 }
 
 // PrintError prints an error message to stderr
 func PrintError(msg string) {
+	if silent {
+		return
+	}
 	fmt.Fprintln(os.Stderr, FormatError(msg))
 }
 
+// PrintDebug prints a debug message to stderr when --log-level debug is set
+func PrintDebug(msg string) {
+	if debugEnabled && !silent {
+		fmt.Fprintln(os.Stderr, "[DEBUG] "+msg)
+	}
+}
+
 // PrintInfo prints an info message to stdout
 func PrintInfo(msg string) {
+	if silent {
+		return
+	}
 	fmt.Println(FormatInfo(msg))
 }
 
 // PrintTrust prints a trust message to stdout
 func PrintTrust(msg string) {
+	if silent {
+		return
+	}
 	fmt.Println(FormatTrust(msg))
 }
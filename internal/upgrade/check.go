@@ -0,0 +1,78 @@
+package upgrade
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CheckResult contains the result of an update check
+type CheckResult struct {
+	CurrentVersion  string `json:"currentVersion"`
+	LatestVersion   string `json:"latestVersion"`
+	UpdateAvailable bool   `json:"updateAvailable"`
+	Message         string `json:"message"`
+}
+
+// CheckForUpdate fetches the latest release and reports whether it is newer than
+// currentVersion. It only reads release metadata; nothing is downloaded or installed.
+func CheckForUpdate(apiBase, currentVersion string) (*CheckResult, error) {
+	if apiBase == "" {
+		apiBase = "https://api.github.com"
+	}
+
+	release, err := fetchLatestRelease(apiBase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+
+	result := &CheckResult{
+		CurrentVersion:  currentVersion,
+		LatestVersion:   release.TagName,
+		UpdateAvailable: isNewerVersion(release.TagName, currentVersion),
+	}
+	if result.UpdateAvailable {
+		result.Message = fmt.Sprintf("A newer version is available: %s (current %s). Run 'acc upgrade' to install it.", release.TagName, currentVersion)
+	} else {
+		result.Message = fmt.Sprintf("Up-to-date (version %s)", currentVersion)
+	}
+	return result, nil
+}
+
+// isNewerVersion reports whether latest is newer than current. Versions that are not
+// vMAJOR.MINOR.PATCH (e.g. "dev" builds) are only compared for equality, the same
+// rule Upgrade uses to decide whether it is already up-to-date.
+func isNewerVersion(latest, current string) bool {
+	l, lok := parseVersion(latest)
+	c, cok := parseVersion(current)
+	if !lok || !cok {
+		return normalizeVersion(current) != normalizeVersion(latest)
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" (or "1.2.3"), ignoring any pre-release or build suffix
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,70 @@
+package upgrade
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCheckForUpdate tests the up-to-date and behind messages against a mock release API
+func TestCheckForUpdate(t *testing.T) {
+	var installRequested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/cloudcwfranck/acc/releases/latest" {
+			installRequested = true
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"tag_name": "v0.3.0", "name": "Release v0.3.0", "assets": []}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		current         string
+		updateAvailable bool
+		message         string
+	}{
+		{current: "v0.3.0", updateAvailable: false, message: "Up-to-date (version v0.3.0)"},
+		{current: "0.3.0", updateAvailable: false, message: "Up-to-date (version 0.3.0)"},
+		{current: "v0.4.0", updateAvailable: false, message: "Up-to-date (version v0.4.0)"},
+		{current: "v0.2.9", updateAvailable: true, message: "A newer version is available: v0.3.0 (current v0.2.9)"},
+		{current: "v0.3.0-rc.1", updateAvailable: false, message: "Up-to-date"},
+		{current: "dev", updateAvailable: true, message: "A newer version is available: v0.3.0 (current dev)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current, func(t *testing.T) {
+			result, err := CheckForUpdate(server.URL, tt.current)
+			if err != nil {
+				t.Fatalf("CheckForUpdate failed: %v", err)
+			}
+			if result.LatestVersion != "v0.3.0" {
+				t.Errorf("LatestVersion = %q, want v0.3.0", result.LatestVersion)
+			}
+			if result.UpdateAvailable != tt.updateAvailable {
+				t.Errorf("UpdateAvailable = %v, want %v", result.UpdateAvailable, tt.updateAvailable)
+			}
+			if !strings.HasPrefix(result.Message, tt.message) {
+				t.Errorf("Message = %q, want prefix %q", result.Message, tt.message)
+			}
+		})
+	}
+
+	if installRequested {
+		t.Error("CheckForUpdate requested something other than the latest release metadata")
+	}
+}
+
+// TestCheckForUpdateAPIError tests that API failures are reported
+func TestCheckForUpdateAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if _, err := CheckForUpdate(server.URL, "v0.1.0"); err == nil {
+		t.Error("Expected error for 500 response, got nil")
+	}
+}
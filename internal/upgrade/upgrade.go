@@ -6,10 +6,14 @@ import (
 	"bufio"
 	"compress/gzip"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"os/exec"
@@ -17,6 +21,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/cloudcwfranck/acc/internal/slsa"
 )
 
 // UpgradeOptions contains options for upgrade
@@ -28,6 +34,7 @@ type UpgradeOptions struct {
 	DisableInstall    bool   // If true, don't actually install (for testing)
 	CurrentVersion    string // Current version
 	CurrentExecutable string // Path to current executable
+	InstallDir        string // Install into this directory instead of replacing the current executable
 
 	// Supply-chain verification (opt-in)
 	VerifySignature  bool   // If true, verify cosign signature
@@ -43,7 +50,9 @@ type UpgradeResult struct {
 	Message            string `json:"message"`
 	AssetName          string `json:"assetName,omitempty"`
 	Checksum           string `json:"checksum,omitempty"`
+	ChecksumAlgorithm  string `json:"checksumAlgorithm,omitempty"` // sha256 or sha512
 	InstallPath        string `json:"installPath,omitempty"`
+	PathInstructions   string `json:"pathInstructions,omitempty"` // set when InstallDir is not on PATH
 	SignatureVerified  bool   `json:"signatureVerified,omitempty"`
 	ProvenanceVerified bool   `json:"provenanceVerified,omitempty"`
 }
@@ -129,8 +138,7 @@ func Upgrade(opts *UpgradeOptions) (*UpgradeResult, error) {
 	}
 
 	// Verify checksum
-	checksumsURL := buildChecksumsURL(opts.DownloadBase, release.TagName)
-	checksums, err := fetchChecksums(checksumsURL)
+	checksums, checksumsFile, err := fetchReleaseChecksums(opts.DownloadBase, release.TagName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch checksums: %w", err)
 	}
@@ -140,7 +148,8 @@ func Upgrade(opts *UpgradeOptions) (*UpgradeResult, error) {
 		return nil, fmt.Errorf("checksum not found for %s", asset.Name)
 	}
 
-	actualChecksum, err := computeSHA256(archivePath)
+	algorithm := checksumAlgorithm(checksumsFile, expectedChecksum)
+	actualChecksum, err := computeChecksum(archivePath, algorithm)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute checksum: %w", err)
 	}
@@ -150,6 +159,7 @@ func Upgrade(opts *UpgradeOptions) (*UpgradeResult, error) {
 	}
 
 	result.Checksum = actualChecksum
+	result.ChecksumAlgorithm = algorithm
 
 	// Optional: Verify cosign signature (opt-in)
 	if opts.VerifySignature {
@@ -186,7 +196,14 @@ func Upgrade(opts *UpgradeOptions) (*UpgradeResult, error) {
 	// Install binary (if not disabled for testing)
 	if !opts.DisableInstall {
 		installPath := opts.CurrentExecutable
-		if installPath == "" {
+		if opts.InstallDir != "" {
+			// --install-dir: install alongside (not over) the running executable
+			if err := os.MkdirAll(opts.InstallDir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create install directory: %w", installPermissionError(opts.InstallDir, err))
+			}
+			installPath = filepath.Join(opts.InstallDir, binaryName(runtime.GOOS))
+			result.PathInstructions = pathInstructions(opts.InstallDir, os.Getenv("PATH"))
+		} else if installPath == "" {
 			installPath, err = os.Executable()
 			if err != nil {
 				return nil, fmt.Errorf("failed to get executable path: %w", err)
@@ -273,12 +290,54 @@ func normalizeVersion(version string) string {
 	return version
 }
 
-// buildChecksumsURL builds the URL for the checksums.txt file
-func buildChecksumsURL(downloadBase, tag string) string {
-	return fmt.Sprintf("%s/cloudcwfranck/acc/releases/download/%s/checksums.txt", downloadBase, tag)
+// Checksum algorithms a release's checksum file may use
+const (
+	checksumSHA256 = "sha256"
+	checksumSHA512 = "sha512"
+)
+
+// checksumFiles are the checksum files a release may publish, in order of preference.
+// checksums.txt holds sha256 digests unless its digests are sha512-length.
+var checksumFiles = []string{"checksums.txt", "checksums_sha512.txt"}
+
+// buildChecksumsURL builds the URL for a release's checksum file (e.g. checksums.txt)
+func buildChecksumsURL(downloadBase, tag, name string) string {
+	return fmt.Sprintf("%s/cloudcwfranck/acc/releases/download/%s/%s", downloadBase, tag, name)
+}
+
+// fetchReleaseChecksums fetches the first of checksumFiles the release publishes and
+// returns its entries and its name
+func fetchReleaseChecksums(downloadBase, tag string) (map[string]string, string, error) {
+	var firstErr error
+	for _, name := range checksumFiles {
+		checksums, err := fetchChecksums(buildChecksumsURL(downloadBase, tag, name))
+		if err == nil {
+			return checksums, name, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, "", firstErr
+}
+
+// checksumAlgorithm detects the algorithm of a checksum file from its name
+// (checksums_sha512.txt) or, for a generic name like checksums.txt, the digest length.
+// SHA256 is the default.
+func checksumAlgorithm(fileName, digest string) string {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.Contains(name, checksumSHA512):
+		return checksumSHA512
+	case strings.Contains(name, checksumSHA256):
+		return checksumSHA256
+	case len(digest) == sha512.Size*2:
+		return checksumSHA512
+	}
+	return checksumSHA256
 }
 
-// fetchChecksums fetches and parses checksums.txt
+// fetchChecksums fetches and parses a checksum file ("<hex digest>  <filename>" lines)
 func fetchChecksums(url string) (map[string]string, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Get(url)
@@ -339,18 +398,32 @@ func downloadFile(url, dest string) error {
 
 // computeSHA256 computes the SHA256 checksum of a file
 func computeSHA256(path string) (string, error) {
+	return computeChecksum(path, checksumSHA256)
+}
+
+// computeChecksum computes the checksum of a file with algorithm (sha256 or sha512)
+func computeChecksum(path, algorithm string) (string, error) {
+	var hasher hash.Hash
+	switch algorithm {
+	case checksumSHA256:
+		hasher = sha256.New()
+	case checksumSHA512:
+		hasher = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return "", err
 	}
 	defer file.Close()
 
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
+	if _, err := io.Copy(hasher, file); err != nil {
 		return "", err
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
 // extractArchive extracts a tar.gz or zip archive
@@ -543,7 +616,8 @@ func installBinaryUnix(srcPath, destPath string) error {
 	// Write new binary to .new file
 	newPath := destPath + ".new"
 	if err := copyFile(srcPath, newPath); err != nil {
-		return err
+		os.Remove(newPath)
+		return installPermissionError(destPath, err)
 	}
 
 	// Make executable
@@ -559,12 +633,43 @@ func installBinaryUnix(srcPath, destPath string) error {
 		if _, backupErr := os.Stat(backupPath); backupErr == nil {
 			os.Rename(backupPath, destPath)
 		}
-		return err
+		return installPermissionError(destPath, err)
 	}
 
 	return nil
 }
 
+// installPermissionError turns EACCES/EPERM into guidance for root-owned installs
+// Other errors are returned unchanged
+func installPermissionError(destPath string, err error) error {
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	return fmt.Errorf("permission denied writing %s (acc is likely installed in a root-owned location)\n\nRemediation:\n  - Re-run with elevated privileges: sudo acc upgrade\n  - Or install to a user-writable directory: acc upgrade --install-dir ~/.local/bin (then add it to PATH)\n\nCause: %w", destPath, err)
+}
+
+// binaryName returns the acc executable name for goos
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "acc.exe"
+	}
+	return "acc"
+}
+
+// pathInstructions returns instructions for adding dir to PATH, or "" if it is already on pathEnv
+func pathInstructions(dir, pathEnv string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	for _, entry := range filepath.SplitList(pathEnv) {
+		if entryAbs, err := filepath.Abs(entry); err == nil && entryAbs == abs {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s is not on your PATH. Add it with:\n  export PATH=\"%s:$PATH\"\n(add this line to your shell profile, e.g. ~/.bashrc or ~/.zshrc)", abs, abs)
+}
+
 // installBinaryWindows handles Windows installation
 func installBinaryWindows(srcPath, destPath string) error {
 	// On Windows, we write to .new.exe and instruct user to replace
@@ -692,42 +797,16 @@ func verifySLSAProvenance(downloadBase, tag, assetName string) error {
 		return fmt.Errorf("no SLSA provenance found for this release (tried: provenance.intoto.jsonl, %s.intoto.jsonl, %s.intoto.jsonl): %v", tag, assetName, lastErr)
 	}
 
-	// Basic provenance validation
-	// Parse as JSON to ensure it's valid SLSA provenance
-	var provenance map[string]interface{}
-	if err := json.Unmarshal(provenanceData, &provenance); err != nil {
-		return fmt.Errorf("provenance file is not valid JSON: %w", err)
-	}
-
-	// Verify provenance structure (basic checks)
-	predicateType, ok := provenance["predicateType"].(string)
-	if !ok || predicateType == "" {
-		return fmt.Errorf("provenance missing predicateType field")
-	}
-
-	if !strings.Contains(predicateType, "slsa") && !strings.Contains(predicateType, "provenance") {
-		return fmt.Errorf("provenance predicateType is not SLSA provenance: %s", predicateType)
-	}
-
-	// Verify subject refers to the correct asset
-	predicate, ok := provenance["predicate"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("provenance missing predicate field")
-	}
-
-	// Check buildType
-	buildType, _ := predicate["buildType"].(string)
-	if buildType != "" && !strings.Contains(buildType, "github") {
-		return fmt.Errorf("provenance buildType is not GitHub Actions: %s", buildType)
+	// Basic provenance validation (structure, predicateType, predicate)
+	// Subjects are not checked: a single provenance file may cover all release assets
+	statement, err := slsa.ValidateStatement(provenanceData, "", "")
+	if err != nil {
+		return err
 	}
 
 	// Verify builder identity (should be GitHub Actions)
-	builder, ok := predicate["builder"].(map[string]interface{})
-	if ok {
-		builderID, _ := builder["id"].(string)
-		if builderID != "" && !strings.Contains(builderID, "github") {
-			return fmt.Errorf("provenance builder is not GitHub: %s", builderID)
-		}
+	if err := slsa.ValidateBuilderGitHub(statement); err != nil {
+		return err
 	}
 
 	// Note: Full cryptographic verification would require slsa-verifier or similar tool
@@ -3,12 +3,15 @@ package upgrade
 import (
 	"archive/tar"
 	"compress/gzip"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"syscall"
 	"testing"
 )
 
@@ -275,6 +278,72 @@ func TestComputeSHA256(t *testing.T) {
 	}
 }
 
+// TestChecksumAlgorithms tests parsing sha256 and sha512 checksum files and detecting
+// the algorithm from the file name or digest length
+func TestChecksumAlgorithms(t *testing.T) {
+	const assetName = "acc_0.2.7_linux_amd64.tar.gz"
+	archivePath := filepath.Join(t.TempDir(), assetName)
+	if err := os.WriteFile(archivePath, []byte("hello world"), 0644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	sha256Sum := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	sha512Sum := "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f" +
+		"989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f"
+
+	tests := []struct {
+		name       string
+		files      map[string]string // checksum files the release publishes
+		wantFile   string
+		wantAlgo   string
+		wantDigest string
+	}{
+		{"sha256 default", map[string]string{"checksums.txt": sha256Sum}, "checksums.txt", "sha256", sha256Sum},
+		{"sha512 file", map[string]string{"checksums_sha512.txt": sha512Sum}, "checksums_sha512.txt", "sha512", sha512Sum},
+		{"sha512 digests in checksums.txt", map[string]string{"checksums.txt": sha512Sum}, "checksums.txt", "sha512", sha512Sum},
+		{"checksums.txt preferred", map[string]string{"checksums.txt": sha256Sum, "checksums_sha512.txt": sha512Sum}, "checksums.txt", "sha256", sha256Sum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sum, ok := tt.files[filepath.Base(r.URL.Path)]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprintf(w, "%s  %s\n", sum, assetName)
+			}))
+			defer server.Close()
+
+			checksums, file, err := fetchReleaseChecksums(server.URL, "v0.2.7")
+			if err != nil {
+				t.Fatalf("fetchReleaseChecksums failed: %v", err)
+			}
+			if file != tt.wantFile {
+				t.Errorf("checksum file = %s, want %s", file, tt.wantFile)
+			}
+			expected := checksums[assetName]
+			algorithm := checksumAlgorithm(file, expected)
+			if algorithm != tt.wantAlgo {
+				t.Errorf("algorithm = %s, want %s", algorithm, tt.wantAlgo)
+			}
+
+			digest, err := computeChecksum(archivePath, algorithm)
+			if err != nil {
+				t.Fatalf("computeChecksum failed: %v", err)
+			}
+			wantLen := map[string]int{"sha256": 64, "sha512": 128}[tt.wantAlgo]
+			if len(digest) != wantLen || digest != tt.wantDigest || digest != expected {
+				t.Errorf("digest = %q (len %d), want %q (len %d)", digest, len(digest), tt.wantDigest, wantLen)
+			}
+		})
+	}
+
+	if _, err := computeChecksum(archivePath, "md5"); err == nil {
+		t.Error("expected an error for an unsupported algorithm")
+	}
+}
+
 // TestExtractTarGz tests tar.gz extraction
 func TestExtractTarGz(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "acc-extract-test-*")
@@ -877,3 +946,71 @@ func TestFindCosignBinary(t *testing.T) {
 		t.Errorf("Expected path to contain 'cosign', got: %s", path)
 	}
 }
+
+// TestInstallPermissionError tests that EACCES/EPERM become actionable guidance
+func TestInstallPermissionError(t *testing.T) {
+	denied := &os.PathError{Op: "open", Path: "/usr/local/bin/acc.new", Err: syscall.EACCES}
+	err := installPermissionError("/usr/local/bin/acc", denied)
+	for _, want := range []string{"permission denied", "sudo acc upgrade", "--install-dir", "PATH"} {
+		if !containsStr(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got: %v", want, err)
+		}
+	}
+	if !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("expected error to wrap the permission error, got: %v", err)
+	}
+
+	eperm := &os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.EPERM}
+	if err := installPermissionError("/usr/local/bin/acc", eperm); !containsStr(err.Error(), "--install-dir") {
+		t.Errorf("expected EPERM guidance, got: %v", err)
+	}
+
+	// Other errors pass through unchanged
+	other := fmt.Errorf("disk full")
+	if err := installPermissionError("/usr/local/bin/acc", other); err != other {
+		t.Errorf("expected non-permission error unchanged, got: %v", err)
+	}
+}
+
+// TestInstallBinaryUnix_PermissionDenied tests installing into a directory the user cannot write
+func TestInstallBinaryUnix_PermissionDenied(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions only")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root bypasses directory permissions")
+	}
+
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "acc-new")
+	os.WriteFile(src, []byte("new"), 0755)
+
+	installDir := filepath.Join(tmpDir, "root-owned")
+	os.MkdirAll(installDir, 0755)
+	dest := filepath.Join(installDir, "acc")
+	os.WriteFile(dest, []byte("old"), 0755)
+	os.Chmod(installDir, 0555)
+	defer os.Chmod(installDir, 0755)
+
+	err := installBinaryUnix(src, dest)
+	if err == nil {
+		t.Fatal("expected permission error")
+	}
+	if !containsStr(err.Error(), "--install-dir") || !containsStr(err.Error(), "sudo") {
+		t.Errorf("expected actionable guidance, got: %v", err)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "old" {
+		t.Errorf("existing binary should be untouched, got %q", data)
+	}
+}
+
+func TestPathInstructions(t *testing.T) {
+	dir := t.TempDir()
+
+	if got := pathInstructions(dir, "/usr/bin"+string(os.PathListSeparator)+dir); got != "" {
+		t.Errorf("expected no instructions when dir is on PATH, got %q", got)
+	}
+	if got := pathInstructions(dir, "/usr/bin"); !containsStr(got, "export PATH=") || !containsStr(got, dir) {
+		t.Errorf("expected PATH instructions for %s, got %q", dir, got)
+	}
+}
@@ -0,0 +1,137 @@
+package upgrade
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Check statuses reported by VerifyArchive
+const (
+	CheckPass    = "pass"
+	CheckFail    = "fail"
+	CheckSkipped = "skipped"
+)
+
+// VerifyCheck is the outcome of one verification step against a local archive
+type VerifyCheck struct {
+	Name    string `json:"name"`   // checksum, signature, provenance
+	Status  string `json:"status"` // pass, fail, skipped
+	Message string `json:"message,omitempty"`
+}
+
+// VerifyOnlyResult reports the checks run by acc upgrade --verify-only
+type VerifyOnlyResult struct {
+	ArchivePath string        `json:"archivePath"`
+	Version     string        `json:"version"`
+	AssetName   string        `json:"assetName"`
+	Checksum    string        `json:"checksum,omitempty"`          // digest of the local archive
+	Algorithm   string        `json:"checksumAlgorithm,omitempty"` // sha256 or sha512, from the release's checksum file
+	Verified    bool          `json:"verified"`                    // every check that ran passed
+	Checks      []VerifyCheck `json:"checks"`
+}
+
+// VerifyArchive runs the upgrade verification (checksum, and the signature and provenance
+// checks enabled in opts) against a release archive already on disk, without installing it.
+// opts.Version must name the release; the archive's file name must match its release asset.
+// Every check runs even after a failure, so the result shows the full picture; an error is
+// returned when any check fails.
+func VerifyArchive(opts *UpgradeOptions, archivePath string) (*VerifyOnlyResult, error) {
+	if opts.DownloadBase == "" {
+		opts.DownloadBase = "https://github.com"
+	}
+	if opts.Version == "" || opts.Version == "latest" {
+		return nil, fmt.Errorf("--verify-only requires --version <tag> (the release the archive was downloaded from)")
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		return nil, fmt.Errorf("cannot read archive: %w", err)
+	}
+
+	tag := normalizeVersion(opts.Version)
+	result := &VerifyOnlyResult{
+		ArchivePath: archivePath,
+		Version:     tag,
+		AssetName:   filepath.Base(archivePath),
+		Checks:      []VerifyCheck{},
+	}
+
+	// Verify a private copy: signature verification downloads sidecar files next to the
+	// archive and removes them afterwards, which must not touch files beside the user's download
+	tmpDir, err := os.MkdirTemp("", "acc-verify-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	copyPath := filepath.Join(tmpDir, result.AssetName)
+	if err := copyFile(archivePath, copyPath); err != nil {
+		return nil, fmt.Errorf("failed to copy archive: %w", err)
+	}
+
+	result.Checks = append(result.Checks, checkArchiveChecksum(result, copyPath, opts.DownloadBase, tag))
+
+	if opts.VerifySignature {
+		check := VerifyCheck{Name: "signature", Status: CheckPass, Message: "cosign signature verified"}
+		if err := verifyCosignSignature(copyPath, opts.DownloadBase, tag, opts.CosignKey); err != nil {
+			check.Status, check.Message = CheckFail, err.Error()
+		}
+		result.Checks = append(result.Checks, check)
+	} else {
+		result.Checks = append(result.Checks, VerifyCheck{Name: "signature", Status: CheckSkipped, Message: "enable with --verify-signature"})
+	}
+
+	if opts.VerifyProvenance {
+		check := VerifyCheck{Name: "provenance", Status: CheckPass, Message: "SLSA provenance verified"}
+		if err := verifySLSAProvenance(opts.DownloadBase, tag, result.AssetName); err != nil {
+			check.Status, check.Message = CheckFail, err.Error()
+		}
+		result.Checks = append(result.Checks, check)
+	} else {
+		result.Checks = append(result.Checks, VerifyCheck{Name: "provenance", Status: CheckSkipped, Message: "enable with --verify-provenance"})
+	}
+
+	failed := 0
+	for _, check := range result.Checks {
+		if check.Status == CheckFail {
+			failed++
+		}
+	}
+	result.Verified = failed == 0
+	if failed > 0 {
+		return result, fmt.Errorf("%d verification check(s) failed for %s", failed, result.AssetName)
+	}
+	return result, nil
+}
+
+// checkArchiveChecksum compares the archive's digest with the release's checksum file
+// (checksums.txt, or checksums_sha512.txt), using the algorithm that file was written with
+func checkArchiveChecksum(result *VerifyOnlyResult, path, downloadBase, tag string) VerifyCheck {
+	check := VerifyCheck{Name: "checksum"}
+
+	checksums, checksumsFile, err := fetchReleaseChecksums(downloadBase, tag)
+	if err != nil {
+		check.Status, check.Message = CheckFail, fmt.Sprintf("failed to fetch checksums: %v", err)
+		return check
+	}
+	expected, ok := checksums[result.AssetName]
+	if !ok {
+		check.Status, check.Message = CheckFail, fmt.Sprintf("checksum not found for %s in %s %s (is the file named as released?)", result.AssetName, tag, checksumsFile)
+		return check
+	}
+
+	result.Algorithm = checksumAlgorithm(checksumsFile, expected)
+	actual, err := computeChecksum(path, result.Algorithm)
+	if err != nil {
+		check.Status, check.Message = CheckFail, fmt.Sprintf("failed to compute checksum: %v", err)
+		return check
+	}
+	result.Checksum = actual
+
+	if actual != expected {
+		check.Status, check.Message = CheckFail, fmt.Sprintf("checksum mismatch: expected %s, got %s", expected, actual)
+		return check
+	}
+
+	check.Status, check.Message = CheckPass, fmt.Sprintf("%s matches %s", result.Algorithm, checksumsFile)
+	return check
+}
@@ -0,0 +1,121 @@
+package upgrade
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newReleaseServer serves checksums.txt, a signature, and provenance for release v0.2.7
+func newReleaseServer(t *testing.T, assetName string, archive []byte) *httptest.Server {
+	t.Helper()
+	sum := sha256.Sum256(archive)
+	provenance := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2",` +
+		`"subject":[{"name":"` + assetName + `","digest":{"sha256":"` + hex.EncodeToString(sum[:]) + `"}}],` +
+		`"predicate":{"builder":{"id":"https://github.com/actions/runner"}}}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := "/cloudcwfranck/acc/releases/download/v0.2.7/"
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "checksums.txt":
+			fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(sum[:]), assetName)
+		case assetName + ".sig":
+			w.Write([]byte("signature"))
+		case "provenance.intoto.jsonl":
+			w.Write([]byte(provenance))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestVerifyArchive tests verifying a good and a tampered local archive
+func TestVerifyArchive(t *testing.T) {
+	const assetName = "acc_0.2.7_linux_amd64.tar.gz"
+	archive := []byte("release archive contents")
+	server := newReleaseServer(t, assetName, archive)
+
+	// cosign stub: verify-blob succeeds
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "cosign"), []byte("#!/bin/sh\nexit 0\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	opts := func() *UpgradeOptions {
+		return &UpgradeOptions{
+			Version:          "0.2.7",
+			DownloadBase:     server.URL,
+			VerifySignature:  true,
+			CosignKey:        "cosign.pub",
+			VerifyProvenance: true,
+		}
+	}
+
+	t.Run("good archive", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, assetName)
+		os.WriteFile(path, archive, 0644)
+
+		result, err := VerifyArchive(opts(), path)
+		if err != nil {
+			t.Fatalf("VerifyArchive failed: %v (%+v)", err, result)
+		}
+		if !result.Verified || result.Version != "v0.2.7" || len(result.Checks) != 3 {
+			t.Fatalf("unexpected result %+v", result)
+		}
+		for _, check := range result.Checks {
+			if check.Status != CheckPass {
+				t.Errorf("check %s = %s (%s), want pass", check.Name, check.Status, check.Message)
+			}
+		}
+
+		// Nothing is installed or left beside the download
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			t.Errorf("expected only the archive in its directory, found %d entries", len(entries))
+		}
+	})
+
+	t.Run("tampered archive", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), assetName)
+		os.WriteFile(path, append(archive, []byte("backdoor")...), 0644)
+
+		result, err := VerifyArchive(opts(), path)
+		if err == nil || result == nil || result.Verified {
+			t.Fatalf("expected verification failure, got %v (%+v)", err, result)
+		}
+		if result.Checks[0].Name != "checksum" || result.Checks[0].Status != CheckFail || !strings.Contains(result.Checks[0].Message, "checksum mismatch") {
+			t.Errorf("expected checksum mismatch, got %+v", result.Checks[0])
+		}
+		// The remaining checks still ran
+		if len(result.Checks) != 3 || result.Checks[2].Name != "provenance" || result.Checks[2].Status != CheckPass {
+			t.Errorf("expected every check to be reported, got %+v", result.Checks)
+		}
+	})
+
+	t.Run("optional checks skipped", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), assetName)
+		os.WriteFile(path, archive, 0644)
+
+		result, err := VerifyArchive(&UpgradeOptions{Version: "v0.2.7", DownloadBase: server.URL}, path)
+		if err != nil || !result.Verified {
+			t.Fatalf("expected checksum-only verification to pass, got %v", err)
+		}
+		if result.Checks[1].Status != CheckSkipped || result.Checks[2].Status != CheckSkipped {
+			t.Errorf("expected signature and provenance skipped, got %+v", result.Checks)
+		}
+	})
+
+	t.Run("version required", func(t *testing.T) {
+		if _, err := VerifyArchive(&UpgradeOptions{DownloadBase: server.URL}, "missing.tar.gz"); err == nil || !strings.Contains(err.Error(), "--version") {
+			t.Errorf("expected --version error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// benchHotspots is how many of the slowest policy expressions acc policy bench reports
+const benchHotspots = 10
+
+// BenchResult is the output of acc policy bench. Times are in nanoseconds, averaged over Count runs.
+type BenchResult struct {
+	Pack     string        `json:"pack"`
+	Input    string        `json:"input"` // input file, or "sample" for the built-in sample input
+	Count    int           `json:"count"`
+	Queries  []QueryTiming `json:"queries"`
+	EvalNs   int64         `json:"evalNs"`   // sum of the queries' mean evaluation times
+	WallNs   int64         `json:"wallNs"`   // sum of the queries' mean opa eval run times
+	Hotspots []ExprTiming  `json:"hotspots"` // slowest expressions first (OPA profiler)
+}
+
+// QueryTiming is the evaluation time of one query
+type QueryTiming struct {
+	Query     string `json:"query"`
+	EvalNs    int64  `json:"evalNs"`    // mean query evaluation time reported by OPA
+	MinEvalNs int64  `json:"minEvalNs"` // fastest run
+	MaxEvalNs int64  `json:"maxEvalNs"` // slowest run
+	CompileNs int64  `json:"compileNs"` // mean module parse and query compile time
+	WallNs    int64  `json:"wallNs"`    // mean opa eval run time, including process start and loading
+}
+
+// ExprTiming is the time OPA spent in one policy expression
+type ExprTiming struct {
+	Location string `json:"location"` // file:row:col, relative to the pack
+	Query    string `json:"query"`
+	TimeNs   int64  `json:"timeNs"` // mean time per run
+	NumEval  int    `json:"numEval"`
+}
+
+// opaBenchOutput is the part of `opa eval --metrics --profile --format json` output bench reads
+type opaBenchOutput struct {
+	Metrics map[string]int64 `json:"metrics"`
+	Profile []struct {
+		TotalTimeNs int64 `json:"total_time_ns"`
+		NumEval     int   `json:"num_eval"`
+		Location    struct {
+			File string `json:"file"`
+			Row  int    `json:"row"`
+			Col  int    `json:"col"`
+		} `json:"location"`
+	} `json:"profile"`
+}
+
+// Bench evaluates the policy pack count times per query against the policy input in inputPath
+// (a sample input when empty) and reports the timings OPA measured. Queries default to the
+// decision document (policy.regoQuery); policy.data files are loaded as in verify.
+func Bench(cfg *config.Config, inputPath string, queries []string, count int) (*BenchResult, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("--count must be at least 1")
+	}
+
+	pack, err := policy.ResolvePack(cfg.Policy.Pack)
+	if err != nil {
+		return nil, err
+	}
+	defer pack.Cleanup()
+	if _, err := os.Stat(pack.Dir); err != nil {
+		return nil, fmt.Errorf("no policy to benchmark: %s does not exist", pack.Dir)
+	}
+
+	opaPath, violation := checkOPA()
+	if violation != nil {
+		return nil, fmt.Errorf("%s", violation.Message)
+	}
+
+	result := &BenchResult{Pack: pack.Dir, Input: inputPath, Count: count}
+	if pack.Builtin {
+		result.Pack = "builtin:" + pack.Name
+	}
+
+	if inputPath == "" {
+		result.Input = "sample"
+		inputPath, err = writeSampleInput()
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(inputPath)
+	} else if _, err := loadRegoInput(inputPath); err != nil {
+		return nil, err
+	}
+
+	dataPaths := []string{pack.Dir}
+	if len(cfg.Policy.Data) > 0 {
+		dataDir, err := writeExternalData(cfg.Policy.Data)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dataDir)
+		dataPaths = append(dataPaths, dataDir)
+	}
+
+	if len(queries) == 0 {
+		queries = []string{cfg.RegoQuery()}
+	}
+
+	hotspots := map[string]*ExprTiming{}
+	for _, query := range queries {
+		timing := QueryTiming{Query: query}
+		for run := 0; run < count; run++ {
+			output, wall, err := benchEval(opaPath, dataPaths, inputPath, query, cfg.RegoTimeout())
+			if err != nil {
+				return nil, err
+			}
+
+			// OPA omits timers it did not use; the run time stands in when it reports none
+			eval, ok := output.Metrics["timer_rego_query_eval_ns"]
+			if !ok {
+				eval = wall.Nanoseconds()
+			}
+			if run == 0 || eval < timing.MinEvalNs {
+				timing.MinEvalNs = eval
+			}
+			if eval > timing.MaxEvalNs {
+				timing.MaxEvalNs = eval
+			}
+			timing.EvalNs += eval
+			timing.CompileNs += output.Metrics["timer_rego_module_parse_ns"] + output.Metrics["timer_rego_query_compile_ns"]
+			timing.WallNs += wall.Nanoseconds()
+
+			for _, expr := range output.Profile {
+				file := expr.Location.File
+				if rel, err := filepath.Rel(pack.Dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+					file = rel
+				}
+				location := fmt.Sprintf("%s:%d:%d", file, expr.Location.Row, expr.Location.Col)
+				key := query + " " + location
+				if hotspots[key] == nil {
+					hotspots[key] = &ExprTiming{Location: location, Query: query}
+				}
+				hotspots[key].TimeNs += expr.TotalTimeNs
+				hotspots[key].NumEval += expr.NumEval
+			}
+		}
+
+		timing.EvalNs /= int64(count)
+		timing.CompileNs /= int64(count)
+		timing.WallNs /= int64(count)
+		result.Queries = append(result.Queries, timing)
+		result.EvalNs += timing.EvalNs
+		result.WallNs += timing.WallNs
+	}
+
+	result.Hotspots = []ExprTiming{}
+	for _, expr := range hotspots {
+		expr.TimeNs /= int64(count)
+		expr.NumEval /= count
+		result.Hotspots = append(result.Hotspots, *expr)
+	}
+	sort.Slice(result.Hotspots, func(i, j int) bool {
+		if result.Hotspots[i].TimeNs != result.Hotspots[j].TimeNs {
+			return result.Hotspots[i].TimeNs > result.Hotspots[j].TimeNs
+		}
+		return result.Hotspots[i].Location < result.Hotspots[j].Location
+	})
+	if len(result.Hotspots) > benchHotspots {
+		result.Hotspots = result.Hotspots[:benchHotspots]
+	}
+
+	return result, nil
+}
+
+// benchEval runs one timed opa eval of query and returns its metrics and profile with the run time
+func benchEval(opaPath string, dataPaths []string, inputPath, query string, timeout time.Duration) (*opaBenchOutput, time.Duration, error) {
+	args := []string{"eval"}
+	for _, path := range dataPaths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, "--input", inputPath, "--format", "json", "--metrics", "--profile", query)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, opaPath, args...)
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	data, err := cmd.Output()
+	wall := time.Since(start)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, 0, fmt.Errorf("evaluating %s did not finish within %s", query, timeout)
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, 0, fmt.Errorf("OPA evaluation of %s failed: %s", query, string(exitErr.Stderr))
+		}
+		return nil, 0, fmt.Errorf("OPA evaluation of %s failed: %w", query, err)
+	}
+
+	var output opaBenchOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse OPA output: %w", err)
+	}
+	return &output, wall, nil
+}
+
+// writeSampleInput writes the input policy bench uses without --input: a non-root image with
+// an SBOM and common labels, to a temporary file
+func writeSampleInput() (string, error) {
+	input := RegoInput{
+		Config: ImageConfig{
+			User: "1000",
+			Labels: map[string]string{
+				"org.opencontainers.image.source":  "https://github.com/example/app",
+				"org.opencontainers.image.version": "1.0.0",
+			},
+			Env:          []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"},
+			ExposedPorts: map[string]struct{}{"8080/tcp": {}},
+		},
+		SBOM: SBOMInfo{Present: true},
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp("", "acc-bench-input-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write sample input: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// Print prints the timings for humans
+func (r *BenchResult) Print() {
+	ui.PrintInfo(fmt.Sprintf("Policy evaluation time for %s (%s input, %d run(s) per query):", r.Pack, r.Input, r.Count))
+	for _, q := range r.Queries {
+		fmt.Printf("  %s\n", q.Query)
+		fmt.Printf("      eval %s (min %s, max %s), compile %s, opa run %s\n",
+			time.Duration(q.EvalNs), time.Duration(q.MinEvalNs), time.Duration(q.MaxEvalNs),
+			time.Duration(q.CompileNs), time.Duration(q.WallNs))
+	}
+	fmt.Printf("  Total: eval %s, opa run %s\n", time.Duration(r.EvalNs), time.Duration(r.WallNs))
+
+	if len(r.Hotspots) == 0 {
+		return
+	}
+	fmt.Println()
+	ui.PrintInfo("Slowest expressions:")
+	for _, expr := range r.Hotspots {
+		fmt.Printf("  %-40s %10s  (%d eval(s))\n", expr.Location, time.Duration(expr.TimeNs), expr.NumEval)
+	}
+}
@@ -0,0 +1,72 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// TestBench tests that policy bench reports OPA's evaluation timings per query and the
+// slowest expressions relative to the pack
+func TestBench(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	os.MkdirAll(filepath.Join(".acc", "policy"), 0755)
+	os.WriteFile(filepath.Join(".acc", "policy", "policy.rego"), []byte("package acc.policy\n"), 0644)
+	os.WriteFile("input.json", []byte(`{"config":{"User":"root","Labels":{}},"sbom":{"present":true}}`), 0644)
+
+	// opa eval --metrics --profile output for a policy with two expressions
+	binDir := t.TempDir()
+	opa := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+case "$*" in *--metrics*--profile*) ;; *) echo "missing --metrics --profile" >&2; exit 1 ;; esac
+echo '{"result":[{"expressions":[{"value":{"violations":[]}}]}],
+ "metrics":{"timer_rego_query_eval_ns":2000,"timer_rego_query_compile_ns":300,"timer_rego_module_parse_ns":200},
+ "profile":[{"total_time_ns":500,"num_eval":2,"location":{"file":".acc/policy/policy.rego","row":3,"col":1}},
+            {"total_time_ns":1500,"num_eval":1,"location":{"file":".acc/policy/policy.rego","row":7,"col":5}}]}'
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(opa), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.DefaultConfig("bench-test")
+	result, err := Bench(cfg, "input.json", []string{"data.acc.policy.result", "data.acc.policy.deny"}, 3)
+	if err != nil {
+		t.Fatalf("Bench failed: %v", err)
+	}
+
+	if result.Input != "input.json" || result.Count != 3 || len(result.Queries) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	for _, q := range result.Queries {
+		if q.EvalNs != 2000 || q.MinEvalNs != 2000 || q.MaxEvalNs != 2000 || q.CompileNs != 500 || q.WallNs <= 0 {
+			t.Errorf("unexpected timing for %s: %+v", q.Query, q)
+		}
+	}
+	if result.EvalNs != 4000 || result.WallNs <= 0 {
+		t.Errorf("expected total eval 4000ns and a run time, got %d, %d", result.EvalNs, result.WallNs)
+	}
+	if len(result.Hotspots) != 4 {
+		t.Fatalf("expected one hotspot per expression and query, got %+v", result.Hotspots)
+	}
+	if top := result.Hotspots[0]; top.Location != "policy.rego:7:5" || top.TimeNs != 1500 || top.NumEval != 1 {
+		t.Errorf("expected the slowest expression first, got %+v", top)
+	}
+
+	// Without --input, the sample input is evaluated
+	result, err = Bench(cfg, "", nil, 1)
+	if err != nil {
+		t.Fatalf("Bench with the sample input failed: %v", err)
+	}
+	if result.Input != "sample" || len(result.Queries) != 1 || result.Queries[0].Query != cfg.RegoQuery() {
+		t.Errorf("expected the sample input and the decision query, got %+v", result)
+	}
+
+	if _, err := Bench(cfg, "input.json", nil, 0); err == nil {
+		t.Error("expected a count of 0 to be rejected")
+	}
+}
@@ -0,0 +1,193 @@
+package verify
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/profile"
+	"gopkg.in/yaml.v3"
+)
+
+// Evidence bundle member names
+const (
+	bundleManifestName = "manifest.json"
+	bundleResultName   = "verify-result.json"
+	bundleProfileName  = "profile.yaml"
+	bundleSBOMDir      = "sbom"
+)
+
+// BundleOptions contains options for writing an evidence bundle
+type BundleOptions struct {
+	Sign      bool   // If true, sign the bundle with cosign sign-blob
+	CosignKey string // Path/URL to cosign private key (optional, keyless if empty)
+}
+
+// BundleManifest describes the contents of an evidence bundle
+type BundleManifest struct {
+	SchemaVersion string   `json:"schemaVersion"`
+	CreatedAt     string   `json:"createdAt"`
+	ImageRef      string   `json:"imageRef"`
+	Status        string   `json:"status"`
+	PolicyPack    string   `json:"policyPack"`
+	PolicyHash    string   `json:"policyHash"` // sha256 over .rego files in the policy pack
+	SBOM          string   `json:"sbom,omitempty"`
+	Profile       string   `json:"profile,omitempty"`
+	Members       []string `json:"members"`
+}
+
+// BundleResult describes a written evidence bundle
+type BundleResult struct {
+	Path        string `json:"path"`
+	Signature   string `json:"signature,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+}
+
+// WriteBundle writes a tar evidence bundle containing the verify result, SBOM,
+// policy pack hash, and profile, optionally signed with cosign
+func WriteBundle(path string, cfg *config.Config, imageRef string, result *VerifyResult, prof *profile.Profile, opts BundleOptions) (*BundleResult, error) {
+	pack, err := policy.ResolvePack(cfg.Policy.Pack)
+	if err != nil {
+		return nil, err
+	}
+	defer pack.Cleanup()
+	policyHash, err := policy.PackHash(pack.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash policy pack: %w", err)
+	}
+	policyDir := pack.Dir
+	if pack.Builtin {
+		policyDir = "builtin:" + pack.Name
+	}
+
+	resultData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal verify result: %w", err)
+	}
+
+	manifest := BundleManifest{
+		SchemaVersion: "v0.1",
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		ImageRef:      imageRef,
+		Status:        result.Status,
+		PolicyPack:    policyDir,
+		PolicyHash:    policyHash,
+		Members:       []string{bundleManifestName, bundleResultName},
+	}
+	members := map[string][]byte{bundleResultName: resultData}
+
+	if sbomFile := findSBOMFile(cfg); sbomFile != "" {
+		data, err := os.ReadFile(sbomFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SBOM: %w", err)
+		}
+		name := bundleSBOMDir + "/" + filepath.Base(sbomFile)
+		manifest.SBOM = name
+		manifest.Members = append(manifest.Members, name)
+		members[name] = data
+	}
+
+	if prof != nil {
+		data, err := yaml.Marshal(prof)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal profile: %w", err)
+		}
+		manifest.Profile = prof.Name
+		manifest.Members = append(manifest.Members, bundleProfileName)
+		members[bundleProfileName] = data
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle manifest: %w", err)
+	}
+	members[bundleManifestName] = manifestData
+
+	if err := writeTar(path, manifest.Members, members); err != nil {
+		return nil, err
+	}
+
+	bundle := &BundleResult{Path: path}
+	if opts.Sign {
+		if err := signBundle(bundle, opts.CosignKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return bundle, nil
+}
+
+// writeTar writes members to a tar archive in the given order
+func writeTar(path string, order []string, members map[string][]byte) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create bundle directory: %w", err)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	modTime := time.Now().UTC()
+	for _, name := range order {
+		data := members[name]
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write bundle member %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write bundle member %s: %w", name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	return f.Close()
+}
+
+// signBundle signs the bundle with cosign sign-blob, writing <bundle>.sig (and <bundle>.pem for keyless)
+func signBundle(bundle *BundleResult, cosignKey string) error {
+	cosignPath, err := findCosign("--sign")
+	if err != nil {
+		return err
+	}
+
+	sigPath := bundle.Path + ".sig"
+	args := []string{"sign-blob", "--yes", "--output-signature", sigPath}
+
+	certPath := ""
+	if cosignKey != "" {
+		// Key-based signing
+		args = append(args, "--key", cosignKey)
+	} else {
+		// Keyless signing (certificate is needed for verification)
+		certPath = bundle.Path + ".pem"
+		args = append(args, "--output-certificate", certPath)
+	}
+	args = append(args, bundle.Path)
+
+	cmd := exec.Command(cosignPath, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cosign signing failed: %w\nOutput: %s", err, string(output))
+	}
+
+	bundle.Signature = sigPath
+	bundle.Certificate = certPath
+	return nil
+}
@@ -0,0 +1,157 @@
+package verify
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/profile"
+)
+
+// readBundle returns the members of a tar bundle in order
+func readBundle(t *testing.T, path string) ([]string, map[string][]byte) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open bundle: %v", err)
+	}
+	defer f.Close()
+
+	var names []string
+	members := make(map[string][]byte)
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid bundle: %v", err)
+		}
+		data, _ := io.ReadAll(tr)
+		names = append(names, header.Name)
+		members[header.Name] = data
+	}
+	return names, members
+}
+
+func setupBundleProject(t *testing.T) *config.Config {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	os.MkdirAll(filepath.Join(".acc", "sbom"), 0755)
+	os.MkdirAll(filepath.Join(".acc", "policy"), 0755)
+	os.WriteFile(filepath.Join(".acc", "sbom", "demo.spdx.json"), []byte(`{"spdxVersion":"SPDX-2.3"}`), 0644)
+	os.WriteFile(filepath.Join(".acc", "policy", "default.rego"), []byte("package acc.policy\n"), 0644)
+
+	return config.DefaultConfig("demo")
+}
+
+// TestWriteBundle tests that the bundle contains the expected members
+func TestWriteBundle(t *testing.T) {
+	cfg := setupBundleProject(t)
+	result := &VerifyResult{Status: "pass", SBOMPresent: true, PolicyResult: &PolicyResult{Allow: true}}
+	prof := &profile.Profile{SchemaVersion: 1, Name: "baseline"}
+
+	bundle, err := WriteBundle(filepath.Join("out", "evidence.tar"), cfg, "demo:latest", result, prof, BundleOptions{})
+	if err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+	if bundle.Signature != "" {
+		t.Errorf("unsigned bundle should have no signature, got %s", bundle.Signature)
+	}
+
+	names, members := readBundle(t, bundle.Path)
+	want := []string{"manifest.json", "verify-result.json", "sbom/demo.spdx.json", "profile.yaml"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("bundle members = %v, want %v", names, want)
+	}
+
+	var manifest BundleManifest
+	if err := json.Unmarshal(members["manifest.json"], &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.ImageRef != "demo:latest" || manifest.Status != "pass" || manifest.Profile != "baseline" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if !strings.HasPrefix(manifest.PolicyHash, "sha256:") {
+		t.Errorf("policyHash = %q, want sha256 digest", manifest.PolicyHash)
+	}
+	if string(members["sbom/demo.spdx.json"]) != `{"spdxVersion":"SPDX-2.3"}` {
+		t.Errorf("SBOM member content mismatch: %s", members["sbom/demo.spdx.json"])
+	}
+
+	var decoded VerifyResult
+	if err := json.Unmarshal(members["verify-result.json"], &decoded); err != nil || decoded.Status != "pass" {
+		t.Errorf("verify-result.json invalid (err=%v): %s", err, members["verify-result.json"])
+	}
+
+	// Policy hash changes with policy content
+	before := manifest.PolicyHash
+	os.WriteFile(filepath.Join(".acc", "policy", "default.rego"), []byte("package acc.policy\n\ndeny[msg] { false }\n"), 0644)
+	after, _ := policy.PackHash(filepath.Join(".acc", "policy"))
+	if after == before {
+		t.Error("policy hash should change when policy content changes")
+	}
+}
+
+// TestWriteBundle_Signed tests that a signed bundle verifies with cosign
+func TestWriteBundle_Signed(t *testing.T) {
+	cfg := setupBundleProject(t)
+
+	// Fake cosign: the "signature" is the sha256 of the blob, so verify-blob detects tampering
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+cmd="$1"; shift
+sig=""
+while [ $# -gt 1 ]; do
+  case "$1" in
+    --output-signature|--signature) sig="$2"; shift ;;
+    --key|--output-certificate) shift ;;
+  esac
+  shift
+done
+blob="$1"
+case "$cmd" in
+  sign-blob) sha256sum "$blob" | cut -d' ' -f1 > "$sig" ;;
+  verify-blob) [ "$(sha256sum "$blob" | cut -d' ' -f1)" = "$(cat "$sig")" ] || { echo "invalid signature"; exit 1; } ;;
+  *) exit 2 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "cosign"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	result := &VerifyResult{Status: "pass", PolicyResult: &PolicyResult{Allow: true}}
+	bundle, err := WriteBundle("evidence.tar", cfg, "demo:latest", result, nil, BundleOptions{Sign: true, CosignKey: "cosign.key"})
+	if err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+	if bundle.Signature != "evidence.tar.sig" {
+		t.Fatalf("signature = %q, want evidence.tar.sig", bundle.Signature)
+	}
+
+	verify := func() error {
+		return exec.Command("cosign", "verify-blob", "--key", "cosign.pub", "--signature", bundle.Signature, bundle.Path).Run()
+	}
+	if err := verify(); err != nil {
+		t.Errorf("signed bundle failed cosign verification: %v", err)
+	}
+
+	// Tampering invalidates the signature
+	os.WriteFile(bundle.Path, []byte("tampered"), 0644)
+	if err := verify(); err == nil {
+		t.Error("expected verification to fail for a tampered bundle")
+	}
+}
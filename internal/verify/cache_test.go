@@ -0,0 +1,100 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/cache"
+)
+
+// fakeCountingOPA installs an opa that records each evaluation and reports one violation
+func fakeCountingOPA(t *testing.T) string {
+	t.Helper()
+	binDir := t.TempDir()
+	countFile := filepath.Join(binDir, "count")
+	script := `if [ "$1" = "version" ]; then echo '{"Version":"1.0.0"}'; exit 0; fi
+echo x >> ` + countFile + `
+echo '{"result":[{"expressions":[{"value":{"violations":[{"rule":"no-root-user","severity":"critical","result":"fail","message":"runs as root"}]}}]}]}'
+`
+	if err := os.WriteFile(filepath.Join(binDir, "opa"), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("failed to write fake opa: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return countFile
+}
+
+func opaInvocations(t *testing.T, countFile string) int {
+	t.Helper()
+	data, err := os.ReadFile(countFile)
+	if err != nil {
+		return 0
+	}
+	return strings.Count(string(data), "x")
+}
+
+// TestEvaluateRego_SharedCacheDir tests that two runs pointing at the same cache dir reuse the evaluation
+func TestEvaluateRego_SharedCacheDir(t *testing.T) {
+	countFile := fakeCountingOPA(t)
+	t.Setenv(cache.EnvDir, "")
+	defer cache.SetDir("")
+
+	policyDir := t.TempDir()
+	os.WriteFile(filepath.Join(policyDir, "policy.rego"), []byte("package acc.policy\n"), 0644)
+	cacheDir := t.TempDir()
+
+	input := &RegoInput{Config: ImageConfig{User: "root", Labels: map[string]string{}}}
+
+	// Two invocations, each configuring the cache dir as a fresh run would
+	for i := 0; i < 2; i++ {
+		cache.SetDir(cacheDir)
+		violations, err := evaluateRego(policyDir, input)
+		if err != nil {
+			t.Fatalf("run %d: evaluateRego failed: %v", i+1, err)
+		}
+		if len(violations) != 1 || violations[0].Rule != "no-root-user" {
+			t.Fatalf("run %d: unexpected violations: %+v", i+1, violations)
+		}
+	}
+	if n := opaInvocations(t, countFile); n != 1 {
+		t.Errorf("expected opa to run once with a shared cache dir, ran %d times", n)
+	}
+
+	// Changing the policy content must miss the cache
+	os.WriteFile(filepath.Join(policyDir, "policy.rego"), []byte("package acc.policy\n# changed\n"), 0644)
+	if _, err := evaluateRego(policyDir, input); err != nil {
+		t.Fatalf("evaluateRego failed: %v", err)
+	}
+	if n := opaInvocations(t, countFile); n != 2 {
+		t.Errorf("expected policy change to miss the cache, opa ran %d times", n)
+	}
+
+	// Changing the input must miss the cache
+	input.Config.User = "1000"
+	if _, err := evaluateRego(policyDir, input); err != nil {
+		t.Fatalf("evaluateRego failed: %v", err)
+	}
+	if n := opaInvocations(t, countFile); n != 3 {
+		t.Errorf("expected input change to miss the cache, opa ran %d times", n)
+	}
+}
+
+// TestEvaluateRego_NoCacheByDefault tests that evaluations are not cached unless a cache dir is set
+func TestEvaluateRego_NoCacheByDefault(t *testing.T) {
+	countFile := fakeCountingOPA(t)
+	t.Setenv(cache.EnvDir, "")
+	cache.SetDir("")
+
+	policyDir := t.TempDir()
+	input := &RegoInput{Config: ImageConfig{Labels: map[string]string{}}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := evaluateRego(policyDir, input); err != nil {
+			t.Fatalf("evaluateRego failed: %v", err)
+		}
+	}
+	if n := opaInvocations(t, countFile); n != 2 {
+		t.Errorf("expected opa to run on every evaluation without a cache dir, ran %d times", n)
+	}
+}
@@ -0,0 +1,116 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CommentMarker opens every --comment-out body, so a CI bot can find and update its
+// previous comment instead of posting a new one per push
+const CommentMarker = "<!-- acc-verify -->"
+
+// maxCommentRows bounds each table in a comment; PR comments have a size limit
+// (65536 characters on GitHub) and a long table is unreadable anyway
+const maxCommentRows = 50
+
+// statusBadges are the leading emoji of the comment heading, per status
+var statusBadges = map[string]string{
+	"pass": "✅",
+	"warn": "⚠️",
+	"fail": "❌",
+}
+
+// FormatMarkdown renders the result as a Markdown PR comment body (verify --comment-out):
+// a status heading, a table of the SBOM, policy, and attestation checks, and tables of the
+// violations and warnings, most severe first
+func (r *VerifyResult) FormatMarkdown(imageRef string) string {
+	if r == nil {
+		r = &VerifyResult{Status: "fail"}
+	}
+	var b strings.Builder
+
+	badge := statusBadges[r.Status]
+	if badge == "" {
+		badge = "❔"
+	}
+	fmt.Fprintf(&b, "%s\n## %s acc verify: %s\n\n", CommentMarker, badge, strings.ToUpper(r.Status))
+	fmt.Fprintf(&b, "**Image:** `%s`\n", markdownCell(imageRef))
+	profileUsed := r.ProfileUsed
+	if profileUsed == "" {
+		profileUsed = "none"
+	}
+	fmt.Fprintf(&b, "**Profile:** %s\n", markdownCell(profileUsed))
+	if r.Env != "" {
+		fmt.Fprintf(&b, "**Environment:** %s\n", markdownCell(r.Env))
+	}
+	if r.Team != "" {
+		fmt.Fprintf(&b, "**Team:** %s\n", markdownCell(r.Team))
+	}
+
+	b.WriteString("\n| Check | Result |\n|---|---|\n")
+	sbomStatus := "❌ missing"
+	if r.SBOMPresent {
+		sbomStatus = "✅ present"
+		if r.SBOMFormat != "" {
+			sbomStatus += " (" + r.SBOMFormat + ")"
+		}
+	}
+	fmt.Fprintf(&b, "| SBOM | %s |\n", sbomStatus)
+	fmt.Fprintf(&b, "| Policy | %s |\n", r.policyCheckSummary())
+	attestations := "none"
+	if len(r.Attestations) > 0 {
+		attestations = fmt.Sprintf("%d", len(r.Attestations))
+	}
+	fmt.Fprintf(&b, "| Attestations | %s |\n", attestations)
+
+	writeViolationTable(&b, "Violations", r.Violations)
+	if r.PolicyResult != nil {
+		writeViolationTable(&b, "Warnings", r.PolicyResult.Warnings)
+	}
+
+	return b.String()
+}
+
+// policyCheckSummary describes the policy outcome for the checks table
+func (r *VerifyResult) policyCheckSummary() string {
+	switch {
+	case r.PolicyUnevaluated:
+		return "⚠️ not evaluated (opa not installed)"
+	case len(r.Violations) > 0:
+		return fmt.Sprintf("❌ %d violation(s)", len(r.Violations))
+	case r.PolicyResult != nil && len(r.PolicyResult.Warnings) > 0:
+		return fmt.Sprintf("⚠️ %d warning(s)", len(r.PolicyResult.Warnings))
+	}
+	return "✅ passed"
+}
+
+// writeViolationTable writes a titled severity/rule/message table, omitted when empty
+func writeViolationTable(b *strings.Builder, title string, violations []PolicyViolation) {
+	if len(violations) == 0 {
+		return
+	}
+	shown, more := violationsForDisplay(violations, maxCommentRows)
+
+	fmt.Fprintf(b, "\n### %s (%d)\n\n| Severity | Rule | Message |\n|---|---|---|\n", title, len(violations))
+	for _, v := range shown {
+		fmt.Fprintf(b, "| %s | `%s` | %s |\n", markdownCell(v.Severity), markdownCell(v.Rule), markdownCell(v.Message))
+	}
+	if more > 0 {
+		fmt.Fprintf(b, "\n_+%d more; run `acc verify --json` for the full list._\n", more)
+	}
+}
+
+// markdownCell makes s safe inside a table cell: one line, with pipes escaped
+func markdownCell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// WriteComment writes the FormatMarkdown body for result to path
+func WriteComment(path, imageRef string, result *VerifyResult) error {
+	if err := os.WriteFile(path, []byte(result.FormatMarkdown(imageRef)), 0644); err != nil {
+		return fmt.Errorf("failed to write comment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFormatMarkdownGolden tests the --comment-out Markdown body against golden files
+func TestFormatMarkdownGolden(t *testing.T) {
+	tests := []struct {
+		name       string
+		goldenFile string
+		result     *VerifyResult
+	}{
+		{
+			name:       "pass",
+			goldenFile: "testdata/golden/verify/comment-pass.md",
+			result: &VerifyResult{
+				Status:       "pass",
+				SBOMPresent:  true,
+				SBOMFormat:   "spdx",
+				PolicyResult: &PolicyResult{Allow: true, Violations: []PolicyViolation{}, Warnings: []PolicyViolation{}},
+				Attestations: []string{".acc/attestations/abc/attestation.json"},
+				Violations:   []PolicyViolation{},
+			},
+		},
+		{
+			name:       "fail",
+			goldenFile: "testdata/golden/verify/comment-fail.md",
+			result: &VerifyResult{
+				Status:      "fail",
+				SBOMPresent: true,
+				SBOMFormat:  "cyclonedx",
+				PolicyResult: &PolicyResult{
+					Allow: false,
+					Warnings: []PolicyViolation{
+						{Rule: "no-healthcheck", Severity: "medium", Result: "warn", Message: "Image defines no HEALTHCHECK"},
+					},
+				},
+				Attestations: []string{},
+				Violations: []PolicyViolation{
+					{Rule: "require-healthcheck", Severity: "medium", Result: "fail", Message: "Container must define healthcheck"},
+					{Rule: "no-root-user", Severity: "high", Result: "fail", Message: "Container must not run as root"},
+					{Rule: "allowed-registries", Severity: "critical", Result: "fail", Message: "Registry not in allow list: docker.io|quay.io\nsee policy"},
+				},
+				ProfileUsed: "strict",
+				Env:         "prod",
+				Team:        "payments",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.result.FormatMarkdown("ghcr.io/org/app:1.0")
+
+			golden, err := os.ReadFile(filepath.Join("../..", tt.goldenFile))
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if actual != string(golden) {
+				t.Errorf("Markdown mismatch\n--- got ---\n%s\n--- want ---\n%s", actual, golden)
+			}
+		})
+	}
+}
+
+// TestFormatMarkdown_TruncatesLongTables tests that a comment lists at most maxCommentRows violations
+func TestFormatMarkdown_TruncatesLongTables(t *testing.T) {
+	result := &VerifyResult{Status: "fail"}
+	for i := 0; i < maxCommentRows+5; i++ {
+		result.Violations = append(result.Violations, PolicyViolation{Rule: "rule", Severity: "low", Result: "fail", Message: "denied"})
+	}
+
+	body := result.FormatMarkdown("app:1.0")
+	if rows := strings.Count(body, "| low | `rule` |"); rows != maxCommentRows {
+		t.Errorf("expected %d violation rows, got %d", maxCommentRows, rows)
+	}
+	if !strings.Contains(body, "_+5 more;") {
+		t.Errorf("expected a note for the omitted violations in:\n%s", body)
+	}
+}
+
+// TestWriteComment tests that the comment body is written to the --comment-out path
+func TestWriteComment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "comment.md")
+	result := &VerifyResult{Status: "warn", PolicyResult: &PolicyResult{}}
+	if err := WriteComment(path, "app:1.0", result); err != nil {
+		t.Fatalf("WriteComment failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), CommentMarker+"\n## ⚠️ acc verify: WARN") {
+		t.Errorf("unexpected comment:\n%s", data)
+	}
+}
@@ -0,0 +1,129 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/cloudcwfranck/acc/internal/cache"
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// ConfigBundleOptions control how verify --config-from-oci pulls and checks a bundle
+type ConfigBundleOptions struct {
+	CosignKey string // verify the bundle's cosign signature with this public key (--config-oci-key)
+	CacheDir  string // where bundles are cached (default: <cache dir>/config-bundles or .acc/cache/config-bundles)
+	Reuse     bool   // pull once per process, and use a cached digest-pinned bundle offline (--rego-bundle-cache)
+}
+
+var (
+	loadedMu      sync.Mutex
+	loadedBundles = map[string]*oci.ConfigBundle{} // bundles pulled and checked in this process (Reuse)
+)
+
+// configBundleCacheDir is the default bundle cache: the shared cache when one is set, so
+// repositories gated by the same standard pull it once
+func configBundleCacheDir() string {
+	if dir := cache.Dir(); dir != "" {
+		return filepath.Join(dir, "config-bundles")
+	}
+	return filepath.Join(".acc", "cache", "config-bundles")
+}
+
+// LoadConfigBundle pulls a central acc.yaml and policy pack from ref (see oci.PullConfigBundle)
+// and returns the config it defines. When the bundle has policies, policy.pack points at them,
+// so a repository with no .acc of its own is gated by the bundle alone.
+func LoadConfigBundle(ref string, opts ConfigBundleOptions) (*config.Config, *oci.ConfigBundle, error) {
+	registry, repository, reference, err := oci.ParseReference(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	repo, err := oci.NewRepository(registry, repository)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loadConfigBundle(context.Background(), repo, registry+"/"+repository, reference, opts)
+}
+
+// loadConfigBundle pulls reference from repo (named repoName for cosign) and loads its config
+func loadConfigBundle(ctx context.Context, repo *remote.Repository, repoName, reference string, opts ConfigBundleOptions) (*config.Config, *oci.ConfigBundle, error) {
+	cacheDir := opts.CacheDir
+	if cacheDir == "" {
+		cacheDir = configBundleCacheDir()
+	}
+
+	bundle, err := pullConfigBundle(ctx, repo, repoName, reference, cacheDir, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := config.Load(bundle.ConfigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid acc.yaml in config bundle %s: %w", bundle.Digest, err)
+	}
+	if policies, _ := filepath.Glob(filepath.Join(bundle.PolicyDir, "*.rego")); len(policies) > 0 {
+		cfg.Policy.Pack = bundle.PolicyDir
+	}
+	return cfg, bundle, nil
+}
+
+// pullConfigBundle pulls and signature-checks reference. With opts.Reuse, a bundle already
+// loaded in this process is returned as is, so verifying many images pulls and checks it once,
+// and a digest reference cached on disk (by an earlier run) is used without the registry.
+func pullConfigBundle(ctx context.Context, repo *remote.Repository, repoName, reference, cacheDir string, opts ConfigBundleOptions) (*oci.ConfigBundle, error) {
+	key := strings.Join([]string{repoName + "@" + reference, opts.CosignKey, cacheDir}, "\x00")
+	if opts.Reuse {
+		loadedMu.Lock()
+		loaded := loadedBundles[key]
+		loadedMu.Unlock()
+		if loaded != nil {
+			bundle := *loaded
+			bundle.Cached = true
+			return &bundle, nil
+		}
+	}
+
+	var bundle *oci.ConfigBundle
+	if opts.Reuse {
+		bundle = oci.CachedConfigBundle(reference, cacheDir)
+	}
+	if bundle == nil {
+		var err error
+		if bundle, err = oci.PullConfigBundle(ctx, repo, reference, cacheDir); err != nil {
+			return nil, err
+		}
+	}
+
+	// The signature is checked on every run, including cached bundles, so a revoked key takes effect
+	if opts.CosignKey != "" {
+		if err := verifyConfigBundleSignature(repoName+"@"+bundle.Digest, opts.CosignKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Reuse {
+		loadedMu.Lock()
+		loadedBundles[key] = bundle
+		loadedMu.Unlock()
+	}
+	return bundle, nil
+}
+
+// verifyConfigBundleSignature runs cosign verify --key against the bundle digest
+func verifyConfigBundleSignature(ref, key string) error {
+	cosignPath, err := findCosign("config bundle signature verification")
+	if err != nil {
+		return err
+	}
+	output, err := exec.Command(cosignPath, "verify", "--key", key, ref).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("config bundle signature verification failed for %s: %s", ref, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
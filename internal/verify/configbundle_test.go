@@ -0,0 +1,194 @@
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// TestLoadConfigBundle tests that a repository without .acc is verified with the config and
+// policy pulled from a bundle
+func TestLoadConfigBundle(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	repo, host, _ := newConfigBundleRegistry(t, nil)
+
+	cfg, bundle, err := loadConfigBundle(context.Background(), repo, host+"/central/standard", "v1", ConfigBundleOptions{})
+	if err != nil {
+		t.Fatalf("loadConfigBundle failed: %v", err)
+	}
+	if cfg.Project.Name != "central" || cfg.Policy.Pack != bundle.PolicyDir {
+		t.Fatalf("expected the bundle's config and policy, got project %q pack %q", cfg.Project.Name, cfg.Policy.Pack)
+	}
+	if !strings.HasPrefix(bundle.Dir, filepath.Join(".acc", "cache", "config-bundles")) {
+		t.Errorf("expected the bundle under .acc/cache/config-bundles, got %s", bundle.Dir)
+	}
+
+	// The fake opa reports the rule named in the policies it was given
+	binDir := t.TempDir()
+	opa := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+rule=""
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--data" ]; then rule="$rule$(sed -n 's/^# rule: //p' "$2"/*.rego 2>/dev/null)"; shift; fi
+  shift
+done
+echo "{\"result\":[{\"expressions\":[{\"value\":{\"violations\":[{\"rule\":\"$rule\",\"severity\":\"high\",\"result\":\"fail\",\"message\":\"denied\"}]}}]}]}"
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(opa), 0755)
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":{\"User\":\"root\",\"Labels\":null}}]'\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	os.MkdirAll(filepath.Join(".acc", "sbom"), 0755)
+	os.WriteFile(filepath.Join(".acc", "sbom", "central.spdx.json"), []byte("{}"), 0644)
+
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || len(result.Violations) != 1 || result.Violations[0].Rule != "central-standard" {
+		t.Fatalf("expected the bundle's policy to be evaluated, got %+v", result.Violations)
+	}
+
+	// A second load reuses the cached bundle
+	if _, bundle, err := loadConfigBundle(context.Background(), repo, host+"/central/standard", "v1", ConfigBundleOptions{}); err != nil || !bundle.Cached {
+		t.Errorf("expected the cached bundle to be reused, got %+v, %v", bundle, err)
+	}
+}
+
+// TestLoadConfigBundle_Reuse tests that with Reuse (--rego-bundle-cache) a batch of images
+// pulls the bundle once, and a later run uses a digest-pinned bundle without the registry
+func TestLoadConfigBundle_Reuse(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+	defer func() {
+		loadedMu.Lock()
+		clear(loadedBundles)
+		loadedMu.Unlock()
+	}()
+
+	var requests atomic.Int32
+	repo, host, manifestDigest := newConfigBundleRegistry(t, &requests)
+	repoName := host + "/central/standard"
+	opts := ConfigBundleOptions{Reuse: true}
+
+	// Two images verified in one process
+	for _, image := range []string{"app-a:1.0", "app-b:1.0"} {
+		cfg, _, err := loadConfigBundle(context.Background(), repo, repoName, "v1", opts)
+		if err != nil {
+			t.Fatalf("%s: loadConfigBundle failed: %v", image, err)
+		}
+		if cfg.Project.Name != "central" {
+			t.Errorf("%s: expected the bundle's config, got project %q", image, cfg.Project.Name)
+		}
+	}
+	pulled := requests.Load()
+	// The manifest and its two layers are fetched for the first image only
+	if pulled != 3 {
+		t.Errorf("expected the bundle to be pulled once (3 requests), got %d requests", pulled)
+	}
+
+	// A new run (empty in-process cache) pinned by digest is served from the disk cache
+	loadedMu.Lock()
+	clear(loadedBundles)
+	loadedMu.Unlock()
+	_, bundle, err := loadConfigBundle(context.Background(), repo, repoName, manifestDigest, opts)
+	if err != nil {
+		t.Fatalf("loadConfigBundle by digest failed: %v", err)
+	}
+	if !bundle.Cached || bundle.Digest != manifestDigest {
+		t.Errorf("expected the cached bundle %s, got %+v", manifestDigest, bundle)
+	}
+	if requests.Load() != pulled {
+		t.Errorf("expected no registry requests for a cached digest-pinned bundle, got %d", requests.Load()-pulled)
+	}
+
+	// Without Reuse every load resolves the reference again
+	if _, _, err := loadConfigBundle(context.Background(), repo, repoName, "v1", ConfigBundleOptions{}); err != nil {
+		t.Fatalf("loadConfigBundle failed: %v", err)
+	}
+	if requests.Load() == pulled {
+		t.Error("expected a load without Reuse to contact the registry")
+	}
+}
+
+// newConfigBundleRegistry serves a config bundle (central acc.yaml and one policy) as
+// central/standard:v1, counting registry requests in requests when it is not nil
+func newConfigBundleRegistry(t *testing.T, requests *atomic.Int32) (*remote.Repository, string, string) {
+	t.Helper()
+
+	blobs := map[string]string{}
+	layer := func(mediaType, data, title string) ocispec.Descriptor {
+		sum := sha256.Sum256([]byte(data))
+		desc := ocispec.Descriptor{
+			MediaType:   mediaType,
+			Digest:      digest.Digest("sha256:" + hex.EncodeToString(sum[:])),
+			Size:        int64(len(data)),
+			Annotations: map[string]string{ocispec.AnnotationTitle: title},
+		}
+		blobs[desc.Digest.String()] = data
+		return desc
+	}
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{
+			layer(oci.MediaTypeConfigBundleConfig, config.DefaultConfig("central").ToYAML(), "acc.yaml"),
+			layer(oci.MediaTypeConfigBundlePolicy, "package acc.policy\n# rule: central-standard\n", "central.rego"),
+		},
+	})
+	sum := sha256.Sum256(manifest)
+	manifestDigest := "sha256:" + hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			requests.Add(1)
+		}
+		path := strings.TrimPrefix(r.URL.Path, "/v2/central/standard/")
+		body, ok := blobs[strings.TrimPrefix(path, "blobs/")]
+		mediaType := "application/octet-stream"
+		if path == "manifests/v1" || path == "manifests/"+manifestDigest {
+			body, ok, mediaType = string(manifest), true, ocispec.MediaTypeImageManifest
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		sum := sha256.Sum256([]byte(body))
+		w.Header().Set("Content-Type", mediaType)
+		w.Header().Set("Docker-Content-Digest", "sha256:"+hex.EncodeToString(sum[:]))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method == http.MethodGet {
+			w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	repo, err := remote.NewRepository(host + "/central/standard")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+	repo.Client = http.DefaultClient
+
+	return repo, host, manifestDigest
+}
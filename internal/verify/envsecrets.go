@@ -0,0 +1,144 @@
+package verify
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultEnvSecretPatterns match environment variable names that usually hold credentials.
+// policy.envSecretPatterns (and --env-secret-patterns) add to them.
+var defaultEnvSecretPatterns = []string{
+	`PASSW(OR)?D`,
+	`SECRET`,
+	`TOKEN`,
+	`API_?KEY`,
+	`ACCESS_?KEY`,
+	`PRIVATE_?KEY`,
+	`CREDENTIAL`,
+}
+
+// envSecretReferenceSuffixes mark variables that point at a secret rather than hold one
+// (DB_PASSWORD_FILE=/run/secrets/db)
+var envSecretReferenceSuffixes = []string{"_FILE", "_PATH", "_DIR"}
+
+// envSecretValues are well-known credential formats, matched against every value
+var envSecretValues = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"an AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"a GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"a Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"a Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}`)},
+	{"a Stripe secret key", regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{20,}`)},
+	{"a private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"a JSON Web Token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{"a URL with embedded credentials", regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`)},
+}
+
+// High-entropy values: a token-like string of at least envSecretMinLength characters with at
+// least envSecretMinEntropy bits per character. Hex digests stay below 4 bits, so checksums
+// (GOLANG_SHA256=...) are not reported; random base64 and mixed-case keys are.
+const (
+	envSecretMinLength  = 20
+	envSecretMinEntropy = 4.2
+)
+
+var tokenCharset = regexp.MustCompile(`^[A-Za-z0-9+/=_\-.]+$`)
+
+// compileEnvSecretPatterns compiles the built-in name patterns and extra, case-insensitively
+func compileEnvSecretPatterns(extra []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, p := range append(append([]string{}, defaultEnvSecretPatterns...), extra...) {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env secret pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// checkEnvSecrets returns an env-secret-detected violation for each KEY=VALUE in env whose
+// name matches a secret pattern or whose value looks like a credential, in key order. Empty
+// values are skipped (nothing is baked in), and messages never include the value.
+func checkEnvSecrets(env []string, patterns []string) ([]PolicyViolation, error) {
+	names, err := compileEnvSecretPatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]string{}
+	for _, entry := range env {
+		key, value, _ := strings.Cut(entry, "=")
+		if key == "" || value == "" {
+			continue
+		}
+		if reason := envSecretReason(key, value, names); reason != "" {
+			found[key] = reason
+		}
+	}
+
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations []PolicyViolation
+	for _, key := range keys {
+		violations = append(violations, PolicyViolation{
+			Rule:        "env-secret-detected",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Image sets environment variable %s=<redacted>, which looks like a secret (%s)", key, found[key]),
+			Remediation: remediationEnvSecret,
+		})
+	}
+	return violations, nil
+}
+
+// envSecretReason says why key=value looks like a secret, or returns "" when it does not
+func envSecretReason(key, value string, names []*regexp.Regexp) string {
+	for _, v := range envSecretValues {
+		if v.pattern.MatchString(value) {
+			return "value looks like " + v.kind
+		}
+	}
+
+	upper := strings.ToUpper(key)
+	for _, suffix := range envSecretReferenceSuffixes {
+		if strings.HasSuffix(upper, suffix) {
+			return ""
+		}
+	}
+	for _, re := range names {
+		if re.MatchString(key) {
+			return "name matches " + re.String()[len("(?i)"):]
+		}
+	}
+
+	if len(value) >= envSecretMinLength && tokenCharset.MatchString(value) &&
+		strings.ContainsAny(value, "0123456789") && shannonEntropy(value) >= envSecretMinEntropy {
+		return "high-entropy value"
+	}
+	return ""
+}
+
+// shannonEntropy returns the bits of entropy per character of s
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
@@ -0,0 +1,143 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/policy"
+)
+
+// traceEvent is the part of an `opa eval --explain full` trace event explain-deny reads.
+// Node and Locals are kept raw: only the objects carrying a "rule" key are of interest.
+type traceEvent struct {
+	Op       string          `json:"Op"`
+	Node     json.RawMessage `json:"Node"`
+	Locals   json.RawMessage `json:"Locals"`
+	Location *struct {
+		File string `json:"file"`
+		Row  int    `json:"row"`
+	} `json:"Location"`
+}
+
+// attachTraceSources sets each violation's Source to the location OPA reports for the rule
+// body that produced it (policy.explainDeny): the first rule exit in the trace whose bindings
+// hold an object with the violation's rule ID and message. Rule bodies exit before the rules
+// that collect their results (e.g. result.violations), so the reporting body is found first,
+// whether its rule ID is a literal or computed. Violations the trace does not account for
+// keep their Source.
+func attachTraceSources(violations []PolicyViolation, explanation []traceEvent) {
+	type reported struct{ rule, message, location string }
+	var exits []reported
+	for _, event := range explanation {
+		if event.Op != "Exit" || event.Location == nil || event.Location.File == "" || !isRuleNode(event.Node) {
+			continue
+		}
+		location := fmt.Sprintf("%s:%d", event.Location.File, event.Location.Row)
+		for _, raw := range []json.RawMessage{event.Node, event.Locals} {
+			var tree interface{}
+			if len(raw) == 0 || json.Unmarshal(raw, &tree) != nil {
+				continue
+			}
+			walkRuleObjects(tree, func(fields map[string]string) {
+				exits = append(exits, reported{fields["rule"], fields["message"], location})
+			})
+		}
+	}
+
+	for i := range violations {
+		source := ""
+		for _, exit := range exits {
+			if exit.rule != violations[i].Rule {
+				continue
+			}
+			if exit.message == violations[i].Message {
+				source = exit.location
+				break
+			}
+			if source == "" {
+				source = exit.location
+			}
+		}
+		if source != "" {
+			violations[i].Source = source
+		}
+	}
+}
+
+// isRuleNode reports whether a trace event's node is a rule (rules have a head)
+func isRuleNode(node json.RawMessage) bool {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(node, &fields) != nil {
+		return false
+	}
+	_, ok := fields["head"]
+	return ok
+}
+
+// walkRuleObjects calls fn with the string fields of every object in tree that has a string
+// "rule" field. Objects appear either as plain JSON or as OPA AST terms, whose object value
+// is a list of [key, value] term pairs ({"type": "string", "value": "rule"}).
+func walkRuleObjects(tree interface{}, fn func(fields map[string]string)) {
+	switch node := tree.(type) {
+	case map[string]interface{}:
+		if rule, ok := node["rule"].(string); ok {
+			fields := map[string]string{}
+			for key, value := range node {
+				if s, ok := value.(string); ok {
+					fields[key] = s
+				}
+			}
+			fields["rule"] = rule
+			fn(fields)
+		}
+		for _, value := range node {
+			walkRuleObjects(value, fn)
+		}
+	case []interface{}:
+		fields := map[string]string{}
+		for _, item := range node {
+			pair, ok := item.([]interface{})
+			if !ok || len(pair) != 2 {
+				continue
+			}
+			key, keyOK := stringTerm(pair[0])
+			value, valueOK := stringTerm(pair[1])
+			if keyOK && valueOK {
+				fields[key] = value
+			}
+		}
+		if _, ok := fields["rule"]; ok {
+			fn(fields)
+		}
+		for _, item := range node {
+			walkRuleObjects(item, fn)
+		}
+	}
+}
+
+// stringTerm returns the value of an OPA AST string term ({"type": "string", "value": ...})
+func stringTerm(term interface{}) (string, bool) {
+	fields, ok := term.(map[string]interface{})
+	if !ok || fields["type"] != "string" {
+		return "", false
+	}
+	value, ok := fields["value"].(string)
+	return value, ok
+}
+
+// relabelBuiltinSources rewrites sources inside a built-in pack's temporary directory as
+// builtin:<name>/<file>:<line>, since the extracted directory is removed after verify
+func relabelBuiltinSources(violations []PolicyViolation, pack *policy.Pack) {
+	if !pack.Builtin {
+		return
+	}
+	for i := range violations {
+		rel, err := filepath.Rel(pack.Dir, violations[i].Source)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		violations[i].Source = filepath.Join("builtin:"+pack.Name, rel)
+	}
+}
@@ -0,0 +1,71 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/profile"
+)
+
+// explainTrace is an `opa eval --explain full` trace for a pack where two files report
+// no-root-user and a third builds its rule ID at evaluation time. The result rule, which
+// collects every violation, exits last.
+const explainTrace = `[
+ {"Op":"Enter","Node":{"head":{"name":"result"}},"Location":{"file":".acc/policy/policy.rego","row":20,"col":1}},
+ {"Op":"Exit","Node":{"head":{"name":"deny"}},"Location":{"file":".acc/policy/legacy.rego","row":3,"col":1},
+  "Locals":[{"name":"msg","value":[[{"type":"string","value":"rule"},{"type":"string","value":"no-root-user"}],[{"type":"string","value":"message"},{"type":"string","value":"legacy check"}]]}]},
+ {"Op":"Exit","Node":{"head":{"name":"deny"}},"Location":{"file":".acc/policy/security.rego","row":14,"col":1},
+  "Locals":[{"name":"msg","value":[[{"type":"string","value":"rule"},{"type":"string","value":"no-root-user"}],[{"type":"string","value":"message"},{"type":"string","value":"runs as root"}]]}]},
+ {"Op":"Exit","Node":{"head":{"name":"deny"}},"Location":{"file":".acc/policy/labels.rego","row":9,"col":1},
+  "Locals":[{"name":"id","value":"label-team"},{"name":"msg","value":[[{"type":"string","value":"rule"},{"type":"string","value":"label-team"}],[{"type":"string","value":"message"},{"type":"string","value":"missing team label"}]]}]},
+ {"Op":"Eval","Node":{"terms":[]},"Location":{"file":".acc/policy/policy.rego","row":21,"col":2},
+  "Locals":[{"name":"v","value":[[{"type":"string","value":"rule"},{"type":"string","value":"no-root-user"}]]}]},
+ {"Op":"Exit","Node":{"head":{"name":"result"}},"Location":{"file":".acc/policy/policy.rego","row":20,"col":1},
+  "Locals":[{"name":"violations","value":[{"type":"object","value":[[{"type":"string","value":"rule"},{"type":"string","value":"no-root-user"}],[{"type":"string","value":"message"},{"type":"string","value":"runs as root"}]]},{"type":"object","value":[[{"type":"string","value":"rule"},{"type":"string","value":"label-team"}],[{"type":"string","value":"message"},{"type":"string","value":"missing team label"}]]}]}]}
+]`
+
+// TestVerify_ExplainDeny tests that --explain-deny attributes each violation to the rule body
+// OPA's trace shows producing it, and that the source survives profile filtering
+func TestVerify_ExplainDeny(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+
+	violations := `[{"rule":"no-root-user","severity":"high","result":"fail","message":"runs as root"},` +
+		`{"rule":"label-team","severity":"low","result":"fail","message":"missing team label"}]`
+	binDir := t.TempDir()
+	opa := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+case "$*" in
+  *"--explain full"*) echo '{"result":[{"expressions":[{"value":{"violations":` + violations + `}}]}],"explanation":` + explainTrace + `}' ;;
+  *) echo '{"result":[{"expressions":[{"value":{"violations":` + violations + `}}]}]}' ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(binDir, "opa"), []byte(opa), 0755); err != nil {
+		t.Fatalf("failed to write fake opa: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	result, _ := Verify(cfg, "test:latest", false, true, nil)
+	if len(result.Violations) != 2 || result.Violations[0].Source != "" {
+		t.Fatalf("expected no source without --explain-deny, got %+v", result.Violations)
+	}
+
+	cfg.Policy.ExplainDeny = true
+	prof := &profile.Profile{
+		Name:       "lenient",
+		Violations: profile.ViolationConfig{Ignore: []string{"low"}},
+		Warnings:   profile.WarningConfig{Show: true},
+	}
+	result, _ = Verify(cfg, "test:latest", false, true, prof)
+
+	// no-root-user is reported by two files; the message identifies the body that fired
+	want := filepath.Join(".acc", "policy", "security.rego") + ":14"
+	if len(result.Violations) != 1 || result.Violations[0].Source != want {
+		t.Errorf("expected no-root-user to come from %s, got %+v", want, result.Violations)
+	}
+	// The computed rule ID is located too, and stays attributed as a profile warning
+	want = filepath.Join(".acc", "policy", "labels.rego") + ":9"
+	if len(result.PolicyResult.Warnings) != 1 || result.PolicyResult.Warnings[0].Source != want {
+		t.Errorf("expected label-team to come from %s, got %+v", want, result.PolicyResult.Warnings)
+	}
+}
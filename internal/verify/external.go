@@ -0,0 +1,73 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadExternalData reads --data files (JSON or YAML objects) and merges them into one
+// document. Later files win when top-level keys collide.
+func loadExternalData(files []string) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data file: %w", err)
+		}
+
+		var doc map[string]interface{}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".json":
+			err = json.Unmarshal(data, &doc)
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(data, &doc)
+		default:
+			return nil, fmt.Errorf("data file %s must be .json, .yaml, or .yml", file)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse data file %s: %w", file, err)
+		}
+		if doc == nil {
+			return nil, fmt.Errorf("data file %s must contain an object", file)
+		}
+
+		for k, v := range doc {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
+
+// writeExternalData writes the merged --data files to a temporary directory laid out
+// as acc/external/data.json, so OPA loads them under data.acc.external. The caller
+// removes the returned directory.
+func writeExternalData(files []string) (string, error) {
+	merged, err := loadExternalData(files)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal external data: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "acc-external-data-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	nsDir := filepath.Join(dir, "acc", "external")
+	if err := os.MkdirAll(nsDir, 0755); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to create external data dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(nsDir, "data.json"), data, 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write external data: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,146 @@
+package verify
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ownershipPolicy denies images whose team label is not listed in the injected data
+const ownershipPolicy = `package acc.policy
+
+import rego.v1
+
+result := {"violations": [v |
+	not input.config.Labels.team in object.get(data.acc, ["external", "teams"], [])
+	v := {"rule": "team-ownership", "severity": "high", "result": "fail", "message": "team is not allowed"}
+]}
+`
+
+func writeDataFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write data file: %v", err)
+	}
+	return path
+}
+
+func TestLoadExternalData(t *testing.T) {
+	jsonFile := writeDataFile(t, "registries.json", `{"registries": ["ghcr.io"], "teams": ["search"]}`)
+	yamlFile := writeDataFile(t, "teams.yaml", "teams:\n  - payments\n")
+
+	merged, err := loadExternalData([]string{jsonFile, yamlFile})
+	if err != nil {
+		t.Fatalf("loadExternalData failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"registries": []interface{}{"ghcr.io"},
+		"teams":      []interface{}{"payments"}, // later file wins
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("merged = %v, want %v", merged, want)
+	}
+
+	for name, content := range map[string]string{
+		"list.json":  `["not", "an", "object"]`,
+		"data.txt":   `teams: []`,
+		"empty.yaml": ``,
+	} {
+		if _, err := loadExternalData([]string{writeDataFile(t, name, content)}); err == nil {
+			t.Errorf("loadExternalData(%s) succeeded, want error", name)
+		}
+	}
+	if _, err := loadExternalData([]string{filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("loadExternalData(missing) succeeded, want error")
+	}
+}
+
+func TestWriteExternalData_Namespace(t *testing.T) {
+	dir, err := writeExternalData([]string{writeDataFile(t, "teams.json", `{"teams": ["payments"]}`)})
+	if err != nil {
+		t.Fatalf("writeExternalData failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, "acc", "external", "data.json"))
+	if err != nil {
+		t.Fatalf("expected data under acc/external: %v", err)
+	}
+	if string(data) != `{"teams":["payments"]}` {
+		t.Errorf("data.json = %s", data)
+	}
+}
+
+// TestEvaluateRego_ExternalDataFakeOPA tests that injected data reaches OPA as an extra --data path
+func TestEvaluateRego_ExternalDataFakeOPA(t *testing.T) {
+	// Fake opa: allow only when an acc/external/data.json it was given lists the payments team
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--data" ] && grep -q '"payments"' "$2/acc/external/data.json" 2>/dev/null; then
+    echo '{"result":[{"expressions":[{"value":{"violations":[]}}]}]}'; exit 0
+  fi
+  shift
+done
+echo '{"result":[{"expressions":[{"value":{"violations":[{"rule":"team-ownership","severity":"high","result":"fail","message":"team is not allowed"}]}}]}]}'
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(script), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	assertExternalDataDecision(t)
+}
+
+// TestEvaluateRego_ExternalDataRego tests a real Rego rule reading data.acc.external
+func TestEvaluateRego_ExternalDataRego(t *testing.T) {
+	if _, err := exec.LookPath("opa"); err != nil {
+		t.Skip("opa not installed")
+	}
+	assertExternalDataDecision(t)
+}
+
+func assertExternalDataDecision(t *testing.T) {
+	t.Helper()
+	policyDir := t.TempDir()
+	os.WriteFile(filepath.Join(policyDir, "ownership.rego"), []byte(ownershipPolicy), 0644)
+	input := &RegoInput{Config: ImageConfig{User: "1000", Labels: map[string]string{"team": "payments"}}}
+
+	tests := []struct {
+		name      string
+		files     []string
+		wantRules []string
+	}{
+		{"no data denies", nil, []string{"team-ownership"}},
+		{"team allowed", []string{writeDataFile(t, "teams.yaml", "teams: [payments]\n")}, nil},
+		{"team not allowed", []string{writeDataFile(t, "teams.json", `{"teams": ["search"]}`)}, []string{"team-ownership"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dataPaths []string
+			if len(tt.files) > 0 {
+				dir, err := writeExternalData(tt.files)
+				if err != nil {
+					t.Fatalf("writeExternalData failed: %v", err)
+				}
+				defer os.RemoveAll(dir)
+				dataPaths = append(dataPaths, dir)
+			}
+
+			violations, err := evaluateRego(policyDir, input, dataPaths...)
+			if err != nil {
+				t.Fatalf("evaluateRego failed: %v", err)
+			}
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.Rule)
+			}
+			if strings.Join(rules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("rules = %v, want %v", rules, tt.wantRules)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/policy"
+	"github.com/cloudcwfranck/acc/internal/profile"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture member names, relative to the fixture directory
+const (
+	fixtureManifestName = "fixture.json"
+	fixtureResultName   = "result.json"
+	fixtureInputName    = "input.json"
+	fixturePolicyDir    = "policy"
+	fixtureProfileName  = "profile.yaml"
+	fixtureConfigName   = "acc.yaml"
+	fixtureDataName     = "data/external.json"
+	fixtureBaselineDir  = "baseline"
+)
+
+// FixtureManifest describes a verify fixture (acc verify --fixture)
+type FixtureManifest struct {
+	SchemaVersion string `json:"schemaVersion"`
+	CreatedAt     string `json:"createdAt"`
+	ImageRef      string `json:"imageRef"`
+	Status        string `json:"status"`     // status of the captured run, see result.json
+	PolicyPack    string `json:"policyPack"` // pack the policy files were copied from
+	Profile       string `json:"profile,omitempty"`
+	Replay        string `json:"replay"` // command that replays the fixture from its directory
+}
+
+// WriteFixture saves what a verification evaluated into dir as a self-contained project:
+// the policy input (input.json), the policy pack (policy/), the applied profile, the
+// effective config (acc.yaml), the SBOM, waivers, and --data files, plus the result of
+// the captured run. Running the Replay command in dir evaluates the same input without
+// a container runtime. Checks that call out to cosign or a registry are not captured.
+func WriteFixture(dir string, cfg *config.Config, imageRef string, result *VerifyResult, prof *profile.Profile) (*FixtureManifest, error) {
+	if result.Input == nil {
+		return nil, fmt.Errorf("no policy input was built for %s (the image could not be inspected), nothing to replay", imageRef)
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("fixture directory %s is not empty", dir)
+	}
+
+	pack, err := policy.ResolvePack(cfg.Policy.Pack)
+	if err != nil {
+		return nil, err
+	}
+	defer pack.Cleanup()
+	packName := pack.Dir
+	if pack.Builtin {
+		packName = "builtin:" + pack.Name
+	}
+
+	// The fixture's own config points at the fixture's copies of every file
+	fixtureCfg := *cfg
+	fixtureCfg.Policy.Pack = "./" + fixturePolicyDir
+	fixtureCfg.Policy.Input = fixtureInputName
+	fixtureCfg.Policy.Data = nil
+	fixtureCfg.SBOM.Baseline = "" // its diff is recorded in input.sbom.changed
+	fixtureCfg.Hooks = config.HooksConfig{}
+
+	if err := copyTree(pack.Dir, filepath.Join(dir, fixturePolicyDir)); err != nil {
+		return nil, fmt.Errorf("failed to copy policy pack: %w", err)
+	}
+
+	files := map[string][]byte{}
+	if files[fixtureInputName], err = json.MarshalIndent(result.Input, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to marshal policy input: %w", err)
+	}
+	if files[fixtureResultName], err = json.MarshalIndent(result, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to marshal verify result: %w", err)
+	}
+
+	if len(cfg.Policy.Data) > 0 {
+		external, err := loadExternalData(cfg.Policy.Data)
+		if err != nil {
+			return nil, err
+		}
+		if files[fixtureDataName], err = json.MarshalIndent(external, "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to marshal external data: %w", err)
+		}
+		fixtureCfg.Policy.Data = []string{fixtureDataName}
+	}
+
+	if sbomFile := findSBOMFile(cfg); sbomFile != "" {
+		// The fixture always carries the SBOM in the default directory
+		if err := copyFixtureFile(files, sbomFile, filepath.Join(config.DefaultSBOMDir, filepath.Base(sbomFile))); err != nil {
+			return nil, err
+		}
+		fixtureCfg.SBOM.Dir = ""
+	}
+	if cfg.SBOM.DenyNewPackages != "" {
+		name := filepath.Join(fixtureBaselineDir, filepath.Base(cfg.SBOM.DenyNewPackages))
+		if err := copyFixtureFile(files, cfg.SBOM.DenyNewPackages, name); err != nil {
+			return nil, err
+		}
+		fixtureCfg.SBOM.DenyNewPackages = name
+	}
+	if waiversFile := filepath.Join(".acc", "waivers.yaml"); !cfg.Policy.NoWaivers {
+		if _, err := os.Stat(waiversFile); err == nil {
+			if err := copyFixtureFile(files, waiversFile, waiversFile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if files[fixtureConfigName], err = fixtureCfg.EffectiveYAML(); err != nil {
+		return nil, err
+	}
+
+	manifest := &FixtureManifest{
+		SchemaVersion: "v0.1",
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		ImageRef:      imageRef,
+		Status:        result.Status,
+		PolicyPack:    packName,
+	}
+	replay := []string{"acc", "verify", "--input", fixtureInputName, "--policy", fixturePolicyDir}
+	if prof != nil {
+		if files[fixtureProfileName], err = yaml.Marshal(prof); err != nil {
+			return nil, fmt.Errorf("failed to marshal profile: %w", err)
+		}
+		manifest.Profile = prof.Name
+		replay = append(replay, "--profile", fixtureProfileName)
+	}
+	manifest.Replay = strings.Join(append(replay, imageRef), " ")
+
+	if files[fixtureManifestName], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to marshal fixture manifest: %w", err)
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write fixture file %s: %w", name, err)
+		}
+	}
+	return manifest, nil
+}
+
+// copyFixtureFile reads src into files under name
+func copyFixtureFile(files map[string][]byte, src, name string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	files[name] = data
+	return nil
+}
+
+// copyTree copies the regular files under src to dst, keeping their relative paths
+func copyTree(src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		// No policy directory: the replay allows by default, as the captured run did
+		return nil
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+// loadRegoInput reads a policy input document recorded with --print-input or --fixture
+func loadRegoInput(path string) (*RegoInput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy input: %w", err)
+	}
+	var input RegoInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("failed to parse policy input %s: %w", path, err)
+	}
+	if input.Config.Labels == nil {
+		input.Config.Labels = map[string]string{}
+	}
+	return &input, nil
+}
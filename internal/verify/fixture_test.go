@@ -0,0 +1,124 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/profile"
+)
+
+// inputAwareOPA is a fake opa whose violations depend on the input document and on
+// data.acc.external, so a replay only matches when the fixture captured both
+const inputAwareOPA = `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+input=""; data=""
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --input) input="$2"; shift ;;
+    --data) [ -f "$2/acc/external/data.json" ] && data="$2/acc/external/data.json" ;;
+  esac
+  shift
+done
+v=""
+grep -q '"User":"root"' "$input" && v='{"rule":"no-root-user","severity":"critical","result":"fail","message":"runs as root"}'
+grep -q '"Labels":{}' "$input" && v="$v${v:+,}"'{"rule":"image-labels","severity":"low","result":"warn","message":"no labels"}'
+[ -n "$data" ] && grep -q '"blocked"' "$data" && v="$v${v:+,}"'{"rule":"blocked-base","severity":"high","result":"fail","message":"base image is blocked"}'
+echo '{"result":[{"expressions":[{"value":{"violations":['"$v"']}}]}]}'
+`
+
+// TestWriteFixture_Replay tests that a captured fixture replays to the same result from its
+// own directory, with no container runtime and no access to the original project
+func TestWriteFixture_Replay(t *testing.T) {
+	projectDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(projectDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	os.MkdirAll(filepath.Join(".acc", "sbom"), 0755)
+	os.WriteFile(filepath.Join(".acc", "sbom", "fixture-test.spdx.json"), []byte(spdxWithPackages(3)), 0644)
+	os.MkdirAll(filepath.Join(".acc", "policy", "network"), 0755)
+	os.WriteFile(filepath.Join(".acc", "policy", "policy.rego"), []byte("package acc.policy\n"), 0644)
+	os.WriteFile(filepath.Join(".acc", "policy", "network", "egress.rego"), []byte("package acc.policy\n"), 0644)
+	os.WriteFile("blocklist.json", []byte(`{"blocked": ["alpine:3.12"]}`), 0644)
+	os.WriteFile("profile.yaml", []byte("schemaVersion: 1\nname: lenient\ndescription: ignores missing labels\nviolations:\n  ignore: [image-labels]\nwarnings:\n  show: true\n"), 0644)
+
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":{\"User\":\"root\",\"Labels\":null}}]'\n"), 0755)
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(inputAwareOPA), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.DefaultConfig("fixture-test")
+	cfg.Policy.Data = []string{"blocklist.json"}
+	cfg.SBOM.MinComponents = 2
+	prof, err := profile.Load("profile.yaml")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+
+	captured, err := Verify(cfg, "app:1.0", false, true, prof)
+	if err == nil || captured.Status != "fail" {
+		t.Fatalf("expected the captured run to fail, got %v", err)
+	}
+
+	fixtureDir := filepath.Join(t.TempDir(), "fixture")
+	manifest, err := WriteFixture(fixtureDir, cfg, "app:1.0", captured, prof)
+	if err != nil {
+		t.Fatalf("WriteFixture failed: %v", err)
+	}
+	want := "acc verify --input input.json --policy policy --profile profile.yaml app:1.0"
+	if manifest.Replay != want || manifest.Status != "fail" || manifest.PolicyPack != filepath.Join(".acc", "policy") {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+	for _, name := range []string{"fixture.json", "result.json", "input.json", "acc.yaml", "profile.yaml", "policy/network/egress.rego", "data/external.json", ".acc/sbom/fixture-test.spdx.json"} {
+		if _, err := os.Stat(filepath.Join(fixtureDir, name)); err != nil {
+			t.Errorf("fixture is missing %s", name)
+		}
+	}
+
+	// A second capture into the same directory must not mix two runs
+	if _, err := WriteFixture(fixtureDir, cfg, "app:1.0", captured, prof); err == nil {
+		t.Error("expected an error for a non-empty fixture directory")
+	}
+
+	// Replay as acc verify --input input.json --policy policy --profile profile.yaml does,
+	// from the fixture directory, with an image that cannot be inspected
+	os.Chdir(fixtureDir)
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\nexit 1\n"), 0755)
+	replayCfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("fixture config does not load: %v", err)
+	}
+	replayCfg.Policy.Pack = "./policy"
+	replayCfg.Policy.Input = "input.json"
+	replayProf, err := profile.Load("profile.yaml")
+	if err != nil {
+		t.Fatalf("fixture profile does not load: %v", err)
+	}
+
+	replayed, err := Verify(replayCfg, "app:1.0", false, true, replayProf)
+	if err == nil {
+		t.Fatal("expected the replay to fail like the captured run")
+	}
+	if replayed.Status != captured.Status ||
+		!reflect.DeepEqual(replayed.Violations, captured.Violations) ||
+		!reflect.DeepEqual(replayed.PolicyResult, captured.PolicyResult) ||
+		!reflect.DeepEqual(replayed.Input, captured.Input) {
+		got, _ := json.Marshal(replayed)
+		expected, _ := json.Marshal(captured)
+		t.Errorf("replay differs from the captured run:\n%s\nwant\n%s", got, expected)
+	}
+	if len(replayed.Violations) != 2 || len(replayed.PolicyResult.Warnings) != 1 {
+		t.Errorf("expected no-root-user and blocked-base plus an image-labels warning, got %+v", replayed.PolicyResult)
+	}
+}
+
+func TestWriteFixture_NoInput(t *testing.T) {
+	result := &VerifyResult{Status: "fail"}
+	if _, err := WriteFixture(t.TempDir(), config.DefaultConfig("x"), "app:1.0", result, nil); err == nil {
+		t.Error("expected an error when the run built no policy input")
+	}
+}
@@ -0,0 +1,32 @@
+package verify
+
+import (
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// checkHealthcheck returns a no-healthcheck finding when the image defines no HEALTHCHECK
+// (or disables one with HEALTHCHECK NONE). It is a warning unless severity is critical
+// (policy.healthcheckSeverity), in which case it fails verification like any violation.
+func checkHealthcheck(healthcheck *oci.HealthConfig, severity string) *PolicyViolation {
+	if healthcheck.Defined() {
+		return nil
+	}
+
+	violation := &PolicyViolation{
+		Rule:        "no-healthcheck",
+		Severity:    "medium",
+		Result:      "warn",
+		Message:     "Image defines no HEALTHCHECK",
+		Remediation: remediationHealthcheck,
+	}
+	if healthcheck != nil && len(healthcheck.Test) > 0 {
+		violation.Message = "Image disables its healthcheck (HEALTHCHECK NONE)"
+	}
+	if strings.EqualFold(severity, "critical") {
+		violation.Severity = "critical"
+		violation.Result = "fail"
+	}
+	return violation
+}
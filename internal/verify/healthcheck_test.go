@@ -0,0 +1,67 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+func TestCheckHealthcheck(t *testing.T) {
+	defined := &oci.HealthConfig{Test: []string{"CMD-SHELL", "curl -f http://localhost/ || exit 1"}, Retries: 3}
+	if v := checkHealthcheck(defined, ""); v != nil {
+		t.Errorf("expected no finding for a defined healthcheck, got %+v", v)
+	}
+
+	v := checkHealthcheck(nil, "")
+	if v == nil || v.Rule != "no-healthcheck" || v.Result != "warn" || v.Severity != "medium" {
+		t.Fatalf("expected a no-healthcheck warning by default, got %+v", v)
+	}
+
+	v = checkHealthcheck(&oci.HealthConfig{Test: []string{"NONE"}}, "critical")
+	if v == nil || v.Result != "fail" || v.Severity != "critical" {
+		t.Fatalf("expected HEALTHCHECK NONE to fail at critical severity, got %+v", v)
+	}
+	if v.Message != "Image disables its healthcheck (HEALTHCHECK NONE)" {
+		t.Errorf("unexpected message %q", v.Message)
+	}
+}
+
+// fakeDockerConfig installs a docker whose inspect output has the given Config JSON
+func fakeDockerConfig(t *testing.T, config string) {
+	t.Helper()
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":"+config+"}]'\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestVerify_RequireHealthcheck tests the check against inspected configs with and without a healthcheck
+func TestVerify_RequireHealthcheck(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	cfg.Policy.RequireHealthcheck = true
+
+	fakeDockerConfig(t, `{"User":"app","Labels":null}`)
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected a missing healthcheck to only warn, got %v (%+v)", err, result.Violations)
+	}
+	if len(result.PolicyResult.Warnings) != 1 || result.PolicyResult.Warnings[0].Rule != "no-healthcheck" {
+		t.Errorf("expected a no-healthcheck warning, got %+v", result.PolicyResult.Warnings)
+	}
+
+	cfg.Policy.HealthcheckSeverity = "critical"
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || len(result.Violations) != 1 || result.Violations[0].Rule != "no-healthcheck" {
+		t.Fatalf("expected a critical no-healthcheck violation, got %v (%+v)", err, result.Violations)
+	}
+
+	fakeDockerConfig(t, `{"User":"app","Labels":null,"Healthcheck":{"Test":["CMD","/healthz"],"Interval":30000000000}}`)
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" || len(result.PolicyResult.Warnings) != 0 {
+		t.Errorf("expected an image with a healthcheck to pass cleanly, got %v (%+v)", err, result.PolicyResult)
+	}
+	if hc := result.Input.Config.Healthcheck; hc == nil || hc.Interval != 30000000000 {
+		t.Errorf("expected the healthcheck in the policy input, got %+v", hc)
+	}
+}
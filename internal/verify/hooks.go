@@ -0,0 +1,49 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// Hook phases
+const (
+	hookPreVerify  = "preVerify"
+	hookPostVerify = "postVerify"
+)
+
+// verifyResultPath is the result JSON passed to hooks as ACC_VERIFY_RESULT
+// Pre-verify hooks see the previous verification (if any); post-verify hooks see the current one
+var verifyResultPath = filepath.Join(".acc", "state", "last_verify.json")
+
+// runHooks runs each hook command with sh -c, stopping at the first failure
+// resultPath is passed as ACC_VERIFY_RESULT; hook output is only shown with --log-level debug
+func runHooks(phase string, commands []string, imageRef, resultPath string) error {
+	for _, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			continue
+		}
+
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = append(os.Environ(),
+			"ACC_HOOK="+phase,
+			"ACC_IMAGE_REF="+imageRef,
+			"ACC_VERIFY_RESULT="+resultPath,
+		)
+
+		ui.PrintDebug(fmt.Sprintf("%s hook: %s", phase, command))
+		output, err := cmd.CombinedOutput()
+		if len(output) > 0 {
+			ui.PrintDebug(fmt.Sprintf("%s hook output:\n%s", phase, strings.TrimRight(string(output), "\n")))
+		}
+		if err != nil {
+			return fmt.Errorf("%s hook %q failed: %w", phase, command, err)
+		}
+	}
+
+	return nil
+}
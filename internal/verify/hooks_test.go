@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// writeHookScript writes an executable shell script and returns its absolute path
+func writeHookScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatalf("failed to write hook script: %v", err)
+	}
+	return path
+}
+
+// TestVerify_PreVerifyHookFailureAborts tests that a failing pre-verify hook stops verification
+func TestVerify_PreVerifyHookFailureAborts(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	postMarker := filepath.Join(tmpDir, "post-ran")
+	cfg := config.DefaultConfig("test-project")
+	cfg.Hooks.PreVerify = []string{writeHookScript(t, tmpDir, "pre.sh", "echo scan failed\nexit 3\n")}
+	cfg.Hooks.PostVerify = []string{"touch " + postMarker}
+
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil {
+		t.Fatal("expected pre-verify hook failure to abort verification")
+	}
+	if result == nil || result.Status != "fail" || result.ExitCode() != 1 {
+		t.Fatalf("expected failed result, got %+v", result)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "pre-verify-hook" {
+		t.Errorf("expected pre-verify-hook violation, got %+v", result.Violations)
+	}
+
+	// Verification never ran: no state saved and no post-verify hooks
+	if _, err := os.Stat(filepath.Join(".acc", "state", "last_verify.json")); !os.IsNotExist(err) {
+		t.Error("aborted verification must not save state")
+	}
+	if _, err := os.Stat(postMarker); !os.IsNotExist(err) {
+		t.Error("post-verify hooks must not run when pre-verify aborts")
+	}
+}
+
+// TestVerify_HooksRunAroundVerification tests that successful hooks receive the image ref and result path
+func TestVerify_HooksRunAroundVerification(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	preLog := filepath.Join(tmpDir, "pre.log")
+	postLog := filepath.Join(tmpDir, "post.log")
+	cfg := config.DefaultConfig("test-project")
+	cfg.Hooks.PreVerify = []string{writeHookScript(t, tmpDir, "pre.sh", `echo "$ACC_IMAGE_REF" > `+preLog+"\n")}
+	cfg.Hooks.PostVerify = []string{writeHookScript(t, tmpDir, "post.sh", `echo "$ACC_IMAGE_REF $ACC_VERIFY_RESULT" > `+postLog+"\n"+`cat "$ACC_VERIFY_RESULT" >> `+postLog+"\n")}
+
+	// No SBOM: verification fails in enforce mode after saving state
+	result, _ := Verify(cfg, "test:latest", false, true, nil)
+	if result == nil {
+		t.Fatal("expected verification result")
+	}
+
+	pre, err := os.ReadFile(preLog)
+	if err != nil {
+		t.Fatalf("pre-verify hook did not run: %v", err)
+	}
+	if strings.TrimSpace(string(pre)) != "test:latest" {
+		t.Errorf("pre-verify hook ACC_IMAGE_REF = %q", strings.TrimSpace(string(pre)))
+	}
+
+	post, err := os.ReadFile(postLog)
+	if err != nil {
+		t.Fatalf("post-verify hook did not run: %v", err)
+	}
+	if !strings.HasPrefix(string(post), "test:latest "+filepath.Join(".acc", "state", "last_verify.json")) {
+		t.Errorf("unexpected post-verify hook environment: %s", post)
+	}
+	// State was saved before post-verify hooks ran
+	if !strings.Contains(string(post), `"imageRef": "test:latest"`) {
+		t.Errorf("post-verify hook should see the saved result JSON, got: %s", post)
+	}
+}
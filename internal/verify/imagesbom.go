@@ -0,0 +1,80 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+	"github.com/cloudcwfranck/acc/internal/trust"
+)
+
+// imageSBOMDir is where the SBOM pulled for digest by --sbom-from-image is cached:
+// <sbom.dir>/image/<digest>/
+func imageSBOMDir(cfg *config.Config, digest string) string {
+	return filepath.Join(cfg.SBOMDir(), "image", oci.DigestFileName(digest))
+}
+
+// imageSBOMConfig returns cfg with sbom.dir pointed at the SBOM of imageRef's digest, so every
+// SBOM check uses the SBOM the image carries instead of the project's (sbom.fromImage). The
+// SBOM referrer is pulled from the image's registry on first use and cached by digest; source
+// is the referrer manifest, or the cached file. When the SBOM cannot be pulled the returned
+// config still points at the (empty) image directory, so verify reports sbom-required.
+func imageSBOMConfig(cfg *config.Config, imageRef string) (imageCfg *config.Config, source string, err error) {
+	copied := *cfg
+	imageCfg = &copied
+	imageCfg.SBOM.Dir = filepath.Join(cfg.SBOMDir(), "image")
+
+	digest, err := oci.ResolveDigest(imageRef)
+	if err != nil {
+		return imageCfg, "", fmt.Errorf("cannot pull the image SBOM: %w", err)
+	}
+	imageCfg.SBOM.Dir = imageSBOMDir(cfg, digest)
+
+	// A digest pins its SBOM referrers, so a cached SBOM is reused without the registry
+	if cached := findSBOMFile(imageCfg); cached != "" {
+		return imageCfg, cached, nil
+	}
+
+	registry, repository, _, err := oci.ParseReference(imageRef)
+	if err != nil {
+		return imageCfg, "", err
+	}
+	repo, err := oci.NewRepository(registry, repository)
+	if err != nil {
+		return imageCfg, "", err
+	}
+	manifestDigest, err := pullImageSBOM(context.Background(), repo, digest, imageCfg)
+	if err != nil {
+		return imageCfg, "", err
+	}
+	return imageCfg, fmt.Sprintf("%s/%s@%s", registry, repository, manifestDigest), nil
+}
+
+// pullImageSBOM fetches the SBOM referrer of digest from repo and writes it to imageCfg's SBOM
+// directory as <project>.<format>.json, returning the digest of the manifest that carried it
+func pullImageSBOM(ctx context.Context, repo *remote.Repository, digest string, imageCfg *config.Config) (string, error) {
+	data, manifestDigest, err := trust.FetchSBOMReferrer(ctx, repo, digest)
+	if err != nil {
+		return "", fmt.Errorf("no SBOM attached to the image: %w", err)
+	}
+	doc, err := sbom.Parse(data)
+	if err != nil {
+		return "", fmt.Errorf("SBOM attached to the image (%s) is not valid: %w", manifestDigest, err)
+	}
+
+	dir := imageCfg.SBOMDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create SBOM cache directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s.%s.json", imageCfg.Project.Name, doc.Format))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to cache the image SBOM: %w", err)
+	}
+	return manifestDigest, nil
+}
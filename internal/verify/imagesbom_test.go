@@ -0,0 +1,112 @@
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// TestImageSBOM tests pulling an image's SBOM referrer into the digest's SBOM cache, that it
+// satisfies the SBOM presence check, and that verify uses the cached copy afterwards
+func TestImageSBOM(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	imageDigest := strings.Repeat("ab", 32)
+	imageRef := "ghcr.io/example/app@sha256:" + imageDigest
+
+	sha := func(data []byte) string {
+		sum := sha256.Sum256(data)
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+	sbomData := []byte(`{"spdxVersion":"SPDX-2.3","name":"app","packages":[{"name":"openssl","versionInfo":"3.0.13"}]}`)
+	manifest, _ := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/spdx+json",
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers:       []ocispec.Descriptor{{MediaType: "application/spdx+json", Digest: digest.Digest(sha(sbomData)), Size: int64(len(sbomData))}},
+		Subject:      &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.Digest("sha256:" + imageDigest), Size: 2},
+	})
+	index, _ := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/spdx+json", Digest: digest.Digest(sha(manifest)), Size: int64(len(manifest))}},
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content := map[string]struct {
+			mediaType string
+			data      []byte
+		}{
+			"/v2/test/repo/referrers/sha256:" + imageDigest: {ocispec.MediaTypeImageIndex, index},
+			"/v2/test/repo/manifests/" + sha(manifest):      {ocispec.MediaTypeImageManifest, manifest},
+			"/v2/test/repo/blobs/" + sha(sbomData):          {"application/spdx+json", sbomData},
+		}
+		c, ok := content[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", c.mediaType)
+		w.Header().Set("Docker-Content-Digest", sha(c.data))
+		w.Write(c.data)
+	}))
+	defer server.Close()
+
+	repo, err := remote.NewRepository(strings.TrimPrefix(server.URL, "http://") + "/test/repo")
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	repo.PlainHTTP = true
+
+	imageCfg := *cfg
+	imageCfg.SBOM.Dir = imageSBOMDir(cfg, imageDigest)
+	manifestDigest, err := pullImageSBOM(context.Background(), repo, imageDigest, &imageCfg)
+	if err != nil {
+		t.Fatalf("pullImageSBOM failed: %v", err)
+	}
+	if manifestDigest != sha(manifest) {
+		t.Errorf("expected the SBOM from manifest %s, got %s", sha(manifest), manifestDigest)
+	}
+	if present, _ := checkSBOMExists(&imageCfg); !present {
+		t.Fatal("expected the pulled SBOM to satisfy the presence check")
+	}
+	cached := filepath.Join(".acc", "sbom", "image", imageDigest, "waiver-test.spdx.json")
+	if data, _ := os.ReadFile(cached); string(data) != string(sbomData) {
+		t.Errorf("expected the SBOM cached at %s, got %q", cached, data)
+	}
+
+	// Verify uses the cached SBOM for the digest without contacting the registry
+	os.Remove(filepath.Join(".acc", "sbom", "waiver-test.spdx.json"))
+	cfg.SBOM.FromImage = true
+	result, err := Verify(cfg, imageRef, false, true, nil)
+	if err != nil || !result.SBOMPresent || result.SBOMFormat != "spdx" {
+		t.Fatalf("expected verify to pass with the image SBOM, got %v (%+v)", err, result)
+	}
+	if result.SBOMSource != cached {
+		t.Errorf("expected sbomSource %s, got %q", cached, result.SBOMSource)
+	}
+
+	// Without the image SBOM, the project's SBOM does not stand in for it
+	os.WriteFile(filepath.Join(".acc", "sbom", "waiver-test.spdx.json"), sbomData, 0644)
+	os.RemoveAll(filepath.Join(".acc", "sbom", "image"))
+	t.Setenv("PATH", "")
+	imageCfgNoDigest, _, err := imageSBOMConfig(cfg, "app:latest")
+	if err == nil {
+		t.Fatal("expected an unresolvable digest to fail")
+	}
+	if present, _ := checkSBOMExists(imageCfgNoDigest); present {
+		t.Error("expected no SBOM when the image SBOM cannot be pulled")
+	}
+}
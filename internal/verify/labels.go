@@ -0,0 +1,27 @@
+package verify
+
+import "fmt"
+
+// checkRequiredLabels returns a required-label-missing violation for each label in required
+// that is absent (or empty) in the image config labels, in the order they are required
+func checkRequiredLabels(labels map[string]string, required []string) []PolicyViolation {
+	var violations []PolicyViolation
+	seen := make(map[string]bool, len(required))
+	for _, label := range required {
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		if labels[label] != "" {
+			continue
+		}
+		violations = append(violations, PolicyViolation{
+			Rule:        "required-label-missing",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Image is missing required label %s", label),
+			Remediation: fmt.Sprintf(remediationLabelMissing, label),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckRequiredLabels(t *testing.T) {
+	labels := map[string]string{
+		"org.opencontainers.image.source": "https://github.com/org/app",
+		"team":                            "",
+	}
+	required := []string{"org.opencontainers.image.source", "org.opencontainers.image.revision", "team", "org.opencontainers.image.revision"}
+
+	violations := checkRequiredLabels(labels, required)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations (missing revision, empty team), got %+v", violations)
+	}
+	for _, v := range violations {
+		if v.Rule != "required-label-missing" || v.Severity != "critical" || v.Remediation == "" {
+			t.Errorf("unexpected violation %+v", v)
+		}
+	}
+	if violations[0].Message != "Image is missing required label org.opencontainers.image.revision" {
+		t.Errorf("unexpected message %q", violations[0].Message)
+	}
+
+	if v := checkRequiredLabels(labels, []string{"org.opencontainers.image.source"}); len(v) != 0 {
+		t.Errorf("expected no violations when the label is present, got %+v", v)
+	}
+}
+
+// TestVerify_RequiredLabels tests required labels against the inspected image config
+func TestVerify_RequiredLabels(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+
+	// Image labeled with source only
+	binDir := t.TempDir()
+	docker := "#!/bin/sh\necho '[{\"Config\":{\"User\":\"app\",\"Labels\":{\"org.opencontainers.image.source\":\"https://github.com/org/app\"}}}]'\n"
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg.Policy.RequiredLabels = []string{"org.opencontainers.image.source"}
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass with the required label present, got %v (%+v)", err, result.Violations)
+	}
+
+	cfg.Policy.RequiredLabels = []string{"org.opencontainers.image.source", "org.opencontainers.image.revision"}
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure with a required label missing, got status %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "required-label-missing" {
+		t.Errorf("expected one required-label-missing violation, got %+v", result.Violations)
+	}
+}
@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+)
+
+// checkNewPackages returns an unexpected-package violation for each package in the SBOM at
+// sbomPath whose name is not in the approved baseline SBOM. Version changes of baselined
+// packages are allowed; a baseline or SBOM that cannot be parsed fails closed.
+func checkNewPackages(baselinePath, sbomPath string) []PolicyViolation {
+	baseline, err := sbom.ParseFile(baselinePath)
+	if err != nil {
+		return []PolicyViolation{baselineInvalid(err)}
+	}
+	doc, err := sbom.ParseFile(sbomPath)
+	if err != nil {
+		return []PolicyViolation{baselineInvalid(err)}
+	}
+
+	approved := map[string]bool{}
+	for _, pkg := range baseline.Packages {
+		approved[pkg.Name] = true
+	}
+
+	var violations []PolicyViolation
+	seen := map[string]bool{}
+	for _, pkg := range sbom.Diff(baseline, doc).Added {
+		if approved[pkg.Name] || seen[pkg.Name] {
+			continue
+		}
+		seen[pkg.Name] = true
+		violations = append(violations, PolicyViolation{
+			Rule:        "unexpected-package",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Package %s %s is not in the approved SBOM baseline %s", pkg.Name, pkg.Version, baselinePath),
+			Remediation: remediationUnexpectedPackage,
+		})
+	}
+	return violations
+}
+
+func baselineInvalid(err error) PolicyViolation {
+	return PolicyViolation{
+		Rule:        "sbom-baseline-invalid",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     (&sbomBaselineError{err: err}).Error(),
+		Remediation: remediationSBOMBaseline,
+	}
+}
+
+// WriteSBOMBaseline copies the project SBOM to path as the approved package set for
+// --deny-new-packages (acc verify --write-sbom-baseline), returning its package count.
+// The SBOM must parse, so a baseline never silently approves nothing.
+func WriteSBOMBaseline(path string, cfg *config.Config) (int, error) {
+	sbomFile := findSBOMFile(cfg)
+	if sbomFile == "" {
+		return 0, fmt.Errorf("no SBOM found in %s to write as baseline", cfg.SBOMDir())
+	}
+	data, err := os.ReadFile(sbomFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read SBOM: %w", err)
+	}
+	doc, err := sbom.Parse(data)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse SBOM %s: %w", sbomFile, err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return 0, fmt.Errorf("failed to create baseline directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write SBOM baseline: %w", err)
+	}
+	return len(doc.Packages), nil
+}
@@ -0,0 +1,65 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestVerify_DenyNewPackages tests that packages outside the approved baseline fail verify
+// with one unexpected-package violation each, and that a baselined set passes
+func TestVerify_DenyNewPackages(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	sbomPath := filepath.Join(".acc", "sbom", "waiver-test.spdx.json")
+	baseline := filepath.Join(t.TempDir(), "approved", "app.spdx.json")
+
+	os.WriteFile(sbomPath, []byte(
+		`{"spdxVersion":"SPDX-2.3","packages":[{"name":"musl","versionInfo":"1.2.4"},{"name":"busybox","versionInfo":"1.36.1"}]}`), 0644)
+	count, err := WriteSBOMBaseline(baseline, cfg)
+	if err != nil || count != 2 {
+		t.Fatalf("WriteSBOMBaseline() = %d, %v; want 2 packages", count, err)
+	}
+
+	cfg.SBOM.DenyNewPackages = baseline
+	if result, err := Verify(cfg, "test:latest", false, true, nil); err != nil || result.Status != "pass" {
+		t.Fatalf("expected the baselined set to pass, got %v (%+v)", err, result.Violations)
+	}
+
+	// An upgrade of a baselined package is allowed; new packages are not
+	os.WriteFile(sbomPath, []byte(
+		`{"spdxVersion":"SPDX-2.3","packages":[{"name":"musl","versionInfo":"1.2.5"},{"name":"busybox","versionInfo":"1.36.1"},`+
+			`{"name":"curl","versionInfo":"8.5.0"},{"name":"netcat","versionInfo":"1.0"}]}`), 0644)
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure for new packages, got status %s", result.Status)
+	}
+	if len(result.Violations) != 2 {
+		t.Fatalf("expected one violation per new package, got %+v", result.Violations)
+	}
+	for i, name := range []string{"curl", "netcat"} {
+		v := result.Violations[i]
+		if v.Rule != "unexpected-package" || !strings.Contains(v.Message, name) || v.Remediation == "" {
+			t.Errorf("violation %d = %+v, want unexpected-package for %s", i, v, name)
+		}
+	}
+}
+
+func TestCheckNewPackages_InvalidBaseline(t *testing.T) {
+	dir := t.TempDir()
+	sbomPath := filepath.Join(dir, "sbom.json")
+	os.WriteFile(sbomPath, []byte(spdxWithPackages(1)), 0644)
+
+	violations := checkNewPackages(filepath.Join(dir, "missing.json"), sbomPath)
+	if len(violations) != 1 || violations[0].Rule != "sbom-baseline-invalid" {
+		t.Errorf("expected sbom-baseline-invalid for a missing baseline, got %+v", violations)
+	}
+}
+
+func TestWriteSBOMBaseline_RequiresParseableSBOM(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	// setupWaiverProject writes "{}", which is not a recognizable SBOM
+	if _, err := WriteSBOMBaseline(filepath.Join(t.TempDir(), "baseline.json"), cfg); err == nil {
+		t.Error("expected error for an unparseable SBOM")
+	}
+}
@@ -0,0 +1,34 @@
+package verify
+
+import "strings"
+
+// checkNonRoot returns a no-root-user violation when the image runs as root: no USER, or a
+// user of root or UID 0 (with or without a group, e.g. "0:0" or "root:app")
+func checkNonRoot(user string) *PolicyViolation {
+	name, _, _ := strings.Cut(strings.TrimSpace(user), ":")
+	if name != "" && name != "0" && name != "root" {
+		return nil
+	}
+
+	message := "Image runs as root (no USER set)"
+	if name != "" {
+		message = "Image runs as root (USER " + user + ")"
+	}
+	return &PolicyViolation{
+		Rule:        "no-root-user",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     message,
+		Remediation: remediationNonRoot,
+	}
+}
+
+// hasViolation reports whether violations include one for rule
+func hasViolation(violations []PolicyViolation, rule string) bool {
+	for _, v := range violations {
+		if v.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
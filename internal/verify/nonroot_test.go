@@ -0,0 +1,65 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckNonRoot(t *testing.T) {
+	tests := []struct {
+		user string
+		root bool
+	}{
+		{"", true},
+		{"0", true},
+		{"root", true},
+		{"0:0", true},
+		{"root:app", true},
+		{"app", false},
+		{"10001", false},
+		{"10001:0", false},
+	}
+	for _, tt := range tests {
+		violation := checkNonRoot(tt.user)
+		if (violation != nil) != tt.root {
+			t.Errorf("checkNonRoot(%q) = %+v, want root=%v", tt.user, violation, tt.root)
+			continue
+		}
+		if violation != nil && (violation.Rule != "no-root-user" || violation.Severity != "critical" || violation.Remediation == "") {
+			t.Errorf("unexpected violation for %q: %+v", tt.user, violation)
+		}
+	}
+}
+
+// TestVerify_RequireNonRoot tests the built-in check without OPA or a policy, and that waivers cover it
+func TestVerify_RequireNonRoot(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	os.RemoveAll(filepath.Join(".acc", "policy"))
+	cfg.Policy.RequireNonRoot = true
+
+	// setupWaiverProject's image runs as root
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || len(result.Violations) != 1 || result.Violations[0].Rule != "no-root-user" {
+		t.Fatalf("expected a no-root-user violation, got %v (%+v)", err, result.Violations)
+	}
+
+	waivers := `waivers:
+  - ruleId: no-root-user
+    justification: legacy base image
+    expiry: "2999-01-01T00:00:00Z"
+`
+	os.WriteFile(filepath.Join(".acc", "waivers.yaml"), []byte(waivers), 0644)
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" || len(result.PolicyResult.Warnings) != 1 {
+		t.Errorf("expected the waiver to downgrade no-root-user, got %v (%+v)", err, result.PolicyResult)
+	}
+
+	os.WriteFile(filepath.Join(".acc", "waivers.yaml"), []byte("waivers: []\n"), 0644)
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":{\"User\":\"10001\",\"Labels\":null}}]'\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if result, err := Verify(cfg, "test:latest", false, true, nil); err != nil || result.Status != "pass" {
+		t.Errorf("expected a non-root image to pass, got %v (%+v)", err, result.Violations)
+	}
+}
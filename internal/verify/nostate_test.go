@@ -0,0 +1,59 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestVerify_NoState tests that policy.noState (--no-state) writes nothing under .acc/state,
+// for passing and failing runs, while postVerify hooks still receive the result
+func TestVerify_NoState(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	cfg.Policy.NoState = true
+	postLog := filepath.Join(t.TempDir(), "post.log")
+	cfg.Hooks.PostVerify = []string{`cp "$ACC_VERIFY_RESULT" ` + postLog}
+
+	// A digest reference would also write .acc/state/verify/<digest>.json
+	ref := "test@sha256:" + strings.Repeat("a", 64)
+	result, err := Verify(cfg, ref, false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(".acc", "state")); !os.IsNotExist(err) {
+		t.Fatalf("expected no .acc/state with --no-state, got %v", err)
+	}
+
+	data, err := os.ReadFile(postLog)
+	if err != nil {
+		t.Fatalf("postVerify hook did not get a result file: %v", err)
+	}
+	var hookState VerifyState
+	if err := json.Unmarshal(data, &hookState); err != nil || hookState.ImageRef != ref || hookState.Status != "pass" {
+		t.Errorf("unexpected hook result %s (%v)", data, err)
+	}
+
+	// A failing run keeps its exit status but still writes nothing
+	os.Remove(filepath.Join(".acc", "sbom", "waiver-test.spdx.json"))
+	result, err = Verify(cfg, ref, false, true, nil)
+	if err == nil || result.Status != "fail" || result.ExitCode() != 1 {
+		t.Fatalf("expected failure without an SBOM, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(".acc", "state")); !os.IsNotExist(err) {
+		t.Fatalf("expected no .acc/state after a failed --no-state run, got %v", err)
+	}
+
+	// Without the flag, state is written as before
+	cfg.Policy.NoState = false
+	Verify(cfg, ref, false, true, nil)
+	for _, path := range []string{
+		filepath.Join(".acc", "state", "last_verify.json"),
+		filepath.Join(".acc", "state", "verify", strings.Repeat("a", 64)+".json"),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s without --no-state: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cloudcwfranck/acc/internal/ui"
+)
+
+// minOPAVersion is the oldest OPA known to evaluate acc policies: the default
+// policy uses `import rego.v1`, which OPA added in v0.59.0
+const minOPAVersion = "0.59.0"
+
+const remediationOPAVersionUnsupported = "Upgrade OPA to v" + minOPAVersion + " or later: https://www.openpolicyagent.org/docs/latest/#running-opa"
+
+// opaVersions caches the detected version per opa binary for the life of the process
+var (
+	opaVersionsMu sync.Mutex
+	opaVersions   = map[string]string{}
+)
+
+var opaVersionLine = regexp.MustCompile(`(?m)^Version:\s*v?(\S+)`)
+
+// checkOPA locates opa and checks its version. It returns the opa path, or the
+// violation to report instead of evaluating policy (opa-required or opa-version-unsupported).
+// A version that cannot be detected is not treated as unsupported.
+func checkOPA() (string, *PolicyViolation) {
+	opaPath, err := exec.LookPath("opa")
+	if err != nil {
+		// v0.1.4: OPA missing is a CRITICAL VIOLATION, not a bypass,
+		// even with the ACC_ALLOW_NO_OPA=1 escape hatch used by tests
+		return "", &PolicyViolation{
+			Rule:        "opa-required",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     "OPA not found. Policy evaluation requires OPA to be installed.\n\nInstall OPA: https://www.openpolicyagent.org/docs/latest/#running-opa",
+			Remediation: remediationOPARequired,
+		}
+	}
+
+	version := detectOPAVersion(opaPath)
+	if version == "" {
+		ui.PrintDebug(fmt.Sprintf("could not detect OPA version for %s; assuming it is supported", opaPath))
+		return opaPath, nil
+	}
+	if supported, ok := versionAtLeast(version, minOPAVersion); ok && !supported {
+		return "", &PolicyViolation{
+			Rule:        "opa-version-unsupported",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("OPA v%s is not supported. Policy evaluation requires OPA v%s or later.", version, minOPAVersion),
+			Remediation: remediationOPAVersionUnsupported,
+		}
+	}
+	return opaPath, nil
+}
+
+// policyUnevaluatedWarning is reported instead of opa-required when opa is missing and
+// policy.allowMissingOpa (verify --allow-missing-opa-advisory) is set: the run passes, but
+// its policies were never evaluated
+func policyUnevaluatedWarning() PolicyViolation {
+	return PolicyViolation{
+		Rule:        "policy-unevaluated",
+		Severity:    "high",
+		Result:      "warn",
+		Message:     "OPA not found; policies were not evaluated (advisory mode: --allow-missing-opa-advisory)",
+		Remediation: remediationOPARequired,
+	}
+}
+
+// detectOPAVersion returns the version reported by opaPath (without a "v" prefix),
+// or "" when it cannot be determined. Results are cached per binary path.
+func detectOPAVersion(opaPath string) string {
+	opaVersionsMu.Lock()
+	defer opaVersionsMu.Unlock()
+
+	if version, ok := opaVersions[opaPath]; ok {
+		return version
+	}
+
+	version := ""
+	if output, err := exec.Command(opaPath, "version", "--format", "json").Output(); err == nil {
+		version = parseOPAVersion(output)
+	}
+	if version == "" {
+		// Older releases have no --format flag for version; fall back to the text output
+		if output, err := exec.Command(opaPath, "version").Output(); err == nil {
+			version = parseOPAVersion(output)
+		}
+	}
+
+	opaVersions[opaPath] = version
+	return version
+}
+
+// parseOPAVersion extracts the version from `opa version` output, JSON or text
+func parseOPAVersion(output []byte) string {
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.Unmarshal(output, &info); err == nil && info.Version != "" {
+		return strings.TrimPrefix(info.Version, "v")
+	}
+	if m := opaVersionLine.FindSubmatch(output); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// versionAtLeast reports whether version >= minimum, comparing MAJOR.MINOR.PATCH and
+// ignoring pre-release suffixes. ok is false when version cannot be parsed (e.g. "dev").
+func versionAtLeast(version, minimum string) (atLeast bool, ok bool) {
+	v, vok := parseSemver(version)
+	m, mok := parseSemver(minimum)
+	if !vok || !mok {
+		return false, false
+	}
+	for i := range v {
+		if v[i] != m[i] {
+			return v[i] > m[i], true
+		}
+	}
+	return true, true
+}
+
+func parseSemver(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
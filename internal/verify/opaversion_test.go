@@ -0,0 +1,149 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeVersionedOPA installs an opa that reports version and records each evaluation
+func fakeVersionedOPA(t *testing.T, versionScript string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	evalFile := filepath.Join(binDir, "evals")
+	script := `#!/bin/sh
+if [ "$1" = "version" ]; then
+` + versionScript + `
+fi
+echo x >> ` + evalFile + `
+echo '{"result":[{"expressions":[{"value":{"violations":[]}}]}]}'
+`
+	if err := os.WriteFile(filepath.Join(binDir, "opa"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake opa: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return evalFile
+}
+
+// TestEvaluateRego_OPAVersionTooOld tests that an old OPA yields opa-version-unsupported instead of running eval
+func TestEvaluateRego_OPAVersionTooOld(t *testing.T) {
+	// Old releases reject --format on version and only print text
+	evalFile := fakeVersionedOPA(t, `  if [ "$2" = "--format" ]; then echo "Error: unknown flag: --format" >&2; exit 1; fi
+  printf 'Version: 0.45.0\nBuild Commit: abc\n'; exit 0`)
+
+	violations, err := evaluateRego(t.TempDir(), &RegoInput{})
+	if err != nil {
+		t.Fatalf("evaluateRego returned error %v, want a violation", err)
+	}
+	if len(violations) != 1 || violations[0].Rule != "opa-version-unsupported" {
+		t.Fatalf("violations = %+v, want one opa-version-unsupported", violations)
+	}
+	v := violations[0]
+	if v.Severity != "critical" || v.Result != "fail" {
+		t.Errorf("severity/result = %s/%s, want critical/fail", v.Severity, v.Result)
+	}
+	if !strings.Contains(v.Message, "v0.45.0") || !strings.Contains(v.Message, "v"+minOPAVersion) {
+		t.Errorf("message %q should name the detected and minimum versions", v.Message)
+	}
+	if !strings.Contains(v.Remediation, "Upgrade OPA") {
+		t.Errorf("remediation = %q, want upgrade guidance", v.Remediation)
+	}
+	if _, err := os.Stat(evalFile); err == nil {
+		t.Error("opa eval ran with an unsupported OPA version")
+	}
+}
+
+// TestEvaluateRego_OPAVersionSupported tests that a supported OPA evaluates normally and is probed once
+func TestEvaluateRego_OPAVersionSupported(t *testing.T) {
+	binDir := t.TempDir()
+	probeFile := filepath.Join(binDir, "probes")
+	evalFile := fakeVersionedOPA(t, `  echo x >> `+probeFile+`
+  echo '{"Version":"0.68.0","Build-Commit":"abc"}'; exit 0`)
+
+	for i := 0; i < 2; i++ {
+		violations, err := evaluateRego(t.TempDir(), &RegoInput{})
+		if err != nil {
+			t.Fatalf("evaluateRego failed: %v", err)
+		}
+		if len(violations) != 0 {
+			t.Fatalf("violations = %+v, want none", violations)
+		}
+	}
+
+	probes, _ := os.ReadFile(probeFile)
+	if n := strings.Count(string(probes), "x"); n != 1 {
+		t.Errorf("opa version ran %d times, want 1 (cached per process)", n)
+	}
+	evals, _ := os.ReadFile(evalFile)
+	if n := strings.Count(string(evals), "x"); n != 2 {
+		t.Errorf("opa eval ran %d times, want 2", n)
+	}
+}
+
+// TestEvaluateRego_OPAVersionUndetectable tests that an unknown version does not block evaluation
+func TestEvaluateRego_OPAVersionUndetectable(t *testing.T) {
+	evalFile := fakeVersionedOPA(t, `  echo "garbage"; exit 0`)
+
+	if _, err := evaluateRego(t.TempDir(), &RegoInput{}); err != nil {
+		t.Fatalf("evaluateRego failed: %v", err)
+	}
+	if _, err := os.Stat(evalFile); err != nil {
+		t.Error("expected opa eval to run when the version cannot be detected")
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+		ok      bool
+	}{
+		{"0.59.0", true, true},
+		{"0.58.1", false, true},
+		{"1.0.0", true, true},
+		{"0.60.0-dev", true, true},
+		{"dev", false, false},
+	}
+	for _, tt := range tests {
+		got, ok := versionAtLeast(tt.version, minOPAVersion)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("versionAtLeast(%q) = %v, %v; want %v, %v", tt.version, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestVerify_AllowMissingOPAAdvisory tests that a missing opa fails with a critical opa-required
+// violation by default, and only downgrades to a policy-unevaluated warning in advisory mode
+func TestVerify_AllowMissingOPAAdvisory(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+
+	// PATH holds docker but no opa
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":{\"User\":\"root\",\"Labels\":null}}]'\n"), 0755)
+	t.Setenv("PATH", binDir)
+
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" || result.PolicyUnevaluated {
+		t.Fatalf("expected a failure without opa by default, got status %s (unevaluated %v)", result.Status, result.PolicyUnevaluated)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "opa-required" || result.Violations[0].Severity != "critical" {
+		t.Fatalf("expected one critical opa-required violation, got %+v", result.Violations)
+	}
+
+	cfg.Policy.AllowMissingOPA = true
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected advisory mode to pass, got %v (%+v)", err, result.Violations)
+	}
+	if !result.PolicyUnevaluated {
+		t.Error("expected policyUnevaluated to be set")
+	}
+	if len(result.Violations) != 0 {
+		t.Errorf("expected no violations, got %+v", result.Violations)
+	}
+	warnings := result.PolicyResult.Warnings
+	if len(warnings) != 1 || warnings[0].Rule != "policy-unevaluated" || warnings[0].Result != "warn" {
+		t.Errorf("expected one policy-unevaluated warning, got %+v", warnings)
+	}
+}
@@ -0,0 +1,35 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVerify_PolicyPack tests that a named pack is evaluated instead of .acc/policy
+func TestVerify_PolicyPack(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	writePolicy(t, filepath.Join(".acc", "policy-packs", "strict", "strict.rego"))
+	fakeGroupOPA(t)
+
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Violations[0].Rule != "policy" {
+		t.Fatalf("expected the default .acc/policy to be evaluated, got %+v", result.Violations)
+	}
+
+	cfg.Policy.Pack = "strict"
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || len(result.Violations) != 1 || result.Violations[0].Rule != "strict" {
+		t.Fatalf("expected only the strict pack to be evaluated, got %+v", result.Violations)
+	}
+
+	cfg.Policy.Pack = "nope"
+	result, _ = Verify(cfg, "test:latest", false, true, nil)
+	if result.Status != "fail" || result.Violations[0].Rule != "policy-evaluation-error" {
+		t.Errorf("expected an unknown pack to fail verification, got %s %+v", result.Status, result.Violations)
+	}
+
+	if _, err := os.Stat(filepath.Join(".acc", "policy", "policy.rego")); err != nil {
+		t.Errorf("project policy should be untouched: %v", err)
+	}
+}
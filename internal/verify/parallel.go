@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudcwfranck/acc/internal/pool"
+)
+
+// policyGroup is a set of policy files evaluated in one OPA invocation
+type policyGroup struct {
+	Dir   string   // reported as the violation source
+	Paths []string // passed to opa eval as --data
+}
+
+// policyGroups splits policyDir for parallel evaluation: each subdirectory containing
+// .rego files is a group, and .rego files directly in policyDir form the root group
+// Groups are returned root first, then subdirectories in name order
+func policyGroups(policyDir string) ([]policyGroup, error) {
+	entries, err := os.ReadDir(policyDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []policyGroup
+	root := policyGroup{Dir: policyDir}
+	for _, entry := range entries {
+		path := filepath.Join(policyDir, entry.Name())
+		if !entry.IsDir() {
+			if filepath.Ext(entry.Name()) == ".rego" {
+				root.Paths = append(root.Paths, path)
+			}
+			continue
+		}
+
+		hasRego, err := containsRego(path)
+		if err != nil {
+			return nil, err
+		}
+		if hasRego {
+			groups = append(groups, policyGroup{Dir: path, Paths: []string{path}})
+		}
+	}
+
+	if len(root.Paths) > 0 {
+		groups = append([]policyGroup{root}, groups...)
+	}
+	return groups, nil
+}
+
+// containsRego reports whether dir contains any .rego file
+func containsRego(dir string) (bool, error) {
+	found := false
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".rego" {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found, err
+}
+
+// evaluateRegoGroups evaluates each policy group in its own OPA invocation, in parallel
+// (at most --concurrency at once).
+// Violations are aggregated in group order and attributed to their group's directory (or, with
+// policy.explainDeny, the rule body OPA reports);
+// any violation from any group denies, as with a single evaluation.
+func evaluateRegoGroups(groups []policyGroup, opts regoOptions, input *RegoInput) ([]PolicyViolation, error) {
+	// Without a usable OPA every group would report the same violation
+	if _, violation := checkOPA(); violation != nil {
+		return []PolicyViolation{*violation}, nil
+	}
+
+	results := make([][]PolicyViolation, len(groups))
+	errs := make([]error, len(groups))
+
+	pool.Default().Each(len(groups), func(i int) {
+		group := groups[i]
+		violations, err := evaluateRegoPaths(group.Paths, opts, input)
+		if err != nil {
+			errs[i] = fmt.Errorf("policy group %s: %w", group.Dir, err)
+			return
+		}
+		// --explain-deny already located violations in the group's files
+		for j := range violations {
+			if violations[j].Source == "" {
+				violations[j].Source = group.Dir
+			}
+		}
+		results[i] = violations
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var violations []PolicyViolation
+	for _, groupViolations := range results {
+		violations = append(violations, groupViolations...)
+	}
+	return violations, nil
+}
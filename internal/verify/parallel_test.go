@@ -0,0 +1,144 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/profile"
+)
+
+// fakeGroupOPA installs an opa that reports one violation named after the --data paths it was given
+func fakeGroupOPA(t *testing.T) {
+	t.Helper()
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+rule=""
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--data" ]; then rule="$rule$(basename "$2")"; shift; fi
+  shift
+done
+echo "{\"result\":[{\"expressions\":[{\"value\":{\"violations\":[{\"rule\":\"$rule\",\"severity\":\"high\",\"result\":\"fail\",\"message\":\"denied\"}]}}]}]}"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "opa"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake opa: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func writePolicy(t *testing.T, path string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte("package acc.policy\n"), 0644); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+}
+
+func TestPolicyGroups(t *testing.T) {
+	policyDir := t.TempDir()
+	writePolicy(t, filepath.Join(policyDir, "default.rego"))
+	writePolicy(t, filepath.Join(policyDir, "storage", "volumes.rego"))
+	writePolicy(t, filepath.Join(policyDir, "network", "nested", "egress.rego"))
+	os.MkdirAll(filepath.Join(policyDir, "docs"), 0755)
+	os.WriteFile(filepath.Join(policyDir, "docs", "README.md"), []byte("not policy"), 0644)
+
+	groups, err := policyGroups(policyDir)
+	if err != nil {
+		t.Fatalf("policyGroups failed: %v", err)
+	}
+
+	want := []policyGroup{
+		{Dir: policyDir, Paths: []string{filepath.Join(policyDir, "default.rego")}},
+		{Dir: filepath.Join(policyDir, "network"), Paths: []string{filepath.Join(policyDir, "network")}},
+		{Dir: filepath.Join(policyDir, "storage"), Paths: []string{filepath.Join(policyDir, "storage")}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("policyGroups = %+v, want %+v", groups, want)
+	}
+}
+
+// TestEvaluateRegoGroups tests that each policy group contributes its violation, attributed to its directory
+func TestEvaluateRegoGroups(t *testing.T) {
+	fakeGroupOPA(t)
+
+	policyDir := t.TempDir()
+	writePolicy(t, filepath.Join(policyDir, "network", "egress.rego"))
+	writePolicy(t, filepath.Join(policyDir, "storage", "volumes.rego"))
+
+	groups, err := policyGroups(policyDir)
+	if err != nil {
+		t.Fatalf("policyGroups failed: %v", err)
+	}
+
+	violations, err := evaluateRegoGroups(groups, regoOptions{}, &RegoInput{Config: ImageConfig{Labels: map[string]string{}}})
+	if err != nil {
+		t.Fatalf("evaluateRegoGroups failed: %v", err)
+	}
+
+	if len(violations) != 2 {
+		t.Fatalf("expected one violation per group, got %+v", violations)
+	}
+	wantSources := map[string]string{
+		"network": filepath.Join(policyDir, "network"),
+		"storage": filepath.Join(policyDir, "storage"),
+	}
+	for i, rule := range []string{"network", "storage"} {
+		if violations[i].Rule != rule || violations[i].Source != wantSources[rule] {
+			t.Errorf("violation %d = %+v, want rule %q from %q", i, violations[i], rule, wantSources[rule])
+		}
+	}
+}
+
+// TestEvaluateRegoGroups_GroupFailure tests that a failing group fails the evaluation with the group named
+func TestEvaluateRegoGroups_GroupFailure(t *testing.T) {
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte("#!/bin/sh\necho 'rego_parse_error' >&2\nexit 1\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	policyDir := t.TempDir()
+	writePolicy(t, filepath.Join(policyDir, "broken", "bad.rego"))
+
+	groups, _ := policyGroups(policyDir)
+	_, err := evaluateRegoGroups(groups, regoOptions{}, &RegoInput{})
+	if err == nil {
+		t.Fatal("expected evaluation error from failing group")
+	}
+	if want := filepath.Join(policyDir, "broken"); !contains(err.Error(), want) {
+		t.Errorf("expected error to name group %s, got: %v", want, err)
+	}
+}
+
+// TestVerify_ParallelOPAWithProfile tests that violations and profile warnings keep the source
+// group policy.parallelOpa attributed them to
+func TestVerify_ParallelOPAWithProfile(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	fakeGroupOPA(t)
+	writePolicy(t, filepath.Join(".acc", "policy", "network", "egress.rego"))
+	writePolicy(t, filepath.Join(".acc", "policy", "storage", "volumes.rego"))
+	cfg.Policy.ParallelOPA = true
+
+	prof := &profile.Profile{
+		Name:       "lenient",
+		Violations: profile.ViolationConfig{Ignore: []string{"network"}},
+		Warnings:   profile.WarningConfig{Show: true},
+	}
+	result, _ := Verify(cfg, "test:latest", false, true, prof)
+	if result == nil || result.PolicyResult == nil {
+		t.Fatal("expected a policy result")
+	}
+
+	sources := map[string]string{}
+	for _, v := range append(result.Violations, result.PolicyResult.Warnings...) {
+		sources[v.Rule] = v.Source
+	}
+	want := map[string]string{
+		"network": filepath.Join(".acc", "policy", "network"),
+		"storage": filepath.Join(".acc", "policy", "storage"),
+	}
+	for rule, source := range want {
+		if sources[rule] != source {
+			t.Errorf("expected %s to keep source %s under the profile, got %q (%+v)", rule, source, sources[rule], sources)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// Verification phases (policy.phase / verify --two-phase)
+const (
+	PhaseBuild  = "build"  // SBOM and policy gates; signature, provenance, and freshness checks are skipped
+	PhaseDeploy = "deploy" // signature, provenance, and freshness checks, after a passing build phase
+)
+
+// phaseConfig returns the config verify runs with for cfg.Policy.Phase. The build phase turns
+// off the checks that need a pushed, signed image (image and SBOM signatures, provenance, SBOM
+// freshness); the deploy phase turns on image signature, provenance, and freshness checks. The
+// rest of acc.yaml applies to both, so one config serves the whole pipeline.
+func phaseConfig(cfg *config.Config) *config.Config {
+	switch cfg.Policy.Phase {
+	case PhaseBuild:
+		phased := *cfg
+		phased.Policy.VerifyImageSignature = false
+		phased.Policy.RequireSBOMSigned = false
+		phased.Policy.RequireProvenance = false
+		phased.Policy.ProvenanceSource = ""
+		phased.SBOM.SinceBuild = false
+		return &phased
+	case PhaseDeploy:
+		phased := *cfg
+		phased.Policy.VerifyImageSignature = true
+		phased.Policy.RequireProvenance = true
+		phased.SBOM.SinceBuild = true
+		return &phased
+	}
+	return cfg
+}
+
+// buildPhaseStatePath is where the build-phase verification of digest is kept, apart from
+// .acc/state/verify/<digest>.json so later (deploy-phase) runs don't overwrite it
+func buildPhaseStatePath(digest string) string {
+	return filepath.Join(".acc", "state", "verify", oci.DigestFileName(digest)+".build.json")
+}
+
+// checkBuildPhase returns a build-phase-missing violation unless a build-phase verification of
+// imageRef's digest is recorded and did not fail
+func checkBuildPhase(imageRef string) *PolicyViolation {
+	violation := func(message string) *PolicyViolation {
+		return &PolicyViolation{
+			Rule:        "build-phase-missing",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     message,
+			Remediation: remediationBuildPhase,
+		}
+	}
+
+	digest, err := oci.ResolveDigest(imageRef)
+	if err != nil {
+		return violation(fmt.Sprintf("Cannot look up the build-phase verification: %v", err))
+	}
+	data, err := os.ReadFile(buildPhaseStatePath(digest))
+	if err != nil {
+		return violation(fmt.Sprintf("No build-phase verification recorded for %s", oci.QualifiedDigest(digest)))
+	}
+
+	var buildState VerifyState
+	if err := json.Unmarshal(data, &buildState); err != nil || buildState.Phase != PhaseBuild {
+		return violation(fmt.Sprintf("Build-phase verification record for %s is not valid", oci.QualifiedDigest(digest)))
+	}
+	if buildState.Status == "fail" {
+		return violation(fmt.Sprintf("Build-phase verification of %s failed (%s)", oci.QualifiedDigest(digest), buildState.Timestamp))
+	}
+	return nil
+}
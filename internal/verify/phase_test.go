@@ -0,0 +1,126 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func hasRule(violations []PolicyViolation, rule string) bool {
+	for _, v := range violations {
+		if v.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// TestVerify_TwoPhase tests that the build phase skips deploy-time checks and that the deploy
+// phase fails without a passing build phase for the digest
+func TestVerify_TwoPhase(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	digest := strings.Repeat("ab", 32)
+	imageRef := "ghcr.io/example/app@sha256:" + digest
+
+	// Deploy before any build phase
+	cfg.Policy.Phase = PhaseDeploy
+	result, err := Verify(cfg, imageRef, false, true, nil)
+	if err == nil || !hasRule(result.Violations, "build-phase-missing") {
+		t.Fatalf("expected build-phase-missing without a build phase, got %v (%+v)", err, result.Violations)
+	}
+	if !hasRule(result.Violations, "provenance-missing") || result.Phase != PhaseDeploy {
+		t.Errorf("expected the deploy phase to require provenance, got %+v", result)
+	}
+
+	// The build phase skips signature and provenance checks even when configured
+	cfg.Policy.Phase = PhaseBuild
+	cfg.Policy.VerifyImageSignature = true
+	cfg.Policy.RequireProvenance = true
+	result, err = Verify(cfg, imageRef, false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected the build phase to pass, got %v (%+v)", err, result.Violations)
+	}
+	data, err := os.ReadFile(buildPhaseStatePath(digest))
+	if err != nil {
+		t.Fatalf("expected the build phase to be recorded: %v", err)
+	}
+	var recorded VerifyState
+	if json.Unmarshal(data, &recorded); recorded.Phase != PhaseBuild || recorded.Status != "pass" {
+		t.Errorf("expected a passing build-phase record, got phase %q status %q", recorded.Phase, recorded.Status)
+	}
+
+	// The deploy phase now finds the build phase (and still fails on missing provenance)
+	cfg.Policy.Phase = PhaseDeploy
+	result, _ = Verify(cfg, imageRef, false, true, nil)
+	if hasRule(result.Violations, "build-phase-missing") {
+		t.Errorf("expected the recorded build phase to satisfy the deploy phase, got %+v", result.Violations)
+	}
+	if data, _ := os.ReadFile(buildPhaseStatePath(digest)); !strings.Contains(string(data), `"phase": "build"`) {
+		t.Error("expected the deploy phase to leave the build-phase record in place")
+	}
+
+	// A failed build phase does not count
+	cfg = setupWaiverProject(t, "waivers: []\n", `{"rule":"no-root-user","severity":"critical","result":"fail","message":"root"}`)
+	cfg.Policy.Phase = PhaseBuild
+	if _, err := Verify(cfg, imageRef, false, true, nil); err == nil {
+		t.Fatal("expected the build phase to fail")
+	}
+	if violation := checkBuildPhase(imageRef); violation == nil || !strings.Contains(violation.Message, "failed") {
+		t.Errorf("expected a failed build phase to be rejected, got %+v", violation)
+	}
+	if violation := checkBuildPhase("ghcr.io/example/app@sha256:" + strings.Repeat("0", 64)); violation == nil {
+		t.Error("expected another digest to have no build phase")
+	}
+}
+
+// TestVerify_TwoPhaseStateUnsaved tests that a build phase whose record cannot be written
+// reports it instead of leaving the deploy phase to fail with no build phase recorded
+func TestVerify_TwoPhaseStateUnsaved(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	cfg.Policy.Phase = PhaseBuild
+	imageRef := "ghcr.io/example/app@sha256:" + strings.Repeat("ab", 32)
+
+	// A file where the verify state directory belongs makes the digest-scoped writes fail
+	os.MkdirAll(filepath.Join(".acc", "state"), 0755)
+	if err := os.WriteFile(filepath.Join(".acc", "state", "verify"), nil, 0644); err != nil {
+		t.Fatalf("failed to block the verify state directory: %v", err)
+	}
+	result, err := Verify(cfg, imageRef, false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected the build phase itself to pass, got %v (%+v)", err, result.Violations)
+	}
+	if result.StateError == "" {
+		t.Error("expected the unsaved build-phase record to be reported")
+	}
+
+	cfg.Policy.NoState = true
+	if result, _ = Verify(cfg, imageRef, false, true, nil); result.StateError != "" {
+		t.Errorf("expected --no-state to report nothing, got %q", result.StateError)
+	}
+}
+
+func TestPhaseConfig(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	cfg.Policy.VerifyImageSignature = true
+	cfg.SBOM.SinceBuild = true
+
+	cfg.Policy.Phase = PhaseBuild
+	build := phaseConfig(cfg)
+	if build.Policy.VerifyImageSignature || build.SBOM.SinceBuild || !cfg.Policy.VerifyImageSignature {
+		t.Errorf("expected the build phase to skip signature and freshness without changing the config, got %+v", build.Policy)
+	}
+
+	cfg.Policy.VerifyImageSignature = false
+	cfg.Policy.Phase = PhaseDeploy
+	deploy := phaseConfig(cfg)
+	if !deploy.Policy.VerifyImageSignature || !deploy.Policy.RequireProvenance || !deploy.SBOM.SinceBuild {
+		t.Errorf("expected the deploy phase to require signature, provenance, and freshness, got %+v", deploy.Policy)
+	}
+
+	cfg.Policy.Phase = ""
+	if phaseConfig(cfg) != cfg {
+		t.Error("expected no phase to leave the config as is")
+	}
+}
@@ -0,0 +1,25 @@
+package verify
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// digestPinnedPattern matches references pinned to a content digest (name@sha256:<64 hex> or
+// name@sha512:<128 hex>)
+var digestPinnedPattern = regexp.MustCompile(`@(?:sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`)
+
+// checkDigestPinned returns a tag-not-digest-pinned violation when imageRef names a mutable
+// tag (or no tag at all) instead of @sha256:<digest>; a tag alongside the digest is allowed
+func checkDigestPinned(imageRef string) *PolicyViolation {
+	if digestPinnedPattern.MatchString(imageRef) {
+		return nil
+	}
+	return &PolicyViolation{
+		Rule:        "tag-not-digest-pinned",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     fmt.Sprintf("Image reference %s is not pinned to a digest (@sha256:...)", imageRef),
+		Remediation: remediationDigestPinned,
+	}
+}
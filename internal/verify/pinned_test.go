@@ -0,0 +1,74 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckDigestPinned(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	tests := []struct {
+		ref    string
+		pinned bool
+	}{
+		{ref: "myapp:latest"},
+		{ref: "myapp"},
+		{ref: "ghcr.io/org/app:1.0"},
+		{ref: "localhost:5000/app:1.0"},
+		{ref: "ghcr.io/org/app@sha256:" + digest[:12]},
+		{ref: "ghcr.io/org/app@sha256:" + digest, pinned: true},
+		{ref: "ghcr.io/org/app:1.0@sha256:" + digest, pinned: true},
+		{ref: "localhost:5000/app@sha256:" + digest, pinned: true},
+	}
+
+	for _, tt := range tests {
+		violation := checkDigestPinned(tt.ref)
+		if (violation == nil) != tt.pinned {
+			t.Errorf("checkDigestPinned(%q) = %+v, want pinned=%v", tt.ref, violation, tt.pinned)
+			continue
+		}
+		if violation != nil && (violation.Rule != "tag-not-digest-pinned" || violation.Severity != "critical" || violation.Remediation == "") {
+			t.Errorf("unexpected violation %+v", violation)
+		}
+	}
+}
+
+// TestVerify_RequireDigestPinned tests the gate with a tag-only and a digest-pinned reference
+func TestVerify_RequireDigestPinned(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+
+	// Off by default: tags are accepted
+	if result, err := Verify(cfg, "test:latest", false, true, nil); err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass without requireDigestPinned, got %v", err)
+	}
+
+	cfg.Policy.RequireDigestPinned = true
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure for a tag-only reference, got status %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "tag-not-digest-pinned" {
+		t.Errorf("expected one tag-not-digest-pinned violation, got %+v", result.Violations)
+	}
+
+	result, err = Verify(cfg, "test@sha256:"+strings.Repeat("ab", 32), false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass for a digest-pinned reference, got %v (%+v)", err, result.Violations)
+	}
+}
+
+// TestVerify_DigestPinnedComposesWithPolicy tests that the ref check and Rego violations are both reported
+func TestVerify_DigestPinnedComposesWithPolicy(t *testing.T) {
+	latest := `{"rule":"no-latest-tag","severity":"high","result":"fail","message":"uses latest"}`
+	cfg := setupWaiverProject(t, "waivers: []\n", latest)
+	cfg.Policy.RequireDigestPinned = true
+
+	result, _ := Verify(cfg, "test:latest", false, true, nil)
+	rules := map[string]bool{}
+	for _, v := range result.Violations {
+		rules[v.Rule] = true
+	}
+	if !rules["no-latest-tag"] || !rules["tag-not-digest-pinned"] || len(result.Violations) != 2 {
+		t.Errorf("expected both the Rego and digest-pinning violations, got %+v", result.Violations)
+	}
+}
@@ -0,0 +1,51 @@
+package verify
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// privilegedPortLimit is the first unprivileged port; binding below it needs root or CAP_NET_BIND_SERVICE
+const privilegedPortLimit = 1024
+
+// checkPrivilegedPorts returns a privileged-port finding for each exposed port below 1024, in
+// port order. They are violations unless severity is warning (policy.privilegedPortSeverity).
+func checkPrivilegedPorts(exposed map[string]struct{}, severity string) []PolicyViolation {
+	type port struct {
+		spec   string
+		number int
+	}
+	var ports []port
+	for spec := range exposed {
+		number, err := strconv.Atoi(strings.SplitN(spec, "/", 2)[0])
+		if err != nil || number >= privilegedPortLimit {
+			continue
+		}
+		ports = append(ports, port{spec, number})
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].number != ports[j].number {
+			return ports[i].number < ports[j].number
+		}
+		return ports[i].spec < ports[j].spec
+	})
+
+	var violations []PolicyViolation
+	for _, p := range ports {
+		violation := PolicyViolation{
+			Rule:        "privileged-port",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Image exposes privileged port %s (below %d)", p.spec, privilegedPortLimit),
+			Remediation: remediationPrivilegedPort,
+		}
+		if strings.EqualFold(severity, "warning") {
+			violation.Severity = "medium"
+			violation.Result = "warn"
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
@@ -0,0 +1,56 @@
+package verify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckPrivilegedPorts(t *testing.T) {
+	exposed := map[string]struct{}{"8080/tcp": {}, "443/tcp": {}, "80/tcp": {}}
+
+	violations := checkPrivilegedPorts(exposed, "")
+	var messages []string
+	for _, v := range violations {
+		if v.Rule != "privileged-port" || v.Severity != "critical" || v.Result != "fail" || v.Remediation == "" {
+			t.Errorf("unexpected violation %+v", v)
+		}
+		messages = append(messages, v.Message)
+	}
+	want := []string{
+		"Image exposes privileged port 80/tcp (below 1024)",
+		"Image exposes privileged port 443/tcp (below 1024)",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("expected 80 and 443 (not 8080) to be flagged, got %v", messages)
+	}
+
+	if v := checkPrivilegedPorts(exposed, "warning"); len(v) != 2 || v[0].Result != "warn" {
+		t.Errorf("expected warnings with severity warning, got %+v", v)
+	}
+	if v := checkPrivilegedPorts(map[string]struct{}{"8080/tcp": {}, "1024/udp": {}}, ""); len(v) != 0 {
+		t.Errorf("expected no violations for unprivileged ports, got %+v", v)
+	}
+}
+
+// TestVerify_ForbidPrivilegedPorts tests the check against the inspected image's exposed ports
+func TestVerify_ForbidPrivilegedPorts(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	cfg.Policy.ForbidPrivilegedPorts = true
+
+	fakeDockerConfig(t, `{"User":"app","Labels":null,"ExposedPorts":{"8080/tcp":{}}}`)
+	if result, err := Verify(cfg, "test:latest", false, true, nil); err != nil || result.Status != "pass" {
+		t.Fatalf("expected port 8080 to pass, got %v (%+v)", err, result.Violations)
+	}
+
+	fakeDockerConfig(t, `{"User":"app","Labels":null,"ExposedPorts":{"80/tcp":{},"443/tcp":{},"8080/tcp":{}}}`)
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || len(result.Violations) != 2 || result.Violations[0].Rule != "privileged-port" {
+		t.Fatalf("expected privileged-port violations for 80 and 443, got %v (%+v)", err, result.Violations)
+	}
+
+	cfg.Policy.PrivilegedPortSeverity = "warning"
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" || len(result.PolicyResult.Warnings) != 2 {
+		t.Errorf("expected privileged ports to only warn, got %v (%+v)", err, result.PolicyResult)
+	}
+}
@@ -0,0 +1,155 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/slsa"
+)
+
+// checkProvenance looks for SLSA build provenance for the image digest
+// Returns a provenance-missing or provenance-invalid violation, or nil if valid provenance was found
+func checkProvenance(imageRef, sourcePattern string) *PolicyViolation {
+	digest, err := oci.ResolveDigest(imageRef)
+	if err != nil {
+		return &PolicyViolation{
+			Rule:        "provenance-missing",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Cannot check build provenance: %v", err),
+			Remediation: remediationImageInspectFailed,
+		}
+	}
+
+	return validateProvenanceForDigest(digest, sourcePattern)
+}
+
+// validateProvenanceForDigest checks provenance documents for a digest
+// Searched locations:
+//   - .acc/provenance/ (provenance placed by the user or CI, e.g. *.intoto.jsonl)
+//   - .acc/attestations/<digest12>/ (includes remote attestations cached by --remote)
+//
+// With sourcePattern (policy.provenanceSource), valid provenance must also record a source
+// repository matching the glob; otherwise the result is provenance-source-mismatch.
+func validateProvenanceForDigest(digest, sourcePattern string) *PolicyViolation {
+	digestPrefix := oci.ShortDigest(digest)
+
+	var found int
+	var lastErr error
+	var mismatched []string // source repositories of valid provenance that did not match sourcePattern
+	for _, dir := range []string{
+		filepath.Join(".acc", "provenance"),
+		filepath.Join(".acc", "attestations", digestPrefix),
+	} {
+		for _, statement := range findProvenanceStatements(dir) {
+			found++
+
+			// Provenance in .acc/provenance/ may describe other images, so the subject must match
+			parsed, err := slsa.ValidateStatement(statement, digest, "")
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if parsed.BuilderID() == "" {
+				lastErr = fmt.Errorf("provenance missing builder id")
+				continue
+			}
+
+			if sourcePattern != "" {
+				if ok, _ := parsed.MatchSource(sourcePattern); !ok {
+					mismatched = append(mismatched, orUnknownSource(parsed.SourceRepository()))
+					continue
+				}
+			}
+
+			// Valid provenance for this digest
+			return nil
+		}
+	}
+
+	if found == 0 {
+		return &PolicyViolation{
+			Rule:        "provenance-missing",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("No SLSA provenance found for image digest %s", oci.QualifiedDigest(digest)),
+			Remediation: remediationProvenanceMissing,
+		}
+	}
+
+	if len(mismatched) > 0 {
+		return &PolicyViolation{
+			Rule:        "provenance-source-mismatch",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("SLSA provenance for %s records source %s, which does not match %s", oci.QualifiedDigest(digest), strings.Join(mismatched, ", "), sourcePattern),
+			Remediation: remediationProvenanceSource,
+		}
+	}
+
+	return &PolicyViolation{
+		Rule:        "provenance-invalid",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     fmt.Sprintf("SLSA provenance found but invalid: %v", lastErr),
+		Remediation: remediationProvenanceInvalid,
+	}
+}
+
+// findProvenanceStatements returns the in-toto statements in dir whose predicateType is SLSA provenance
+// Files may contain a single JSON document or JSON lines; DSSE envelopes are unwrapped
+func findProvenanceStatements(dir string) [][]byte {
+	var statements [][]byte
+
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".json" && ext != ".jsonl" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		documents := [][]byte{data}
+		if !json.Valid(data) {
+			documents = bytes.Split(data, []byte("\n"))
+		}
+
+		for _, doc := range documents {
+			doc = bytes.TrimSpace(doc)
+			if len(doc) == 0 {
+				continue
+			}
+			statement := slsa.DecodeEnvelope(doc)
+
+			var peek struct {
+				PredicateType string `json:"predicateType"`
+			}
+			if err := json.Unmarshal(statement, &peek); err != nil || !slsa.IsProvenancePredicate(peek.PredicateType) {
+				// Not provenance (e.g. acc attestations, SBOM attestations)
+				continue
+			}
+			statements = append(statements, statement)
+		}
+		return nil
+	})
+
+	return statements
+}
+
+// orUnknownSource names a missing source repository in messages
+func orUnknownSource(repo string) string {
+	if repo == "" {
+		return "(no source repository)"
+	}
+	return repo
+}
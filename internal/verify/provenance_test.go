@@ -0,0 +1,184 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const provenanceTestDigest = "abcdef0123456789abcdef0123456789abcdef0123456789abcdef0123456789"
+
+// provenanceStatement builds a SLSA v0.2 provenance statement for subjectDigest
+func provenanceStatement(subjectDigest, builderID string) []byte {
+	statement := map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"subject": []map[string]interface{}{
+			{"name": "ghcr.io/example/app", "digest": map[string]string{"sha256": subjectDigest}},
+		},
+		"predicate": map[string]interface{}{
+			"builder":   map[string]string{"id": builderID},
+			"buildType": "https://github.com/slsa-framework/slsa-github-generator/container@v1",
+		},
+	}
+	data, _ := json.Marshal(statement)
+	return data
+}
+
+func writeProvenance(t *testing.T, dir, name string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestValidateProvenanceForDigest tests the provenance-missing and provenance-invalid violations
+func TestValidateProvenanceForDigest(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T)
+		wantRule string // "" means valid provenance
+	}{
+		{
+			name:     "no provenance",
+			setup:    func(t *testing.T) {},
+			wantRule: "provenance-missing",
+		},
+		{
+			name: "valid provenance in .acc/provenance",
+			setup: func(t *testing.T) {
+				writeProvenance(t, filepath.Join(".acc", "provenance"), "app.intoto.json",
+					provenanceStatement(provenanceTestDigest, "https://github.com/slsa-framework/slsa-github-generator"))
+			},
+		},
+		{
+			name: "valid provenance as DSSE envelope in JSON lines",
+			setup: func(t *testing.T) {
+				envelope, _ := json.Marshal(map[string]string{
+					"payloadType": "application/vnd.in-toto+json",
+					"payload": base64.StdEncoding.EncodeToString(
+						provenanceStatement("sha256:"+provenanceTestDigest, "https://github.com/actions/runner")),
+				})
+				writeProvenance(t, filepath.Join(".acc", "provenance"), "app.intoto.jsonl", append(envelope, '\n'))
+			},
+		},
+		{
+			name: "valid provenance cached from remote attestations",
+			setup: func(t *testing.T) {
+				writeProvenance(t, filepath.Join(".acc", "attestations", provenanceTestDigest[:12], "remote", "ghcr.io", "example"),
+					"0123456789abcdef.json", provenanceStatement(provenanceTestDigest, "https://github.com/actions/runner"))
+			},
+		},
+		{
+			name: "subject digest mismatch",
+			setup: func(t *testing.T) {
+				writeProvenance(t, filepath.Join(".acc", "provenance"), "other.json",
+					provenanceStatement(strings.Repeat("0", 64), "https://github.com/actions/runner"))
+			},
+			wantRule: "provenance-invalid",
+		},
+		{
+			name: "missing builder",
+			setup: func(t *testing.T) {
+				writeProvenance(t, filepath.Join(".acc", "provenance"), "app.json",
+					provenanceStatement(provenanceTestDigest, ""))
+			},
+			wantRule: "provenance-invalid",
+		},
+		{
+			name: "non-provenance attestations are ignored",
+			setup: func(t *testing.T) {
+				writeProvenance(t, filepath.Join(".acc", "attestations", provenanceTestDigest[:12]), "attestation.json",
+					[]byte(`{"schemaVersion":"v0.1","subject":{"imageDigest":"`+provenanceTestDigest+`"}}`))
+			},
+			wantRule: "provenance-missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			oldDir, _ := os.Getwd()
+			os.Chdir(tmpDir)
+			defer os.Chdir(oldDir)
+
+			tt.setup(t)
+
+			violation := validateProvenanceForDigest(provenanceTestDigest, "")
+			if tt.wantRule == "" {
+				if violation != nil {
+					t.Fatalf("expected valid provenance, got violation: %+v", violation)
+				}
+				return
+			}
+			if violation == nil {
+				t.Fatalf("expected %s violation, got nil", tt.wantRule)
+			}
+			if violation.Rule != tt.wantRule {
+				t.Errorf("rule = %q, want %q (message: %s)", violation.Rule, tt.wantRule, violation.Message)
+			}
+			if violation.Severity != "critical" || violation.Remediation == "" {
+				t.Errorf("expected critical violation with remediation, got %+v", violation)
+			}
+		})
+	}
+}
+
+// TestCheckProvenance_DigestRef tests that a digest reference is checked without a container tool
+func TestCheckProvenance_DigestRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	writeProvenance(t, filepath.Join(".acc", "provenance"), "app.json",
+		provenanceStatement(provenanceTestDigest, "https://github.com/actions/runner"))
+
+	if violation := checkProvenance("ghcr.io/example/app@sha256:"+provenanceTestDigest, ""); violation != nil {
+		t.Errorf("expected valid provenance for digest reference, got %+v", violation)
+	}
+}
+
+// TestValidateProvenanceForDigest_Source tests provenance-source-mismatch for provenance
+// built from another repository
+func TestValidateProvenanceForDigest_Source(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	var statement map[string]interface{}
+	json.Unmarshal(provenanceStatement(provenanceTestDigest, "https://github.com/actions/runner"), &statement)
+	statement["predicate"].(map[string]interface{})["invocation"] = map[string]interface{}{
+		"configSource": map[string]string{"uri": "git+https://github.com/example/app@refs/heads/main"},
+	}
+	data, _ := json.Marshal(statement)
+	writeProvenance(t, filepath.Join(".acc", "provenance"), "app.json", data)
+
+	for _, pattern := range []string{"https://github.com/example/app", "https://github.com/example/*"} {
+		if violation := validateProvenanceForDigest(provenanceTestDigest, pattern); violation != nil {
+			t.Errorf("expected source to match %q, got %+v", pattern, violation)
+		}
+	}
+
+	violation := validateProvenanceForDigest(provenanceTestDigest, "https://github.com/trusted-org/*")
+	if violation == nil || violation.Rule != "provenance-source-mismatch" || violation.Remediation == "" {
+		t.Fatalf("expected provenance-source-mismatch, got %+v", violation)
+	}
+	if !strings.Contains(violation.Message, "https://github.com/example/app") || !strings.Contains(violation.Message, "trusted-org") {
+		t.Errorf("expected the message to name the source and pattern, got %q", violation.Message)
+	}
+
+	// Provenance without a source repository does not match any pattern
+	writeProvenance(t, filepath.Join(".acc", "provenance"), "app.json",
+		provenanceStatement(provenanceTestDigest, "https://github.com/actions/runner"))
+	if violation := validateProvenanceForDigest(provenanceTestDigest, "*"); violation == nil || !strings.Contains(violation.Message, "no source repository") {
+		t.Errorf("expected a mismatch for provenance without a source, got %+v", violation)
+	}
+}
@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// regoPrintOutput collects OPA print() output (stderr) across evaluations, which may run in
+// parallel (policy.parallelOpa)
+type regoPrintOutput struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// add records the non-empty lines of one evaluation's stderr
+func (p *regoPrintOutput) add(stderr string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, line := range strings.Split(stderr, "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			p.lines = append(p.lines, line)
+		}
+	}
+}
+
+// Lines returns the collected output in the order evaluations finished
+func (p *regoPrintOutput) Lines() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.lines...)
+}
+
+// printRegoOutput writes policy print() output captured by verify --rego-print
+func printRegoOutput(w io.Writer, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nPolicy print output:")
+	for _, line := range lines {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,42 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestVerify_RegoPrint tests that policy print() output (OPA stderr) is captured only under --rego-print
+func TestVerify_RegoPrint(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+
+	// A fake opa that prints like a policy calling print() would
+	binDir := t.TempDir()
+	opa := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+echo 'policy.rego:4: user is root' >&2
+echo 'policy.rego:9: labels: {}' >&2
+echo '{"result":[{"expressions":[{"value":{"violations":[]}}]}]}'
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(opa), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if result.PolicyResult.Print != nil {
+		t.Errorf("expected no print output without --rego-print, got %v", result.PolicyResult.Print)
+	}
+
+	cfg.Policy.RegoPrint = true
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	want := []string{"policy.rego:4: user is root", "policy.rego:9: labels: {}"}
+	if !reflect.DeepEqual(result.PolicyResult.Print, want) {
+		t.Errorf("Print = %v, want %v", result.PolicyResult.Print, want)
+	}
+}
@@ -0,0 +1,78 @@
+package verify
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// customPackagePolicy exposes its decision under data.mycompany.images instead of data.acc.policy
+const customPackagePolicy = `package mycompany.images
+
+import rego.v1
+
+deny contains {"rule": "no-root", "severity": "critical", "message": "image runs as root"} if {
+	input.config.user == ""
+}
+
+decision := {"violations": [v | some v in deny]}
+`
+
+// TestEvaluateRego_CustomQueryFakeOPA tests that the query reaches OPA and both result shapes are parsed
+func TestEvaluateRego_CustomQueryFakeOPA(t *testing.T) {
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+for last; do :; done
+case "$last" in
+  data.mycompany.images.decision) echo '{"result":[{"expressions":[{"value":{"violations":[{"rule":"no-root","severity":"critical","message":"image runs as root"}]}}]}]}' ;;
+  data.mycompany.images.deny) echo '{"result":[{"expressions":[{"value":[{"rule":"no-root","severity":"critical","message":"image runs as root"}]}]}]}' ;;
+  *) echo '{"result":[]}' ;;
+esac
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(script), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	assertCustomQueryDecision(t)
+}
+
+// TestEvaluateRego_CustomQueryRego tests a real policy outside the acc.policy package
+func TestEvaluateRego_CustomQueryRego(t *testing.T) {
+	if _, err := exec.LookPath("opa"); err != nil {
+		t.Skip("opa not installed")
+	}
+	assertCustomQueryDecision(t)
+}
+
+func assertCustomQueryDecision(t *testing.T) {
+	t.Helper()
+	policyDir := t.TempDir()
+	os.WriteFile(filepath.Join(policyDir, "images.rego"), []byte(customPackagePolicy), 0644)
+	input := &RegoInput{Config: ImageConfig{Labels: map[string]string{}}}
+
+	tests := []struct {
+		query     string
+		wantRules []string
+	}{
+		{"data.mycompany.images.decision", []string{"no-root"}},
+		{"data.mycompany.images.deny", []string{"no-root"}},
+		{"data.acc.policy.result", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			violations, err := evaluateRegoPaths([]string{policyDir}, regoOptions{Query: tt.query}, input)
+			if err != nil {
+				t.Fatalf("evaluateRegoPaths failed: %v", err)
+			}
+			var rules []string
+			for _, v := range violations {
+				rules = append(rules, v.Rule)
+			}
+			if strings.Join(rules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("rules = %v, want %v", rules, tt.wantRules)
+			}
+		})
+	}
+}
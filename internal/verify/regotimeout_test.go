@@ -0,0 +1,34 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEvaluateRego_Timeout tests that a hung opa eval is stopped and reported as a violation
+func TestEvaluateRego_Timeout(t *testing.T) {
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+exec sleep 30
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(script), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	start := time.Now()
+	violations, err := evaluateRegoPaths([]string{t.TempDir()}, regoOptions{Timeout: 200 * time.Millisecond}, &RegoInput{})
+	if err != nil {
+		t.Fatalf("expected a violation, not an error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("evaluation was not stopped at the timeout (took %s)", elapsed)
+	}
+	if len(violations) != 1 || violations[0].Rule != "policy-evaluation-timeout" {
+		t.Fatalf("expected a policy-evaluation-timeout violation, got %+v", violations)
+	}
+	if violations[0].Severity != "critical" || violations[0].Remediation != remediationRegoTimeout {
+		t.Errorf("expected a critical violation with remediation, got %+v", violations[0])
+	}
+}
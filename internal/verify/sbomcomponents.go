@@ -0,0 +1,30 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/cloudcwfranck/acc/internal/sbom"
+)
+
+// checkSBOMComponents returns an sbom-too-sparse violation when the SBOM at path lists fewer
+// than min components, or cannot be parsed at all; silently failed SBOM generation often
+// leaves an empty or truncated document that still passes the presence check
+func checkSBOMComponents(path string, min int) *PolicyViolation {
+	violation := &PolicyViolation{
+		Rule:        "sbom-too-sparse",
+		Severity:    "critical",
+		Result:      "fail",
+		Remediation: remediationSBOMTooSparse,
+	}
+
+	doc, err := sbom.ParseFile(path)
+	if err != nil {
+		violation.Message = fmt.Sprintf("SBOM could not be parsed to count components (minimum %d): %v", min, err)
+		return violation
+	}
+	if count := len(doc.Packages); count < min {
+		violation.Message = fmt.Sprintf("SBOM lists %d component(s), below the minimum of %d", count, min)
+		return violation
+	}
+	return nil
+}
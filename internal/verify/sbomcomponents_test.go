@@ -0,0 +1,81 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// spdxWithPackages returns an SPDX JSON SBOM listing n packages
+func spdxWithPackages(n int) string {
+	packages := make([]string, n)
+	for i := range packages {
+		packages[i] = fmt.Sprintf(`{"name":"pkg-%d","versionInfo":"1.0.%d"}`, i, i)
+	}
+	return `{"spdxVersion":"SPDX-2.3","packages":[` + strings.Join(packages, ",") + `]}`
+}
+
+func TestCheckSBOMComponents(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		sbom     string
+		wantRule bool
+	}{
+		{name: "empty SPDX", sbom: spdxWithPackages(0), wantRule: true},
+		{name: "below threshold", sbom: spdxWithPackages(2), wantRule: true},
+		{name: "at threshold", sbom: spdxWithPackages(3)},
+		{name: "above threshold", sbom: spdxWithPackages(10)},
+		{name: "empty CycloneDX", sbom: `{"bomFormat":"CycloneDX","components":[]}`, wantRule: true},
+		{name: "unparseable", sbom: `{}`, wantRule: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-")+".json")
+			os.WriteFile(path, []byte(tt.sbom), 0644)
+
+			violation := checkSBOMComponents(path, 3)
+			if (violation != nil) != tt.wantRule {
+				t.Fatalf("checkSBOMComponents() = %+v, want violation: %v", violation, tt.wantRule)
+			}
+			if violation != nil && (violation.Rule != "sbom-too-sparse" || violation.Severity != "critical" || violation.Remediation == "") {
+				t.Errorf("unexpected violation %+v", violation)
+			}
+		})
+	}
+}
+
+// TestVerify_MinSBOMComponents tests the sbom.minComponents gate with a sparse and a populated SBOM
+func TestVerify_MinSBOMComponents(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	sbomPath := filepath.Join(".acc", "sbom", "waiver-test.spdx.json")
+	cfg.SBOM.MinComponents = 5
+
+	os.WriteFile(sbomPath, []byte(spdxWithPackages(1)), 0644)
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure for a sparse SBOM, got status %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "sbom-too-sparse" {
+		t.Fatalf("expected one sbom-too-sparse violation, got %+v", result.Violations)
+	}
+	if !strings.Contains(result.Violations[0].Message, "1 component(s), below the minimum of 5") {
+		t.Errorf("unexpected message %q", result.Violations[0].Message)
+	}
+
+	os.WriteFile(sbomPath, []byte(spdxWithPackages(5)), 0644)
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass for a populated SBOM, got %v (%+v)", err, result.Violations)
+	}
+
+	// Disabled by default: the sparse SBOM passes the presence check
+	cfg.SBOM.MinComponents = 0
+	os.WriteFile(sbomPath, []byte(spdxWithPackages(0)), 0644)
+	if result, err := Verify(cfg, "test:latest", false, true, nil); err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass without minComponents, got %v", err)
+	}
+}
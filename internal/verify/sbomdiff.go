@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+)
+
+// sbomBaselineError reports that sbom.baseline is set but could not be compared with the
+// current SBOM; policies gating on input.sbom.changed must not silently see no changes
+type sbomBaselineError struct {
+	err error
+}
+
+func (e *sbomBaselineError) Error() string {
+	return fmt.Sprintf("cannot compare SBOM with baseline: %v", e.err)
+}
+
+func (e *sbomBaselineError) Unwrap() error {
+	return e.err
+}
+
+// sbomChanges diffs the project SBOM against sbom.baseline (a previous build's SBOM) for
+// input.sbom.changed. It returns nil without a baseline or without a current SBOM, which
+// the sbom-required check already reports.
+func sbomChanges(cfg *config.Config) (*sbom.Delta, error) {
+	if cfg.SBOM.Baseline == "" {
+		return nil, nil
+	}
+	current := findSBOMFile(cfg)
+	if current == "" {
+		return nil, nil
+	}
+
+	baseline, err := sbom.ParseFile(cfg.SBOM.Baseline)
+	if err != nil {
+		return nil, &sbomBaselineError{err: err}
+	}
+	doc, err := sbom.ParseFile(current)
+	if err != nil {
+		return nil, &sbomBaselineError{err: err}
+	}
+	return sbom.Diff(baseline, doc), nil
+}
@@ -0,0 +1,42 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVerify_SBOMBaseline tests that sbom.baseline feeds the package diff to policy as input.sbom.changed
+func TestVerify_SBOMBaseline(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	os.WriteFile(filepath.Join(".acc", "sbom", "waiver-test.spdx.json"), []byte(
+		`{"spdxVersion":"SPDX-2.3","packages":[{"name":"openssl","versionInfo":"3.0.13"},{"name":"curl","versionInfo":"8.5.0"}]}`), 0644)
+	baseline := filepath.Join(t.TempDir(), "baseline.spdx.json")
+	os.WriteFile(baseline, []byte(`{"spdxVersion":"SPDX-2.3","packages":[{"name":"openssl","versionInfo":"3.0.2"}]}`), 0644)
+
+	// Without a baseline, input.sbom.changed is absent
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Input == nil || result.Input.SBOM.Changed != nil {
+		t.Fatalf("expected input without sbom.changed, got %v (%+v)", err, result.Input)
+	}
+
+	cfg.SBOM.Baseline = baseline
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Input == nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	changed := result.Input.SBOM.Changed
+	if changed == nil || len(changed.Added) != 1 || changed.Added[0].Name != "curl" {
+		t.Fatalf("expected curl added in input.sbom.changed, got %+v", changed)
+	}
+	if len(changed.Upgraded) != 1 || changed.Upgraded[0].OldVersion != "3.0.2" || changed.Upgraded[0].NewVersion != "3.0.13" {
+		t.Errorf("expected openssl 3.0.2 -> 3.0.13, got %+v", changed.Upgraded)
+	}
+
+	// An unreadable baseline fails closed rather than hiding changes from policy
+	cfg.SBOM.Baseline = filepath.Join(t.TempDir(), "missing.json")
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || len(result.Violations) == 0 || result.Violations[0].Rule != "sbom-baseline-invalid" {
+		t.Fatalf("expected sbom-baseline-invalid, got %v (%+v)", err, result.Violations)
+	}
+}
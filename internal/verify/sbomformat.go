@@ -0,0 +1,57 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+)
+
+// sbomLookupFormat is the format whose {project}.{format}.json SBOM the presence check looks
+// for first: sbom.requireFormat (--require-sbom-format) when set, else sbom.format
+func sbomLookupFormat(cfg *config.Config) string {
+	if cfg.SBOM.RequireFormat != "" {
+		return cfg.SBOM.RequireFormat
+	}
+	return cfg.SBOM.Format
+}
+
+// sbomFormatAccepted reports whether the SBOM at path satisfies sbom.acceptedFormats; every
+// file qualifies when the list is empty, otherwise its content-detected format must be listed
+func sbomFormatAccepted(cfg *config.Config, path string) bool {
+	if len(cfg.SBOM.AcceptedFormats) == 0 {
+		return true
+	}
+	format, err := sbom.DetectFileFormat(path)
+	if err != nil {
+		return false
+	}
+	for _, accepted := range cfg.SBOM.AcceptedFormats {
+		if format == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSBOMFormat returns an sbom-format-mismatch violation when the SBOM at path is not in
+// the required format (spdx or cyclonedx), detected from its content rather than its name
+func checkSBOMFormat(path, required string) *PolicyViolation {
+	violation := &PolicyViolation{
+		Rule:        "sbom-format-mismatch",
+		Severity:    "critical",
+		Result:      "fail",
+		Remediation: fmt.Sprintf(remediationSBOMFormat, required),
+	}
+
+	doc, err := sbom.ParseFile(path)
+	if err != nil {
+		violation.Message = fmt.Sprintf("SBOM could not be parsed to check its format (required %s): %v", required, err)
+		return violation
+	}
+	if doc.Format != required {
+		violation.Message = fmt.Sprintf("SBOM %s is %s, but %s is required", path, doc.Format, required)
+		return violation
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cycloneDXWithPackage = `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[{"name":"pkg-0","version":"1.0.0"}]}`
+
+func TestCheckSBOMFormat(t *testing.T) {
+	dir := t.TempDir()
+	spdxPath := filepath.Join(dir, "app.spdx.json")
+	os.WriteFile(spdxPath, []byte(spdxWithPackages(1)), 0644)
+	cdxPath := filepath.Join(dir, "app.cyclonedx.json")
+	os.WriteFile(cdxPath, []byte(cycloneDXWithPackage), 0644)
+	badPath := filepath.Join(dir, "bad.json")
+	os.WriteFile(badPath, []byte(`{}`), 0644)
+
+	tests := []struct {
+		name     string
+		path     string
+		required string
+		wantRule bool
+	}{
+		{name: "spdx required, spdx found", path: spdxPath, required: "spdx"},
+		{name: "cyclonedx required, cyclonedx found", path: cdxPath, required: "cyclonedx"},
+		{name: "cyclonedx required, spdx found", path: spdxPath, required: "cyclonedx", wantRule: true},
+		{name: "spdx required, cyclonedx found", path: cdxPath, required: "spdx", wantRule: true},
+		{name: "unparseable", path: badPath, required: "spdx", wantRule: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := checkSBOMFormat(tt.path, tt.required)
+			if (violation != nil) != tt.wantRule {
+				t.Fatalf("checkSBOMFormat() = %+v, want violation: %v", violation, tt.wantRule)
+			}
+			if violation != nil && (violation.Rule != "sbom-format-mismatch" || violation.Severity != "critical" || !strings.Contains(violation.Remediation, tt.required)) {
+				t.Errorf("unexpected violation %+v", violation)
+			}
+		})
+	}
+}
+
+// TestVerify_RequireSBOMFormat tests that sbom.requireFormat overrides sbom.format for the
+// presence check and fails verification for an SBOM in another format
+func TestVerify_RequireSBOMFormat(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	spdxPath := filepath.Join(".acc", "sbom", "waiver-test.spdx.json")
+	os.WriteFile(spdxPath, []byte(spdxWithPackages(1)), 0644)
+
+	// The repo builds SPDX; a consumer requiring CycloneDX rejects it
+	cfg.SBOM.RequireFormat = "cyclonedx"
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure for an SPDX SBOM, got status %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "sbom-format-mismatch" {
+		t.Fatalf("expected one sbom-format-mismatch violation, got %+v", result.Violations)
+	}
+	if !strings.Contains(result.Violations[0].Message, "is spdx, but cyclonedx is required") {
+		t.Errorf("unexpected message %q", result.Violations[0].Message)
+	}
+
+	// With a CycloneDX SBOM alongside, the required format's file is found first
+	os.WriteFile(filepath.Join(".acc", "sbom", "waiver-test.cyclonedx.json"), []byte(cycloneDXWithPackage), 0644)
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass for a CycloneDX SBOM, got %v (%+v)", err, result.Violations)
+	}
+
+	cfg.SBOM.RequireFormat = "spdx"
+	if result, err := Verify(cfg, "test:latest", false, true, nil); err != nil || result.Status != "pass" {
+		t.Fatalf("expected pass for a required SPDX SBOM, got %v (%+v)", err, result.Violations)
+	}
+}
+
+// TestVerify_SBOMAcceptedFormats tests that sbom.acceptedFormats and sbom.dir drive the presence
+// check and that the detected format is recorded on the result
+func TestVerify_SBOMAcceptedFormats(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	os.RemoveAll(filepath.Join(".acc", "sbom"))
+	cfg.SBOM.Dir = filepath.Join("dist", "sboms")
+	os.MkdirAll(cfg.SBOM.Dir, 0755)
+
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		accepted []string
+		wantPass bool
+		format   string
+	}{
+		{name: "spdx accepted", file: "sbom.json", content: spdxWithPackages(1), accepted: []string{"spdx", "cyclonedx"}, wantPass: true, format: "spdx"},
+		{name: "cyclonedx accepted", file: "waiver-test.cyclonedx.json", content: cycloneDXWithPackage, accepted: []string{"spdx", "cyclonedx"}, wantPass: true, format: "cyclonedx"},
+		{name: "cyclonedx rejected", file: "waiver-test.cyclonedx.json", content: cycloneDXWithPackage, accepted: []string{"spdx"}},
+		{name: "unrecognized rejected", file: "waiver-test.spdx.json", content: `{}`, accepted: []string{"spdx", "cyclonedx"}},
+		{name: "any file without acceptedFormats", file: "waiver-test.spdx.json", content: `{}`, wantPass: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(cfg.SBOM.Dir, tt.file)
+			os.WriteFile(path, []byte(tt.content), 0644)
+			defer os.Remove(path)
+			cfg.SBOM.AcceptedFormats = tt.accepted
+
+			result, err := Verify(cfg, "test:latest", false, true, nil)
+			if tt.wantPass {
+				if err != nil || result.Status != "pass" {
+					t.Fatalf("expected pass, got %v (%+v)", err, result.Violations)
+				}
+				if result.SBOMFormat != tt.format {
+					t.Errorf("SBOMFormat = %q, want %q", result.SBOMFormat, tt.format)
+				}
+				return
+			}
+			if err == nil || result.SBOMPresent {
+				t.Fatalf("expected a missing SBOM, got status %s", result.Status)
+			}
+			if len(result.Violations) != 1 || result.Violations[0].Rule != "sbom-required" {
+				t.Fatalf("expected one sbom-required violation, got %+v", result.Violations)
+			}
+			if !strings.Contains(result.Violations[0].Message, "accepted format") || !strings.Contains(result.Violations[0].Message, cfg.SBOM.Dir) {
+				t.Errorf("unexpected message %q", result.Violations[0].Message)
+			}
+		})
+	}
+}
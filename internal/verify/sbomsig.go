@@ -0,0 +1,104 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// checkSBOMSignature checks that the SBOM is signed with cosign, either by a local
+// <sbom>.sig (and <sbom>.pem for keyless) next to the SBOM file, or by an SBOM
+// attestation attached to the image digest in the registry.
+// Returns an sbom-unsigned or sbom-signature-invalid violation, or nil if the signature verified
+func checkSBOMSignature(cfg *config.Config, imageRef string) *PolicyViolation {
+	sbomFile := findSBOMFile(cfg)
+	if sbomFile == "" {
+		// Presence is reported by the sbom-required check
+		return nil
+	}
+
+	cosignPath, err := findCosign("SBOM signature verification")
+	if err != nil {
+		return &PolicyViolation{
+			Rule:        "sbom-signature-invalid",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Cannot verify SBOM signature: %v", err),
+			Remediation: remediationCosignRequired,
+		}
+	}
+
+	// Local detached signature (cosign sign-blob --output-signature <sbom>.sig)
+	if _, err := os.Stat(sbomFile + ".sig"); err == nil {
+		output, err := exec.Command(cosignPath, cosignVerifyBlobArgs(cfg, sbomFile)...).CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		return &PolicyViolation{
+			Rule:        "sbom-signature-invalid",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("SBOM signature verification failed for %s: %s", sbomFile, strings.TrimSpace(string(output))),
+			Remediation: remediationSBOMSigInvalid,
+		}
+	}
+
+	// Otherwise look for an SBOM attestation referring to the image digest
+	ref, err := imageSignatureRef(imageRef)
+	if err != nil {
+		return &PolicyViolation{
+			Rule:        "sbom-unsigned",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("SBOM %s has no signature (%s.sig not found, and the registry could not be checked: %v)", sbomFile, sbomFile, err),
+			Remediation: remediationSBOMUnsigned,
+		}
+	}
+
+	output, err := exec.Command(cosignPath, cosignVerifyAttestationArgs(cfg, ref)...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	lower := strings.ToLower(string(output))
+	if strings.Contains(lower, "no signatures found") || strings.Contains(lower, "no matching attestations") {
+		return &PolicyViolation{
+			Rule:        "sbom-unsigned",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("SBOM %s has no signature (%s.sig not found, and %s has no SBOM attestation)", sbomFile, sbomFile, ref),
+			Remediation: remediationSBOMUnsigned,
+		}
+	}
+	return &PolicyViolation{
+		Rule:        "sbom-signature-invalid",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     fmt.Sprintf("SBOM attestation verification failed for %s: %s", ref, strings.TrimSpace(string(output))),
+		Remediation: remediationSBOMSigInvalid,
+	}
+}
+
+// cosignVerifyBlobArgs builds the cosign verify-blob arguments for a local SBOM signature.
+// Keyless signatures are verified against the <sbom>.pem certificate.
+func cosignVerifyBlobArgs(cfg *config.Config, sbomFile string) []string {
+	args := []string{"verify-blob", "--signature", sbomFile + ".sig"}
+	if cfg.Signing.Key == "" {
+		args = append(args, "--certificate", sbomFile+".pem")
+	}
+	args = append(args, cosignKeyArgs(cfg)...)
+	return append(args, sbomFile)
+}
+
+// cosignVerifyAttestationArgs builds the cosign verify-attestation arguments for the
+// SBOM predicate type matching sbom.format
+func cosignVerifyAttestationArgs(cfg *config.Config, ref string) []string {
+	predicateType := "spdxjson"
+	if cfg.SBOM.Format == "cyclonedx" {
+		predicateType = "cyclonedx"
+	}
+	args := append([]string{"verify-attestation", "--type", predicateType}, cosignKeyArgs(cfg)...)
+	return append(args, ref)
+}
@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+// setupSBOMProject writes .acc/sbom/demo.spdx.json (and <sbom>.sig when signed) in a temp dir
+func setupSBOMProject(t *testing.T, signed bool) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	sbomFile := filepath.Join(".acc", "sbom", "demo.spdx.json")
+	os.MkdirAll(filepath.Dir(sbomFile), 0755)
+	os.WriteFile(sbomFile, []byte(`{"spdxVersion":"SPDX-2.3"}`), 0644)
+	if signed {
+		os.WriteFile(sbomFile+".sig", []byte("MEUCIQ=="), 0644)
+	}
+	return sbomFile
+}
+
+func TestCheckSBOMSignature(t *testing.T) {
+	tests := []struct {
+		name     string
+		signed   bool
+		body     string
+		wantRule string
+		wantArgs string
+	}{
+		{"signed", true, "exit 0", "", "verify-blob --signature .acc/sbom/demo.spdx.json.sig --key cosign.pub .acc/sbom/demo.spdx.json"},
+		{"tampered", true, "echo 'Error: invalid signature when validating ASN.1 encoded signature' >&2; exit 1", "sbom-signature-invalid", ""},
+		{"attested in registry", false, "exit 0", "", "verify-attestation --type spdxjson --key cosign.pub " + signedRef},
+		{"unsigned", false, "echo 'Error: no matching attestations' >&2; exit 1", "sbom-unsigned", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupSBOMProject(t, tt.signed)
+			argsFile := fakeCosign(t, tt.body)
+			cfg := config.DefaultConfig("demo")
+			cfg.Signing.Key = "cosign.pub"
+
+			violation := checkSBOMSignature(cfg, signedRef)
+			if tt.wantRule == "" {
+				if violation != nil {
+					t.Fatalf("unexpected violation: %+v", violation)
+				}
+			} else {
+				if violation == nil || violation.Rule != tt.wantRule {
+					t.Fatalf("violation = %+v, want rule %s", violation, tt.wantRule)
+				}
+				if violation.Severity != "critical" || violation.Remediation == "" {
+					t.Errorf("violation should be critical with a remediation: %+v", violation)
+				}
+			}
+
+			if tt.wantArgs != "" {
+				args, _ := os.ReadFile(argsFile)
+				if got := strings.TrimSpace(string(args)); got != tt.wantArgs {
+					t.Errorf("cosign args = %q, want %q", got, tt.wantArgs)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSBOMSignature_KeylessBlob(t *testing.T) {
+	sbomFile := setupSBOMProject(t, true)
+	argsFile := fakeCosign(t, "exit 0")
+
+	cfg := config.DefaultConfig("demo")
+	if v := checkSBOMSignature(cfg, signedRef); v != nil {
+		t.Fatalf("unexpected violation: %+v", v)
+	}
+	args, _ := os.ReadFile(argsFile)
+	want := "verify-blob --signature " + sbomFile + ".sig --certificate " + sbomFile + ".pem --certificate-identity-regexp .* --certificate-oidc-issuer-regexp .* " + sbomFile
+	if got := strings.TrimSpace(string(args)); got != want {
+		t.Errorf("keyless args = %q, want %q", got, want)
+	}
+}
+
+func TestCheckSBOMSignature_CosignMissing(t *testing.T) {
+	setupSBOMProject(t, true)
+	t.Setenv("PATH", t.TempDir())
+
+	violation := checkSBOMSignature(config.DefaultConfig("demo"), signedRef)
+	if violation == nil || violation.Rule != "sbom-signature-invalid" || violation.Remediation != remediationCosignRequired {
+		t.Fatalf("expected sbom-signature-invalid with cosign remediation, got %+v", violation)
+	}
+}
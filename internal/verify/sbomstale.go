@@ -0,0 +1,62 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/build"
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// imageBuildTime returns when the image was built and where that time came from. The
+// org.opencontainers.image.created label in the acc build manifest is preferred: it is
+// stamped before the build starts, so an SBOM generated by the same build is always newer.
+// Otherwise the image's Created time is read with docker/podman/nerdctl.
+func imageBuildTime(imageRef string) (time.Time, string, error) {
+	if digest, err := oci.ResolveDigest(imageRef); err == nil {
+		if manifest, err := build.LoadManifest(digest); err == nil {
+			if created, err := time.Parse(time.RFC3339, manifest.Labels[build.LabelCreated]); err == nil {
+				return created, "build manifest", nil
+			}
+		}
+	}
+
+	for _, tool := range []string{"docker", "podman", "nerdctl"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		for _, ref := range oci.InspectRefs(imageRef) {
+			output, err := exec.Command(tool, "inspect", "--format={{.Created}}", ref).Output()
+			if err != nil {
+				continue
+			}
+			if created, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(output))); err == nil {
+				return created, "image", nil
+			}
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("could not determine when %s was built", imageRef)
+}
+
+// checkSBOMStale returns an sbom-stale violation when the SBOM at path was last written
+// before the image was built, i.e. it describes an earlier build
+func checkSBOMStale(path string, built time.Time, source string) *PolicyViolation {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if !info.ModTime().Before(built) {
+		return nil
+	}
+	return &PolicyViolation{
+		Rule:     "sbom-stale",
+		Severity: "critical",
+		Result:   "fail",
+		Message: fmt.Sprintf("SBOM %s (written %s) predates the image build (%s, from the %s)",
+			path, info.ModTime().UTC().Format(time.RFC3339), built.UTC().Format(time.RFC3339), source),
+		Remediation: remediationSBOMStale,
+	}
+}
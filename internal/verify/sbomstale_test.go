@@ -0,0 +1,102 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudcwfranck/acc/internal/build"
+)
+
+func TestCheckSBOMStale(t *testing.T) {
+	built := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "app.spdx.json")
+	os.WriteFile(path, []byte(spdxWithPackages(1)), 0644)
+
+	// An SBOM written before the build describes an earlier image
+	old := built.Add(-time.Hour)
+	os.Chtimes(path, old, old)
+	violation := checkSBOMStale(path, built, "image")
+	if violation == nil || violation.Rule != "sbom-stale" || violation.Severity != "critical" {
+		t.Fatalf("expected a critical sbom-stale violation, got %+v", violation)
+	}
+	if !strings.Contains(violation.Message, "2026-03-01T12:00:00Z") {
+		t.Errorf("expected the build time in the message, got %q", violation.Message)
+	}
+
+	newer := built.Add(time.Minute)
+	os.Chtimes(path, newer, newer)
+	if violation := checkSBOMStale(path, built, "image"); violation != nil {
+		t.Errorf("expected no violation for an SBOM newer than the build, got %+v", violation)
+	}
+}
+
+// writeBuildTimeDocker installs a fake docker that reports digest and created for inspect --format
+func writeBuildTimeDocker(t *testing.T, digest, created string) {
+	t.Helper()
+	binDir := t.TempDir()
+	docker := `#!/bin/sh
+case "$2" in
+  "--format={{.Id}}") echo "sha256:` + digest + `" ;;
+  "--format={{.Created}}") echo "` + created + `" ;;
+  *) echo '[{"Config":{"User":"root","Labels":null}}]' ;;
+esac
+`
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(docker), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestImageBuildTime(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	digest := strings.Repeat("ef", 32)
+	writeBuildTimeDocker(t, digest, "2026-03-01T12:00:00.123456789Z")
+
+	// Without a build manifest the image's Created time is used
+	built, source, err := imageBuildTime("app:1.0")
+	if err != nil || source != "image" || !built.Equal(time.Date(2026, 3, 1, 12, 0, 0, 123456789, time.UTC)) {
+		t.Fatalf("imageBuildTime() = %v, %q, %v; want the image Created time", built, source, err)
+	}
+
+	// The build manifest's created label takes precedence
+	manifest := build.Manifest{SchemaVersion: "v1", ImageDigest: digest, Labels: map[string]string{build.LabelCreated: "2026-02-01T08:00:00Z"}}
+	data, _ := json.Marshal(manifest)
+	os.MkdirAll(filepath.Dir(build.ManifestPath(digest)), 0755)
+	os.WriteFile(build.ManifestPath(digest), data, 0644)
+
+	built, source, err = imageBuildTime("app:1.0")
+	if err != nil || source != "build manifest" || !built.Equal(time.Date(2026, 2, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("imageBuildTime() = %v, %q, %v; want the build manifest time", built, source, err)
+	}
+}
+
+// TestVerify_SinceBuild tests that sbom.sinceBuild fails verification only for an SBOM older than the image
+func TestVerify_SinceBuild(t *testing.T) {
+	cfg := setupWaiverProject(t, "waivers: []\n", "")
+	writeBuildTimeDocker(t, strings.Repeat("ef", 32), "2026-03-01T12:00:00Z")
+	cfg.SBOM.SinceBuild = true
+	sbomPath := filepath.Join(".acc", "sbom", "waiver-test.spdx.json")
+
+	old := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	os.Chtimes(sbomPath, old, old)
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure for a stale SBOM, got status %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "sbom-stale" {
+		t.Fatalf("expected one sbom-stale violation, got %+v", result.Violations)
+	}
+
+	newer := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	os.Chtimes(sbomPath, newer, newer)
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Errorf("expected a fresh SBOM to pass, got %v (%+v)", err, result.Violations)
+	}
+}
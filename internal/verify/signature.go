@@ -0,0 +1,122 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+)
+
+// findCosign locates cosign, explaining which feature needs it when it is missing
+func findCosign(feature string) (string, error) {
+	cosignPath, err := exec.LookPath("cosign")
+	if err != nil {
+		return "", fmt.Errorf("cosign is required for %s but was not found in PATH. Install cosign: https://docs.sigstore.dev/cosign/installation/", feature)
+	}
+	return cosignPath, nil
+}
+
+// checkImageSignature runs cosign verify against the image digest
+// Returns an image-unsigned or image-signature-invalid violation, or nil if the signature verified
+func checkImageSignature(cfg *config.Config, imageRef string) *PolicyViolation {
+	cosignPath, err := findCosign("image signature verification")
+	if err != nil {
+		return &PolicyViolation{
+			Rule:        "image-signature-invalid",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Cannot verify image signature: %v", err),
+			Remediation: remediationCosignRequired,
+		}
+	}
+
+	ref, err := imageSignatureRef(imageRef)
+	if err != nil {
+		return &PolicyViolation{
+			Rule:        "image-signature-invalid",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Cannot verify image signature: %v", err),
+			Remediation: remediationImageSigInvalid,
+		}
+	}
+
+	output, err := exec.Command(cosignPath, cosignVerifyArgs(cfg, ref)...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	// cosign reports a missing signature distinctly from one that does not verify
+	if strings.Contains(strings.ToLower(string(output)), "no signatures found") {
+		return &PolicyViolation{
+			Rule:        "image-unsigned",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Image %s has no cosign signature", ref),
+			Remediation: remediationImageUnsigned,
+		}
+	}
+	return &PolicyViolation{
+		Rule:        "image-signature-invalid",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     fmt.Sprintf("Image signature verification failed for %s: %s", ref, strings.TrimSpace(string(output))),
+		Remediation: remediationImageSigInvalid,
+	}
+}
+
+// cosignVerifyArgs builds the cosign verify arguments for the image signature
+func cosignVerifyArgs(cfg *config.Config, ref string) []string {
+	args := append([]string{"verify"}, cosignKeyArgs(cfg)...)
+	return append(args, ref)
+}
+
+// cosignKeyArgs selects the verification material: signing.key for key-based
+// verification, otherwise keyless with the configured identity and issuer patterns
+func cosignKeyArgs(cfg *config.Config) []string {
+	if cfg.Signing.Key != "" {
+		return []string{"--key", cfg.Signing.Key}
+	}
+	identity, issuer := cfg.Signing.IdentityRegexp, cfg.Signing.IssuerRegexp
+	if identity == "" {
+		identity = ".*"
+	}
+	if issuer == "" {
+		issuer = ".*"
+	}
+	return []string{"--certificate-identity-regexp", identity, "--certificate-oidc-issuer-regexp", issuer}
+}
+
+// imageSignatureRef returns the repo@<algorithm>:<digest> reference cosign should verify.
+// Digest references are used as-is; tags are resolved against the registry, since
+// signatures are attached to the registry manifest digest (not the local image ID).
+func imageSignatureRef(imageRef string) (string, error) {
+	if _, _, ok := oci.SplitDigestRef(imageRef); ok {
+		return imageRef, nil
+	}
+
+	registry, repository, reference, err := oci.ParseReference(imageRef)
+	if err != nil {
+		return "", err
+	}
+	repo, err := oci.NewRepository(registry, repository)
+	if err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+	var desc ocispec.Descriptor
+	err = oci.Retry(ctx, oci.DefaultRetryPolicy, func() error {
+		desc, err = repo.Resolve(ctx, reference)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s in registry: %w", imageRef, err)
+	}
+	return fmt.Sprintf("%s/%s@%s", registry, repository, desc.Digest), nil
+}
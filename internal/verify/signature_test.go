@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+)
+
+const signedRef = "ghcr.io/org/app@sha256:abababababababababababababababababababababababababababababababab"
+
+// fakeCosign installs a cosign that records its arguments and runs body
+func fakeCosign(t *testing.T, body string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "cosign"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake cosign: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func TestCheckImageSignature(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantRule string
+	}{
+		{"signed", "exit 0", ""},
+		{"unsigned", "echo 'Error: no signatures found' >&2; exit 1", "image-unsigned"},
+		{"wrong key", "echo 'Error: no matching signatures: invalid signature' >&2; exit 1", "image-signature-invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeCosign(t, tt.body)
+			cfg := config.DefaultConfig("demo")
+			cfg.Signing.Key = "cosign.pub"
+
+			violation := checkImageSignature(cfg, signedRef)
+			if tt.wantRule == "" {
+				if violation != nil {
+					t.Fatalf("unexpected violation: %+v", violation)
+				}
+				return
+			}
+			if violation == nil || violation.Rule != tt.wantRule {
+				t.Fatalf("violation = %+v, want rule %s", violation, tt.wantRule)
+			}
+			if violation.Severity != "critical" || violation.Remediation == "" {
+				t.Errorf("violation should be critical with a remediation: %+v", violation)
+			}
+		})
+	}
+}
+
+func TestCheckImageSignature_Args(t *testing.T) {
+	argsFile := fakeCosign(t, "exit 0")
+
+	cfg := config.DefaultConfig("demo")
+	cfg.Signing.Key = "cosign.pub"
+	if v := checkImageSignature(cfg, signedRef); v != nil {
+		t.Fatalf("unexpected violation: %+v", v)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if got, want := strings.TrimSpace(string(args)), "verify --key cosign.pub "+signedRef; got != want {
+		t.Errorf("key-based args = %q, want %q", got, want)
+	}
+
+	// Keyless uses the identity/issuer patterns, defaulting to ".*"
+	cfg.Signing.Key = ""
+	cfg.Signing.IdentityRegexp = "^https://github.com/org/"
+	if v := checkImageSignature(cfg, signedRef); v != nil {
+		t.Fatalf("unexpected violation: %+v", v)
+	}
+	args, _ = os.ReadFile(argsFile)
+	want := "verify --certificate-identity-regexp ^https://github.com/org/ --certificate-oidc-issuer-regexp .* " + signedRef
+	if got := strings.TrimSpace(string(args)); got != want {
+		t.Errorf("keyless args = %q, want %q", got, want)
+	}
+}
+
+func TestCheckImageSignature_NoCosign(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	violation := checkImageSignature(config.DefaultConfig("demo"), signedRef)
+	if violation == nil || violation.Rule != "image-signature-invalid" {
+		t.Fatalf("violation = %+v, want image-signature-invalid", violation)
+	}
+	if violation.Remediation != remediationCosignRequired {
+		t.Errorf("remediation = %q, want cosign install hint", violation.Remediation)
+	}
+}
@@ -0,0 +1,104 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudcwfranck/acc/internal/waivers"
+)
+
+// Waiver statuses reported by acc waiver list
+const (
+	WaiverActive  = "active"
+	WaiverExpired = "expired"
+	WaiverUnused  = "unused"
+)
+
+// unusedWaivers returns the active waivers whose rule matched no violation or warning in
+// this run, so they suppress nothing. It must run before applyWaivers moves violations.
+func unusedWaivers(result *VerifyResult, loaded []waivers.Waiver) []waivers.Waiver {
+	fired := map[string]bool{}
+	for _, v := range result.Violations {
+		fired[v.Rule] = true
+	}
+	if result.PolicyResult != nil {
+		for _, v := range result.PolicyResult.Violations {
+			fired[v.Rule] = true
+		}
+		for _, v := range result.PolicyResult.Warnings {
+			fired[v.Rule] = true
+		}
+	}
+
+	unused := []waivers.Waiver{}
+	for _, w := range loaded {
+		if !w.IsExpired() && !fired[w.RuleID] {
+			unused = append(unused, w)
+		}
+	}
+	return unused
+}
+
+// unusedWaiverViolations fails verification for each unused waiver (--fail-on-unused-waivers)
+func unusedWaiverViolations(unused []waivers.Waiver) []PolicyViolation {
+	violations := make([]PolicyViolation, 0, len(unused))
+	for _, w := range unused {
+		violations = append(violations, PolicyViolation{
+			Rule:        "unused-waiver",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Waiver for rule '%s' matched no violation in this run", w.RuleID),
+			Remediation: remediationUnusedWaiver,
+		})
+	}
+	return violations
+}
+
+// WaiverListEntry is a waiver from .acc/waivers.yaml with its status
+type WaiverListEntry struct {
+	waivers.Waiver
+	Status string `json:"status"` // active, expired, unused
+}
+
+// WaiverListResult is the output of acc waiver list
+type WaiverListResult struct {
+	Waivers []WaiverListEntry `json:"waivers"`
+	// UnusedFrom is the image of the last verification that reported unused waivers
+	// (verify --report-unused-waivers); without one, no waiver is marked unused
+	UnusedFrom string `json:"unusedFrom,omitempty"`
+}
+
+// ListWaivers returns the waivers in .acc/waivers.yaml with their status: expired, unused
+// when the last verification (.acc/state/last_verify.json) reported them unused, else active
+func ListWaivers() (*WaiverListResult, error) {
+	loaded, err := waivers.LoadWaivers()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WaiverListResult{Waivers: []WaiverListEntry{}}
+	unused := map[string]bool{}
+	if data, err := os.ReadFile(filepath.Join(".acc", "state", "last_verify.json")); err == nil {
+		var last VerifyState
+		if json.Unmarshal(data, &last) == nil && last.Result != nil && last.Result.UnusedWaivers != nil {
+			result.UnusedFrom = last.ImageRef
+			for _, w := range last.Result.UnusedWaivers {
+				unused[w.RuleID] = true
+			}
+		}
+	}
+
+	for _, w := range loaded {
+		entry := WaiverListEntry{Waiver: w, Status: WaiverActive}
+		switch {
+		case w.IsExpired():
+			entry.Status = WaiverExpired
+		case unused[w.RuleID]:
+			entry.Status = WaiverUnused
+		}
+		result.Waivers = append(result.Waivers, entry)
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"testing"
+)
+
+// activeWaiversYAML waives a rule the fake opa reports (no-root-user) and one it does not
+const activeWaiversYAML = `waivers:
+  - ruleId: no-root-user
+    justification: legacy base image, tracked in JIRA-42
+    expiry: "2999-01-01T00:00:00Z"
+  - ruleId: no-latest-tag
+    justification: fixed in v2, waiver left behind
+    expiry: "2999-01-01T00:00:00Z"
+`
+
+const rootUserViolation = `{"rule":"no-root-user","severity":"critical","result":"fail","message":"runs as root"}`
+
+// TestVerify_ReportUnusedWaivers tests that a waiver whose rule fired is used and one whose
+// rule did not fire is reported unused, and that --fail-on-unused-waivers fails on it
+func TestVerify_ReportUnusedWaivers(t *testing.T) {
+	cfg := setupWaiverProject(t, activeWaiversYAML, rootUserViolation)
+
+	// Not reported unless asked
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.UnusedWaivers != nil {
+		t.Fatalf("expected pass without unusedWaivers, got %v (%+v)", err, result.UnusedWaivers)
+	}
+
+	cfg.Policy.ReportUnusedWaivers = true
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err != nil || result.Status != "pass" {
+		t.Fatalf("reporting unused waivers should not fail verification, got %v", err)
+	}
+	if len(result.UnusedWaivers) != 1 || result.UnusedWaivers[0].RuleID != "no-latest-tag" {
+		t.Fatalf("expected no-latest-tag unused, got %+v", result.UnusedWaivers)
+	}
+	if len(result.PolicyResult.Warnings) != 1 || result.PolicyResult.Warnings[0].Rule != "no-root-user" {
+		t.Errorf("the used waiver should still apply, got warnings %+v", result.PolicyResult.Warnings)
+	}
+
+	cfg.Policy.FailOnUnusedWaivers = true
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err == nil || result.Status != "fail" {
+		t.Fatalf("expected failure for an unused waiver, got status %s", result.Status)
+	}
+	if len(result.Violations) != 1 || result.Violations[0].Rule != "unused-waiver" || result.Violations[0].Remediation == "" {
+		t.Errorf("expected one unused-waiver violation, got %+v", result.Violations)
+	}
+}
+
+func TestListWaivers(t *testing.T) {
+	cfg := setupWaiverProject(t, activeWaiversYAML+`  - ruleId: old-rule
+    justification: long gone
+    expiry: "2000-01-01T00:00:00Z"
+`, rootUserViolation)
+
+	// Before any reporting run, nothing is marked unused
+	list, err := ListWaivers()
+	if err != nil {
+		t.Fatalf("ListWaivers failed: %v", err)
+	}
+	want := map[string]string{"no-root-user": WaiverActive, "no-latest-tag": WaiverActive, "old-rule": WaiverExpired}
+	for _, w := range list.Waivers {
+		if w.Status != want[w.RuleID] {
+			t.Errorf("%s status = %s, want %s", w.RuleID, w.Status, want[w.RuleID])
+		}
+	}
+
+	// A reporting run saves the unused waivers in the verification state; the expired
+	// waiver fails that run but is listed as expired, not unused
+	cfg.Policy.ReportUnusedWaivers = true
+	cfg.Policy.Mode = "warn"
+	Verify(cfg, "test:latest", false, true, nil)
+
+	list, err = ListWaivers()
+	if err != nil {
+		t.Fatalf("ListWaivers failed: %v", err)
+	}
+	if len(list.Waivers) != 3 {
+		t.Fatalf("expected 3 waivers, got %+v", list.Waivers)
+	}
+	want = map[string]string{"no-root-user": WaiverActive, "no-latest-tag": WaiverUnused, "old-rule": WaiverExpired}
+	for _, w := range list.Waivers {
+		if w.Status != want[w.RuleID] {
+			t.Errorf("%s status = %s, want %s", w.RuleID, w.Status, want[w.RuleID])
+		}
+	}
+	if list.UnusedFrom != "test:latest" {
+		t.Errorf("unusedFrom = %q, want test:latest", list.UnusedFrom)
+	}
+}
@@ -1,16 +1,28 @@
 package verify
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/cloudcwfranck/acc/internal/build"
+	"github.com/cloudcwfranck/acc/internal/cache"
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
+	"github.com/cloudcwfranck/acc/internal/policy"
 	"github.com/cloudcwfranck/acc/internal/profile"
+	"github.com/cloudcwfranck/acc/internal/sbom"
+	"github.com/cloudcwfranck/acc/internal/state"
+	"github.com/cloudcwfranck/acc/internal/trust"
 	"github.com/cloudcwfranck/acc/internal/ui"
 	"github.com/cloudcwfranck/acc/internal/waivers"
 )
@@ -19,10 +31,28 @@ import (
 type VerifyResult struct {
 	Status       string            `json:"status"` // pass, warn, fail
 	SBOMPresent  bool              `json:"sbomPresent"`
+	SBOMFormat   string            `json:"sbomFormat,omitempty"` // format detected from the SBOM's content (spdx|cyclonedx)
+	SBOMSource   string            `json:"sbomSource,omitempty"` // with sbom.fromImage: the referrer manifest the SBOM was pulled from, or its cached copy
 	PolicyResult *PolicyResult     `json:"policyResult"`
 	Attestations []string          `json:"attestations"`
 	Violations   []PolicyViolation `json:"violations"`
-	Input        *RegoInput        `json:"input,omitempty"` // v0.1.3: Rego input document
+	Input        *RegoInput        `json:"input,omitempty"`      // v0.1.3: Rego input document
+	PolicyMode   string            `json:"policyMode,omitempty"` // enforce|warn actually applied for this run
+	// WaiversApplied is false when waivers were skipped (verify --no-waivers)
+	WaiversApplied *bool  `json:"waiversApplied,omitempty"`
+	Env            string `json:"env,omitempty"`         // environment selected with --env (policy.Env)
+	ProfileUsed    string `json:"profileUsed,omitempty"` // profile applied (--profile, profiles.byEnv, or .accignore)
+	Team           string `json:"team,omitempty"`        // owning team (project.team or --team)
+	// WarningBudget is set when policy.failOnWarningCount (--fail-on-warning-count) is configured
+	WarningBudget *WarningBudget `json:"warningBudget,omitempty"`
+	// UnusedWaivers lists active waivers whose rule did not fire (policy.reportUnusedWaivers)
+	UnusedWaivers []waivers.Waiver `json:"unusedWaivers,omitempty"`
+	// PolicyUnevaluated is true when opa was missing and policy.allowMissingOpa skipped evaluation
+	PolicyUnevaluated bool `json:"policyUnevaluated,omitempty"`
+	// Phase is the verification phase run (policy.phase / --two-phase): build or deploy
+	Phase string `json:"phase,omitempty"`
+	// StateError is set when the verification state (.acc/state) could not be saved
+	StateError string `json:"stateError,omitempty"`
 }
 
 // PolicyResult represents policy evaluation result
@@ -30,24 +60,119 @@ type PolicyResult struct {
 	Allow      bool              `json:"allow"`
 	Violations []PolicyViolation `json:"violations"`
 	Warnings   []PolicyViolation `json:"warnings"`
+	Print      []string          `json:"print,omitempty"` // print() output from policies (verify --rego-print)
+	// unevaluated is set when opa was missing under policy.allowMissingOpa
+	unevaluated bool
 }
 
 // PolicyViolation represents a single policy violation or warning
 type PolicyViolation struct {
-	Rule     string `json:"rule"`
-	Severity string `json:"severity"`
-	Result   string `json:"result"`
-	Message  string `json:"message"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Result      string `json:"result"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"` // actionable next step (built-in or from Rego)
+	Source      string `json:"source,omitempty"`      // policy file:line that reported it (policy.explainDeny), else the policy group directory (policy.parallelOpa)
 }
 
+// Remediation hints for built-in violations
+const (
+	remediationSBOMRequired       = "Generate an SBOM with 'acc build' or 'syft <image> -o spdx-json=.acc/sbom/<project>.spdx.json'"
+	remediationWaiverExpired      = "Renew or remove the expired waiver in .acc/waivers.yaml"
+	remediationImageInspectFailed = "Ensure the image exists locally (docker pull <image>) and docker/podman/nerdctl is installed"
+	remediationPolicyEvalError    = "Check .rego files in .acc/policy/ for syntax errors (opa check .acc/policy)"
+	remediationOPARequired        = "Install OPA: https://www.openpolicyagent.org/docs/latest/#running-opa"
+	remediationProvenanceMissing  = "Place SLSA provenance for the image digest in .acc/provenance/, or fetch registry attestations with 'acc trust verify --remote <image>'"
+	remediationProvenanceInvalid  = "Regenerate provenance for this image digest with a trusted builder (e.g. slsa-github-generator)"
+	remediationProvenanceSource   = "Build and release the image from the expected repository, or update --require-provenance-source / policy.provenanceSource if the source moved"
+	remediationPreVerifyHook      = "Fix the failing hook (run with --log-level debug to see its output) or remove it from hooks.preVerify in acc.yaml"
+	remediationImageUnsigned      = "Sign the pushed image with 'cosign sign <image>@<digest>' (or 'cosign sign --key cosign.key ...')"
+	remediationImageSigInvalid    = "Check that the image was signed by the expected key or identity (signing.key, signing.identityRegexp, signing.issuerRegexp) and that cosign can reach the registry"
+	remediationCosignRequired     = "Install cosign: https://docs.sigstore.dev/cosign/installation/"
+	remediationSBOMUnsigned       = "Sign the SBOM with 'cosign sign-blob --output-signature <sbom>.sig <sbom>', or attach it with 'cosign attest --type spdxjson --predicate <sbom> <image>@<digest>'"
+	remediationSBOMSigInvalid     = "Re-sign the current SBOM with the expected key or identity (signing.key, signing.identityRegexp, signing.issuerRegexp); the SBOM may have been modified after signing"
+	remediationLabelMissing       = "Add the label at build time, e.g. 'acc build --label %s=<value>' or a LABEL instruction in the Dockerfile"
+	remediationRegoTimeout        = "Look for expensive rules in .acc/policy (opa eval --profile), or raise the limit with --rego-timeout / policy.regoTimeout"
+	remediationSBOMTooSparse      = "Regenerate the SBOM and check the generator's output (e.g. syft <image> -o spdx-json=.acc/sbom/<project>.spdx.json); a near-empty SBOM usually means generation failed"
+	remediationDigestPinned       = "Reference the image by digest, e.g. 'acc verify <image>@sha256:<digest>' or '--digest <sha256>'; look up the digest with 'docker inspect --format {{index .RepoDigests 0}} <image>'"
+	remediationSBOMBaseline       = "Point sbom.baseline (or --sbom-baseline) at a readable SPDX or CycloneDX JSON SBOM from a previous build"
+	remediationUnexpectedPackage  = "Remove the dependency, or review it and refresh the approved set with 'acc verify <image> --write-sbom-baseline <baseline>'"
+	remediationUnusedWaiver       = "Remove the waiver from .acc/waivers.yaml; it no longer suppresses anything"
+	remediationSBOMFormat         = "Generate the SBOM in %s format (e.g. 'syft <image> -o cyclonedx-json=...' or '-o spdx-json=...' into .acc/sbom/), or relax --require-sbom-format / sbom.requireFormat"
+	remediationSBOMStale          = "Regenerate the SBOM for the current image with 'acc build' or 'syft <image> -o spdx-json=.acc/sbom/<project>.spdx.json'"
+	remediationNonRoot            = "Add a non-root USER to the Dockerfile, e.g. 'RUN adduser -D app' then 'USER app' (or a numeric UID such as 'USER 10001')"
+	remediationHealthcheck        = "Add a HEALTHCHECK instruction to the Dockerfile, e.g. 'HEALTHCHECK CMD wget -qO- http://localhost:8080/healthz || exit 1'"
+	remediationPrivilegedPort     = "Listen on a port of 1024 or above (e.g. EXPOSE 8080) and map it at runtime ('-p 80:8080' or a Service port)"
+	remediationEnvSecret          = "Remove the secret from ENV/ARG in the Dockerfile and pass it at runtime (docker run -e, a mounted secret file, or a Kubernetes Secret); rotate it, since it is readable in the image config"
+	remediationBuildPhase         = "Run 'acc verify --two-phase build <image>' for this image digest in the build pipeline and keep its .acc/state (not --no-state) for the deploy step"
+	remediationWarningBudget      = "Fix some of the warned issues (shown in the warnings list), or remove their profile/waiver suppressions, to get back under the budget"
+)
+
 // Verify verifies SBOM, policy compliance, and attestations (AGENTS.md Section 2 - acc verify)
 // This is critical: verification gates execution (Section 1.1)
 // v0.2.0: Accepts optional profile for post-evaluation filtering (pass nil for v0.1.x behavior)
+// Hooks from cfg.Hooks run around verification: a failing preVerify hook aborts before any checks,
+// postVerify hooks run after the verification state is saved
+// policy.noState (--no-state) leaves .acc/state untouched; postVerify hooks then get the result in a temp file
 func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON bool, prof *profile.Profile) (*VerifyResult, error) {
+	if err := runHooks(hookPreVerify, cfg.Hooks.PreVerify, imageRef, verifyResultPath); err != nil {
+		violation := PolicyViolation{
+			Rule:        "pre-verify-hook",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     err.Error(),
+			Remediation: remediationPreVerifyHook,
+		}
+		if !outputJSON {
+			ui.PrintError(fmt.Sprintf("Verification aborted: %v", err))
+		}
+		return &VerifyResult{
+			Status:       "fail",
+			Attestations: []string{},
+			Violations:   []PolicyViolation{violation},
+			PolicyMode:   cfg.Policy.Mode,
+			Env:          cfg.Policy.Env,
+			Team:         cfg.Project.Team,
+		}, fmt.Errorf("verification aborted: %w", err)
+	}
+
+	result, err := verify(cfg, imageRef, forPromotion, outputJSON, prof)
+	if result != nil && prof != nil {
+		result.ProfileUsed = prof.Name
+	}
+
+	// Post-verify hooks only run once a result (and its saved state) exists
+	if result != nil && len(cfg.Hooks.PostVerify) > 0 {
+		resultPath := verifyResultPath
+		if cfg.Policy.NoState {
+			path, cleanup, writeErr := writeTempVerifyState(imageRef, result, prof)
+			if writeErr != nil && !outputJSON {
+				ui.PrintWarning(fmt.Sprintf("postVerify hooks get no result: %v", writeErr))
+			}
+			defer cleanup()
+			resultPath = path
+		}
+		if hookErr := runHooks(hookPostVerify, cfg.Hooks.PostVerify, imageRef, resultPath); hookErr != nil && !outputJSON {
+			ui.PrintWarning(hookErr.Error())
+		}
+	}
+
+	return result, err
+}
+
+// verify performs SBOM, waiver, policy, and attestation checks and saves the verification state
+func verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON bool, prof *profile.Profile) (*VerifyResult, error) {
 	if !outputJSON {
 		ui.PrintTrust("Starting verification process")
+		if cfg.Policy.Phase != "" {
+			ui.PrintInfo(fmt.Sprintf("Verification phase: %s", cfg.Policy.Phase))
+		}
 	}
 
+	// policy.phase (--two-phase) selects the build-time or deploy-time checks
+	cfg = phaseConfig(cfg)
+
+	waiversApplied := !cfg.Policy.NoWaivers
 	result := &VerifyResult{
 		Status:       "pass",
 		Attestations: []string{},
@@ -57,6 +182,11 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 			Violations: []PolicyViolation{},
 			Warnings:   []PolicyViolation{},
 		},
+		PolicyMode:     cfg.Policy.Mode,
+		WaiversApplied: &waiversApplied,
+		Env:            cfg.Policy.Env,
+		Team:           cfg.Project.Team,
+		Phase:          cfg.Policy.Phase,
 	}
 
 	// Step 1: Verify SBOM exists
@@ -64,23 +194,45 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		ui.PrintInfo("Checking for SBOM...")
 	}
 
+	// sbom.fromImage (--sbom-from-image): check the SBOM the image carries instead of the project's
+	if cfg.SBOM.FromImage {
+		imageCfg, source, err := imageSBOMConfig(cfg, imageRef)
+		if err != nil && !outputJSON {
+			ui.PrintWarning(err.Error())
+		}
+		cfg = imageCfg
+		result.SBOMSource = source
+		if source != "" && !outputJSON {
+			ui.PrintInfo(fmt.Sprintf("Using the image's SBOM from %s", source))
+		}
+	}
+
 	sbomExists, err := checkSBOMExists(cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	result.SBOMPresent = sbomExists
+	if sbomExists {
+		result.SBOMFormat, _ = sbom.DetectFileFormat(findSBOMFile(cfg))
+	}
 
 	if !sbomExists {
+		sbomDir := cfg.SBOMDir()
+		notFound := fmt.Sprintf("SBOM is required but not found in %s/", sbomDir)
+		if len(cfg.SBOM.AcceptedFormats) > 0 {
+			notFound = fmt.Sprintf("SBOM is required but none in an accepted format (%s) was found in %s/", strings.Join(cfg.SBOM.AcceptedFormats, ", "), sbomDir)
+		}
 		// v0.2.2: Improved SBOM error message with actionable workflow guidance
-		errorMsg := fmt.Sprintf("SBOM is required but not found in .acc/sbom/\n\nWorkflow:\n  1. Build image: docker build -t %s .\n  2. Generate SBOM: syft %s -o spdx-json=.acc/sbom/%s.spdx.json\n  3. Verify: acc verify %s\n\nOr use: acc build -t %s . (generates SBOM automatically)",
-			imageRef, imageRef, cfg.Project.Name, imageRef, imageRef)
+		errorMsg := fmt.Sprintf("%s\n\nWorkflow:\n  1. Build image: docker build -t %s .\n  2. Generate SBOM: syft %s -o spdx-json=%s\n  3. Verify: acc verify %s\n\nOr use: acc build -t %s . (generates SBOM automatically)",
+			notFound, imageRef, imageRef, filepath.Join(sbomDir, cfg.Project.Name+".spdx.json"), imageRef, imageRef)
 
 		violation := PolicyViolation{
-			Rule:     "sbom-required",
-			Severity: "critical",
-			Result:   "fail",
-			Message:  "SBOM is required but not found in .acc/sbom/",
+			Rule:        "sbom-required",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     notFound,
+			Remediation: remediationSBOMRequired,
 		}
 		result.Violations = append(result.Violations, violation)
 		result.Status = "fail"
@@ -92,7 +244,7 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		// CRITICAL: Per AGENTS.md Section 1.1 - verification failures block execution
 		if cfg.Policy.Mode == "enforce" {
 			// Save state before failing
-			saveVerifyState(imageRef, result, prof)
+			persistVerifyState(cfg, imageRef, result, prof, outputJSON)
 			return result, fmt.Errorf("verification failed: SBOM required but not found\n\n%s", errorMsg)
 		}
 	} else {
@@ -102,27 +254,36 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 	}
 
 	// Step 2: Check for expired waivers (CRITICAL: expired waiver = fail)
-	if !outputJSON {
-		ui.PrintInfo("Checking policy waivers...")
-	}
-
-	loadedWaivers, err := waivers.LoadWaivers()
-	if err != nil {
-		// Waiver loading failure is not fatal, just log
+	// policy.noWaivers (--no-waivers) skips waivers entirely: no expiry failures, no suppression
+	loadedWaivers := []waivers.Waiver{}
+	if cfg.Policy.NoWaivers {
+		if !outputJSON {
+			ui.PrintInfo("Skipping policy waivers (--no-waivers)")
+		}
+	} else {
 		if !outputJSON {
-			ui.PrintWarning(fmt.Sprintf("Failed to load waivers: %v", err))
+			ui.PrintInfo("Checking policy waivers...")
+		}
+
+		loadedWaivers, err = waivers.LoadWaivers()
+		if err != nil {
+			// Waiver loading failure is not fatal, just log
+			if !outputJSON {
+				ui.PrintWarning(fmt.Sprintf("Failed to load waivers: %v", err))
+			}
+			loadedWaivers = []waivers.Waiver{}
 		}
-		loadedWaivers = []waivers.Waiver{}
 	}
 
 	// Check for expired waivers - CRITICAL: expired waiver causes verification failure
 	for _, waiver := range loadedWaivers {
 		if waiver.IsExpired() {
 			violation := PolicyViolation{
-				Rule:     waiver.RuleID,
-				Severity: "critical",
-				Result:   "fail",
-				Message:  fmt.Sprintf("Waiver for rule '%s' expired on %s", waiver.RuleID, waiver.Expiry),
+				Rule:        waiver.RuleID,
+				Severity:    "critical",
+				Result:      "fail",
+				Message:     fmt.Sprintf("Waiver for rule '%s' expired on %s", waiver.RuleID, waiver.Expiry),
+				Remediation: remediationWaiverExpired,
 			}
 			result.Violations = append(result.Violations, violation)
 			result.Status = "fail"
@@ -134,7 +295,7 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 	}
 
 	if result.Status == "fail" && len(result.Violations) > 0 && cfg.Policy.Mode == "enforce" {
-		saveVerifyState(imageRef, result, prof)
+		persistVerifyState(cfg, imageRef, result, prof, outputJSON)
 		return result, fmt.Errorf("verification failed: one or more waivers have expired")
 	}
 
@@ -148,10 +309,17 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 	if err != nil {
 		// v0.1.3: Image inspection failure is a CRITICAL violation
 		violation := PolicyViolation{
-			Rule:     "image-inspect-failed",
-			Severity: "critical",
-			Result:   "fail",
-			Message:  fmt.Sprintf("Unable to inspect image config: %v", err),
+			Rule:        "image-inspect-failed",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Unable to inspect image config: %v", err),
+			Remediation: remediationImageInspectFailed,
+		}
+		var baselineErr *sbomBaselineError
+		if errors.As(err, &baselineErr) {
+			violation.Rule = "sbom-baseline-invalid"
+			violation.Message = baselineErr.Error()
+			violation.Remediation = remediationSBOMBaseline
 		}
 		result.Violations = append(result.Violations, violation)
 		result.Status = "fail"
@@ -161,7 +329,7 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		}
 
 		if cfg.Policy.Mode == "enforce" {
-			saveVerifyState(imageRef, result, prof)
+			persistVerifyState(cfg, imageRef, result, prof, outputJSON)
 			return result, fmt.Errorf("verification failed: %s", violation.Message)
 		}
 	} else {
@@ -173,10 +341,11 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 	if err != nil {
 		// v0.1.4: Never return nil result - convert error to violation
 		violation := PolicyViolation{
-			Rule:     "policy-evaluation-error",
-			Severity: "critical",
-			Result:   "fail",
-			Message:  fmt.Sprintf("Policy evaluation error: %v", err),
+			Rule:        "policy-evaluation-error",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Policy evaluation error: %v", err),
+			Remediation: remediationPolicyEvalError,
 		}
 		result.Violations = append(result.Violations, violation)
 		result.Status = "fail"
@@ -191,7 +360,7 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		}
 
 		// Save state before returning
-		saveVerifyState(imageRef, result, prof)
+		persistVerifyState(cfg, imageRef, result, prof, outputJSON)
 
 		// v0.1.4: ALWAYS return valid result (never nil)
 		if cfg.Policy.Mode == "enforce" {
@@ -201,6 +370,186 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 	} else {
 		result.PolicyResult = policyResult
 		result.Violations = append(result.Violations, policyResult.Violations...)
+		result.PolicyUnevaluated = policyResult.unevaluated
+		if !outputJSON {
+			printRegoOutput(os.Stderr, policyResult.Print)
+			if result.PolicyUnevaluated {
+				ui.PrintWarning("OPA not found: policies were NOT evaluated (advisory mode, policy-unevaluated)")
+			}
+		}
+	}
+
+	// Step 3b: Check image build provenance (opt-in via policy.requireProvenance / --require-provenance),
+	// and its source repository when policy.provenanceSource / --require-provenance-source is set
+	if (cfg.Policy.RequireProvenance || cfg.Policy.ProvenanceSource != "") && result.PolicyResult != nil {
+		if !outputJSON {
+			ui.PrintInfo("Checking build provenance...")
+		}
+
+		if violation := checkProvenance(imageRef, cfg.Policy.ProvenanceSource); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess("Build provenance found")
+		}
+	}
+
+	// Step 3c: Check the image signature (opt-in via policy.verifyImageSignature / --verify-image-signature)
+	if cfg.Policy.VerifyImageSignature && result.PolicyResult != nil {
+		if !outputJSON {
+			ui.PrintInfo("Verifying image signature...")
+		}
+
+		if violation := checkImageSignature(cfg, imageRef); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess("Image signature verified")
+		}
+	}
+
+	// Step 3d: Check the SBOM signature (opt-in via policy.requireSbomSigned / --require-sbom-signed)
+	if cfg.Policy.RequireSBOMSigned && result.PolicyResult != nil {
+		if !outputJSON {
+			ui.PrintInfo("Verifying SBOM signature...")
+		}
+
+		if violation := checkSBOMSignature(cfg, imageRef); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess("SBOM signature verified")
+		}
+	}
+
+	// Step 3e: Check mandatory labels (policy.requiredLabels / --require-labels)
+	if len(cfg.Policy.RequiredLabels) > 0 && result.PolicyResult != nil && result.Input != nil {
+		if !outputJSON {
+			ui.PrintInfo("Checking required labels...")
+		}
+
+		violations := checkRequiredLabels(result.Input.Config.Labels, cfg.Policy.RequiredLabels)
+		for _, violation := range violations {
+			addViolation(result, violation, outputJSON)
+		}
+		if len(violations) == 0 && !outputJSON {
+			ui.PrintSuccess("Required labels present")
+		}
+	}
+
+	// Step 3f: Check the SBOM is not near-empty (sbom.minComponents / --min-sbom-components)
+	if cfg.SBOM.MinComponents > 0 && result.SBOMPresent && result.PolicyResult != nil {
+		if violation := checkSBOMComponents(findSBOMFile(cfg), cfg.SBOM.MinComponents); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		}
+	}
+
+	// Step 3g: Check the image is referenced by digest (policy.requireDigestPinned / --require-digest-pinned)
+	if cfg.Policy.RequireDigestPinned && result.PolicyResult != nil {
+		if violation := checkDigestPinned(imageRef); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		}
+	}
+
+	// Step 3h: Check for packages missing from the approved baseline (sbom.denyNewPackages / --deny-new-packages)
+	if cfg.SBOM.DenyNewPackages != "" && result.SBOMPresent && result.PolicyResult != nil {
+		violations := checkNewPackages(cfg.SBOM.DenyNewPackages, findSBOMFile(cfg))
+		for _, violation := range violations {
+			addViolation(result, violation, outputJSON)
+		}
+		if len(violations) == 0 && !outputJSON {
+			ui.PrintSuccess("No packages outside the SBOM baseline")
+		}
+	}
+
+	// Step 3i: Check the SBOM is in the format consumers require (sbom.requireFormat / --require-sbom-format)
+	if cfg.SBOM.RequireFormat != "" && result.SBOMPresent && result.PolicyResult != nil {
+		if violation := checkSBOMFormat(findSBOMFile(cfg), cfg.SBOM.RequireFormat); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess(fmt.Sprintf("SBOM is in %s format", cfg.SBOM.RequireFormat))
+		}
+	}
+
+	// Step 3j: Check the SBOM was generated after the image was built (sbom.sinceBuild / --since-build)
+	if cfg.SBOM.SinceBuild && result.SBOMPresent && result.PolicyResult != nil {
+		built, source, err := imageBuildTime(imageRef)
+		if err != nil {
+			if !outputJSON {
+				ui.PrintWarning(fmt.Sprintf("SBOM freshness not checked: %v", err))
+			}
+		} else if violation := checkSBOMStale(findSBOMFile(cfg), built, source); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess("SBOM is newer than the image build")
+		}
+	}
+
+	// Step 3k: Check the image does not run as root (policy.requireNonRoot / --require-nonroot)
+	// A no-root-user violation from the policy itself is not reported twice
+	if cfg.Policy.RequireNonRoot && result.PolicyResult != nil && result.Input != nil && !hasViolation(result.Violations, "no-root-user") {
+		if violation := checkNonRoot(result.Input.Config.User); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess(fmt.Sprintf("Image runs as non-root user %s", result.Input.Config.User))
+		}
+	}
+
+	// Step 3l: Check the image defines a HEALTHCHECK (policy.requireHealthcheck / --require-healthcheck)
+	// A warning unless policy.healthcheckSeverity is critical
+	// Built-in warnings are added after profile filtering, which rebuilds the warnings from the violations
+	var builtinWarnings []PolicyViolation
+	if cfg.Policy.RequireHealthcheck && result.PolicyResult != nil && result.Input != nil {
+		if violation := checkHealthcheck(result.Input.Config.Healthcheck, cfg.Policy.HealthcheckSeverity); violation == nil {
+			if !outputJSON {
+				ui.PrintSuccess("Image defines a healthcheck")
+			}
+		} else if violation.Result == "fail" {
+			addViolation(result, *violation, outputJSON)
+		} else {
+			builtinWarnings = append(builtinWarnings, *violation)
+			if !outputJSON {
+				ui.PrintWarning(violation.Message)
+			}
+		}
+	}
+
+	// Step 3m: Check the image exposes no port below 1024 (policy.forbidPrivilegedPorts / --forbid-privileged-ports)
+	if cfg.Policy.ForbidPrivilegedPorts && result.PolicyResult != nil && result.Input != nil {
+		violations := checkPrivilegedPorts(result.Input.Config.ExposedPorts, cfg.Policy.PrivilegedPortSeverity)
+		for _, violation := range violations {
+			if violation.Result == "warn" {
+				builtinWarnings = append(builtinWarnings, violation)
+				if !outputJSON {
+					ui.PrintWarning(violation.Message)
+				}
+				continue
+			}
+			addViolation(result, violation, outputJSON)
+		}
+		if len(violations) == 0 && !outputJSON {
+			ui.PrintSuccess("No privileged ports exposed")
+		}
+	}
+
+	// Step 3n: Check the image's environment for baked-in secrets (policy.denyEnvSecrets / --deny-env-secrets)
+	if cfg.Policy.DenyEnvSecrets && result.PolicyResult != nil && result.Input != nil {
+		violations, err := checkEnvSecrets(result.Input.Config.Env, cfg.Policy.EnvSecretPatterns)
+		if err != nil {
+			return result, err
+		}
+		for _, violation := range violations {
+			addViolation(result, violation, outputJSON)
+		}
+		if len(violations) == 0 && !outputJSON {
+			ui.PrintSuccess("No secrets found in image environment variables")
+		}
+	}
+
+	// Step 3o: The deploy phase requires a passing build-phase verification of the same digest
+	if cfg.Policy.Phase == PhaseDeploy && result.PolicyResult != nil {
+		if violation := checkBuildPhase(imageRef); violation != nil {
+			addViolation(result, *violation, outputJSON)
+		} else if !outputJSON {
+			ui.PrintSuccess("Build-phase verification found")
+		}
 	}
 
 	// v0.2.0: Apply profile filtering if profile is provided (post-evaluation gating)
@@ -209,10 +558,12 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		profileViolations := make([]profile.Violation, len(result.PolicyResult.Violations))
 		for i, v := range result.PolicyResult.Violations {
 			profileViolations[i] = profile.Violation{
-				Rule:     v.Rule,
-				Severity: v.Severity,
-				Result:   v.Result,
-				Message:  v.Message,
+				Rule:        v.Rule,
+				Severity:    v.Severity,
+				Result:      v.Result,
+				Message:     v.Message,
+				Remediation: v.Remediation,
+				Source:      v.Source,
 			}
 		}
 
@@ -223,20 +574,24 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		filteredViolations := make([]PolicyViolation, len(resolution.Violations))
 		for i, v := range resolution.Violations {
 			filteredViolations[i] = PolicyViolation{
-				Rule:     v.Rule,
-				Severity: v.Severity,
-				Result:   v.Result,
-				Message:  v.Message,
+				Rule:        v.Rule,
+				Severity:    v.Severity,
+				Result:      v.Result,
+				Message:     v.Message,
+				Remediation: v.Remediation,
+				Source:      v.Source,
 			}
 		}
 
 		filteredWarnings := make([]PolicyViolation, len(resolution.Warnings))
 		for i, v := range resolution.Warnings {
 			filteredWarnings[i] = PolicyViolation{
-				Rule:     v.Rule,
-				Severity: v.Severity,
-				Result:   v.Result,
-				Message:  v.Message,
+				Rule:        v.Rule,
+				Severity:    v.Severity,
+				Result:      v.Result,
+				Message:     v.Message,
+				Remediation: v.Remediation,
+				Source:      v.Source,
 			}
 		}
 
@@ -256,6 +611,41 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		}
 	}
 
+	if len(builtinWarnings) > 0 {
+		result.PolicyResult.Warnings = append(result.PolicyResult.Warnings, builtinWarnings...)
+	}
+
+	// Waivers whose rule did not fire suppress nothing; find them before waivers move violations
+	if (cfg.Policy.ReportUnusedWaivers || cfg.Policy.FailOnUnusedWaivers) && result.PolicyResult != nil {
+		result.UnusedWaivers = unusedWaivers(result, loadedWaivers)
+		if !outputJSON {
+			for _, w := range result.UnusedWaivers {
+				ui.PrintWarning(fmt.Sprintf("Unused waiver: %s (rule did not fire in this run)", w.RuleID))
+			}
+		}
+	}
+
+	// Active waivers downgrade the violations they cover to warnings (after profile filtering)
+	if waived := applyWaivers(result, loadedWaivers); waived > 0 && !outputJSON {
+		ui.PrintInfo(fmt.Sprintf("%d violation(s) waived by .acc/waivers.yaml", waived))
+	}
+
+	// --fail-on-unused-waivers: stale waivers fail verification
+	if cfg.Policy.FailOnUnusedWaivers && len(result.UnusedWaivers) > 0 {
+		for _, violation := range unusedWaiverViolations(result.UnusedWaivers) {
+			result.PolicyResult.Violations = append(result.PolicyResult.Violations, violation)
+			result.PolicyResult.Allow = false
+			result.Violations = append(result.Violations, violation)
+		}
+	}
+
+	// Too many suppressed issues fail verification even with no violations left
+	if cfg.Policy.FailOnWarningCount != nil {
+		if applyWarningBudget(result, *cfg.Policy.FailOnWarningCount) && !outputJSON {
+			ui.PrintWarning(fmt.Sprintf("Warning budget exceeded: %d warnings (budget %d)", result.WarningBudget.Count, result.WarningBudget.Limit))
+		}
+	}
+
 	// SINGLE AUTHORITATIVE FINAL GATE - v0.2.2
 	// Status and exit code MUST derive from PolicyResult.Allow (the final decision)
 	// This ensures consistency: if allow:true, status must be "pass" regardless of earlier checks
@@ -277,16 +667,14 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 		result.Status = "fail"
 		if !outputJSON && result.PolicyResult != nil && len(result.PolicyResult.Violations) > 0 {
 			ui.PrintError(fmt.Sprintf("Policy evaluation failed with %d violations:", len(result.PolicyResult.Violations)))
-			for _, v := range result.PolicyResult.Violations {
-				ui.PrintError(fmt.Sprintf("  [%s] %s: %s", v.Severity, v.Rule, v.Message))
-			}
+			printViolations(os.Stderr, result.PolicyResult.Violations, cfg.Policy.MaxViolations)
 		}
 
 		// CRITICAL: Per Testing Contract - exit code MUST match status field
 		// When status is "fail", verify MUST return error to ensure exit code 1
 		// This is independent of policy mode (warn vs enforce)
 		// Policy mode controls downstream blocking (push/run/promote), not verify exit code
-		saveVerifyState(imageRef, result, prof)
+		persistVerifyState(cfg, imageRef, result, prof, outputJSON)
 		return result, fmt.Errorf("verification failed: policy violations detected")
 	}
 
@@ -298,11 +686,75 @@ func Verify(cfg *config.Config, imageRef string, forPromotion bool, outputJSON b
 	}
 
 	// Save verification state
-	saveVerifyState(imageRef, result, prof)
+	persistVerifyState(cfg, imageRef, result, prof, outputJSON)
 
 	return result, nil
 }
 
+// printViolations writes violations in human format, truncated to max (see violationsForDisplay)
+// JSON output is never truncated
+func printViolations(w io.Writer, violations []PolicyViolation, max int) {
+	shown, more := violationsForDisplay(violations, max)
+	for _, v := range shown {
+		fmt.Fprintln(w, ui.FormatError(fmt.Sprintf("  [%s] %s: %s", v.Severity, v.Rule, v.Message)))
+		if v.Source != "" {
+			fmt.Fprintf(w, "      Source: %s\n", v.Source)
+		}
+		if v.Remediation != "" {
+			fmt.Fprintf(w, "      Remediation: %s\n", v.Remediation)
+		}
+	}
+	if more > 0 {
+		fmt.Fprintln(w, ui.FormatError(fmt.Sprintf("  (+%d more)", more)))
+	}
+}
+
+// severityRank orders severities for display (lower is more severe)
+var severityRank = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+}
+
+// violationsForDisplay sorts violations by severity then rule and truncates to max
+// Returns the violations to print and how many were omitted; max <= 0 means no limit
+func violationsForDisplay(violations []PolicyViolation, max int) ([]PolicyViolation, int) {
+	sorted := make([]PolicyViolation, len(violations))
+	copy(sorted, violations)
+
+	rank := func(severity string) int {
+		if r, ok := severityRank[strings.ToLower(severity)]; ok {
+			return r
+		}
+		return len(severityRank)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := rank(sorted[i].Severity), rank(sorted[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].Rule < sorted[j].Rule
+	})
+
+	if max <= 0 || len(sorted) <= max {
+		return sorted, 0
+	}
+	return sorted[:max], len(sorted) - max
+}
+
+// addViolation records a failing built-in check: it denies the policy result and reports
+// the violation in both violation lists, printing it unless outputJSON is set
+func addViolation(result *VerifyResult, violation PolicyViolation, outputJSON bool) {
+	result.PolicyResult.Violations = append(result.PolicyResult.Violations, violation)
+	result.PolicyResult.Allow = false
+	result.Violations = append(result.Violations, violation)
+
+	if !outputJSON {
+		ui.PrintError(violation.Message)
+	}
+}
+
 // VerifyState represents the persisted verification state for policy explain
 type VerifyState struct {
 	ImageRef    string        `json:"imageRef"`
@@ -310,6 +762,10 @@ type VerifyState struct {
 	Timestamp   string        `json:"timestamp"`
 	Result      *VerifyResult `json:"result"`
 	ProfileUsed string        `json:"profileUsed,omitempty"` // v0.2.0: Profile name if used
+	PolicyMode  string        `json:"policyMode,omitempty"`  // enforce|warn used for this verification
+	Phase       string        `json:"phase,omitempty"`       // build or deploy (verify --two-phase)
+	Env         string        `json:"env,omitempty"`         // environment verified for (verify --env)
+	ImageDigest string        `json:"imageDigest,omitempty"` // <algorithm>:<hex>, when the digest is known
 }
 
 // FormatJSON returns JSON representation
@@ -323,6 +779,15 @@ func (r *VerifyResult) FormatJSON() string {
 	return string(data)
 }
 
+// FormatJSONCompact formats the result as single-line JSON (verify --json-compact)
+func (r *VerifyResult) FormatJSONCompact() string {
+	if r == nil {
+		return `{"status":"fail","error":"internal error: nil result"}`
+	}
+	data, _ := json.Marshal(r)
+	return string(data)
+}
+
 // ExitCode returns the appropriate exit code for this result
 // v0.1.4: Nil-safe to prevent panics
 func (r *VerifyResult) ExitCode() int {
@@ -336,35 +801,75 @@ func (r *VerifyResult) ExitCode() int {
 	return 1
 }
 
-// checkSBOMExists verifies SBOM file presence
-// v0.2.1: Improved to check for exact match first, then any SBOM file
-func checkSBOMExists(cfg *config.Config) (bool, error) {
-	sbomDir := filepath.Join(".acc", "sbom")
+// SummaryLine returns a single key=value status line for cheap parsing in CI gates,
+// e.g. "status=fail violations=3 image=myapp:latest". A team=<name> label is appended when the result has a team.
+func (r *VerifyResult) SummaryLine(imageRef string) string {
+	if r == nil {
+		return fmt.Sprintf("status=fail violations=0 image=%s", imageRef)
+	}
+	line := fmt.Sprintf("status=%s violations=%d image=%s", r.Status, len(r.Violations), imageRef)
+	if r.Team != "" {
+		line += " team=" + r.Team
+	}
+	return line
+}
 
-	// First, check for exact match: {project}.{format}.json
-	sbomFile := filepath.Join(sbomDir, fmt.Sprintf("%s.%s.json", cfg.Project.Name, cfg.SBOM.Format))
-	if _, err := os.Stat(sbomFile); err == nil {
-		return true, nil
+// WriteSummaryFile writes the SummaryLine for result to path
+func WriteSummaryFile(path, imageRef string, result *VerifyResult) error {
+	if err := os.WriteFile(path, []byte(result.SummaryLine(imageRef)+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
 	}
+	return nil
+}
 
+// checkSBOMExists verifies SBOM file presence
+// v0.2.1: Improved to check for exact match first, then any SBOM file
+func checkSBOMExists(cfg *config.Config) (bool, error) {
 	// v0.2.1: Fallback - check for any SBOM file in the directory
 	// This handles cases where format might differ or project name mismatch
-	entries, err := os.ReadDir(sbomDir)
-	if err != nil {
+	if _, err := os.ReadDir(cfg.SBOMDir()); err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
 		}
 		return false, err
 	}
 
+	return findSBOMFile(cfg) != "", nil
+}
+
+// findSBOMFile returns the SBOM checkSBOMExists would accept, or "" if none
+// Prefers {project}.{format}.json, then {project}.{accepted}.json for each of sbom.acceptedFormats,
+// then the first .json file in the SBOM directory. With sbom.acceptedFormats set, only files
+// whose content is in an accepted format qualify.
+func findSBOMFile(cfg *config.Config) string {
+	sbomDir := cfg.SBOMDir()
+
+	candidates := []string{fmt.Sprintf("%s.%s.json", cfg.Project.Name, sbomLookupFormat(cfg))}
+	for _, format := range cfg.SBOM.AcceptedFormats {
+		candidates = append(candidates, fmt.Sprintf("%s.%s.json", cfg.Project.Name, format))
+	}
+	for _, name := range candidates {
+		sbomFile := filepath.Join(sbomDir, name)
+		if _, err := os.Stat(sbomFile); err == nil && sbomFormatAccepted(cfg, sbomFile) {
+			return sbomFile
+		}
+	}
+
+	entries, err := os.ReadDir(sbomDir)
+	if err != nil {
+		return ""
+	}
+
 	// Look for any .json file in SBOM directory
 	for _, entry := range entries {
 		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			return true, nil
+			if sbomFile := filepath.Join(sbomDir, entry.Name()); sbomFormatAccepted(cfg, sbomFile) {
+				return sbomFile
+			}
 		}
 	}
 
-	return false, nil
+	return ""
 }
 
 // RegoInput represents the input document passed to Rego policy evaluation
@@ -373,17 +878,28 @@ type RegoInput struct {
 	SBOM        SBOMInfo        `json:"sbom"`
 	Attestation AttestationInfo `json:"attestation"`
 	Promotion   bool            `json:"promotion"`
+	Build       *BuildInfo      `json:"build,omitempty"` // set when the input comes from the build manifest
 }
 
 // ImageConfig contains image configuration fields
 type ImageConfig struct {
-	User   string            `json:"User"`
-	Labels map[string]string `json:"Labels"`
+	User         string              `json:"User"`
+	Labels       map[string]string   `json:"Labels"`
+	Env          []string            `json:"Env,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	Healthcheck  *oci.HealthConfig   `json:"Healthcheck,omitempty"`
+}
+
+// BuildInfo is build-time context recorded in the build manifest (acc build)
+type BuildInfo struct {
+	BaseImage string `json:"baseImage,omitempty"`
+	CreatedAt string `json:"createdAt"`
 }
 
 // SBOMInfo contains SBOM presence information
 type SBOMInfo struct {
-	Present bool `json:"present"`
+	Present bool        `json:"present"`
+	Changed *sbom.Delta `json:"changed,omitempty"` // packages added/removed/upgraded since sbom.baseline
 }
 
 // AttestationInfo contains attestation presence information
@@ -393,15 +909,20 @@ type AttestationInfo struct {
 
 // inspectImageConfig inspects an image and returns its config
 // v0.1.3: Returns error if inspection fails (no silent fallback)
-func inspectImageConfig(imageRef string) (*ImageConfig, error) {
+// With capturePath (verify --capture-inspect), the raw inspect output the config came from is
+// written there.
+func inspectImageConfig(imageRef, capturePath string) (*ImageConfig, error) {
 	// Try docker/podman/nerdctl to inspect image
 	tools := []string{"docker", "podman", "nerdctl"}
 
 	var lastErr error
 	for _, tool := range tools {
-		if _, err := exec.LookPath(tool); err == nil {
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		for _, ref := range oci.InspectRefs(imageRef) {
 			// Use docker inspect to get full config as JSON
-			cmd := exec.Command(tool, "inspect", imageRef)
+			cmd := exec.Command(tool, "inspect", ref)
 			output, err := cmd.Output()
 			if err != nil {
 				lastErr = err
@@ -410,10 +931,7 @@ func inspectImageConfig(imageRef string) (*ImageConfig, error) {
 
 			// Parse JSON output
 			var inspectOutput []struct {
-				Config struct {
-					User   string            `json:"User"`
-					Labels map[string]string `json:"Labels"`
-				} `json:"Config"`
+				Config ImageConfig `json:"Config"`
 			}
 
 			if err := json.Unmarshal(output, &inspectOutput); err != nil {
@@ -422,14 +940,18 @@ func inspectImageConfig(imageRef string) (*ImageConfig, error) {
 			}
 
 			if len(inspectOutput) > 0 {
+				if capturePath != "" {
+					if err := os.WriteFile(capturePath, output, 0644); err != nil {
+						return nil, fmt.Errorf("failed to write --capture-inspect file: %w", err)
+					}
+				}
 				labels := inspectOutput[0].Config.Labels
 				if labels == nil {
 					labels = make(map[string]string)
 				}
-				return &ImageConfig{
-					User:   inspectOutput[0].Config.User,
-					Labels: labels,
-				}, nil
+				imageConfig := inspectOutput[0].Config
+				imageConfig.Labels = labels
+				return &imageConfig, nil
 			}
 		}
 	}
@@ -441,51 +963,180 @@ func inspectImageConfig(imageRef string) (*ImageConfig, error) {
 	return nil, fmt.Errorf("no container tools found (docker/podman/nerdctl required)")
 }
 
+// fetchRemoteImageConfig reads the image config from the registry without pulling the image
+func fetchRemoteImageConfig(imageRef string) (*ImageConfig, error) {
+	registry, repository, reference, err := oci.ParseReference(imageRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only digest references are cached: a digest pins the config, a tag does not
+	var cacheKey string
+	if oci.IsDigest(reference) {
+		cacheKey = cache.Key([]byte(cache.NamespaceImageConfig), []byte(reference))
+		var cached ImageConfig
+		if cache.Get(cache.NamespaceImageConfig, cacheKey, &cached) {
+			ui.PrintDebug(fmt.Sprintf("image config cache hit (%s)", reference))
+			return &cached, nil
+		}
+	}
+
+	repo, err := oci.NewRepository(registry, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := oci.FetchImageConfig(context.Background(), repo, reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image config from registry: %w", err)
+	}
+
+	labels := config.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	imageConfig := &ImageConfig{
+		User:         config.User,
+		Labels:       labels,
+		Env:          config.Env,
+		ExposedPorts: config.ExposedPorts,
+		Healthcheck:  config.Healthcheck,
+	}
+
+	if cacheKey != "" {
+		if err := cache.Put(cache.NamespaceImageConfig, cacheKey, imageConfig); err != nil {
+			ui.PrintDebug(fmt.Sprintf("failed to cache image config: %v", err))
+		}
+	}
+	return imageConfig, nil
+}
+
+// BuildInput returns the input document policy evaluation would receive for imageRef,
+// without evaluating policy or saving state (acc verify --print-input)
+func BuildInput(cfg *config.Config, imageRef string) (*RegoInput, error) {
+	return buildRegoInput(cfg, imageRef, false)
+}
+
 // buildRegoInput constructs the input document for Rego evaluation
 func buildRegoInput(cfg *config.Config, imageRef string, forPromotion bool) (*RegoInput, error) {
+	// policy.input (--input): replay a recorded input document, e.g. from a --fixture
+	if cfg.Policy.Input != "" {
+		return loadRegoInput(cfg.Policy.Input)
+	}
+
+	// --input-from-manifest: use the config acc build recorded for this digest
+	var imageConfig *ImageConfig
+	var buildInfo *BuildInfo
+	if cfg.Policy.InputFromManifest {
+		imageConfig, buildInfo = manifestImageConfig(imageRef)
+	}
+
 	// Get image configuration - v0.1.3: hard fail if this fails
-	imageConfig, err := inspectImageConfig(imageRef)
-	if err != nil {
-		return nil, err
+	if imageConfig == nil {
+		var err error
+		imageConfig, err = inspectImageConfig(imageRef, cfg.Policy.CaptureInspect)
+		if err != nil && cfg.Registry.RemoteConfig {
+			// Image not available locally (e.g. pull-by-digest CI): read config from the registry
+			imageConfig, err = fetchRemoteImageConfig(imageRef)
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Check for SBOM
 	sbomPresent, _ := checkSBOMExists(cfg)
 
+	// sbom.baseline: packages changed since a previous build's SBOM
+	sbomChanged, err := sbomChanges(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check for attestations
 	attestationPresent := checkAttestations(cfg)
 
 	return &RegoInput{
 		Config:      *imageConfig,
-		SBOM:        SBOMInfo{Present: sbomPresent},
+		SBOM:        SBOMInfo{Present: sbomPresent, Changed: sbomChanged},
 		Attestation: AttestationInfo{Present: attestationPresent},
 		Promotion:   forPromotion,
+		Build:       buildInfo,
 	}, nil
 }
 
+// manifestImageConfig loads the image config recorded by acc build for imageRef's digest
+// Returns nil when there is no usable manifest, so the caller falls back to live inspection
+func manifestImageConfig(imageRef string) (*ImageConfig, *BuildInfo) {
+	digest, err := oci.ResolveDigest(imageRef)
+	if err != nil {
+		ui.PrintDebug(fmt.Sprintf("build manifest not used: %v", err))
+		return nil, nil
+	}
+
+	manifest, err := build.LoadManifest(digest)
+	if err != nil {
+		ui.PrintDebug(fmt.Sprintf("build manifest not used, inspecting image instead: %v", err))
+		return nil, nil
+	}
+
+	labels := manifest.Config.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	return &ImageConfig{
+		User:         manifest.Config.User,
+		Labels:       labels,
+		Env:          manifest.Config.Env,
+		ExposedPorts: manifest.Config.ExposedPorts,
+		Healthcheck:  manifest.Config.Healthcheck,
+	}, &BuildInfo{
+		BaseImage: manifest.BaseImage,
+		CreatedAt: manifest.CreatedAt,
+	}
+}
+
 // evaluateRego runs OPA evaluation and returns violations
 // v0.1.4: OPA missing creates a violation (not an error) to prevent panics
-func evaluateRego(policyDir string, input *RegoInput) ([]PolicyViolation, error) {
-	// Check if opa is available
-	opaPath, err := exec.LookPath("opa")
-	if err != nil {
-		// v0.1.4: OPA missing is a CRITICAL VIOLATION, not a bypass
-		// Even with escape hatch, return a violation (for CI/testing compatibility)
-		violation := PolicyViolation{
-			Rule:     "opa-required",
-			Severity: "critical",
-			Result:   "fail",
-			Message:  "OPA not found. Policy evaluation requires OPA to be installed.\n\nInstall OPA: https://www.openpolicyagent.org/docs/latest/#running-opa",
-		}
+// dataPaths are loaded alongside the policy directory (e.g. the --data namespace dir)
+func evaluateRego(policyDir string, input *RegoInput, dataPaths ...string) ([]PolicyViolation, error) {
+	return evaluateRegoPaths(append([]string{policyDir}, dataPaths...), regoOptions{}, input)
+}
 
-		// Escape hatch for CI/testing: allows tests to run but still records violation
-		if os.Getenv("ACC_ALLOW_NO_OPA") == "1" {
-			// Return violation but don't error - allows tests to complete
-			return []PolicyViolation{violation}, nil
-		}
+// regoOptions controls an OPA evaluation; zero values use the defaults
+type regoOptions struct {
+	Query   string           // decision document holding violations/deny (default data.acc.policy.result)
+	Timeout time.Duration    // opa eval is killed after this long (default 30s)
+	Print   *regoPrintOutput // when set, print() output is collected here and the eval cache is bypassed
+	Explain bool             // trace the evaluation and set each violation's Source from it (policy.explainDeny); bypasses the eval cache
+}
+
+// regoOptionsFor returns the evaluation options configured by policy.regoQuery, policy.regoTimeout,
+// policy.regoPrint, and policy.explainDeny
+func regoOptionsFor(cfg *config.Config) regoOptions {
+	opts := regoOptions{Query: cfg.RegoQuery(), Timeout: cfg.RegoTimeout(), Explain: cfg.Policy.ExplainDeny}
+	if cfg.Policy.RegoPrint {
+		opts.Print = &regoPrintOutput{}
+	}
+	return opts
+}
 
-		// Without escape hatch, still return violation (not error) to prevent panic
-		return []PolicyViolation{violation}, nil
+// evaluateRegoPaths runs a single OPA evaluation loading each of dataPaths (files or directories).
+// An evaluation that exceeds opts.Timeout yields a policy-evaluation-timeout violation
+func evaluateRegoPaths(dataPaths []string, opts regoOptions, input *RegoInput) ([]PolicyViolation, error) {
+	query := opts.Query
+	if query == "" {
+		query = config.DefaultRegoQuery
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultRegoTimeout
+	}
+
+	// Check that opa is available and new enough (opa-required / opa-version-unsupported)
+	opaPath, violation := checkOPA()
+	if violation != nil {
+		return []PolicyViolation{*violation}, nil
 	}
 
 	// Marshal input to JSON
@@ -494,6 +1145,22 @@ func evaluateRego(policyDir string, input *RegoInput) ([]PolicyViolation, error)
 		return nil, fmt.Errorf("failed to marshal input: %w", err)
 	}
 
+	// Evaluations are keyed by the policy files OPA loads plus the input, so
+	// runs sharing a cache directory reuse results only for identical content
+	// A cached result would skip the policy's print() calls, so --rego-print always evaluates;
+	// --explain-deny needs the trace, so it does too
+	var cacheKey string
+	if cache.Dir() != "" && opts.Print == nil && !opts.Explain {
+		if policyHash, err := dataPathsHash(dataPaths); err == nil {
+			cacheKey = cache.Key([]byte(cache.NamespacePolicyEval), []byte(opaPath), []byte(query), []byte(policyHash), inputJSON)
+			var cached []PolicyViolation
+			if cache.Get(cache.NamespacePolicyEval, cacheKey, &cached) {
+				ui.PrintDebug(fmt.Sprintf("policy evaluation cache hit (%s)", cacheKey[:12]))
+				return cached, nil
+			}
+		}
+	}
+
 	// Write input to temp file
 	inputFile, err := os.CreateTemp("", "acc-rego-input-*.json")
 	if err != nil {
@@ -507,29 +1174,60 @@ func evaluateRego(policyDir string, input *RegoInput) ([]PolicyViolation, error)
 	}
 	inputFile.Close()
 
-	// Run OPA eval - evaluate data.acc.policy.result (not just deny)
+	// Run OPA eval - evaluate the decision document (data.acc.policy.result by default, not just deny)
 	// This allows policies to build complete result objects
-	cmd := exec.Command(opaPath, "eval",
-		"--data", policyDir,
-		"--input", inputFile.Name(),
-		"--format", "json",
-		"data.acc.policy.result")
+	args := []string{"eval"}
+	for _, path := range dataPaths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, "--input", inputFile.Name(), "--format", "json")
+	if opts.Explain {
+		args = append(args, "--explain", "full")
+	}
+	args = append(args, query)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, opaPath, args...)
+	// Don't wait on output pipes held open by anything opa left behind once it is killed
+	cmd.WaitDelay = time.Second
+	// OPA writes print() output to stderr
+	var stderr bytes.Buffer
+	if opts.Print != nil {
+		cmd.Stderr = &stderr
+	}
 
 	output, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		// Not cached: a slow run says nothing about the policy result
+		return []PolicyViolation{{
+			Rule:        "policy-evaluation-timeout",
+			Severity:    "critical",
+			Result:      "fail",
+			Message:     fmt.Sprintf("Policy evaluation did not finish within %s and was stopped", timeout),
+			Remediation: remediationRegoTimeout,
+		}}, nil
+	}
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
+			if opts.Print != nil {
+				return nil, fmt.Errorf("OPA evaluation failed: %s", stderr.String())
+			}
 			return nil, fmt.Errorf("OPA evaluation failed: %s", string(exitErr.Stderr))
 		}
 		return nil, fmt.Errorf("OPA evaluation failed: %w", err)
 	}
+	if opts.Print != nil {
+		opts.Print.add(stderr.String())
+	}
 
 	// Parse OPA output
 	var opaResult struct {
 		Result []struct {
 			Expressions []struct {
-				Value map[string]interface{} `json:"value"`
+				Value interface{} `json:"value"`
 			} `json:"expressions"`
 		} `json:"result"`
+		Explanation []traceEvent `json:"explanation"`
 	}
 
 	if err := json.Unmarshal(output, &opaResult); err != nil {
@@ -539,8 +1237,15 @@ func evaluateRego(policyDir string, input *RegoInput) ([]PolicyViolation, error)
 	// Extract violations from policy result
 	var violations []PolicyViolation
 	if len(opaResult.Result) > 0 && len(opaResult.Result[0].Expressions) > 0 {
-		value := opaResult.Result[0].Expressions[0].Value
-		if value != nil {
+		switch value := opaResult.Result[0].Expressions[0].Value.(type) {
+		case []interface{}:
+			// A query targeting a single rule (e.g. data.example.deny) yields the set itself
+			for _, item := range value {
+				if violation := parseViolationObject(item); violation != nil {
+					violations = append(violations, *violation)
+				}
+			}
+		case map[string]interface{}:
 			// Extract violations from result.violations
 			if viols, ok := value["violations"].([]interface{}); ok {
 				for _, item := range viols {
@@ -561,9 +1266,32 @@ func evaluateRego(policyDir string, input *RegoInput) ([]PolicyViolation, error)
 		}
 	}
 
+	if opts.Explain {
+		attachTraceSources(violations, opaResult.Explanation)
+	}
+
+	if cacheKey != "" {
+		if err := cache.Put(cache.NamespacePolicyEval, cacheKey, violations); err != nil {
+			ui.PrintDebug(fmt.Sprintf("failed to cache policy evaluation: %v", err))
+		}
+	}
+
 	return violations, nil
 }
 
+// dataPathsHash hashes the policy and data files OPA loads from dataPaths
+func dataPathsHash(dataPaths []string) (string, error) {
+	var parts []string
+	for _, path := range dataPaths {
+		hash, err := policy.FilesHash(path, ".rego", ".json", ".yaml", ".yml")
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, path+"="+hash)
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
 // parseViolationObject parses a single violation object
 func parseViolationObject(obj interface{}) *PolicyViolation {
 	m, ok := obj.(map[string]interface{})
@@ -572,10 +1300,11 @@ func parseViolationObject(obj interface{}) *PolicyViolation {
 	}
 
 	violation := &PolicyViolation{
-		Rule:     getString(m, "rule", "policy-violation"),
-		Severity: getString(m, "severity", "error"),
-		Result:   getString(m, "result", "fail"),
-		Message:  getString(m, "message", "Policy deny rule triggered"),
+		Rule:        getString(m, "rule", "policy-violation"),
+		Severity:    getString(m, "severity", "error"),
+		Result:      getString(m, "result", "fail"),
+		Message:     getString(m, "message", "Policy deny rule triggered"),
+		Remediation: getString(m, "remediation", ""),
 	}
 
 	return violation
@@ -597,8 +1326,13 @@ func evaluatePolicy(cfg *config.Config, imageRef string, forPromotion bool) (*Po
 		Warnings:   []PolicyViolation{},
 	}
 
-	// Load policy files from .acc/policy/
-	policyDir := ".acc/policy"
+	// Load policy files from .acc/policy/, or the pack selected with policy.pack (--policy-pack)
+	pack, err := policy.ResolvePack(cfg.Policy.Pack)
+	if err != nil {
+		return nil, err
+	}
+	defer pack.Cleanup()
+	policyDir := pack.Dir
 
 	// Check if policy directory exists
 	if _, err := os.Stat(policyDir); os.IsNotExist(err) {
@@ -607,14 +1341,27 @@ func evaluatePolicy(cfg *config.Config, imageRef string, forPromotion bool) (*Po
 	}
 
 	// Read all .rego files in policy directory
-	files, err := filepath.Glob(filepath.Join(policyDir, "*.rego"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to read policy files: %w", err)
-	}
+	// policy.parallelOpa evaluates each policy group (subdirectory) separately
+	var groups []policyGroup
+	if cfg.Policy.ParallelOPA {
+		var err error
+		groups, err = policyGroups(policyDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy files: %w", err)
+		}
+		if len(groups) == 0 {
+			return result, nil
+		}
+	} else {
+		files, err := filepath.Glob(filepath.Join(policyDir, "*.rego"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy files: %w", err)
+		}
 
-	if len(files) == 0 {
-		// No policy files - allow by default
-		return result, nil
+		if len(files) == 0 {
+			// No policy files - allow by default
+			return result, nil
+		}
 	}
 
 	// Build Rego input document
@@ -623,11 +1370,48 @@ func evaluatePolicy(cfg *config.Config, imageRef string, forPromotion bool) (*Po
 		return nil, fmt.Errorf("failed to build Rego input: %w", err)
 	}
 
+	// --data files are loaded by every evaluation under data.acc.external
+	var dataPaths []string
+	if len(cfg.Policy.Data) > 0 {
+		dataDir, err := writeExternalData(cfg.Policy.Data)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dataDir)
+		dataPaths = append(dataPaths, dataDir)
+		for i := range groups {
+			groups[i].Paths = append(groups[i].Paths, dataDir)
+		}
+	}
+
+	// Advisory runs without opa report that policies were skipped instead of failing on opa-required
+	if cfg.Policy.AllowMissingOPA {
+		if _, err := exec.LookPath("opa"); err != nil {
+			result.Warnings = append(result.Warnings, policyUnevaluatedWarning())
+			result.unevaluated = true
+			return result, nil
+		}
+	}
+
 	// Evaluate policy with OPA
-	violations, err := evaluateRego(policyDir, regoInput)
+	var violations []PolicyViolation
+	opts := regoOptionsFor(cfg)
+	if cfg.Policy.ParallelOPA {
+		violations, err = evaluateRegoGroups(groups, opts, regoInput)
+	} else {
+		violations, err = evaluateRegoPaths(append([]string{policyDir}, dataPaths...), opts, regoInput)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate policy: %w", err)
 	}
+	if opts.Print != nil {
+		result.Print = opts.Print.Lines()
+	}
+
+	// policy.explainDeny (--explain-deny): sources were set from OPA's trace during evaluation
+	if cfg.Policy.ExplainDeny {
+		relabelBuiltinSources(violations, pack)
+	}
 
 	if len(violations) > 0 {
 		// If ANY deny violations exist, policy fails
@@ -639,95 +1423,132 @@ func evaluatePolicy(cfg *config.Config, imageRef string, forPromotion bool) (*Po
 	return result, nil
 }
 
-// checkAttestations checks if attestations are present (stubbed)
+// checkAttestations checks if acc attestations are present (any image). External (cosign)
+// attestations cached by trust verify --remote are not signature-checked and do not count.
 func checkAttestations(cfg *config.Config) bool {
-	// TODO: Implement actual attestation checking
-	// For now, check if .acc/attestations directory has any files
-	attestDir := filepath.Join(".acc", "attestations")
-	if _, err := os.Stat(attestDir); os.IsNotExist(err) {
-		return false
-	}
-	return true
+	return trust.HasAttestations()
 }
 
-// saveVerifyState persists verification results for policy explain
-func saveVerifyState(imageRef string, result *VerifyResult, prof *profile.Profile) error {
-	stateDir := filepath.Join(".acc", "state")
-	if err := os.MkdirAll(stateDir, 0755); err != nil {
-		return fmt.Errorf("failed to create state directory: %w", err)
+// persistVerifyState saves the verification state unless policy.noState (--no-state) is set.
+// A failed save is reported (StateError and a warning): push, promote, and the deploy phase
+// of --two-phase read this state and would otherwise fail later without saying why.
+func persistVerifyState(cfg *config.Config, imageRef string, result *VerifyResult, prof *profile.Profile, outputJSON bool) {
+	if cfg.Policy.NoState {
+		return
+	}
+	if err := saveVerifyState(imageRef, result, prof); err != nil {
+		result.StateError = err.Error()
+		if !outputJSON {
+			ui.PrintWarning(fmt.Sprintf("Verification state not saved: %v", err))
+		}
 	}
+}
 
-	state := VerifyState{
-		ImageRef:  imageRef,
-		Status:    result.Status,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Result:    result,
+// marshalVerifyState returns the verification state JSON saved for result; digest (in acc's
+// digest form, possibly empty) is recorded with its algorithm
+func marshalVerifyState(imageRef, digest string, result *VerifyResult, prof *profile.Profile) ([]byte, error) {
+	verifyState := VerifyState{
+		ImageRef:   imageRef,
+		Status:     result.Status,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Result:     result,
+		PolicyMode: result.PolicyMode,
+		Phase:      result.Phase,
+		Env:        result.Env,
+	}
+	if digest != "" {
+		verifyState.ImageDigest = oci.QualifiedDigest(digest)
 	}
 
 	// v0.2.0: Save profile name if profile was used
 	if prof != nil {
-		state.ProfileUsed = prof.Name
+		verifyState.ProfileUsed = prof.Name
 	}
 
 	// Mask any potential secrets before saving
 	// (Currently none in VerifyResult, but defensive)
-	data, err := json.MarshalIndent(state, "", "  ")
+	data, err := json.MarshalIndent(verifyState, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal state: %w", err)
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return data, nil
+}
+
+// writeTempVerifyState writes the verification state to a temporary file for postVerify
+// hooks when --no-state keeps it out of .acc/state; cleanup removes the file
+func writeTempVerifyState(imageRef string, result *VerifyResult, prof *profile.Profile) (string, func(), error) {
+	noop := func() {}
+	_, digest, _ := oci.SplitDigestRef(imageRef)
+	data, err := marshalVerifyState(imageRef, digest, result, prof)
+	if err != nil {
+		return "", noop, err
+	}
+	file, err := os.CreateTemp("", "acc-verify-result-*.json")
+	if err != nil {
+		return "", noop, err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", noop, err
+	}
+	return file.Name(), func() { os.Remove(file.Name()) }, nil
+}
+
+// saveVerifyState persists verification results for policy explain
+func saveVerifyState(imageRef string, result *VerifyResult, prof *profile.Profile) error {
+	stateDir := filepath.Join(".acc", "state")
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	// v0.1.5: The digest also scopes the state to this image (see below)
+	digest, digestErr := oci.ResolveDigest(imageRef)
+	if digestErr != nil {
+		digest = ""
+	}
+
+	data, err := marshalVerifyState(imageRef, digest, result, prof)
+	if err != nil {
+		return err
 	}
 
 	// Save to global last_verify.json (for backward compatibility)
 	stateFile := filepath.Join(stateDir, "last_verify.json")
-	if err := os.WriteFile(stateFile, data, 0644); err != nil {
+	if err := state.WriteFile(stateFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write state file: %w", err)
 	}
 
 	// v0.1.5: Also save to digest-scoped file for per-image state
-	digest, err := resolveImageDigest(imageRef)
-	if err == nil && digest != "" {
-		// Create verify subdirectory
-		verifyStateDir := filepath.Join(stateDir, "verify")
-		if err := os.MkdirAll(verifyStateDir, 0755); err != nil {
-			// Non-fatal: just log and continue
-			return nil
-		}
-
-		// Save to .acc/state/verify/<digest>.json
-		digestFile := filepath.Join(verifyStateDir, digest+".json")
-		if err := os.WriteFile(digestFile, data, 0644); err != nil {
-			// Non-fatal: just log and continue
-			return nil
+	if digest == "" {
+		// The deploy phase finds the build phase by digest (checkBuildPhase)
+		if result.Phase == PhaseBuild {
+			return fmt.Errorf("build-phase verification not recorded: %w", digestErr)
 		}
+		return nil
 	}
 
-	return nil
-}
+	// Create verify subdirectory
+	verifyStateDir := filepath.Join(stateDir, "verify")
+	if err := os.MkdirAll(verifyStateDir, 0755); err != nil {
+		return fmt.Errorf("failed to create verify state directory: %w", err)
+	}
 
-// resolveImageDigest resolves an image reference to its digest
-// v0.1.5: Added for digest-scoped state storage
-func resolveImageDigest(imageRef string) (string, error) {
-	tools := []struct {
-		name string
-		args []string
-	}{
-		{"docker", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"podman", []string{"inspect", "--format={{.Id}}", imageRef}},
-		{"nerdctl", []string{"inspect", "--format={{.Id}}", imageRef}},
+	// Save to .acc/state/verify/<digest>.json
+	digestFile := filepath.Join(verifyStateDir, oci.DigestFileName(digest)+".json")
+	if err := state.WriteFile(digestFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write digest state file: %w", err)
 	}
 
-	for _, tool := range tools {
-		if _, err := exec.LookPath(tool.name); err == nil {
-			cmd := exec.Command(tool.name, tool.args...)
-			output, err := cmd.Output()
-			if err == nil {
-				digest := strings.TrimSpace(string(output))
-				digest = strings.TrimPrefix(digest, "sha256:")
-				if digest != "" {
-					return digest, nil
-				}
-			}
+	// The build phase is also kept on its own for the deploy phase (checkBuildPhase)
+	if result.Phase == PhaseBuild {
+		if err := state.WriteFile(buildPhaseStatePath(digest), data, 0644); err != nil {
+			return fmt.Errorf("build-phase verification not recorded: %w", err)
 		}
 	}
 
-	return "", fmt.Errorf("could not resolve digest")
+	return nil
 }
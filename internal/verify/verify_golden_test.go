@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -161,6 +163,28 @@ func TestVerifyJSONSchemaVersion(t *testing.T) {
 	// If schemaVersion is added in the future, this test will catch it
 }
 
+// TestVerifyJSONCompact tests that --json-compact output is one unindented line with the same content
+func TestVerifyJSONCompact(t *testing.T) {
+	result := &VerifyResult{
+		Status:       "fail",
+		SBOMPresent:  true,
+		PolicyResult: &PolicyResult{Allow: false, Violations: []PolicyViolation{{Rule: "no-root-user", Severity: "high", Result: "fail", Message: "Container runs as root"}}, Warnings: []PolicyViolation{}},
+		Attestations: []string{},
+		Violations:   []PolicyViolation{{Rule: "no-root-user", Severity: "high", Result: "fail", Message: "Container runs as root"}},
+	}
+
+	compact := result.FormatJSONCompact()
+	if strings.Contains(compact, "\n") || strings.Contains(compact, "  ") {
+		t.Errorf("compact JSON is indented or multi-line: %q", compact)
+	}
+	var compactObj, prettyObj interface{}
+	json.Unmarshal([]byte(compact), &compactObj)
+	json.Unmarshal([]byte(result.FormatJSON()), &prettyObj)
+	if !reflect.DeepEqual(compactObj, prettyObj) {
+		t.Errorf("compact JSON differs from --json output: %s", compact)
+	}
+}
+
 // compareJSON compares two JSON strings for semantic equality
 func compareJSON(t *testing.T, actual, expected string) error {
 	var actualObj, expectedObj interface{}
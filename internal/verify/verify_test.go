@@ -1,13 +1,17 @@
 package verify
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/oci"
 )
 
 // v0.1.3 REGRESSION TEST 1: Test that input document is properly constructed
@@ -775,3 +779,394 @@ func TestVerify_SBOMMissingErrorMessage(t *testing.T) {
 		t.Error("expected sbom-required violation")
 	}
 }
+
+// TestVerify_PolicyModeOverride tests that --policy-mode changes whether violations block
+func TestVerify_PolicyModeOverride(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "acc-policy-mode-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	// NO SBOM - enforce mode blocks immediately on sbom-required
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "policy-mode-test"},
+		SBOM:    config.SBOMConfig{Format: "spdx"},
+		Policy:  config.PolicyConfig{Mode: "enforce"},
+	}
+
+	result, err := Verify(cfg, "test:image", false, true, nil)
+	if err == nil || !strings.Contains(err.Error(), "SBOM required") {
+		t.Fatalf("expected enforce mode to block on missing SBOM, got: %v", err)
+	}
+	if result.PolicyMode != "enforce" {
+		t.Errorf("expected result policyMode 'enforce', got %q", result.PolicyMode)
+	}
+
+	// Override to warn - missing SBOM no longer blocks early
+	if err := cfg.OverridePolicyMode("warn"); err != nil {
+		t.Fatalf("OverridePolicyMode failed: %v", err)
+	}
+
+	result, err = Verify(cfg, "test:image", false, true, nil)
+	if err != nil && strings.Contains(err.Error(), "SBOM required") {
+		t.Errorf("warn override should not block on missing SBOM, got: %v", err)
+	}
+	if result.PolicyMode != "warn" {
+		t.Errorf("expected result policyMode 'warn', got %q", result.PolicyMode)
+	}
+
+	// State records the mode actually used
+	stateData, err := os.ReadFile(filepath.Join(".acc", "state", "last_verify.json"))
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+	var state VerifyState
+	if err := json.Unmarshal(stateData, &state); err != nil {
+		t.Fatalf("failed to parse state: %v", err)
+	}
+	if state.PolicyMode != "warn" {
+		t.Errorf("expected state policyMode 'warn', got %q", state.PolicyMode)
+	}
+}
+
+// TestViolationRemediation tests that built-in violations carry remediation hints
+// and that Rego-provided remediation is passed through
+func TestViolationRemediation(t *testing.T) {
+	t.Run("sbom-required", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldDir, _ := os.Getwd()
+		os.Chdir(tmpDir)
+		defer os.Chdir(oldDir)
+
+		cfg := &config.Config{
+			Project: config.ProjectConfig{Name: "remediation-test"},
+			SBOM:    config.SBOMConfig{Format: "spdx"},
+			Policy:  config.PolicyConfig{Mode: "enforce"},
+		}
+
+		result, _ := Verify(cfg, "test:image", false, true, nil)
+		if result == nil {
+			t.Fatal("expected result even on error")
+		}
+		for _, v := range result.Violations {
+			if v.Rule == "sbom-required" {
+				if v.Remediation == "" {
+					t.Error("expected sbom-required violation to include remediation")
+				}
+				return
+			}
+		}
+		t.Error("expected sbom-required violation")
+	})
+
+	t.Run("opa-required", func(t *testing.T) {
+		if _, err := exec.LookPath("opa"); err == nil {
+			t.Skip("opa is installed; opa-required violation not produced")
+		}
+		os.Setenv("ACC_ALLOW_NO_OPA", "1")
+		defer os.Unsetenv("ACC_ALLOW_NO_OPA")
+
+		violations, err := evaluateRego(t.TempDir(), &RegoInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(violations) != 1 || violations[0].Rule != "opa-required" {
+			t.Fatalf("expected single opa-required violation, got %+v", violations)
+		}
+		if !strings.Contains(violations[0].Remediation, "openpolicyagent.org") {
+			t.Errorf("expected OPA install remediation, got %q", violations[0].Remediation)
+		}
+	})
+
+	t.Run("rego passthrough", func(t *testing.T) {
+		v := parseViolationObject(map[string]interface{}{
+			"rule":        "no-root-user",
+			"severity":    "high",
+			"message":     "Container runs as root",
+			"remediation": "Add 'USER 1000' to your Dockerfile",
+		})
+		if v == nil {
+			t.Fatal("expected violation")
+		}
+		if v.Remediation != "Add 'USER 1000' to your Dockerfile" {
+			t.Errorf("expected Rego remediation to be passed through, got %q", v.Remediation)
+		}
+
+		v = parseViolationObject(map[string]interface{}{"rule": "no-root-user"})
+		if v == nil || v.Remediation != "" {
+			t.Errorf("expected empty remediation when Rego omits it, got %+v", v)
+		}
+	})
+}
+
+// TestMaxViolations tests that human output is truncated by severity while JSON keeps every violation
+func TestMaxViolations(t *testing.T) {
+	violations := []PolicyViolation{
+		{Rule: "no-latest-tag", Severity: "medium", Message: "uses latest tag"},
+		{Rule: "z-rule", Severity: "low", Message: "low finding"},
+		{Rule: "no-root-user", Severity: "high", Message: "runs as root"},
+		{Rule: "b-rule", Severity: "critical", Message: "critical b"},
+		{Rule: "a-rule", Severity: "critical", Message: "critical a"},
+	}
+
+	shown, more := violationsForDisplay(violations, 3)
+	if more != 2 {
+		t.Errorf("more = %d, want 2", more)
+	}
+	wantRules := []string{"a-rule", "b-rule", "no-root-user"}
+	if len(shown) != len(wantRules) {
+		t.Fatalf("shown %d violations, want %d", len(shown), len(wantRules))
+	}
+	for i, rule := range wantRules {
+		if shown[i].Rule != rule {
+			t.Errorf("shown[%d] = %s, want %s", i, shown[i].Rule, rule)
+		}
+	}
+
+	var buf bytes.Buffer
+	printViolations(&buf, violations, 3)
+	output := buf.String()
+	if !strings.Contains(output, "(+2 more)") {
+		t.Errorf("human output missing truncation line:\n%s", output)
+	}
+	if strings.Contains(output, "no-latest-tag") || strings.Contains(output, "z-rule") {
+		t.Errorf("human output should omit lowest-severity violations:\n%s", output)
+	}
+
+	// No limit prints everything
+	buf.Reset()
+	printViolations(&buf, violations, 0)
+	if strings.Contains(buf.String(), "more)") || !strings.Contains(buf.String(), "z-rule") {
+		t.Errorf("expected full human output without limit:\n%s", buf.String())
+	}
+
+	// JSON output is never truncated
+	result := &VerifyResult{
+		Status:       "fail",
+		PolicyResult: &PolicyResult{Allow: false, Violations: violations},
+		Violations:   violations,
+	}
+	var decoded VerifyResult
+	if err := json.Unmarshal([]byte(result.FormatJSON()), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Violations) != len(violations) || len(decoded.PolicyResult.Violations) != len(violations) {
+		t.Errorf("JSON should contain all %d violations, got %d", len(violations), len(decoded.Violations))
+	}
+}
+
+// TestSaveVerifyState_DigestRef tests that a digest reference (acc verify --digest) keys
+// per-image state by the provided digest without a container runtime
+func TestSaveVerifyState_DigestRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	// No container runtime available
+	t.Setenv("PATH", "")
+
+	digest := strings.Repeat("ab", 32)
+	imageRef := "ghcr.io/example/app@sha256:" + digest
+	result := &VerifyResult{Status: "pass", PolicyResult: &PolicyResult{Allow: true}}
+
+	if err := saveVerifyState(imageRef, result, nil); err != nil {
+		t.Fatalf("saveVerifyState failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(".acc", "state", "verify", digest+".json"))
+	if err != nil {
+		t.Fatalf("expected digest-scoped state for provided digest: %v", err)
+	}
+	var state VerifyState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("invalid state: %v", err)
+	}
+	if state.ImageRef != imageRef || state.Status != "pass" {
+		t.Errorf("unexpected state: imageRef=%s status=%s", state.ImageRef, state.Status)
+	}
+	if state.ImageDigest != "sha256:"+digest {
+		t.Errorf("expected the state to record sha256:%s, got %q", digest, state.ImageDigest)
+	}
+}
+
+// TestSaveVerifyState_SHA512DigestRef tests that an @sha512: reference keys per-image state by
+// <algorithm>-<hex> and keeps the algorithm with the digest when the state is read back
+func TestSaveVerifyState_SHA512DigestRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+	t.Setenv("PATH", "")
+
+	hex := strings.Repeat("ef", 64)
+	imageRef := "ghcr.io/example/app@sha512:" + hex
+	digest, err := oci.ResolveDigest(imageRef)
+	if err != nil || digest != "sha512:"+hex {
+		t.Fatalf("oci.ResolveDigest(%s) = %q, %v", imageRef, digest, err)
+	}
+
+	result := &VerifyResult{Status: "pass", PolicyResult: &PolicyResult{Allow: true}, Phase: PhaseBuild}
+	if err := saveVerifyState(imageRef, result, nil); err != nil {
+		t.Fatalf("saveVerifyState failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(".acc", "state", "verify", "sha512-"+hex+".json"))
+	if err != nil {
+		t.Fatalf("expected digest-scoped state named by algorithm and digest: %v", err)
+	}
+	var state VerifyState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("invalid state: %v", err)
+	}
+	if state.ImageRef != imageRef || state.ImageDigest != "sha512:"+hex {
+		t.Errorf("expected the sha512 digest to round-trip, got imageRef=%s imageDigest=%s", state.ImageRef, state.ImageDigest)
+	}
+
+	// The build-phase record is found again by the same digest
+	if violation := checkBuildPhase(imageRef); violation != nil {
+		t.Errorf("expected the sha512 build phase to be found, got %+v", violation)
+	}
+	if violation := checkDigestPinned(imageRef); violation != nil {
+		t.Errorf("expected an @sha512: reference to count as digest-pinned, got %+v", violation)
+	}
+}
+
+// TestBuildRegoInput_FromManifest tests that --input-from-manifest builds the input from the build manifest
+func TestBuildRegoInput_FromManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	digest := strings.Repeat("cd", 32)
+	manifest := `{
+  "schemaVersion": "v1",
+  "imageRef": "demo:latest",
+  "imageDigest": "` + digest + `",
+  "createdAt": "2025-01-01T00:00:00Z",
+  "baseImage": "alpine:3.19",
+  "config": {
+    "User": "app",
+    "Labels": {"team": "payments"},
+    "Env": ["PORT=8080"],
+    "ExposedPorts": {"8080/tcp": {}}
+  }
+}`
+	manifestDir := filepath.Join(".acc", "state", "build")
+	os.MkdirAll(manifestDir, 0755)
+	os.WriteFile(filepath.Join(manifestDir, digest+".json"), []byte(manifest), 0644)
+
+	cfg := config.DefaultConfig("demo")
+	cfg.Policy.InputFromManifest = true
+
+	// A digest reference needs no container runtime, so the input must come from the manifest
+	input, err := buildRegoInput(cfg, "demo@sha256:"+digest, false)
+	if err != nil {
+		t.Fatalf("buildRegoInput failed: %v", err)
+	}
+
+	wantConfig := ImageConfig{
+		User:         "app",
+		Labels:       map[string]string{"team": "payments"},
+		Env:          []string{"PORT=8080"},
+		ExposedPorts: map[string]struct{}{"8080/tcp": {}},
+	}
+	if !reflect.DeepEqual(input.Config, wantConfig) {
+		t.Errorf("input config = %+v, want %+v", input.Config, wantConfig)
+	}
+	if input.Build == nil || input.Build.BaseImage != "alpine:3.19" || input.Build.CreatedAt != "2025-01-01T00:00:00Z" {
+		t.Errorf("expected build info from manifest, got %+v", input.Build)
+	}
+}
+
+// TestBuildRegoInput_ManifestFallback tests that a missing manifest falls back to image inspection
+func TestBuildRegoInput_ManifestFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":{\"User\":\"root\",\"Labels\":null}}]'\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.DefaultConfig("demo")
+	cfg.Policy.InputFromManifest = true
+
+	input, err := buildRegoInput(cfg, "demo@sha256:"+strings.Repeat("ef", 32), false)
+	if err != nil {
+		t.Fatalf("buildRegoInput failed: %v", err)
+	}
+	if input.Config.User != "root" || input.Config.Labels == nil {
+		t.Errorf("expected inspected config, got %+v", input.Config)
+	}
+	if input.Build != nil {
+		t.Errorf("expected no build info without a manifest, got %+v", input.Build)
+	}
+}
+
+// TestBuildRegoInput_CaptureInspect tests that --capture-inspect writes the raw inspect JSON
+// the policy input was built from
+func TestBuildRegoInput_CaptureInspect(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(originalDir)
+
+	inspectJSON := `[{"Id":"sha256:abc","Config":{"User":"","Labels":{"team":"payments"},"Env":["PATH=/bin"]},"RepoTags":["demo:latest"]}]`
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '"+inspectJSON+"'\n"), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.DefaultConfig("demo")
+	cfg.Policy.CaptureInspect = filepath.Join(tmpDir, "inspect.json")
+
+	input, err := buildRegoInput(cfg, "demo:latest", false)
+	if err != nil {
+		t.Fatalf("buildRegoInput failed: %v", err)
+	}
+	data, err := os.ReadFile(cfg.Policy.CaptureInspect)
+	if err != nil {
+		t.Fatalf("expected the inspect output to be captured: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != inspectJSON {
+		t.Errorf("expected the raw inspect JSON, got %s", data)
+	}
+	if input.Config.Labels["team"] != "payments" {
+		t.Errorf("expected the input to come from the captured inspect, got %+v", input.Config)
+	}
+
+	// A capture path that cannot be written fails instead of silently dropping the capture
+	cfg.Policy.CaptureInspect = filepath.Join(tmpDir, "missing", "inspect.json")
+	if _, err := buildRegoInput(cfg, "demo:latest", false); err == nil || !strings.Contains(err.Error(), "capture-inspect") {
+		t.Errorf("expected an unwritable capture path to fail, got %v", err)
+	}
+}
+
+// TestCheckAttestations_ExternalOnly tests that cached cosign attestations do not make
+// input.attestation.present true
+func TestCheckAttestations_ExternalOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	defer os.Chdir(oldDir)
+
+	cfg := config.DefaultConfig("test-project")
+	remoteDir := filepath.Join(".acc", "attestations", "cdcdcdcdcdcd", "remote")
+	os.MkdirAll(remoteDir, 0755)
+	os.WriteFile(filepath.Join(remoteDir, "envelope.json"), []byte(`{"payloadType":"application/vnd.in-toto+json","payload":""}`), 0644)
+	if checkAttestations(cfg) {
+		t.Error("expected a cosign-only attestation directory not to count as present")
+	}
+
+	os.WriteFile(filepath.Join(".acc", "attestations", "cdcdcdcdcdcd", "attestation.json"), []byte(`{"schemaVersion":"v0.1"}`), 0644)
+	if !checkAttestations(cfg) {
+		t.Error("expected an acc attestation to count as present")
+	}
+}
@@ -0,0 +1,55 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/cloudcwfranck/acc/internal/waivers"
+)
+
+// applyWaivers moves violations covered by an active (unexpired) waiver to warnings,
+// noting the waiver's justification. Expired waivers never suppress anything; they
+// are reported as failures by the expiry check instead.
+// Returns the number of violations waived.
+func applyWaivers(result *VerifyResult, loaded []waivers.Waiver) int {
+	if result.PolicyResult == nil {
+		return 0
+	}
+
+	var active []waivers.Waiver
+	for _, w := range loaded {
+		if !w.IsExpired() {
+			active = append(active, w)
+		}
+	}
+	if len(active) == 0 {
+		return 0
+	}
+
+	kept := []PolicyViolation{}
+	waived := 0
+	for _, v := range result.PolicyResult.Violations {
+		w := waivers.GetWaiverForRule(active, v.Rule)
+		if w == nil {
+			kept = append(kept, v)
+			continue
+		}
+		v.Message = fmt.Sprintf("%s (waived: %s)", v.Message, w.Justification)
+		result.PolicyResult.Warnings = append(result.PolicyResult.Warnings, v)
+		waived++
+	}
+	if waived == 0 {
+		return 0
+	}
+
+	remaining := []PolicyViolation{}
+	for _, v := range result.Violations {
+		if waivers.GetWaiverForRule(active, v.Rule) == nil {
+			remaining = append(remaining, v)
+		}
+	}
+
+	result.PolicyResult.Violations = kept
+	result.PolicyResult.Allow = len(kept) == 0
+	result.Violations = remaining
+	return waived
+}
@@ -0,0 +1,116 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/config"
+	"github.com/cloudcwfranck/acc/internal/waivers"
+)
+
+// setupWaiverProject creates an SBOM, a policy, a fake docker, and a fake opa reporting opaViolations
+func setupWaiverProject(t *testing.T, waiversYAML, opaViolations string) *config.Config {
+	t.Helper()
+	tmpDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tmpDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	os.MkdirAll(filepath.Join(".acc", "sbom"), 0755)
+	os.WriteFile(filepath.Join(".acc", "sbom", "waiver-test.spdx.json"), []byte("{}"), 0644)
+	os.MkdirAll(filepath.Join(".acc", "policy"), 0755)
+	os.WriteFile(filepath.Join(".acc", "policy", "policy.rego"), []byte("package acc.policy\n"), 0644)
+	os.WriteFile(filepath.Join(".acc", "waivers.yaml"), []byte(waiversYAML), 0644)
+
+	binDir := t.TempDir()
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\necho '[{\"Config\":{\"User\":\"root\",\"Labels\":null}}]'\n"), 0755)
+	opa := `#!/bin/sh
+[ "$1" = "version" ] && { echo '{"Version":"1.0.0"}'; exit 0; }
+echo '{"result":[{"expressions":[{"value":{"violations":[` + opaViolations + `]}}]}]}'
+`
+	os.WriteFile(filepath.Join(binDir, "opa"), []byte(opa), 0755)
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return config.DefaultConfig("waiver-test")
+}
+
+// TestVerify_NoWaiversReportsWaivedViolation tests that a normally-waived violation appears under --no-waivers
+func TestVerify_NoWaiversReportsWaivedViolation(t *testing.T) {
+	waiversYAML := `waivers:
+  - ruleId: no-root-user
+    justification: legacy base image, tracked in JIRA-42
+    expiry: "2999-01-01T00:00:00Z"
+`
+	rootViolation := `{"rule":"no-root-user","severity":"critical","result":"fail","message":"runs as root"}`
+	cfg := setupWaiverProject(t, waiversYAML, rootViolation)
+
+	// Waivers applied: the violation becomes a warning and verification passes
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil {
+		t.Fatalf("expected waived violation to pass, got: %v", err)
+	}
+	if result.Status != "pass" || len(result.Violations) != 0 {
+		t.Fatalf("expected pass with no violations, got %s %+v", result.Status, result.Violations)
+	}
+	if len(result.PolicyResult.Warnings) != 1 || !strings.Contains(result.PolicyResult.Warnings[0].Message, "waived: legacy base image") {
+		t.Errorf("expected waived violation as a warning, got %+v", result.PolicyResult.Warnings)
+	}
+	if result.WaiversApplied == nil || !*result.WaiversApplied {
+		t.Errorf("waiversApplied = %v, want true", result.WaiversApplied)
+	}
+
+	// --no-waivers: the true violation set
+	cfg.Policy.NoWaivers = true
+	result, err = Verify(cfg, "test:latest", false, true, nil)
+	if err == nil {
+		t.Fatal("expected verification to fail without waivers")
+	}
+	if result.Status != "fail" || len(result.Violations) != 1 || result.Violations[0].Rule != "no-root-user" {
+		t.Errorf("expected no-root-user violation, got %s %+v", result.Status, result.Violations)
+	}
+	if len(result.PolicyResult.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %+v", result.PolicyResult.Warnings)
+	}
+	if result.WaiversApplied == nil || *result.WaiversApplied {
+		t.Errorf("waiversApplied = %v, want false", result.WaiversApplied)
+	}
+}
+
+// TestVerify_NoWaiversSkipsExpiredWaivers tests that expired waivers do not fail verification under --no-waivers
+func TestVerify_NoWaiversSkipsExpiredWaivers(t *testing.T) {
+	waiversYAML := `waivers:
+  - ruleId: no-root-user
+    justification: expired exception
+    expiry: "2020-01-01T00:00:00Z"
+`
+	cfg := setupWaiverProject(t, waiversYAML, "")
+
+	if _, err := Verify(cfg, "test:latest", false, true, nil); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("expected expired waiver to fail verification, got: %v", err)
+	}
+
+	cfg.Policy.NoWaivers = true
+	result, err := Verify(cfg, "test:latest", false, true, nil)
+	if err != nil {
+		t.Fatalf("expected --no-waivers to ignore the expired waiver, got: %v", err)
+	}
+	if result.Status != "pass" {
+		t.Errorf("status = %s, want pass", result.Status)
+	}
+}
+
+// TestApplyWaivers_ExpiredWaiverDoesNotSuppress tests that only active waivers suppress violations
+func TestApplyWaivers_ExpiredWaiverDoesNotSuppress(t *testing.T) {
+	violation := PolicyViolation{Rule: "no-root-user", Severity: "critical", Result: "fail", Message: "runs as root"}
+	result := &VerifyResult{
+		Violations:   []PolicyViolation{violation},
+		PolicyResult: &PolicyResult{Allow: false, Violations: []PolicyViolation{violation}, Warnings: []PolicyViolation{}},
+	}
+
+	waived := applyWaivers(result, []waivers.Waiver{{RuleID: "no-root-user", Expiry: "2020-01-01T00:00:00Z"}})
+	if waived != 0 || result.PolicyResult.Allow || len(result.Violations) != 1 {
+		t.Errorf("expired waiver suppressed a violation: waived=%d result=%+v", waived, result.PolicyResult)
+	}
+}
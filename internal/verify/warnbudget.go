@@ -0,0 +1,37 @@
+package verify
+
+import "fmt"
+
+// WarningBudget reports the warning count against policy.failOnWarningCount
+type WarningBudget struct {
+	Limit    int  `json:"limit"`
+	Count    int  `json:"count"`
+	Exceeded bool `json:"exceeded"`
+}
+
+// applyWarningBudget fails the policy result when it carries more warnings (violations
+// downgraded by the profile, .accignore, or waivers) than limit, independent of violations.
+// Returns true if the budget was exceeded.
+func applyWarningBudget(result *VerifyResult, limit int) bool {
+	if result.PolicyResult == nil {
+		return false
+	}
+
+	count := len(result.PolicyResult.Warnings)
+	result.WarningBudget = &WarningBudget{Limit: limit, Count: count, Exceeded: count > limit}
+	if count <= limit {
+		return false
+	}
+
+	violation := PolicyViolation{
+		Rule:        "warning-budget-exceeded",
+		Severity:    "critical",
+		Result:      "fail",
+		Message:     fmt.Sprintf("%d warnings exceed the budget of %d", count, limit),
+		Remediation: remediationWarningBudget,
+	}
+	result.PolicyResult.Violations = append(result.PolicyResult.Violations, violation)
+	result.PolicyResult.Allow = false
+	result.Violations = append(result.Violations, violation)
+	return true
+}
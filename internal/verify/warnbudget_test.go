@@ -0,0 +1,65 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/cloudcwfranck/acc/internal/profile"
+)
+
+// TestVerify_WarningBudget tests that verification fails only once profile warnings exceed the budget
+func TestVerify_WarningBudget(t *testing.T) {
+	lowViolations := `{"rule":"missing-healthcheck","severity":"low","result":"fail","message":"no healthcheck"},` +
+		`{"rule":"old-base-image","severity":"low","result":"fail","message":"base image is old"}`
+	prof := &profile.Profile{
+		Name:       "lenient",
+		Violations: profile.ViolationConfig{Ignore: []string{"low"}},
+		Warnings:   profile.WarningConfig{Show: true},
+	}
+
+	tests := []struct {
+		name       string
+		budget     *int
+		wantStatus string
+	}{
+		{"no budget", nil, "pass"},
+		{"under budget", intPtr(3), "pass"},
+		{"at budget", intPtr(2), "pass"},
+		{"over budget", intPtr(1), "fail"},
+		{"zero budget", intPtr(0), "fail"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := setupWaiverProject(t, "waivers: []\n", lowViolations)
+			cfg.Policy.FailOnWarningCount = tt.budget
+
+			result, err := Verify(cfg, "test:latest", false, true, prof)
+			if result == nil {
+				t.Fatalf("expected a result, got error %v", err)
+			}
+			if result.Status != tt.wantStatus || (err != nil) != (tt.wantStatus == "fail") {
+				t.Fatalf("status = %s (err %v), want %s", result.Status, err, tt.wantStatus)
+			}
+			if len(result.PolicyResult.Warnings) != 2 {
+				t.Errorf("expected 2 profile warnings, got %+v", result.PolicyResult.Warnings)
+			}
+
+			if tt.budget == nil {
+				if result.WarningBudget != nil {
+					t.Errorf("expected no warningBudget without a budget, got %+v", result.WarningBudget)
+				}
+				return
+			}
+			want := WarningBudget{Limit: *tt.budget, Count: 2, Exceeded: tt.wantStatus == "fail"}
+			if result.WarningBudget == nil || *result.WarningBudget != want {
+				t.Errorf("warningBudget = %+v, want %+v", result.WarningBudget, want)
+			}
+			if tt.wantStatus == "fail" {
+				if len(result.Violations) != 1 || result.Violations[0].Rule != "warning-budget-exceeded" {
+					t.Errorf("expected only a warning-budget-exceeded violation, got %+v", result.Violations)
+				}
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int { return &n }
@@ -149,6 +149,7 @@ CORE_COMMANDS=(
     "inspect --help:Inspect trust summary"
     "version --help:Show version"
     "upgrade --help:Upgrade acc"
+    "clean --help:Prune local state"
 )
 
 for cmd_spec in "${CORE_COMMANDS[@]}"; do